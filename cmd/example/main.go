@@ -42,11 +42,7 @@ func init() {
 }
 
 func mockCloud() cloud.Cloud {
-	mock := cloud.NewMockGCE()
-	mock.MockZones.Objects[*meta.ZonalKey("abc", "us-central1-b")] = &cloud.MockZonesObj{
-		ga.Zone{Name: "us-central1-b"},
-	}
-	return mock
+	return cloud.NewMockGCE(cloud.WithMockTopology(cloud.DefaultTopologyCatalog))
 }
 
 func realCloud() cloud.Cloud {
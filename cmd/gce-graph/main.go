@@ -0,0 +1,113 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// gce-graph lists all of the compute resources of a project and reports the
+// references to resources that do not exist and, with -owner-label, the
+// owned resources that nothing references. It exits with status 1 if it
+// reports anything.
+//
+//  gce-graph -project my-project -owner-label owner=my-cluster
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"golang.org/x/oauth2/google"
+
+	alpha "google.golang.org/api/compute/v0.alpha"
+	beta "google.golang.org/api/compute/v0.beta"
+	ga "google.golang.org/api/compute/v1"
+
+	"github.com/bowei/gce-gen/pkg/cloud"
+	"github.com/bowei/gce-gen/pkg/cloud/graph"
+)
+
+var flags = struct {
+	project    string
+	resources  string
+	ownerLabel string
+}{}
+
+func init() {
+	flag.StringVar(&flags.project, "project", "", "project to list the resources of")
+	flag.StringVar(&flags.resources, "resources", "", "comma separated resources to list (e.g. backendServices,urlMaps); all if empty")
+	flag.StringVar(&flags.ownerLabel, "owner-label", "", "report the unreferenced resources with this label, as key or key=value")
+}
+
+func newCloud(project string) cloud.Cloud {
+	ctx := context.Background()
+	c, err := google.DefaultClient(ctx, ga.CloudPlatformScope)
+	if err != nil {
+		log.Fatal(err)
+	}
+	g, err := ga.New(c)
+	if err != nil {
+		log.Fatal(err)
+	}
+	a, err := alpha.New(c)
+	if err != nil {
+		log.Fatal(err)
+	}
+	b, err := beta.New(c)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return cloud.NewGCE(&cloud.Service{
+		GA:            g,
+		Alpha:         a,
+		Beta:          b,
+		ProjectRouter: &cloud.SingleProjectRouter{ID: project},
+		RateLimiter:   &cloud.NopRateLimiter{},
+	})
+}
+
+func main() {
+	flag.Parse()
+	if flags.project == "" {
+		log.Fatal("-project is required")
+	}
+
+	var opts graph.BuildOptions
+	if flags.resources != "" {
+		opts.Resources = strings.Split(flags.resources, ",")
+	}
+	g, err := graph.Build(context.Background(), newCloud(flags.project), opts)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	found := false
+	for _, r := range g.Dangling() {
+		fmt.Printf("dangling %v\n", r)
+		found = true
+	}
+	if flags.ownerLabel != "" {
+		parts := strings.SplitN(flags.ownerLabel, "=", 2)
+		parts = append(parts, "")
+		for _, n := range g.Orphans(graph.HasLabel(parts[0], parts[1])) {
+			fmt.Printf("orphan %v\n", n)
+			found = true
+		}
+	}
+	if found {
+		os.Exit(1)
+	}
+}
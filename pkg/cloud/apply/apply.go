@@ -0,0 +1,197 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package apply brings resources to a desired state with the generated
+// cloud.Cloud methods. Apply() gets the current object and inserts, updates
+// or deletes and re-inserts it as needed:
+//
+//  fw := &ga.Firewall{Network: network, SourceRanges: ranges, ...}
+//  result, err := apply.Apply(ctx, c, *meta.GlobalKey("my-fw"), fw)
+//
+// The desired object is compared with the current one with diff.Differences(),
+// so output-only fields (e.g. Id, CreationTimestamp) and the server defaults
+// of unset fields do not cause changes, while a field set in the current
+// object and cleared in the desired one does.
+package apply
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/golang/glog"
+
+	"github.com/bowei/gce-gen/pkg/cloud"
+	"github.com/bowei/gce-gen/pkg/cloud/diff"
+	"github.com/bowei/gce-gen/pkg/cloud/meta"
+)
+
+// Result of Apply().
+type Result string
+
+const (
+	// Unchanged means that the object was already in the desired state.
+	Unchanged Result = "Unchanged"
+	// Created means that the object did not exist and was inserted.
+	Created Result = "Created"
+	// Updated means that the object was updated in place.
+	Updated Result = "Updated"
+	// Recreated means that the object was deleted and inserted again,
+	// because the service cannot update it or an immutable field changed.
+	Recreated Result = "Recreated"
+)
+
+// ImmutableFields are the JSON names of the fields that cannot be changed
+// with Update(), by Object name (e.g. "Firewall"). Objects of services
+// without an Update() method are always recreated.
+var ImmutableFields = map[string][]string{
+	"BackendService": {"loadBalancingScheme"},
+	"Firewall":       {"network", "direction"},
+	"HealthCheck":    {"type"},
+}
+
+// Apply brings the object for key to desired, a pointer to an object of one
+// of the services in meta.AllServices (e.g. *ga.Firewall). The service is
+// chosen by the type of desired and of key. The Name of desired is set to
+// the name of the key.
+func Apply(ctx context.Context, c cloud.Cloud, key meta.Key, desired interface{}) (Result, error) {
+	svc, si, err := service(c, key, desired)
+	if err != nil {
+		return "", err
+	}
+	reflect.ValueOf(desired).Elem().FieldByName("Name").SetString(key.Name)
+
+	current, err := cloud.CallMethod(svc, "Get", ctx, key)
+	if cloud.IsNotFound(err) {
+		if _, err := cloud.CallMethod(svc, "Insert", ctx, key, desired); err != nil {
+			return "", err
+		}
+		glog.V(2).Infof("apply.Apply(%v): created %s", key, si.Service)
+		return Created, nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	changed, err := Diff(desired, current)
+	if err != nil {
+		return "", err
+	}
+	if len(changed) == 0 {
+		return Unchanged, nil
+	}
+
+	if updatesObject(si) && !hasImmutable(si.Object, changed) {
+		copyFingerprint(desired, current)
+		if _, err := cloud.CallMethod(svc, "Update", ctx, key, desired); err != nil {
+			return "", err
+		}
+		glog.V(2).Infof("apply.Apply(%v): updated %s fields %v", key, si.Service, changed)
+		return Updated, nil
+	}
+
+	if _, err := cloud.CallMethod(svc, "Delete", ctx, key); err != nil {
+		return "", err
+	}
+	if _, err := cloud.CallMethod(svc, "Insert", ctx, key, desired); err != nil {
+		return "", err
+	}
+	glog.V(2).Infof("apply.Apply(%v): recreated %s for fields %v", key, si.Service, changed)
+	return Recreated, nil
+}
+
+// Diff returns the sorted JSON names of the top-level fields that differ
+// between desired and current, as compared by diff.Differences() (e.g.
+// "backends" if the group of one of the backends changed).
+func Diff(desired, current interface{}) ([]string, error) {
+	diffs, err := diff.Differences(desired, current)
+	if err != nil {
+		return nil, err
+	}
+	var ret []string
+	for _, d := range diffs {
+		f := d.Path
+		if i := strings.IndexAny(f, ".["); i >= 0 {
+			f = f[:i]
+		}
+		if len(ret) == 0 || ret[len(ret)-1] != f {
+			ret = append(ret, f)
+		}
+	}
+	return ret, nil
+}
+
+// service returns the wrapper (e.g. cloud.Firewalls) in c for the type of
+// obj and key.
+func service(c cloud.Cloud, key meta.Key, obj interface{}) (reflect.Value, *meta.ServiceInfo, error) {
+	t := reflect.TypeOf(obj)
+	if t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, nil, fmt.Errorf("apply: %T is not a pointer to a compute object", obj)
+	}
+	for _, si := range meta.AllServices {
+		if si.Object != t.Elem().Name() || si.KeyType() != key.Type() {
+			continue
+		}
+		svc, ok := cloud.ServiceOf(c, si)
+		if !ok {
+			continue
+		}
+		get, ok := svc.Type().MethodByName("Get")
+		if !ok || get.Type.Out(0) != t {
+			continue
+		}
+		for _, name := range []string{"Insert", "Delete"} {
+			if !cloud.HasMethods(svc, name) {
+				return reflect.Value{}, nil, fmt.Errorf("apply: %s does not have %s()", si.WrapType(), name)
+			}
+		}
+		return svc, si, nil
+	}
+	return reflect.Value{}, nil, fmt.Errorf("apply: no service for %T with a %s key", obj, key.Type())
+}
+
+// updatesObject is true if the service of si has an Update() method that
+// replaces the object (see meta.Method.UpdatesObject()).
+func updatesObject(si *meta.ServiceInfo) bool {
+	for _, m := range si.Methods() {
+		if m.UpdatesObject() {
+			return true
+		}
+	}
+	return false
+}
+
+func hasImmutable(object string, changed []string) bool {
+	for _, f := range ImmutableFields[object] {
+		for _, c := range changed {
+			if f == c {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// copyFingerprint from current to desired if desired does not have one, as
+// the compute API requires the fingerprint of the object being updated.
+func copyFingerprint(desired, current interface{}) {
+	d := reflect.ValueOf(desired).Elem().FieldByName("Fingerprint")
+	c := reflect.ValueOf(current).Elem().FieldByName("Fingerprint")
+	if d.IsValid() && c.IsValid() && d.Kind() == reflect.String && d.String() == "" {
+		d.SetString(c.String())
+	}
+}
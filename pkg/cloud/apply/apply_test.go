@@ -0,0 +1,173 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apply
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	alpha "google.golang.org/api/compute/v0.alpha"
+	ga "google.golang.org/api/compute/v1"
+
+	"github.com/bowei/gce-gen/pkg/cloud"
+	"github.com/bowei/gce-gen/pkg/cloud/meta"
+)
+
+// callRecorder records the operations of the calls.
+type callRecorder struct {
+	ops []string
+}
+
+func (r *callRecorder) Before(ctx context.Context, cc *cloud.CallContext) context.Context {
+	r.ops = append(r.ops, cc.Operation)
+	return ctx
+}
+
+func (r *callRecorder) After(ctx context.Context, cc *cloud.CallContext) {}
+
+func TestApply(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := cloud.NewMockGCE()
+	rec := &callRecorder{}
+	mock.SetObserver(rec)
+
+	fwKey := *meta.GlobalKey("fw")
+	addrKey := *meta.RegionalKey("addr", "us-central1")
+	bsKey := *meta.GlobalKey("bs")
+	fw2Key := *meta.GlobalKey("fw-2")
+	const networkURL = "https://www.googleapis.com/compute/v1/projects/mock-project/global/networks/default"
+	fw := func(network string, ranges ...string) *ga.Firewall {
+		return &ga.Firewall{Network: network, SourceRanges: ranges}
+	}
+
+	for _, tc := range []struct {
+		desc    string
+		key     meta.Key
+		desired interface{}
+		want    Result
+		wantOps []string
+	}{
+		{"create", fwKey, fw("default", "10.0.0.0/8"), Created, []string{"Get", "Insert"}},
+		{"unchanged", fwKey, fw("default", "10.0.0.0/8"), Unchanged, []string{"Get"}},
+		{"update", fwKey, fw("default", "10.0.0.0/8", "192.168.0.0/16"), Updated, []string{"Get", "Update"}},
+		{"cleared field", fwKey, fw("default"), Updated, []string{"Get", "Update"}},
+		{"immutable field", fwKey, fw("other", "10.0.0.0/8"), Recreated, []string{"Get", "Delete", "Insert"}},
+		{"create without Update", addrKey, &ga.Address{Address: "10.0.0.1"}, Created, []string{"Get", "Insert"}},
+		{"recreate without Update", addrKey, &ga.Address{Address: "10.0.0.2"}, Recreated, []string{"Get", "Delete", "Insert"}},
+		{"alpha", bsKey, &alpha.BackendService{Port: 80}, Created, []string{"Get", "Insert"}},
+		{"other version", bsKey, &ga.BackendService{Port: 80}, Unchanged, []string{"Get"}},
+		{"other version update", bsKey, &ga.BackendService{Port: 8080}, Updated, []string{"Get", "Update"}},
+		{"relative network", fw2Key, fw("global/networks/default"), Created, []string{"Get", "Insert"}},
+		{"network URL", fw2Key, fw(networkURL), Unchanged, []string{"Get"}},
+	} {
+		rec.ops = nil
+		got, err := Apply(ctx, mock, tc.key, tc.desired)
+		if err != nil || got != tc.want {
+			t.Errorf("%s: Apply(%v, %+v) = %v, %v; want %v, nil", tc.desc, tc.key, tc.desired, got, err, tc.want)
+		}
+		if !reflect.DeepEqual(rec.ops, tc.wantOps) {
+			t.Errorf("%s: calls = %v, want %v", tc.desc, rec.ops, tc.wantOps)
+		}
+	}
+
+	got, err := mock.Firewalls().Get(ctx, fwKey)
+	if err != nil {
+		t.Fatalf("Firewalls().Get() = _, %v", err)
+	}
+	if got.Name != "fw" || got.Network != "other" || !reflect.DeepEqual(got.SourceRanges, []string{"10.0.0.0/8"}) {
+		t.Errorf("Firewalls().Get() = %+v; want the last desired state", got)
+	}
+}
+
+func TestApplyErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := cloud.NewMockGCE()
+	for _, tc := range []struct {
+		desc    string
+		key     meta.Key
+		desired interface{}
+	}{
+		{"not a pointer", *meta.GlobalKey("fw"), ga.Firewall{}},
+		{"nil", *meta.GlobalKey("fw"), nil},
+		{"wrong key type", *meta.ZonalKey("fw", "us-central1-b"), &ga.Firewall{}},
+		{"not a compute object", *meta.GlobalKey("fw"), &struct{ Name string }{}},
+		{"read only service", *meta.GlobalKey("us-central1"), &ga.Region{}},
+	} {
+		if got, err := Apply(ctx, mock, tc.key, tc.desired); err == nil {
+			t.Errorf("%s: Apply(%v, %+v) = %v, nil; want error", tc.desc, tc.key, tc.desired, got)
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	current := &ga.BackendService{
+		Name:     "bs",
+		Id:       123,
+		Port:     80,
+		Backends: []*ga.Backend{{Group: "ig-1", BalancingMode: "UTILIZATION"}},
+		HealthChecks: []string{
+			"https://www.googleapis.com/compute/v1/projects/proj/global/healthChecks/hc",
+		},
+	}
+	// desired returns the object in the state of current, changed by f.
+	desired := func(f func(bs *ga.BackendService)) *ga.BackendService {
+		bs := &ga.BackendService{
+			Name:         "bs",
+			Backends:     []*ga.Backend{{Group: "ig-1"}},
+			HealthChecks: []string{"global/healthChecks/hc"},
+		}
+		f(bs)
+		return bs
+	}
+	for _, tc := range []struct {
+		desc    string
+		desired *ga.BackendService
+		want    []string
+	}{
+		{"same", desired(func(bs *ga.BackendService) {}), nil},
+		{"defaults", desired(func(bs *ga.BackendService) { bs.Protocol = "HTTP"; bs.Port = 80 }), nil},
+		{"changed", desired(func(bs *ga.BackendService) { bs.Port = 8080; bs.Protocol = "HTTPS" }), []string{"port", "protocol"}},
+		{"nested change", desired(func(bs *ga.BackendService) { bs.Backends[0].Group = "ig-2" }), []string{"backends"}},
+		{"list length", desired(func(bs *ga.BackendService) { bs.Backends = append(bs.Backends, &ga.Backend{Group: "ig-2"}) }), []string{"backends"}},
+		{"cleared", desired(func(bs *ga.BackendService) { bs.Backends = nil }), []string{"backends"}},
+		{"reference in the same project", desired(func(bs *ga.BackendService) {
+			bs.HealthChecks = []string{"projects/proj/global/healthChecks/hc"}
+		}), nil},
+		{"reference to another project", desired(func(bs *ga.BackendService) {
+			bs.HealthChecks = []string{"projects/other/global/healthChecks/hc"}
+		}), []string{"healthChecks"}},
+		{"reference to another resource", desired(func(bs *ga.BackendService) {
+			bs.HealthChecks = []string{"global/healthChecks/hc-2"}
+		}), []string{"healthChecks"}},
+	} {
+		got, err := Diff(tc.desired, current)
+		if err != nil || !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: Diff(%+v, _) = %v, %v; want %v, nil", tc.desc, tc.desired, got, err, tc.want)
+		}
+	}
+
+	if _, err := Diff(&ga.BackendService{}, &ga.Firewall{}); err == nil {
+		t.Errorf("Diff(BackendService, Firewall) = _, nil; want an error")
+	}
+}
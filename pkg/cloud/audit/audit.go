@@ -0,0 +1,162 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package audit records an audit event for each call made through the
+// generated cloud code that mutates a resource (Insert(), Delete(), Update(),
+// SetUrlMap() and so on). Events are written to a pluggable Sink.
+//
+//  sink, err := audit.OpenJSONLinesFile("/var/log/gce-audit.jsonl")
+//  ...
+//  svc := &cloud.Service{
+//    ...
+//    Observer: audit.NewObserver(sink),
+//  }
+//  ...
+//  ctx = audit.WithCaller(ctx, "ingress-controller")
+//  gce.Firewalls().Insert(ctx, key, fw)
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/glog"
+
+	"github.com/bowei/gce-gen/pkg/cloud"
+	"github.com/bowei/gce-gen/pkg/cloud/meta"
+)
+
+// Outcome of a mutation.
+type Outcome string
+
+const (
+	// OutcomeSuccess means the call (and the operation it started, if any)
+	// completed without error.
+	OutcomeSuccess Outcome = "success"
+	// OutcomeFailure means the call returned an error.
+	OutcomeFailure Outcome = "failure"
+	// OutcomeDryRun means the call was recorded in the cloud.DryRunPlan and
+	// not sent to GCE.
+	OutcomeDryRun Outcome = "dry-run"
+)
+
+// Event is the audit record of a single mutation.
+type Event struct {
+	// Time the call started.
+	Time time.Time `json:"time"`
+	// Duration of the call, including the wait for the operation.
+	Duration time.Duration `json:"duration"`
+	// Caller is the identity set in the context of the call with
+	// WithCaller().
+	Caller string `json:"caller,omitempty"`
+	// Project the call was made against, as given by the ProjectRouter.
+	Project string       `json:"project"`
+	Version meta.Version `json:"version"`
+	Service string       `json:"service"`
+	// Method is the name of the method called, e.g. "Insert".
+	Method string `json:"method"`
+	// Key of the resource. This is nil for calls that do not take a key.
+	Key *meta.Key `json:"key,omitempty"`
+	// Request is a copy of the request body of the call, if any. It is a
+	// []interface{} of the arguments of calls with more than one (see
+	// cloud.CallContext.Request).
+	Request interface{} `json:"request,omitempty"`
+	// Operation is the name of the GCE operation started by the call, if
+	// any.
+	Operation string  `json:"operation,omitempty"`
+	Outcome   Outcome `json:"outcome"`
+	// Error is the error message if Outcome is OutcomeFailure.
+	Error string `json:"error,omitempty"`
+}
+
+// Sink receives audit events.
+type Sink interface {
+	// Write the event to the sink. Write may be called concurrently.
+	Write(e *Event) error
+}
+
+type callerKey struct{}
+
+// WithCaller returns a context that identifies the caller of the calls made
+// with it as caller.
+func WithCaller(ctx context.Context, caller string) context.Context {
+	return context.WithValue(ctx, callerKey{}, caller)
+}
+
+// CallerFromContext returns the caller set with WithCaller() or "" if there
+// is none.
+func CallerFromContext(ctx context.Context) string {
+	caller, _ := ctx.Value(callerKey{}).(string)
+	return caller
+}
+
+// Observer is a cloud.CallObserver that writes an Event to its Sink after
+// each call that mutates a resource. Calls that only read resources are
+// ignored.
+type Observer struct {
+	sink Sink
+}
+
+// NewObserver returns an observer that writes events to sink.
+func NewObserver(sink Sink) *Observer {
+	return &Observer{sink: sink}
+}
+
+// Before implements cloud.CallObserver.
+func (o *Observer) Before(ctx context.Context, cc *cloud.CallContext) context.Context {
+	return ctx
+}
+
+// After implements cloud.CallObserver.
+func (o *Observer) After(ctx context.Context, cc *cloud.CallContext) {
+	if !cc.Mutates {
+		return
+	}
+	e := NewEvent(ctx, cc)
+	if err := o.sink.Write(e); err != nil {
+		glog.Errorf("audit: error writing event for %s.%s(%v) in project %q: %v", e.Service, e.Method, e.Key, e.Project, err)
+	}
+}
+
+// NewEvent returns the Event for the completed call cc made with ctx.
+func NewEvent(ctx context.Context, cc *cloud.CallContext) *Event {
+	e := &Event{
+		Time:      cc.Start,
+		Duration:  cc.End.Sub(cc.Start),
+		Caller:    CallerFromContext(ctx),
+		Project:   cc.ProjectID,
+		Version:   cc.Version,
+		Service:   cc.Service,
+		Method:    cc.Operation,
+		Request:   cloud.CopyRequest(cc.Request),
+		Operation: cc.OperationName,
+		Outcome:   OutcomeSuccess,
+	}
+	// The key and request are copied, as the caller may change them after
+	// the call.
+	if cc.Key != nil {
+		key := *cc.Key
+		e.Key = &key
+	}
+	switch {
+	case cc.Err != nil:
+		e.Outcome = OutcomeFailure
+		e.Error = cc.Err.Error()
+	case cc.DryRun:
+		e.Outcome = OutcomeDryRun
+	}
+	return e
+}
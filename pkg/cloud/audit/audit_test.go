@@ -0,0 +1,234 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	alpha "google.golang.org/api/compute/v0.alpha"
+	ga "google.golang.org/api/compute/v1"
+
+	"github.com/bowei/gce-gen/pkg/cloud"
+	"github.com/bowei/gce-gen/pkg/cloud/meta"
+)
+
+type noWaitRateLimiter struct{}
+
+func (*noWaitRateLimiter) Accept(ctx context.Context, key *cloud.RateLimitKey) error {
+	return nil
+}
+
+// fakeCompute is a stand-in for the compute API that handles firewall
+// insert, returning an operation that is already done.
+func fakeCompute(w http.ResponseWriter, r *http.Request) {
+	const opLink = "https://www.googleapis.com/compute/v1/projects/proj/global/operations/op-1"
+	switch {
+	case r.Method == "POST" && r.URL.Path == "/projects/proj/global/firewalls":
+		json.NewEncoder(w).Encode(&ga.Operation{Name: "op-1", SelfLink: opLink, Status: "DONE"})
+	case r.Method == "GET" && r.URL.Path == "/projects/proj/global/operations/op-1":
+		json.NewEncoder(w).Encode(&ga.Operation{Name: "op-1", SelfLink: opLink, Status: "DONE"})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error": {"code": 404, "message": "not found"}}`))
+	}
+}
+
+func newGCE(t *testing.T, o cloud.CallObserver, plan *cloud.DryRunPlan) (*cloud.GCE, func()) {
+	srv := httptest.NewServer(http.HandlerFunc(fakeCompute))
+	svc, err := ga.New(srv.Client())
+	if err != nil {
+		t.Fatalf("ga.New() = _, %v", err)
+	}
+	svc.BasePath = srv.URL + "/projects/"
+	gce := cloud.NewGCE(&cloud.Service{
+		GA:            svc,
+		ProjectRouter: &cloud.SingleProjectRouter{ID: "proj"},
+		RateLimiter:   &noWaitRateLimiter{},
+		Observer:      o,
+		DryRun:        plan,
+	})
+	return gce, srv.Close
+}
+
+func TestObserverGCE(t *testing.T) {
+	t.Parallel()
+
+	sink := NewMemorySink()
+	gce, cleanup := newGCE(t, NewObserver(sink), nil)
+	defer cleanup()
+
+	ctx := WithCaller(context.Background(), "controller")
+	key := meta.GlobalKey("fw-1")
+	fw := &ga.Firewall{Name: "fw-1"}
+	if err := gce.Firewalls().Insert(ctx, *key, fw); err != nil {
+		t.Fatalf("Firewalls().Insert() = %v, want nil", err)
+	}
+	gce.Firewalls().Get(ctx, *key)
+	if err := gce.Firewalls().Delete(ctx, *key); err == nil {
+		t.Fatalf("Firewalls().Delete() = nil, want error")
+	}
+
+	// Changes to the key and request after the call do not change the
+	// events.
+	fw.Description = "changed"
+	key.Name = "changed"
+
+	events := sink.Events()
+	if len(events) != 2 {
+		t.Fatalf("len(events) = %d, want 2 (%+v)", len(events), events)
+	}
+	insert, del := events[0], events[1]
+	if insert.Caller != "controller" || insert.Project != "proj" || insert.Version != meta.VersionGA ||
+		insert.Service != "Firewalls" || insert.Method != "Insert" || *insert.Key != *meta.GlobalKey("fw-1") ||
+		!reflect.DeepEqual(insert.Request, &ga.Firewall{Name: "fw-1"}) || insert.Operation != "op-1" || insert.Outcome != OutcomeSuccess || insert.Error != "" {
+		t.Errorf("insert event = %+v", insert)
+	}
+	if del.Method != "Delete" || del.Request != nil || del.Outcome != OutcomeFailure || del.Error == "" {
+		t.Errorf("delete event = %+v", del)
+	}
+}
+
+func TestObserverDryRun(t *testing.T) {
+	t.Parallel()
+
+	sink := NewMemorySink()
+	gce, cleanup := newGCE(t, NewObserver(sink), &cloud.DryRunPlan{})
+	defer cleanup()
+
+	if err := gce.Firewalls().Delete(context.Background(), *meta.GlobalKey("fw-1")); err != nil {
+		t.Fatalf("Firewalls().Delete() = %v, want nil", err)
+	}
+	events := sink.Events()
+	if len(events) != 1 || events[0].Outcome != OutcomeDryRun {
+		t.Errorf("events = %+v, want a single dry-run event", events)
+	}
+}
+
+func TestObserverMock(t *testing.T) {
+	t.Parallel()
+
+	sink := NewMemorySink()
+	mock := cloud.NewMockGCE()
+	mock.SetObserver(NewObserver(sink))
+
+	ctx := context.Background()
+	key := meta.GlobalKey("tp")
+	urlMap := &ga.UrlMapReference{UrlMap: "um"}
+	if err := mock.TargetHttpProxies().Insert(ctx, *key, &ga.TargetHttpProxy{}); err != nil {
+		t.Fatalf("TargetHttpProxies().Insert() = %v, want nil", err)
+	}
+	if _, err := mock.TargetHttpProxies().List(ctx, nil); err != nil {
+		t.Fatalf("TargetHttpProxies().List() = _, %v, want nil", err)
+	}
+	if err := mock.TargetHttpProxies().SetUrlMap(ctx, *key, urlMap); err != nil {
+		t.Fatalf("TargetHttpProxies().SetUrlMap() = %v, want nil", err)
+	}
+	nic := &alpha.NetworkInterface{Network: "global/networks/default"}
+	if err := mock.AlphaInstances().UpdateNetworkInterface(ctx, *meta.ZonalKey("vm", "us-central1-b"), "nic0", nic); err != nil {
+		t.Fatalf("AlphaInstances().UpdateNetworkInterface() = %v, want nil", err)
+	}
+	nic.Network = "changed"
+
+	events := sink.Events()
+	if len(events) != 3 {
+		t.Fatalf("len(events) = %d, want 3 (%+v)", len(events), events)
+	}
+	if events[0].Method != "Insert" || events[1].Method != "SetUrlMap" || !reflect.DeepEqual(events[1].Request, urlMap) {
+		t.Errorf("events = %+v, want Insert, SetUrlMap", events)
+	}
+	// All of the arguments of methods with more than one are recorded.
+	wantReq := []interface{}{"nic0", &alpha.NetworkInterface{Network: "global/networks/default"}}
+	if events[2].Method != "UpdateNetworkInterface" || !reflect.DeepEqual(events[2].Request, wantReq) {
+		t.Errorf("events[2] = %+v, want UpdateNetworkInterface(%v)", events[2], wantReq)
+	}
+	if events[0].Caller != "" {
+		t.Errorf("events[0].Caller = %q, want \"\"", events[0].Caller)
+	}
+
+	sink.Reset()
+	if len(sink.Events()) != 0 {
+		t.Errorf("Events() after Reset() = %v, want empty", sink.Events())
+	}
+}
+
+func TestJSONLinesFile(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "audit")
+	if err != nil {
+		t.Fatalf("ioutil.TempDir() = _, %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "audit.jsonl")
+
+	for i := 0; i < 2; i++ {
+		// Reopening the file appends to it.
+		sink, err := OpenJSONLinesFile(path)
+		if err != nil {
+			t.Fatalf("OpenJSONLinesFile(%q) = _, %v", path, err)
+		}
+		e := &Event{
+			Caller:  "controller",
+			Project: "proj",
+			Service: "Firewalls",
+			Method:  "Insert",
+			Key:     meta.GlobalKey("fw"),
+			Request: &ga.Firewall{Name: "fw"},
+			Outcome: OutcomeSuccess,
+		}
+		if err := sink.Write(e); err != nil {
+			t.Fatalf("sink.Write() = %v, want nil", err)
+		}
+		if err := sink.Close(); err != nil {
+			t.Fatalf("sink.Close() = %v, want nil", err)
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("os.Open(%q) = _, %v", path, err)
+	}
+	defer f.Close()
+	var lines int
+	for scanner := bufio.NewScanner(f); scanner.Scan(); lines++ {
+		var got struct {
+			Caller  string
+			Method  string
+			Key     meta.Key
+			Request ga.Firewall
+			Outcome Outcome
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &got); err != nil {
+			t.Fatalf("json.Unmarshal(%q) = %v", scanner.Text(), err)
+		}
+		if got.Caller != "controller" || got.Method != "Insert" || got.Key.Name != "fw" || got.Request.Name != "fw" || got.Outcome != OutcomeSuccess {
+			t.Errorf("line %d = %q", lines, scanner.Text())
+		}
+	}
+	if lines != 2 {
+		t.Errorf("lines = %d, want 2", lines)
+	}
+}
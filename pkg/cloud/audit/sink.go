@@ -0,0 +1,97 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+)
+
+// JSONLinesSink writes each event as a single line of JSON.
+type JSONLinesSink struct {
+	lock sync.Mutex
+	w    io.Writer
+	enc  *json.Encoder
+}
+
+// NewJSONLinesSink returns a sink that writes to w.
+func NewJSONLinesSink(w io.Writer) *JSONLinesSink {
+	return &JSONLinesSink{w: w, enc: json.NewEncoder(w)}
+}
+
+// OpenJSONLinesFile returns a sink that appends to the file at path, creating
+// it if it does not exist. The sink should be closed with Close().
+func OpenJSONLinesFile(path string) (*JSONLinesSink, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return NewJSONLinesSink(f), nil
+}
+
+// Write implements Sink.
+func (s *JSONLinesSink) Write(e *Event) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	// Encode() terminates each value with a newline.
+	return s.enc.Encode(e)
+}
+
+// Close the underlying writer if it is an io.Closer.
+func (s *JSONLinesSink) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if c, ok := s.w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// MemorySink keeps the events in memory. This is intended for use in tests.
+type MemorySink struct {
+	lock   sync.Mutex
+	events []*Event
+}
+
+// NewMemorySink returns a new, empty sink.
+func NewMemorySink() *MemorySink {
+	return &MemorySink{}
+}
+
+// Write implements Sink.
+func (s *MemorySink) Write(e *Event) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.events = append(s.events, e)
+	return nil
+}
+
+// Events returns the events written so far, in order.
+func (s *MemorySink) Events() []*Event {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]*Event(nil), s.events...)
+}
+
+// Reset clears the events.
+func (s *MemorySink) Reset() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.events = nil
+}
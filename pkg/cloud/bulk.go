@@ -0,0 +1,116 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/golang/glog"
+
+	"github.com/bowei/gce-gen/pkg/cloud/meta"
+)
+
+// BulkOptions configure Bulk().
+type BulkOptions struct {
+	// Parallelism is the maximum number of concurrent calls. It is 1 if not
+	// set.
+	Parallelism int
+	// StopOnError stops starting calls once a call fails. The calls in
+	// progress are not interrupted.
+	StopOnError bool
+}
+
+// BulkError is returned by Bulk() if some of the calls failed or were not
+// made.
+type BulkError struct {
+	// Errors of the calls that failed, by key.
+	Errors map[meta.Key]error
+	// Skipped keys, for which no call was made because of StopOnError or
+	// because the context was done.
+	Skipped []meta.Key
+}
+
+func (e *BulkError) Error() string {
+	var msgs []string
+	for k, err := range e.Errors {
+		msgs = append(msgs, fmt.Sprintf("%v: %v", k, err))
+	}
+	sort.Strings(msgs)
+	return fmt.Sprintf("%d calls failed, %d skipped: %s", len(e.Errors), len(e.Skipped), strings.Join(msgs, "; "))
+}
+
+// Bulk calls f for each of the keys, with at most opts.Parallelism calls at
+// a time. f is usually a generated method, or a closure calling one:
+//
+//  err := cloud.Bulk(ctx, keys, cloud.BulkOptions{Parallelism: 10}, gce.Instances().Delete)
+//  err := cloud.Bulk(ctx, keys, opts, func(ctx context.Context, key meta.Key) error {
+//    return gce.Firewalls().Insert(ctx, key, firewalls[key])
+//  })
+//
+// The calls are rate limited by the RateLimiter of the Service as usual, so
+// Parallelism only bounds the number of calls waiting for their operation to
+// complete. No calls are started once ctx is done. Bulk returns a *BulkError
+// if any call failed or was skipped.
+func Bulk(ctx context.Context, keys []meta.Key, opts BulkOptions, f func(ctx context.Context, key meta.Key) error) error {
+	parallelism := opts.Parallelism
+	if parallelism < 1 {
+		parallelism = 1
+	}
+
+	var (
+		wg     sync.WaitGroup
+		lock   sync.Mutex
+		failed bool
+		sem    = make(chan struct{}, parallelism)
+		bErr   = &BulkError{Errors: map[meta.Key]error{}}
+	)
+	for i, key := range keys {
+		sem <- struct{}{}
+		lock.Lock()
+		stop := ctx.Err() != nil || opts.StopOnError && failed
+		lock.Unlock()
+		if stop {
+			<-sem
+			bErr.Skipped = append(bErr.Skipped, keys[i:]...)
+			break
+		}
+
+		wg.Add(1)
+		go func(key meta.Key) {
+			defer func() { <-sem; wg.Done() }()
+			err := f(ctx, key)
+			if err == nil {
+				return
+			}
+			glog.V(4).Infof("Bulk: call for %v failed: %v", key, err)
+			lock.Lock()
+			defer lock.Unlock()
+			failed = true
+			bErr.Errors[key] = err
+		}(key)
+	}
+	wg.Wait()
+
+	if len(bErr.Errors) > 0 || len(bErr.Skipped) > 0 {
+		return bErr
+	}
+	return nil
+}
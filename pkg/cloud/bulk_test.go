@@ -0,0 +1,129 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	ga "google.golang.org/api/compute/v1"
+
+	"github.com/bowei/gce-gen/pkg/cloud/meta"
+)
+
+// newFirewalls returns a mock with n firewalls and their keys.
+func newFirewalls(t *testing.T, n int) (*MockGCE, []meta.Key) {
+	ctx := context.Background()
+	mock := NewMockGCE()
+	var keys []meta.Key
+	for i := 0; i < n; i++ {
+		key := *meta.GlobalKey(fmt.Sprintf("fw-%d", i))
+		if err := mock.Firewalls().Insert(ctx, key, &ga.Firewall{}); err != nil {
+			t.Fatalf("Firewalls().Insert(%v) = %v", key, err)
+		}
+		keys = append(keys, key)
+	}
+	return mock, keys
+}
+
+func TestBulk(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock, keys := newFirewalls(t, 50)
+	errFailed := errors.New("injected error")
+	mock.MockFirewalls.DeleteError[keys[10]] = errFailed
+	mock.MockFirewalls.DeleteError[keys[20]] = errFailed
+
+	// Track the number of concurrent calls.
+	var lock sync.Mutex
+	var running, maxRunning int
+	mock.MockFirewalls.DeleteHook = func(m *MockFirewalls, ctx context.Context, key meta.Key) (bool, error) {
+		lock.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		lock.Unlock()
+		time.Sleep(time.Millisecond)
+		lock.Lock()
+		running--
+		lock.Unlock()
+		return false, nil
+	}
+
+	err := Bulk(ctx, keys, BulkOptions{Parallelism: 5}, mock.Firewalls().Delete)
+	bErr, ok := err.(*BulkError)
+	if !ok || len(bErr.Errors) != 2 || bErr.Errors[keys[10]] != errFailed || bErr.Errors[keys[20]] != errFailed || len(bErr.Skipped) != 0 {
+		t.Fatalf("Bulk() = %v; want errors for %v and %v", err, keys[10], keys[20])
+	}
+	if maxRunning > 5 || maxRunning < 2 {
+		t.Errorf("%d concurrent calls; want between 2 and 5", maxRunning)
+	}
+
+	fws, err := mock.Firewalls().List(ctx, nil)
+	if err != nil || len(fws) != 2 {
+		t.Errorf("Firewalls().List() = %d firewalls, %v; want the 2 that failed", len(fws), err)
+	}
+
+	// Nothing fails.
+	if err := Bulk(ctx, []meta.Key{keys[10]}, BulkOptions{}, func(context.Context, meta.Key) error { return nil }); err != nil {
+		t.Errorf("Bulk() = %v; want nil", err)
+	}
+}
+
+func TestBulkStopOnError(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock, keys := newFirewalls(t, 10)
+	mock.MockFirewalls.DeleteError[keys[3]] = errors.New("injected error")
+
+	err := Bulk(ctx, keys, BulkOptions{StopOnError: true}, mock.Firewalls().Delete)
+	bErr, ok := err.(*BulkError)
+	if !ok || len(bErr.Errors) != 1 || len(bErr.Skipped) != 6 || bErr.Skipped[0] != keys[4] {
+		t.Fatalf("Bulk() = %v; want an error for %v and %v... skipped", err, keys[3], keys[4])
+	}
+	for _, key := range keys[3:] {
+		if _, err := mock.Firewalls().Get(ctx, key); err != nil {
+			t.Errorf("Firewalls().Get(%v) = _, %v; want the firewall to exist", key, err)
+		}
+	}
+}
+
+func TestBulkContextDone(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	mock, keys := newFirewalls(t, 10)
+	mock.MockFirewalls.DeleteHook = func(m *MockFirewalls, ctx context.Context, key meta.Key) (bool, error) {
+		if key == keys[1] {
+			cancel()
+		}
+		return false, nil
+	}
+
+	err := Bulk(ctx, keys, BulkOptions{}, mock.Firewalls().Delete)
+	bErr, ok := err.(*BulkError)
+	if !ok || len(bErr.Errors) != 0 || len(bErr.Skipped) != 8 {
+		t.Errorf("Bulk() = %v; want 8 skipped keys", err)
+	}
+}
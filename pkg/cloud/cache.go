@@ -0,0 +1,213 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/bowei/gce-gen/pkg/cloud/filter"
+	"github.com/bowei/gce-gen/pkg/cloud/meta"
+)
+
+// CacheOptions configure NewCachedGCE().
+type CacheOptions struct {
+	// TTL of the cached results by service (e.g. "Zones"). The versions of
+	// a service share the same cache.
+	TTL map[string]time.Duration
+	// DefaultTTL is the TTL of the services that are not in TTL. Results
+	// are not cached if the TTL is 0.
+	DefaultTTL time.Duration
+}
+
+// CacheStats are the counters of the cache of a service.
+type CacheStats struct {
+	// Hits are the calls answered from the cache, including the calls
+	// that waited for an identical call in progress.
+	Hits int64
+	// Misses are the calls made to the underlying Cloud.
+	Misses int64
+}
+
+// cache is shared by all of the services of a CachedGCE.
+type cache struct {
+	opts CacheOptions
+	// now is replaced in tests.
+	now func() time.Time
+
+	lock     sync.Mutex
+	services map[string]*serviceCache
+}
+
+func newCache(opts CacheOptions) *cache {
+	return &cache{opts: opts, now: time.Now, services: map[string]*serviceCache{}}
+}
+
+// service returns the cache of service, which is shared by its versions.
+func (c *cache) service(service string) *serviceCache {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if s, ok := c.services[service]; ok {
+		return s
+	}
+	ttl, ok := c.opts.TTL[service]
+	if !ok {
+		ttl = c.opts.DefaultTTL
+	}
+	s := &serviceCache{c: c, ttl: ttl, entries: map[cacheKey]*cacheEntry{}}
+	c.services[service] = s
+	return s
+}
+
+func (c *cache) stats() map[string]CacheStats {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	ret := map[string]CacheStats{}
+	for name, s := range c.services {
+		s.lock.Lock()
+		ret[name] = s.stats
+		s.lock.Unlock()
+	}
+	return ret
+}
+
+func (c *cache) invalidateAll() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for _, s := range c.services {
+		s.invalidate(nil)
+	}
+}
+
+// cacheKey identifies a call.
+type cacheKey struct {
+	version   meta.Version
+	operation string
+	// project set in the context with WithProjectID().
+	project  string
+	key      meta.Key
+	location string
+	filter   string
+}
+
+func newCacheKey(ctx context.Context, version meta.Version, operation string, key *meta.Key, location string, fl *filter.F) cacheKey {
+	ret := cacheKey{version: version, operation: operation, location: location}
+	ret.project, _ = ProjectIDFromContext(ctx)
+	if key != nil {
+		ret.key = *key
+	}
+	if fl != nil {
+		ret.filter = fl.String()
+	}
+	return ret
+}
+
+// cacheEntry is the result of a call, which is in progress until done is
+// closed.
+type cacheEntry struct {
+	done    chan struct{}
+	value   interface{}
+	err     error
+	fetched time.Time
+}
+
+// serviceCache caches the results of the calls to a service.
+type serviceCache struct {
+	c   *cache
+	ttl time.Duration
+
+	lock    sync.Mutex
+	entries map[cacheKey]*cacheEntry
+	stats   CacheStats
+}
+
+// do returns the cached result for k, or calls f and caches its result if
+// there is none. Concurrent calls for the same k wait for the first one.
+// Errors are not cached.
+func (s *serviceCache) do(ctx context.Context, k cacheKey, f func() (interface{}, error)) (interface{}, error) {
+	if s.ttl == 0 {
+		return f()
+	}
+
+	s.lock.Lock()
+	if e, ok := s.entries[k]; ok {
+		select {
+		case <-e.done:
+			if s.c.now().Sub(e.fetched) < s.ttl {
+				s.stats.Hits++
+				s.lock.Unlock()
+				return e.value, e.err
+			}
+		default:
+			s.stats.Hits++
+			s.lock.Unlock()
+			select {
+			case <-e.done:
+				return e.value, e.err
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+	}
+	e := &cacheEntry{done: make(chan struct{})}
+	s.entries[k] = e
+	s.stats.Misses++
+	s.lock.Unlock()
+
+	value, err := f()
+
+	// A failed entry is removed before done is closed, so that no call
+	// finds it done. The entry is not in entries anymore if invalidate()
+	// was called during the call, in which case the result is not cached.
+	s.lock.Lock()
+	e.value, e.err = value, err
+	e.fetched = s.c.now()
+	if err != nil && s.entries[k] == e {
+		delete(s.entries, k)
+	}
+	close(e.done)
+	s.lock.Unlock()
+	return value, err
+}
+
+// invalidate the results of the Get() calls for key and of all of the List()
+// and AggregatedList() calls, as the object for key may have changed. All of
+// the results are invalidated if key is nil.
+func (s *serviceCache) invalidate(key *meta.Key) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for k := range s.entries {
+		if key == nil || k.operation != "Get" || k.key == *key {
+			delete(s.entries, k)
+		}
+	}
+}
+
+// Stats returns the cache counters by service (e.g. "Zones").
+func (c *CachedGCE) Stats() map[string]CacheStats {
+	return c.cache.stats()
+}
+
+// Invalidate all of the cached results.
+func (c *CachedGCE) Invalidate() {
+	c.cache.invalidateAll()
+}
@@ -0,0 +1,234 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	alpha "google.golang.org/api/compute/v0.alpha"
+	ga "google.golang.org/api/compute/v1"
+
+	"github.com/bowei/gce-gen/pkg/cloud/filter"
+	"github.com/bowei/gce-gen/pkg/cloud/meta"
+)
+
+// callCounter counts the calls by operation.
+type callCounter struct {
+	lock  sync.Mutex
+	calls map[string]int
+}
+
+func (c *callCounter) Before(ctx context.Context, cc *CallContext) context.Context {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.calls[cc.Operation]++
+	return ctx
+}
+
+func (c *callCounter) After(ctx context.Context, cc *CallContext) {}
+
+func (c *callCounter) get(op string) int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.calls[op]
+}
+
+func TestCachedGCE(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := NewMockGCE()
+	counter := &callCounter{calls: map[string]int{}}
+	mock.SetObserver(counter)
+	now := time.Now()
+	c := NewCachedGCE(mock, CacheOptions{
+		TTL:        map[string]time.Duration{"Firewalls": 0},
+		DefaultTTL: time.Minute,
+	})
+	c.cache.now = func() time.Time { return now }
+
+	key := *meta.GlobalKey("bs")
+	if err := c.BackendServices().Insert(ctx, key, &ga.BackendService{Port: 80}); err != nil {
+		t.Fatalf("Insert() = %v", err)
+	}
+
+	for _, tc := range []struct {
+		desc string
+		// do is called before Get().
+		do        func()
+		wantGets  int
+		wantLists int
+	}{
+		{desc: "first", wantGets: 1, wantLists: 1},
+		{desc: "cached", wantGets: 1, wantLists: 1},
+		{
+			desc: "returned object changed",
+			do: func() {
+				bs, _ := c.BackendServices().Get(ctx, key)
+				bs.Port = 8080
+			},
+			wantGets:  1,
+			wantLists: 1,
+		},
+		{
+			desc: "update of another version",
+			do: func() {
+				c.AlphaBackendServices().Update(ctx, key, &alpha.BackendService{Name: "bs", Port: 80})
+			},
+			wantGets:  2,
+			wantLists: 2,
+		},
+		{
+			desc: "insert of another key",
+			do: func() {
+				c.BackendServices().Insert(ctx, *meta.GlobalKey("other"), &ga.BackendService{})
+			},
+			wantGets:  2,
+			wantLists: 3,
+		},
+		{
+			desc:      "TTL expired",
+			do:        func() { now = now.Add(time.Minute) },
+			wantGets:  3,
+			wantLists: 4,
+		},
+		{
+			desc:      "invalidate",
+			do:        func() { c.Invalidate() },
+			wantGets:  4,
+			wantLists: 5,
+		},
+	} {
+		if tc.do != nil {
+			tc.do()
+		}
+		bs, err := c.BackendServices().Get(ctx, key)
+		if err != nil || bs.Port != 80 {
+			t.Errorf("%s: Get() = %+v, %v; want port 80", tc.desc, bs, err)
+		}
+		if _, err := c.BackendServices().List(ctx, filter.None); err != nil {
+			t.Errorf("%s: List() = _, %v", tc.desc, err)
+		}
+		if got := counter.get("Get"); got != tc.wantGets {
+			t.Errorf("%s: %d calls to Get(); want %d", tc.desc, got, tc.wantGets)
+		}
+		if got := counter.get("List"); got != tc.wantLists {
+			t.Errorf("%s: %d calls to List(); want %d", tc.desc, got, tc.wantLists)
+		}
+	}
+
+	// Firewalls are not cached and errors are not cached.
+	for i := 0; i < 2; i++ {
+		c.Firewalls().Get(ctx, key)
+		c.BackendServices().Get(ctx, *meta.GlobalKey("missing"))
+	}
+	stats := c.Stats()
+	if want := (CacheStats{Hits: 6, Misses: 11}); stats["BackendServices"] != want {
+		t.Errorf("Stats()[BackendServices] = %+v; want %+v", stats["BackendServices"], want)
+	}
+	if want := (CacheStats{}); stats["Firewalls"] != want {
+		t.Errorf("Stats()[Firewalls] = %+v; want %+v", stats["Firewalls"], want)
+	}
+}
+
+func TestCachedGCECoalescing(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := NewMockGCE()
+	release := make(chan struct{})
+	calls := 0
+	mock.MockZones.ListHook = func(m *MockZones, ctx context.Context, fl *filter.F) (bool, []*ga.Zone, error) {
+		calls++
+		<-release
+		return true, []*ga.Zone{{Name: "us-central1-b"}}, nil
+	}
+	c := NewCachedGCE(mock, CacheOptions{TTL: map[string]time.Duration{"Zones": time.Hour}})
+
+	const n = 5
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if zones, err := c.Zones().List(ctx, filter.None); err != nil || len(zones) != 1 {
+				t.Errorf("Zones().List() = %v, %v; want 1 zone", zones, err)
+			}
+		}()
+	}
+	// Wait for all of the calls to be waiting for the first one.
+	for c.Stats()["Zones"].Hits+c.Stats()["Zones"].Misses < n {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+
+	if calls != 1 {
+		t.Errorf("%d calls to List(); want 1", calls)
+	}
+	if want := (CacheStats{Hits: n - 1, Misses: 1}); c.Stats()["Zones"] != want {
+		t.Errorf("Stats()[Zones] = %+v; want %+v", c.Stats()["Zones"], want)
+	}
+}
+
+func TestCachedGCECoalescingError(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := NewMockGCE()
+	release := make(chan struct{})
+	calls := 0
+	mock.MockZones.ListHook = func(m *MockZones, ctx context.Context, fl *filter.F) (bool, []*ga.Zone, error) {
+		calls++
+		if calls > 1 {
+			return true, []*ga.Zone{{Name: "us-central1-b"}}, nil
+		}
+		<-release
+		return true, nil, errors.New("injected error")
+	}
+	c := NewCachedGCE(mock, CacheOptions{TTL: map[string]time.Duration{"Zones": time.Hour}})
+
+	// All of the calls waiting for a failed call get its error.
+	const n = 5
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if zones, err := c.Zones().List(ctx, filter.None); err == nil {
+				t.Errorf("Zones().List() = %v, nil; want an error", zones)
+			}
+		}()
+	}
+	for c.Stats()["Zones"].Hits+c.Stats()["Zones"].Misses < n {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+
+	// The error is not cached.
+	if zones, err := c.Zones().List(ctx, filter.None); err != nil || len(zones) != 1 {
+		t.Errorf("Zones().List() after an error = %v, %v; want 1 zone", zones, err)
+	}
+	if calls != 2 {
+		t.Errorf("%d calls to List(); want 2", calls)
+	}
+}
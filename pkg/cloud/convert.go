@@ -0,0 +1,192 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/golang/glog"
+)
+
+// convertVersion copies src to dest, objects of different versions of the
+// API, via JSON. It returns the JSON paths of the fields of src that dest does
+// not have, such as "securityPolicy" or "backends[1].failover". Only the
+// non-empty fields are in the JSON of the objects, so empty fields are never
+// reported.
+func convertVersion(dest, src interface{}) ([]string, error) {
+	b, err := json.Marshal(src)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, dest); err != nil {
+		return nil, fmt.Errorf("cannot convert %T to %T: %v", src, dest, err)
+	}
+	destBytes, err := json.Marshal(dest)
+	if err != nil {
+		return nil, err
+	}
+
+	var srcFields, destFields interface{}
+	if err := json.Unmarshal(b, &srcFields); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(destBytes, &destFields); err != nil {
+		return nil, err
+	}
+	return lostFields("", srcFields, destFields), nil
+}
+
+// lostFields returns the paths of the fields in src that are not in dest,
+// both decoded from JSON.
+func lostFields(path string, src, dest interface{}) []string {
+	var ret []string
+	switch src := src.(type) {
+	case map[string]interface{}:
+		destMap, _ := dest.(map[string]interface{})
+		var keys []string
+		for k := range src {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			p := k
+			if path != "" {
+				p = path + "." + k
+			}
+			d, ok := destMap[k]
+			if !ok {
+				ret = append(ret, p)
+				continue
+			}
+			ret = append(ret, lostFields(p, src[k], d)...)
+		}
+	case []interface{}:
+		destList, _ := dest.([]interface{})
+		for i, e := range src {
+			if i < len(destList) {
+				ret = append(ret, lostFields(fmt.Sprintf("%s[%d]", path, i), e, destList[i])...)
+			}
+		}
+	}
+	return ret
+}
+
+// mergeVersion returns the object that replaces old, of any version of the
+// API, when it is updated with obj. The fields of old that the version of obj
+// does not have, including those of nested objects, are kept; all of the
+// other fields are those of obj. The result has the type of old, or of obj if
+// that loses fewer fields.
+func mergeVersion(old, obj interface{}) interface{} {
+	if old == nil || reflect.TypeOf(old) == reflect.TypeOf(obj) {
+		return obj
+	}
+	var oldFields, objFields interface{}
+	if err := copyViaJSON(&oldFields, old); err != nil {
+		glog.Errorf("Could not merge %T into %T: %v", obj, old, err)
+		return obj
+	}
+	if err := copyViaJSON(&objFields, obj); err != nil {
+		glog.Errorf("Could not merge %T into %T: %v", obj, old, err)
+		return obj
+	}
+	merged := mergeFields(oldFields, objFields, reflect.TypeOf(obj))
+
+	var ret interface{}
+	var retLost []string
+	for _, t := range []reflect.Type{reflect.TypeOf(old), reflect.TypeOf(obj)} {
+		v := reflect.New(t.Elem()).Interface()
+		if err := copyViaJSON(v, merged); err != nil {
+			continue
+		}
+		var fields interface{}
+		if err := copyViaJSON(&fields, v); err != nil {
+			continue
+		}
+		lost := lostFields("", merged, fields)
+		if ret == nil || len(lost) < len(retLost) {
+			ret, retLost = v, lost
+		}
+	}
+	if ret == nil {
+		glog.Errorf("Could not merge %T into %T", obj, old)
+		return obj
+	}
+	if len(retLost) > 0 {
+		glog.Warningf("Fields %v lost when merging %T into %T", retLost, obj, old)
+	}
+	return ret
+}
+
+// mergeFields merges the JSON fields of obj, an object of type t, into old.
+// The fields of old that t does not have are kept.
+func mergeFields(old, obj interface{}, t reflect.Type) interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch obj := obj.(type) {
+	case map[string]interface{}:
+		oldMap, ok := old.(map[string]interface{})
+		if !ok || t.Kind() != reflect.Struct {
+			return obj
+		}
+		ret := map[string]interface{}{}
+		for k, v := range obj {
+			ret[k] = v
+		}
+		for k, v := range oldMap {
+			ft, ok := jsonFieldType(t, k)
+			switch {
+			case !ok:
+				ret[k] = v
+			case ret[k] != nil:
+				ret[k] = mergeFields(v, ret[k], ft)
+			}
+		}
+		return ret
+	case []interface{}:
+		oldList, ok := old.([]interface{})
+		if !ok || t.Kind() != reflect.Slice {
+			return obj
+		}
+		ret := make([]interface{}, len(obj))
+		for i, e := range obj {
+			ret[i] = e
+			if i < len(oldList) {
+				ret[i] = mergeFields(oldList[i], e, t.Elem())
+			}
+		}
+		return ret
+	}
+	return obj
+}
+
+// jsonFieldType returns the type of the field of the struct t with the JSON
+// name name.
+func jsonFieldType(t reflect.Type, name string) (reflect.Type, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := strings.Split(f.Tag.Get("json"), ",")[0]
+		if tag == name || tag == "" && f.Name == name {
+			return f.Type, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,198 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"reflect"
+	"testing"
+
+	alpha "google.golang.org/api/compute/v0.alpha"
+	ga "google.golang.org/api/compute/v1"
+)
+
+func TestConvertBackendService(t *testing.T) {
+	t.Parallel()
+
+	obj := &alpha.BackendService{
+		Name:           "bs",
+		Port:           80,
+		SecurityPolicy: "global/securityPolicies/sp",
+		Backends:       []*alpha.Backend{{Group: "zones/us-central1-b/instanceGroups/ig"}},
+	}
+	gaObj, lost, err := ConvertBackendServiceAlphaToGA(obj)
+	if err != nil {
+		t.Fatalf("ConvertBackendServiceAlphaToGA() = _, _, %v", err)
+	}
+	if want := []string{"securityPolicy"}; !reflect.DeepEqual(lost, want) {
+		t.Errorf("ConvertBackendServiceAlphaToGA() lost %v; want %v", lost, want)
+	}
+	want := &ga.BackendService{
+		Name:     "bs",
+		Port:     80,
+		Backends: []*ga.Backend{{Group: "zones/us-central1-b/instanceGroups/ig"}},
+	}
+	if !reflect.DeepEqual(gaObj, want) {
+		t.Errorf("ConvertBackendServiceAlphaToGA() = %+v; want %+v", gaObj, want)
+	}
+
+	alphaObj, lost, err := ConvertBackendServiceGAToAlpha(gaObj)
+	if err != nil || len(lost) != 0 {
+		t.Errorf("ConvertBackendServiceGAToAlpha() = _, %v, %v; want nothing lost", lost, err)
+	}
+	if alphaObj.Name != "bs" || alphaObj.Port != 80 || len(alphaObj.Backends) != 1 {
+		t.Errorf("ConvertBackendServiceGAToAlpha() = %+v", alphaObj)
+	}
+}
+
+func TestConvertVersion(t *testing.T) {
+	t.Parallel()
+
+	type inner struct {
+		A string `json:"a,omitempty"`
+		B string `json:"b,omitempty"`
+	}
+	type src struct {
+		Name   string            `json:"name,omitempty"`
+		Extra  string            `json:"extra,omitempty"`
+		Inner  *inner            `json:"inner,omitempty"`
+		List   []*inner          `json:"list,omitempty"`
+		Labels map[string]string `json:"labels,omitempty"`
+	}
+	type destInner struct {
+		A string `json:"a,omitempty"`
+	}
+	type dest struct {
+		Name   string            `json:"name,omitempty"`
+		Inner  *destInner        `json:"inner,omitempty"`
+		List   []*destInner      `json:"list,omitempty"`
+		Labels map[string]string `json:"labels,omitempty"`
+	}
+
+	for _, tc := range []struct {
+		desc string
+		src  *src
+		want []string
+	}{
+		{desc: "empty", src: &src{}},
+		{desc: "nothing lost", src: &src{Name: "x", Inner: &inner{A: "a"}, Labels: map[string]string{"k": "v"}}},
+		{
+			desc: "fields lost",
+			src: &src{
+				Name:  "x",
+				Extra: "e",
+				Inner: &inner{A: "a", B: "b"},
+				List:  []*inner{{A: "a"}, {B: "b"}},
+			},
+			want: []string{"extra", "inner.b", "list[1].b"},
+		},
+	} {
+		got, err := convertVersion(&dest{}, tc.src)
+		if err != nil {
+			t.Errorf("%s: convertVersion() = _, %v", tc.desc, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: convertVersion() = %v; want %v", tc.desc, got, tc.want)
+		}
+	}
+
+	// Fields with different types cannot be converted.
+	type badDest struct {
+		Name int `json:"name"`
+	}
+	if _, err := convertVersion(&badDest{}, &src{Name: "x"}); err == nil {
+		t.Errorf("convertVersion() = _, nil; want an error")
+	}
+}
+
+func TestMergeVersion(t *testing.T) {
+	t.Parallel()
+
+	type inner struct {
+		A string `json:"a,omitempty"`
+		B string `json:"b,omitempty"`
+	}
+	type full struct {
+		Name  string   `json:"name,omitempty"`
+		Extra string   `json:"extra,omitempty"`
+		Inner *inner   `json:"inner,omitempty"`
+		List  []*inner `json:"list,omitempty"`
+	}
+	type partialInner struct {
+		A string `json:"a,omitempty"`
+	}
+	type partial struct {
+		Name  string          `json:"name,omitempty"`
+		Inner *partialInner   `json:"inner,omitempty"`
+		List  []*partialInner `json:"list,omitempty"`
+	}
+
+	for _, tc := range []struct {
+		desc string
+		old  interface{}
+		obj  interface{}
+		want interface{}
+	}{
+		{
+			desc: "no old object",
+			obj:  &partial{Name: "x"},
+			want: &partial{Name: "x"},
+		},
+		{
+			desc: "same version",
+			old:  &full{Name: "x", Extra: "e"},
+			obj:  &full{Name: "y"},
+			want: &full{Name: "y"},
+		},
+		{
+			desc: "fields kept",
+			old: &full{
+				Name:  "x",
+				Extra: "e",
+				Inner: &inner{A: "a", B: "b"},
+				List:  []*inner{{A: "a0", B: "b0"}, {A: "a1", B: "b1"}},
+			},
+			obj: &partial{
+				Name:  "y",
+				Inner: &partialInner{A: "a2"},
+				List:  []*partialInner{{A: "a3"}},
+			},
+			want: &full{
+				Name:  "y",
+				Extra: "e",
+				Inner: &inner{A: "a2", B: "b"},
+				List:  []*inner{{A: "a3", B: "b0"}},
+			},
+		},
+		{
+			desc: "known fields cleared",
+			old:  &full{Name: "x", Extra: "e", Inner: &inner{A: "a", B: "b"}},
+			obj:  &partial{Name: "x"},
+			want: &full{Name: "x", Extra: "e"},
+		},
+		{
+			desc: "type of the update if it has more fields",
+			old:  &partial{Name: "x"},
+			obj:  &full{Name: "x", Extra: "e"},
+			want: &full{Name: "x", Extra: "e"},
+		},
+	} {
+		if got := mergeVersion(tc.old, tc.obj); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: mergeVersion() = %+v; want %+v", tc.desc, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,259 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package diff compares compute objects (e.g. a desired *ga.BackendService
+// and the one returned by Get()) the way the compute API treats them:
+//
+//  - output-only fields (Id, CreationTimestamp, SelfLink, Fingerprint, Kind,
+//    ...) are ignored.
+//  - fields that are not set are equal to the value the server defaults
+//    them to (e.g. BackendService.Protocol is "HTTP").
+//  - resource references are compared by the resource they refer to, so a
+//    full URL, a "projects/..." path and a "global/..." path are equal.
+//
+// Objects of different API versions of the same type can be compared.
+//
+//  if !diff.Equal(desired, current) {
+//    glog.Infof("BackendService changed:\n%s", diff.Explain(desired, current))
+//  }
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/bowei/gce-gen/pkg/cloud"
+)
+
+// Difference between two objects.
+type Difference struct {
+	// Path to the field, e.g. "backends[0].group".
+	Path string
+	// A and B are the values of the field in each object, as decoded from
+	// JSON. They are nil if the field is not set.
+	A, B interface{}
+}
+
+// String returns the difference as "path: a != b".
+func (d *Difference) String() string {
+	return fmt.Sprintf("%s: %s != %s", d.Path, formatValue(d.A), formatValue(d.B))
+}
+
+// Equal is true if a and b, pointers to compute objects of the same type,
+// are equal. It is false if a and b cannot be compared.
+func Equal(a, b interface{}) bool {
+	diffs, err := Differences(a, b)
+	return err == nil && len(diffs) == 0
+}
+
+// Explain returns the differences between a and b, one per line, sorted by
+// path. It is empty if the objects are equal.
+func Explain(a, b interface{}) string {
+	diffs, err := Differences(a, b)
+	if err != nil {
+		return err.Error()
+	}
+	var lines []string
+	for _, d := range diffs {
+		lines = append(lines, d.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Differences returns the differences between a and b, sorted by path.
+func Differences(a, b interface{}) ([]*Difference, error) {
+	object, err := objectName(a, b)
+	if err != nil {
+		return nil, err
+	}
+	am, err := toJSON(a)
+	if err != nil {
+		return nil, err
+	}
+	bm, err := toJSON(b)
+	if err != nil {
+		return nil, err
+	}
+	c := &comparer{fields: fields[object]}
+	if c.fields == nil {
+		c.fields = &objectFields{}
+	}
+	c.compare("", "", am, bm)
+	sort.Slice(c.diffs, func(i, j int) bool { return c.diffs[i].Path < c.diffs[j].Path })
+	return c.diffs, nil
+}
+
+// objectName returns the name of the type of a and b (e.g. "BackendService").
+func objectName(a, b interface{}) (string, error) {
+	name := func(obj interface{}) string {
+		t := reflect.TypeOf(obj)
+		for t != nil && t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t == nil || t.Kind() != reflect.Struct {
+			return ""
+		}
+		return t.Name()
+	}
+	an, bn := name(a), name(b)
+	if an == "" || bn == "" || an != bn {
+		return "", fmt.Errorf("diff: cannot compare %T with %T", a, b)
+	}
+	return an, nil
+}
+
+func toJSON(obj interface{}) (interface{}, error) {
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	var ret interface{}
+	if err := json.Unmarshal(b, &ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+type comparer struct {
+	fields *objectFields
+	diffs  []*Difference
+}
+
+// compare a and b at path. pattern is the path with "[]" for the indices of
+// list elements, used to look up the fields.
+func (c *comparer) compare(path, pattern string, a, b interface{}) {
+	if pattern != "" && c.outputOnly(pattern) {
+		return
+	}
+	if def, ok := c.fields.defaults[pattern]; ok {
+		if def == assigned {
+			if a == nil || b == nil {
+				return
+			}
+		} else {
+			if a == nil {
+				a = def
+			}
+			if b == nil {
+				b = def
+			}
+		}
+	}
+
+	am, aIsMap := a.(map[string]interface{})
+	bm, bIsMap := b.(map[string]interface{})
+	if aIsMap && (bIsMap || b == nil) || bIsMap && a == nil {
+		keys := map[string]bool{}
+		for k := range am {
+			keys[k] = true
+		}
+		for k := range bm {
+			keys[k] = true
+		}
+		for k := range keys {
+			c.compare(join(path, k), join(pattern, k), am[k], bm[k])
+		}
+		return
+	}
+
+	al, aIsList := a.([]interface{})
+	bl, bIsList := b.([]interface{})
+	if aIsList && (bIsList || b == nil) || bIsList && a == nil {
+		for i := 0; i < len(al) || i < len(bl); i++ {
+			var av, bv interface{}
+			if i < len(al) {
+				av = al[i]
+			}
+			if i < len(bl) {
+				bv = bl[i]
+			}
+			c.compare(fmt.Sprintf("%s[%d]", path, i), pattern+"[]", av, bv)
+		}
+		return
+	}
+
+	if !equalValues(a, b) {
+		c.diffs = append(c.diffs, &Difference{Path: path, A: a, B: b})
+	}
+}
+
+func (c *comparer) outputOnly(pattern string) bool {
+	for _, list := range [][]string{commonOutputOnly, c.fields.outputOnly} {
+		for _, f := range list {
+			if f == pattern {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func join(path, field string) string {
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
+
+// equalValues compares scalar values. Numbers are compared by value, as
+// defaults may be of a different type from the values decoded from JSON, and
+// references are compared by the resource they refer to.
+func equalValues(a, b interface{}) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	as, aIsString := a.(string)
+	bs, bIsString := b.(string)
+	if aIsString && bIsString {
+		if ar, ok := cloud.ParseReference(as); ok {
+			if br, ok := cloud.ParseReference(bs); ok {
+				return sameResource(ar, br)
+			}
+		}
+		return as == bs
+	}
+	if aIsString != bIsString {
+		return false
+	}
+	return fmt.Sprint(a) == fmt.Sprint(b)
+}
+
+// sameResource is true if a and b refer to the same resource. The projects
+// are only compared if both are known.
+func sameResource(a, b *cloud.ResourceID) bool {
+	if a.ProjectID != "" && b.ProjectID != "" && a.ProjectID != b.ProjectID {
+		return false
+	}
+	a2, b2 := *a, *b
+	a2.ProjectID, b2.ProjectID = "", ""
+	return a2.Equal(&b2)
+}
+
+func formatValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "<unset>"
+	case string:
+		return fmt.Sprintf("%q", v)
+	case map[string]interface{}, []interface{}:
+		b, _ := json.Marshal(v)
+		return string(b)
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diff
+
+import (
+	"testing"
+
+	alpha "google.golang.org/api/compute/v0.alpha"
+	ga "google.golang.org/api/compute/v1"
+
+	"github.com/bowei/gce-gen/pkg/cloud/meta"
+)
+
+func TestFieldsForAllServices(t *testing.T) {
+	t.Parallel()
+
+	for _, si := range meta.AllServices {
+		if _, ok := fields[si.Object]; !ok {
+			t.Errorf("fields[%q] is missing (service %s)", si.Object, si.WrapType())
+		}
+	}
+}
+
+// current is a BackendService as returned by Get().
+func current() *ga.BackendService {
+	return &ga.BackendService{
+		Id:                1234,
+		CreationTimestamp: "2018-01-01T00:00:00.000-07:00",
+		Fingerprint:       "abc=",
+		Kind:              "compute#backendService",
+		Name:              "bs",
+		SelfLink:          "https://www.googleapis.com/compute/v1/projects/proj/global/backendServices/bs",
+		Backends: []*ga.Backend{{
+			Group:          "https://www.googleapis.com/compute/v1/projects/proj/zones/us-central1-b/instanceGroups/ig",
+			BalancingMode:  "UTILIZATION",
+			CapacityScaler: 1,
+			MaxUtilization: 0.8,
+		}},
+		HealthChecks:        []string{"https://www.googleapis.com/compute/v1/projects/proj/global/healthChecks/hc"},
+		LoadBalancingScheme: "EXTERNAL",
+		Port:                80,
+		PortName:            "http",
+		Protocol:            "HTTP",
+		SessionAffinity:     "NONE",
+		TimeoutSec:          30,
+	}
+}
+
+func TestEqual(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		desc string
+		a, b interface{}
+		want string
+	}{
+		{
+			desc: "output-only and defaulted fields",
+			a: &ga.BackendService{
+				Name:         "bs",
+				Backends:     []*ga.Backend{{Group: "zones/us-central1-b/instanceGroups/ig"}},
+				HealthChecks: []string{"projects/proj/global/healthChecks/hc"},
+			},
+			b: current(),
+		},
+		{
+			desc: "same object",
+			a:    current(),
+			b:    current(),
+		},
+		{
+			desc: "other version",
+			a:    &alpha.BackendService{Name: "bs", Backends: []*alpha.Backend{{Group: "zones/us-central1-b/instanceGroups/ig"}}, HealthChecks: []string{"global/healthChecks/hc"}},
+			b:    current(),
+		},
+		{
+			desc: "changed fields",
+			a: &ga.BackendService{
+				Name:         "bs",
+				Backends:     []*ga.Backend{{Group: "zones/us-central1-b/instanceGroups/ig", BalancingMode: "RATE"}},
+				HealthChecks: []string{"global/healthChecks/hc-2"},
+				Port:         8080,
+			},
+			b: current(),
+			want: `backends[0].balancingMode: "RATE" != "UTILIZATION"
+healthChecks[0]: "global/healthChecks/hc-2" != "https://www.googleapis.com/compute/v1/projects/proj/global/healthChecks/hc"
+port: 8080 != 80`,
+		},
+		{
+			desc: "reference to another project",
+			a:    &ga.BackendService{Name: "bs", Backends: []*ga.Backend{{Group: "projects/other/zones/us-central1-b/instanceGroups/ig"}}, HealthChecks: []string{"global/healthChecks/hc"}},
+			b:    current(),
+			want: `backends[0].group: "projects/other/zones/us-central1-b/instanceGroups/ig" != "https://www.googleapis.com/compute/v1/projects/proj/zones/us-central1-b/instanceGroups/ig"`,
+		},
+		{
+			desc: "unset and missing elements",
+			a:    &ga.BackendService{Name: "bs", Description: "d"},
+			b:    current(),
+			want: `backends[0].group: <unset> != "https://www.googleapis.com/compute/v1/projects/proj/zones/us-central1-b/instanceGroups/ig"
+description: "d" != <unset>
+healthChecks[0]: <unset> != "https://www.googleapis.com/compute/v1/projects/proj/global/healthChecks/hc"`,
+		},
+		{
+			desc: "server assigned",
+			a:    &ga.ForwardingRule{Name: "fr", PortRange: "80-80"},
+			b:    &ga.ForwardingRule{Name: "fr", PortRange: "80-80", IPAddress: "1.2.3.4", IPProtocol: "TCP", Region: "https://www.googleapis.com/compute/v1/projects/proj/regions/us-central1"},
+		},
+		{
+			desc: "server assigned set in both",
+			a:    &ga.ForwardingRule{Name: "fr", IPAddress: "1.2.3.5"},
+			b:    &ga.ForwardingRule{Name: "fr", IPAddress: "1.2.3.4"},
+			want: `IPAddress: "1.2.3.5" != "1.2.3.4"`,
+		},
+		{
+			desc: "nested defaults",
+			a:    &ga.HealthCheck{Name: "hc", Type: "HTTP", HttpHealthCheck: &ga.HTTPHealthCheck{}},
+			b:    &ga.HealthCheck{Name: "hc", Type: "HTTP", CheckIntervalSec: 5, TimeoutSec: 5, HealthyThreshold: 2, UnhealthyThreshold: 2, HttpHealthCheck: &ga.HTTPHealthCheck{Port: 80, RequestPath: "/", ProxyHeader: "NONE"}},
+		},
+		{
+			desc: "input-only field",
+			a:    &ga.SslCertificate{Name: "cert", Certificate: "c", PrivateKey: "k"},
+			b:    &ga.SslCertificate{Name: "cert", Certificate: "c"},
+		},
+	} {
+		if got := Explain(tc.a, tc.b); got != tc.want {
+			t.Errorf("%s: Explain() =\n%s\nwant\n%s", tc.desc, got, tc.want)
+		}
+		if got := Equal(tc.a, tc.b); got != (tc.want == "") {
+			t.Errorf("%s: Equal() = %t, want %t", tc.desc, got, tc.want == "")
+		}
+	}
+}
+
+func TestEqualErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		desc string
+		a, b interface{}
+	}{
+		{"different types", &ga.Firewall{}, &ga.BackendService{}},
+		{"nil", nil, &ga.Firewall{}},
+		{"not a struct", "abc", "abc"},
+	} {
+		if Equal(tc.a, tc.b) {
+			t.Errorf("%s: Equal(%v, %v) = true, want false", tc.desc, tc.a, tc.b)
+		}
+		if _, err := Differences(tc.a, tc.b); err == nil {
+			t.Errorf("%s: Differences(%v, %v) = _, nil; want error", tc.desc, tc.a, tc.b)
+		}
+	}
+}
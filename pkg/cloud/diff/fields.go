@@ -0,0 +1,201 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diff
+
+// assignedValue is the type of assigned.
+type assignedValue struct{}
+
+// assigned is the default of a field that the server assigns a value to if
+// it is not set (e.g. the IP address of a forwarding rule). Any value is
+// equal to an unset field.
+var assigned = assignedValue{}
+
+// objectFields are the fields of an object that are not compared as is.
+// Fields are named by their JSON path, with "[]" for the elements of a list
+// (e.g. "backends[].balancingMode").
+type objectFields struct {
+	// outputOnly fields are set by the server (or never returned by it) and
+	// are ignored.
+	outputOnly []string
+	// defaults are the values that the server uses for fields that are not
+	// set. An unset field is equal to its default.
+	defaults map[string]interface{}
+}
+
+// commonOutputOnly fields are ignored for all objects.
+var commonOutputOnly = []string{
+	"creationTimestamp",
+	"fingerprint",
+	"id",
+	"kind",
+	"labelFingerprint",
+	"selfLink",
+	"selfLinkWithId",
+}
+
+// healthCheckDefaults are the defaults shared by the health check objects.
+var healthCheckDefaults = map[string]interface{}{
+	"checkIntervalSec":   5,
+	"healthyThreshold":   2,
+	"timeoutSec":         5,
+	"unhealthyThreshold": 2,
+}
+
+// fields for each Object in meta.AllServices.
+var fields = map[string]*objectFields{
+	"Address": {
+		outputOnly: []string{"region", "status", "users"},
+		defaults: map[string]interface{}{
+			"address":     assigned,
+			"addressType": "EXTERNAL",
+			"ipVersion":   "IPV4",
+			"networkTier": "PREMIUM",
+		},
+	},
+	"BackendService": {
+		outputOnly: []string{"region"},
+		defaults: map[string]interface{}{
+			"backends[].balancingMode":  "UTILIZATION",
+			"backends[].capacityScaler": 1,
+			"backends[].maxUtilization": 0.8,
+			"loadBalancingScheme":       "EXTERNAL",
+			"port":                      80,
+			"portName":                  "http",
+			"protocol":                  "HTTP",
+			"sessionAffinity":           "NONE",
+			"timeoutSec":                30,
+		},
+	},
+	"Disk": {
+		outputOnly: []string{
+			"lastAttachTimestamp", "lastDetachTimestamp", "sourceImageId",
+			"sourceSnapshotId", "status", "users", "zone",
+		},
+		defaults: map[string]interface{}{
+			"sizeGb": assigned,
+			"type":   assigned,
+		},
+	},
+	"Firewall": {
+		defaults: map[string]interface{}{
+			"direction": "INGRESS",
+			"network":   assigned,
+			"priority":  1000,
+		},
+	},
+	"ForwardingRule": {
+		outputOnly: []string{"region"},
+		defaults: map[string]interface{}{
+			"IPAddress":           assigned,
+			"IPProtocol":          "TCP",
+			"ipVersion":           "IPV4",
+			"loadBalancingScheme": "EXTERNAL",
+			"networkTier":         "PREMIUM",
+		},
+	},
+	"HealthCheck": {
+		defaults: merge(healthCheckDefaults, map[string]interface{}{
+			"httpHealthCheck.port":         80,
+			"httpHealthCheck.proxyHeader":  "NONE",
+			"httpHealthCheck.requestPath":  "/",
+			"httpsHealthCheck.port":        443,
+			"httpsHealthCheck.proxyHeader": "NONE",
+			"httpsHealthCheck.requestPath": "/",
+			"tcpHealthCheck.port":          80,
+			"tcpHealthCheck.proxyHeader":   "NONE",
+			"sslHealthCheck.port":          443,
+			"sslHealthCheck.proxyHeader":   "NONE",
+		}),
+	},
+	"HttpHealthCheck": {
+		defaults: merge(healthCheckDefaults, map[string]interface{}{
+			"port":        80,
+			"requestPath": "/",
+		}),
+	},
+	"HttpsHealthCheck": {
+		defaults: merge(healthCheckDefaults, map[string]interface{}{
+			"port":        443,
+			"requestPath": "/",
+		}),
+	},
+	"Instance": {
+		outputOnly: []string{
+			"cpuPlatform", "disks[].index", "disks[].kind", "networkInterfaces[].kind",
+			"networkInterfaces[].name", "startRestricted", "status",
+			"statusMessage", "zone",
+		},
+		defaults: map[string]interface{}{
+			"networkInterfaces[].accessConfigs[].natIP": assigned,
+			"networkInterfaces[].networkIP":             assigned,
+			"scheduling.automaticRestart":               true,
+			"scheduling.onHostMaintenance":              "MIGRATE",
+		},
+	},
+	"InstanceGroup": {
+		outputOnly: []string{"network", "region", "size", "subnetwork", "zone"},
+	},
+	"NetworkEndpointGroup": {
+		outputOnly: []string{"size", "zone"},
+		defaults: map[string]interface{}{
+			"networkEndpointType": "GCE_VM_IP_PORT",
+		},
+	},
+	"Project": {
+		outputOnly: []string{"quotas", "xpnProjectStatus"},
+	},
+	"Region": {
+		outputOnly: []string{"deprecated", "quotas", "status", "zones"},
+	},
+	"Route": {
+		outputOnly: []string{"warnings"},
+		defaults: map[string]interface{}{
+			"priority": 1000,
+		},
+	},
+	"SslCertificate": {
+		// The private key is never returned by the server.
+		outputOnly: []string{"expireTime", "privateKey"},
+	},
+	"TargetHttpProxy": {},
+	"TargetHttpsProxy": {
+		defaults: map[string]interface{}{
+			"quicOverride": "NONE",
+		},
+	},
+	"TargetPool": {
+		outputOnly: []string{"region"},
+		defaults: map[string]interface{}{
+			"sessionAffinity": "NONE",
+		},
+	},
+	"UrlMap": {},
+	"Zone": {
+		outputOnly: []string{"availableCpuPlatforms", "deprecated", "region", "status"},
+	},
+}
+
+func merge(a, b map[string]interface{}) map[string]interface{} {
+	ret := map[string]interface{}{}
+	for k, v := range a {
+		ret[k] = v
+	}
+	for k, v := range b {
+		ret[k] = v
+	}
+	return ret
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"net/http"
+	"reflect"
+
+	"google.golang.org/api/googleapi"
+
+	"github.com/bowei/gce-gen/pkg/cloud/meta"
+)
+
+// ServiceOf returns the wrapper of the service si in c, e.g. c.Firewalls() or
+// c.AlphaBackendServices(). It returns false if c does not have it.
+func ServiceOf(c Cloud, si *meta.ServiceInfo) (reflect.Value, bool) {
+	m := reflect.ValueOf(c).MethodByName(si.WrapType())
+	if !m.IsValid() {
+		return reflect.Value{}, false
+	}
+	return m.Call(nil)[0], true
+}
+
+// FindService returns the wrapper in c and the ServiceInfo of the service in
+// meta.AllServices for which match returns true and that has all of methods
+// (e.g. "Get", "Delete"). The GA service is preferred to the beta one, and
+// the beta service to the alpha one.
+func FindService(c Cloud, match func(si *meta.ServiceInfo) bool, methods ...string) (reflect.Value, *meta.ServiceInfo, bool) {
+	var (
+		ret    reflect.Value
+		retSI  *meta.ServiceInfo
+		retVer int
+	)
+	for _, si := range meta.AllServices {
+		if !match(si) {
+			continue
+		}
+		svc, ok := ServiceOf(c, si)
+		if !ok || !HasMethods(svc, methods...) {
+			continue
+		}
+		if v := versionRank(si.Version()); retSI == nil || v < retVer {
+			ret, retSI, retVer = svc, si, v
+		}
+	}
+	return ret, retSI, retSI != nil
+}
+
+func versionRank(v meta.Version) int {
+	switch v {
+	case meta.VersionGA:
+		return 0
+	case meta.VersionBeta:
+		return 1
+	}
+	return 2
+}
+
+// HasMethods is true if svc, a service wrapper, has all of the methods.
+func HasMethods(svc reflect.Value, names ...string) bool {
+	for _, name := range names {
+		if _, ok := svc.Type().MethodByName(name); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// CallMethod calls the method name of svc, a service wrapper, with args. It
+// returns the value returned by the method with the error, if any (e.g. the
+// object of Get()), and the error.
+func CallMethod(svc reflect.Value, name string, args ...interface{}) (interface{}, error) {
+	var in []reflect.Value
+	for _, a := range args {
+		in = append(in, reflect.ValueOf(a))
+	}
+	out := svc.MethodByName(name).Call(in)
+	if err, _ := out[len(out)-1].Interface().(error); err != nil {
+		return nil, err
+	}
+	if len(out) == 2 {
+		return out[0].Interface(), nil
+	}
+	return nil, nil
+}
+
+// IsNotFound is true if err is a googleapi.Error with the code 404, such as
+// the error of a Get() for an object that does not exist.
+func IsNotFound(err error) bool {
+	apiErr, ok := err.(*googleapi.Error)
+	return ok && apiErr.Code == http.StatusNotFound
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	ga "google.golang.org/api/compute/v1"
+
+	"github.com/bowei/gce-gen/pkg/cloud/meta"
+)
+
+func TestFindService(t *testing.T) {
+	t.Parallel()
+
+	mock := NewMockGCE()
+	for _, tc := range []struct {
+		resource string
+		methods  []string
+		want     string
+	}{
+		{resource: "backendServices", methods: []string{"Get"}, want: "BackendServices"},
+		{resource: "networkEndpointGroups", methods: []string{"Get"}, want: "AlphaNetworkEndpointGroups"},
+		{resource: "regions", methods: []string{"Get", "Delete"}},
+		{resource: "noSuchResource"},
+	} {
+		_, si, ok := FindService(mock, func(si *meta.ServiceInfo) bool { return si.Resource == tc.resource }, tc.methods...)
+		var got string
+		if ok {
+			got = si.WrapType()
+		}
+		if got != tc.want {
+			t.Errorf("FindService(%q, %v) = %q, %t; want %q", tc.resource, tc.methods, got, ok, tc.want)
+		}
+	}
+}
+
+func TestCallMethod(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := NewMockGCE()
+	key := *meta.GlobalKey("fw")
+	var svc reflect.Value
+	for _, si := range meta.AllServices {
+		if si.WrapType() == "Firewalls" {
+			svc, _ = ServiceOf(mock, si)
+		}
+	}
+	if !svc.IsValid() {
+		t.Fatalf("ServiceOf(Firewalls) = _, false")
+	}
+
+	if _, err := CallMethod(svc, "Get", ctx, key); !IsNotFound(err) {
+		t.Errorf("CallMethod(Get) = _, %v; want a not found error", err)
+	}
+	if obj, err := CallMethod(svc, "Insert", ctx, key, &ga.Firewall{}); obj != nil || err != nil {
+		t.Errorf("CallMethod(Insert) = %v, %v; want nil, nil", obj, err)
+	}
+	obj, err := CallMethod(svc, "Get", ctx, key)
+	if fw, ok := obj.(*ga.Firewall); err != nil || !ok || fw.Name != "fw" {
+		t.Errorf("CallMethod(Get) = %v, %v; want the firewall", obj, err)
+	}
+	if IsNotFound(errors.New("not found")) {
+		t.Errorf("IsNotFound(errors.New()) = true; want false")
+	}
+}
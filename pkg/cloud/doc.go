@@ -39,6 +39,74 @@ limitations under the License.
 //
 // The generated code allows for custom policies for operation rate limiting
 // and GCE project routing. See RateLimiter and ProjectRouter for more details.
+// MapProjectRouter, ContextProjectRouter and SharedVPCProjectRouter route
+// calls by (version, service), by the project in the context and to the host
+// project of a shared VPC respectively, and can be combined with each other.
+//
+// A key can refer to a resource in a specific project with Key.WithProject()
+// (e.g. a subnetwork in the host project of a shared VPC). The project of the
+// key is used instead of the one from the ProjectRouter. The mock stores the
+// objects of each project separately. Calls to the mock are made to
+// "mock-project" unless routed elsewhere with MockGCE.SetProjectRouter() or
+// the WithMockProjectRouter() option.
+//
+// Observing calls
+//
+// Every generated method invokes the CallObserver set in Service.Observer
+// (or MockGCE.SetObserver() for the mock) before and after the call. This
+// can be used to add logging, metrics, tracing and so on to all calls. See
+// CallObserver for more details.
+//
+// Dry run
+//
+// Setting Service.DryRun to a DryRunPlan records Insert, Delete and the
+// other mutating calls in the plan instead of sending them to GCE. Get and
+// List calls are sent to GCE as usual.
+//
+// Caching
+//
+// NewCachedGCE() wraps a Cloud to cache the results of Get(), List() and
+// AggregatedList() for a TTL per service (CacheOptions). A mutation of an
+// object through the CachedGCE invalidates its cached results and those of
+// List(). Identical concurrent calls are made once, and CachedGCE.Stats()
+// returns the hit and miss counters of each service.
+//
+//  c := cloud.NewCachedGCE(gce, cloud.CacheOptions{
+//    TTL: map[string]time.Duration{"Zones": time.Hour, "Regions": time.Hour},
+//  })
+//
+// Informers
+//
+// An Informer polls the List() or AggregatedList() method of a service every
+// InformerOptions.ResyncPeriod and keeps the objects in a store keyed by
+// meta.Key. The objects that were added, changed or removed between two polls
+// are sent to the InformerHandlers.
+//
+// Bulk operations
+//
+// Bulk() calls a method, such as Instances().Delete, for many keys with a
+// bounded number of concurrent calls. The errors are gathered by key in a
+// *BulkError. With BulkOptions.StopOnError, no more calls are started once a
+// call fails.
+//
+// Key validation
+//
+// Methods taking a meta.Key check it with Key.Validate() before the call is
+// made, both in GCE and in the mock. A key with a malformed name, zone or
+// region, or of the wrong type for the service (e.g. a zonal key for
+// Firewalls), fails with a *meta.InvalidKeyError.
+//
+// Converting between versions
+//
+// ConvertXToY() functions are generated for the objects that exist in more
+// than one version of the API, e.g. ConvertBackendServiceAlphaToGA(). They
+// return the JSON paths of the non-empty fields that the target version does
+// not have, so that a caller can refuse a write that would lose them.
+//
+//  obj, lost, err := cloud.ConvertBackendServiceAlphaToGA(bs)
+//  if len(lost) > 0 {
+//    return fmt.Errorf("fields %v are not in the GA API", lost)
+//  }
 //
 // Mocks
 //
@@ -51,6 +119,23 @@ limitations under the License.
 // Mocks for different versions of the same service will share the same set of
 // objects, i.e. an alpha object will be visible with beta and GA methods.
 // Note that translation is done with JSON serialization between the API versions.
+// An Update() through one version keeps the fields that only the other versions
+// have (e.g. an alpha field after a GA Update()), as in GCE.
+//
+// The mock starts empty. NewMockGCE(WithMockTopology(DefaultTopologyCatalog))
+// seeds the Regions and Zones with a catalog similar to production, which can
+// be queried with a Topology. With a ProjectRouter, the objects are seeded in
+// the project that Regions and Zones are routed to:
+// NewMockGCE(WithMockProjectRouter(r), WithMockTopology(...)).
+//
+// With NewMockGCE(WithReferenceChecking()), Delete() fails with a
+// "resourceInUseByAnotherResource" error if another object in the mock
+// references the object, as in GCE. See References().
+//
+// The informers created with NewInformer() for the mock are polled by
+// MockGCE.PollInformers(), which returns once the events were sent, until
+// they are stopped with Informer.Stop() or the context of their Run() is
+// done.
 //
 // Changing service code generation
 //
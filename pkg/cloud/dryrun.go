@@ -0,0 +1,116 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/golang/glog"
+
+	"github.com/bowei/gce-gen/pkg/cloud/meta"
+)
+
+// Mutation is a call that would have changed the state of GCE had the
+// Service not been in dry-run mode.
+type Mutation struct {
+	// RateLimitKey identifies the project, version, service and operation
+	// (e.g. "Insert", "SetUrlMap") of the call.
+	RateLimitKey
+	// Key of the resource. This is nil for calls that do not take a key
+	// (e.g. Projects().SetCommonInstanceMetadata()).
+	Key *meta.Key
+	// Request is the request object of the call, e.g. the object to
+	// Insert(), or a []interface{} of the arguments of calls with more than
+	// one (see CallContext.Request). This is nil for calls without a
+	// request object such as Delete().
+	Request interface{}
+}
+
+// DryRunPlan records the mutations made to a Service in dry-run mode.
+//
+//  plan := &DryRunPlan{}
+//  gce := NewGCE(&Service{..., DryRun: plan})
+//  reconcile(gce)
+//  for _, m := range plan.Mutations() {
+//    ...
+//  }
+type DryRunPlan struct {
+	lock      sync.Mutex
+	mutations []Mutation
+}
+
+// Mutations returns the mutations recorded so far, in the order they were
+// made.
+func (p *DryRunPlan) Mutations() []Mutation {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return append([]Mutation(nil), p.mutations...)
+}
+
+// Reset clears the recorded mutations.
+func (p *DryRunPlan) Reset() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.mutations = nil
+}
+
+// record the call described by cc as an intended mutation and mark cc as a
+// dry run.
+func (p *DryRunPlan) record(cc *CallContext) {
+	cc.DryRun = true
+	// The key and request are copied, as the caller may change them after
+	// the call.
+	m := Mutation{
+		RateLimitKey: cc.RateLimitKey,
+		Request:      CopyRequest(cc.Request),
+	}
+	if cc.Key != nil {
+		key := *cc.Key
+		m.Key = &key
+	}
+	glog.Infof("Dry run: %s.%s(%v, %+v) (project %q, version %q) not sent to GCE",
+		m.Service, m.Operation, m.Key, m.Request, m.ProjectID, m.Version)
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.mutations = append(p.mutations, m)
+}
+
+// CopyRequest returns a deep copy of req, the CallContext.Request of a call
+// such as *ga.Firewall, or of each of the arguments if it is a []interface{}.
+// Observers that keep requests after the call use it, as the caller may
+// change them.
+func CopyRequest(req interface{}) interface{} {
+	if args, ok := req.([]interface{}); ok {
+		ret := make([]interface{}, len(args))
+		for i, a := range args {
+			ret[i] = CopyRequest(a)
+		}
+		return ret
+	}
+	v := reflect.ValueOf(req)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return req
+	}
+	ret := reflect.New(v.Type().Elem()).Interface()
+	if err := copyViaJSON(ret, req); err != nil {
+		glog.Errorf("Could not copy %T via JSON: %v", req, err)
+		return req
+	}
+	return ret
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+
+	alpha "google.golang.org/api/compute/v0.alpha"
+	ga "google.golang.org/api/compute/v1"
+
+	"github.com/bowei/gce-gen/pkg/cloud/filter"
+	"github.com/bowei/gce-gen/pkg/cloud/meta"
+)
+
+type noWaitRateLimiter struct{}
+
+func (*noWaitRateLimiter) Accept(ctx context.Context, key *RateLimitKey) error {
+	return nil
+}
+
+// readOnlyCompute is a stand-in for the compute API that serves firewall
+// Get and List and records every other request.
+type readOnlyCompute struct {
+	lock     sync.Mutex
+	reads    int
+	unwanted []string
+}
+
+func (f *readOnlyCompute) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	var resp interface{}
+	switch {
+	case r.Method == "GET" && r.URL.Path == "/projects/proj/global/firewalls/fw-1":
+		resp = &ga.Firewall{Name: "fw-1"}
+	case r.Method == "GET" && r.URL.Path == "/projects/proj/global/firewalls":
+		resp = &ga.FirewallList{Items: []*ga.Firewall{{Name: "fw-1"}}}
+	default:
+		f.unwanted = append(f.unwanted, r.Method+" "+r.URL.Path)
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error": {"code": 404, "message": "not found"}}`))
+		return
+	}
+	f.reads++
+	json.NewEncoder(w).Encode(resp)
+}
+
+func TestDryRun(t *testing.T) {
+	t.Parallel()
+
+	fake := &readOnlyCompute{}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+	svc, err := ga.New(srv.Client())
+	if err != nil {
+		t.Fatalf("ga.New() = _, %v", err)
+	}
+	svc.BasePath = srv.URL + "/projects/"
+	alphaSvc, err := alpha.New(srv.Client())
+	if err != nil {
+		t.Fatalf("alpha.New() = _, %v", err)
+	}
+	alphaSvc.BasePath = srv.URL + "/projects/"
+
+	plan := &DryRunPlan{}
+	gce := NewGCE(&Service{
+		GA:            svc,
+		Alpha:         alphaSvc,
+		ProjectRouter: &SingleProjectRouter{ID: "proj"},
+		RateLimiter:   &noWaitRateLimiter{},
+		DryRun:        plan,
+	})
+
+	ctx := context.Background()
+	key := meta.GlobalKey("fw-1")
+	fw := &ga.Firewall{Description: "new"}
+	urlMap := &ga.UrlMapReference{UrlMap: "um"}
+	vmKey := meta.ZonalKey("vm", "us-central1-b")
+	nic := &alpha.NetworkInterface{Network: "global/networks/default"}
+
+	if _, err := gce.Firewalls().Get(ctx, *key); err != nil {
+		t.Errorf("Firewalls().Get() = _, %v, want nil", err)
+	}
+	if l, err := gce.Firewalls().List(ctx, filter.None); err != nil || len(l) != 1 {
+		t.Errorf("Firewalls().List() = %v, %v, want 1 item, nil", l, err)
+	}
+	if err := gce.Firewalls().Insert(ctx, *key, fw); err != nil {
+		t.Errorf("Firewalls().Insert() = %v, want nil", err)
+	}
+	if err := gce.Firewalls().Update(ctx, *key, fw); err != nil {
+		t.Errorf("Firewalls().Update() = %v, want nil", err)
+	}
+	if err := gce.TargetHttpProxies().SetUrlMap(ctx, *meta.GlobalKey("tp"), urlMap); err != nil {
+		t.Errorf("TargetHttpProxies().SetUrlMap() = %v, want nil", err)
+	}
+	if err := gce.AlphaInstances().UpdateNetworkInterface(ctx, *vmKey, "nic0", nic); err != nil {
+		t.Errorf("AlphaInstances().UpdateNetworkInterface() = %v, want nil", err)
+	}
+	if err := gce.Firewalls().Delete(ctx, *key); err != nil {
+		t.Errorf("Firewalls().Delete() = %v, want nil", err)
+	}
+
+	// Changes to the requests after the calls do not change the plan.
+	fw.Description = "changed"
+	nic.Network = "changed"
+
+	if fake.reads != 2 {
+		t.Errorf("reads sent to GCE = %d, want 2", fake.reads)
+	}
+	if len(fake.unwanted) != 0 {
+		t.Errorf("mutations sent to GCE = %v, want none", fake.unwanted)
+	}
+
+	want := []struct {
+		service, operation string
+		key                meta.Key
+		request            interface{}
+	}{
+		{"Firewalls", "Insert", *key, &ga.Firewall{Name: "fw-1", Description: "new"}},
+		{"Firewalls", "Update", *key, &ga.Firewall{Name: "fw-1", Description: "new"}},
+		{"TargetHttpProxies", "SetUrlMap", *meta.GlobalKey("tp"), &ga.UrlMapReference{UrlMap: "um"}},
+		// All of the arguments of methods with more than one are recorded.
+		{"Instances", "UpdateNetworkInterface", *vmKey, []interface{}{"nic0", &alpha.NetworkInterface{Network: "global/networks/default"}}},
+		{"Firewalls", "Delete", *key, nil},
+	}
+	got := plan.Mutations()
+	if len(got) != len(want) {
+		t.Fatalf("len(plan.Mutations()) = %d, want %d (%+v)", len(got), len(want), got)
+	}
+	for i, w := range want {
+		m := got[i]
+		if m.Service != w.service || m.Operation != w.operation || m.ProjectID != "proj" || *m.Key != w.key || !reflect.DeepEqual(m.Request, w.request) {
+			t.Errorf("plan.Mutations()[%d] = %+v, want %s.%s(%v, %v)", i, m, w.service, w.operation, w.key, w.request)
+		}
+	}
+
+	plan.Reset()
+	if len(plan.Mutations()) != 0 {
+		t.Errorf("plan.Mutations() after Reset() = %v, want empty", plan.Mutations())
+	}
+}
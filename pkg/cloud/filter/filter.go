@@ -22,17 +22,25 @@ limitations under the License.
 //  // List global addresses filtering for name matching "abc.*".
 //  c.GlobalAddresses().List(ctx, filter.Regexp("name", "abc.*"))
 //
+//  // List global addresses named exactly by the (user provided) name.
+//  c.GlobalAddresses().List(ctx, filter.Exact("name", name))
+//
 //  // List on multiple conditions.
 //  f := filter.Regexp("name", "homer.*").AndNotRegexp("name", "homers")
 //  c.GlobalAddresses().List(ctx, f)
+//
+//  // List instances labelled env=prod or env=staging.
+//  f := filter.Or(filter.LabelEquals("env", "prod"), filter.LabelEquals("env", "staging"))
+//  c.Instances().List(ctx, "us-central1-b", f)
 package filter
 
 import (
-	"errors"
 	"fmt"
 	"reflect"
 	"regexp"
+	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/golang/glog"
 )
@@ -48,31 +56,111 @@ func Regexp(fieldName, v string) *F {
 	return (&F{}).AndRegexp(fieldName, v)
 }
 
+// LiteralRegexp returns a regexp that matches the literal string v, escaping
+// any regexp metacharacters in v.
+func LiteralRegexp(v string) string {
+	return regexp.QuoteMeta(v)
+}
+
+// Exact returns a filter for fieldName equal to the string v. Unlike Regexp(),
+// v is not interpreted as a regexp.
+func Exact(fieldName, v string) *F {
+	return (&F{}).AndExact(fieldName, v)
+}
+
+// NotExact returns a filter for fieldName not equal to the string v.
+func NotExact(fieldName, v string) *F {
+	return (&F{}).AndNotExact(fieldName, v)
+}
+
 // NotRegexp returns a filter for fieldName not matches regexp v.
 func NotRegexp(fieldName, v string) *F {
 	return (&F{}).AndNotRegexp(fieldName, v)
 }
 
-// EqualInt returns a filter for fieldName ~ v.
+// EqualInt returns a filter for fieldName == v. It is the same as
+// Regexp(fieldName, strconv.Itoa(v)), as numbers are matched by the regexp
+// of eq in their decimal form.
 func EqualInt(fieldName string, v int) *F {
 	return (&F{}).AndEqualInt(fieldName, v)
 }
 
-// NotEqualInt returns a filter for fieldName != v.
+// NotEqualInt returns a filter for fieldName != v, the same as
+// NotRegexp(fieldName, strconv.Itoa(v)).
 func NotEqualInt(fieldName string, v int) *F {
 	return (&F{}).AndNotEqualInt(fieldName, v)
 }
 
-// EqualBool returns a filter for fieldName == v.
+// EqualBool returns a filter for fieldName == v, the same as
+// Regexp(fieldName, strconv.FormatBool(v)).
 func EqualBool(fieldName string, v bool) *F {
 	return (&F{}).AndEqualBool(fieldName, v)
 }
 
-// NotEqualBool returns a filter for fieldName != v.
+// NotEqualBool returns a filter for fieldName != v, the same as
+// NotRegexp(fieldName, strconv.FormatBool(v)).
 func NotEqualBool(fieldName string, v bool) *F {
 	return (&F{}).AndNotEqualBool(fieldName, v)
 }
 
+// CompareInt returns a filter for fieldName op v, e.g. "cpu < 4".
+func CompareInt(fieldName string, op Comparison, v int) *F {
+	return (&F{}).AndCompareInt(fieldName, op, v)
+}
+
+// CompareString returns a filter for fieldName op v where v is compared as a
+// string. This can be used to compare RFC3339 timestamps such as
+// creationTimestamp.
+func CompareString(fieldName string, op Comparison, v string) *F {
+	return (&F{}).AndCompareString(fieldName, op, v)
+}
+
+// HasLabel returns a filter for resources that have the label key.
+func HasLabel(key string) *F {
+	return (&F{}).AndHasLabel(key)
+}
+
+// LabelEquals returns a filter for resources with the label key set to v.
+func LabelEquals(key, v string) *F {
+	return (&F{}).AndLabelEquals(key, v)
+}
+
+// Or returns a filter that matches if any of fs match. A nil or empty filter
+// in fs matches everything and hence so does the result.
+func Or(fs ...*F) *F {
+	for _, f := range fs {
+		if f == nil || len(f.terms) == 0 {
+			return &F{}
+		}
+	}
+	return &F{terms: []term{&orTerm{fs: fs}}}
+}
+
+// Not returns a filter that matches if f does not match. A nil or empty f
+// matches everything, so Not returns a filter matching nothing for it. The
+// compute API has no such filter; "name ne .*" is used, as every resource
+// has a name.
+func Not(f *F) *F {
+	if f == nil || len(f.terms) == 0 {
+		return NotRegexp("name", ".*")
+	}
+	return &F{terms: []term{&notTerm{f: f}}}
+}
+
+// Comparison is a relational operator for CompareInt() and CompareString().
+type Comparison string
+
+const (
+	// LessThan is "<".
+	LessThan Comparison = "<"
+	// LessThanOrEqual is "<=".
+	LessThanOrEqual Comparison = "<="
+	// GreaterThan is ">".
+	GreaterThan Comparison = ">"
+	// GreaterThanOrEqual is ">=".
+	GreaterThanOrEqual Comparison = ">="
+)
+
 // F is a filter to be used with List() operations.
 //
 // From the compute API description:
@@ -100,60 +188,117 @@ func NotEqualBool(fieldName string, v bool) *F {
 // parentheses. For example, (scheduling.automaticRestart eq true)
 // (zone eq us-central1-f). Multiple expressions are treated as AND expressions,
 // meaning that resources must match all expressions to pass the filters.
+//
+// The newer filter syntax adds the operators =, !=, <, <=, >, >= and : (has)
+// and the grouping of expressions with OR and NOT, for example
+// (labels.env = "prod") OR (NOT (cpuPlatform:*)). Note that the compute API
+// does not accept regular expressions (eq, ne) mixed with the newer syntax.
+//
+// F is the AND of its terms, each of which is a predicate or a group of
+// filters joined with OR or NOT.
 type F struct {
-	predicates []filterPredicate
+	terms []term
 }
 
 // And joins two filters together.
 func (fl *F) And(rest *F) *F {
-	fl.predicates = append(fl.predicates, rest.predicates...)
+	fl.terms = append(fl.terms, rest.terms...)
+	return fl
+}
+
+// Or changes the filter to match if either the filter or rest match.
+func (fl *F) Or(rest *F) *F {
+	fl.terms = Or(&F{terms: fl.terms}, rest).terms
 	return fl
 }
 
 // AndRegexp adds a field match string predicate.
 func (fl *F) AndRegexp(fieldName, v string) *F {
-	fl.predicates = append(fl.predicates, filterPredicate{fieldName: fieldName, op: equals, s: &v})
+	fl.terms = append(fl.terms, &filterPredicate{fieldName: fieldName, op: equals, s: &v})
 	return fl
 }
 
 // AndNotRegexp adds a field not match string predicate.
 func (fl *F) AndNotRegexp(fieldName, v string) *F {
-	fl.predicates = append(fl.predicates, filterPredicate{fieldName: fieldName, op: notEquals, s: &v})
+	fl.terms = append(fl.terms, &filterPredicate{fieldName: fieldName, op: notEquals, s: &v})
 	return fl
 }
 
-// AndEqualInt adds a field == int predicate.
+// AndExact adds a field equal to the string v predicate.
+func (fl *F) AndExact(fieldName, v string) *F {
+	return fl.AndRegexp(fieldName, LiteralRegexp(v))
+}
+
+// AndNotExact adds a field not equal to the string v predicate.
+func (fl *F) AndNotExact(fieldName, v string) *F {
+	return fl.AndNotRegexp(fieldName, LiteralRegexp(v))
+}
+
+// AndEqualInt adds a field == int predicate (see EqualInt()).
 func (fl *F) AndEqualInt(fieldName string, v int) *F {
-	fl.predicates = append(fl.predicates, filterPredicate{fieldName: fieldName, op: equals, i: &v})
-	return fl
+	return fl.AndRegexp(fieldName, strconv.Itoa(v))
 }
 
-// AndNotEqualInt adds a field != int predicate.
+// AndNotEqualInt adds a field != int predicate (see NotEqualInt()).
 func (fl *F) AndNotEqualInt(fieldName string, v int) *F {
-	fl.predicates = append(fl.predicates, filterPredicate{fieldName: fieldName, op: notEquals, i: &v})
-	return fl
+	return fl.AndNotRegexp(fieldName, strconv.Itoa(v))
 }
 
-// AndEqualBool adds a field == bool predicate.
+// AndEqualBool adds a field == bool predicate (see EqualBool()).
 func (fl *F) AndEqualBool(fieldName string, v bool) *F {
-	fl.predicates = append(fl.predicates, filterPredicate{fieldName: fieldName, op: equals, b: &v})
-	return fl
+	return fl.AndRegexp(fieldName, strconv.FormatBool(v))
 }
 
-// AndNotEqualBool adds a field != bool predicate.
+// AndNotEqualBool adds a field != bool predicate (see NotEqualBool()).
 func (fl *F) AndNotEqualBool(fieldName string, v bool) *F {
-	fl.predicates = append(fl.predicates, filterPredicate{fieldName: fieldName, op: notEquals, b: &v})
+	return fl.AndNotRegexp(fieldName, strconv.FormatBool(v))
+}
+
+// AndCompareInt adds a fieldName op v predicate. It panics if op is not one
+// of the Comparison constants.
+func (fl *F) AndCompareInt(fieldName string, op Comparison, v int) *F {
+	fl.terms = append(fl.terms, &filterPredicate{fieldName: fieldName, op: comparisonOp(op), i: &v})
+	return fl
+}
+
+// AndCompareString adds a fieldName op v predicate where v is compared as a
+// string. It panics if op is not one of the Comparison constants.
+func (fl *F) AndCompareString(fieldName string, op Comparison, v string) *F {
+	fl.terms = append(fl.terms, &filterPredicate{fieldName: fieldName, op: comparisonOp(op), s: &v})
+	return fl
+}
+
+// comparisonOp returns the filterOp for op. An unknown op is a programming
+// error, which would otherwise silently become a different predicate.
+func comparisonOp(op Comparison) filterOp {
+	ret, ok := comparisonOps[op]
+	if !ok {
+		panic(fmt.Sprintf("filter: unknown comparison %q", op))
+	}
+	return ret
+}
+
+// AndHasLabel adds a predicate for resources that have the label key.
+func (fl *F) AndHasLabel(key string) *F {
+	v := "*"
+	fl.terms = append(fl.terms, &filterPredicate{fieldName: "labels." + key, op: has, s: &v})
+	return fl
+}
+
+// AndLabelEquals adds a predicate for resources with the label key set to v.
+func (fl *F) AndLabelEquals(key, v string) *F {
+	fl.terms = append(fl.terms, &filterPredicate{fieldName: "labels." + key, op: exactly, s: &v})
 	return fl
 }
 
 func (fl *F) String() string {
-	if len(fl.predicates) == 1 {
-		return fl.predicates[0].String()
+	if len(fl.terms) == 1 {
+		return fl.terms[0].String()
 	}
 
 	var pl []string
-	for _, p := range fl.predicates {
-		pl = append(pl, "("+p.String()+")")
+	for _, t := range fl.terms {
+		pl = append(pl, "("+t.String()+")")
 	}
 	return strings.Join(pl, " ")
 }
@@ -166,21 +311,91 @@ func (fl *F) Match(obj interface{}) bool {
 	if fl == nil {
 		return true
 	}
-	for _, p := range fl.predicates {
-		if !p.match(obj) {
+	for _, t := range fl.terms {
+		if !t.match(obj) {
 			return false
 		}
 	}
 	return true
 }
 
+// term is an element of the AND in F.
+type term interface {
+	String() string
+	match(obj interface{}) bool
+}
+
+// orTerm matches if any of fs match.
+type orTerm struct {
+	fs []*F
+}
+
+func (t *orTerm) String() string {
+	var pl []string
+	for _, f := range t.fs {
+		pl = append(pl, "("+f.String()+")")
+	}
+	return strings.Join(pl, " OR ")
+}
+
+func (t *orTerm) match(obj interface{}) bool {
+	for _, f := range t.fs {
+		if f.Match(obj) {
+			return true
+		}
+	}
+	return false
+}
+
+// notTerm matches if f does not match.
+type notTerm struct {
+	f *F
+}
+
+func (t *notTerm) String() string {
+	return "NOT (" + t.f.String() + ")"
+}
+
+func (t *notTerm) match(obj interface{}) bool {
+	return !t.f.Match(obj)
+}
+
 type filterOp int
 
 const (
+	// equals and notEquals are the regexp match operators "eq" and "ne".
 	equals    filterOp = iota
 	notEquals filterOp = iota
+	// exactly and notExactly are "=" and "!=".
+	exactly
+	notExactly
+	lessThan
+	lessThanOrEqual
+	greaterThan
+	greaterThanOrEqual
+	// has is ":". The value "*" tests for the presence of the field.
+	has
 )
 
+var comparisonOps = map[Comparison]filterOp{
+	LessThan:           lessThan,
+	LessThanOrEqual:    lessThanOrEqual,
+	GreaterThan:        greaterThan,
+	GreaterThanOrEqual: greaterThanOrEqual,
+}
+
+var opStrings = map[filterOp]string{
+	equals:             "eq",
+	notEquals:          "ne",
+	exactly:            "=",
+	notExactly:         "!=",
+	lessThan:           "<",
+	lessThanOrEqual:    "<=",
+	greaterThan:        ">",
+	greaterThanOrEqual: ">=",
+	has:                ":",
+}
+
 // filterPredicate is an individual predicate for a fieldName and value.
 type filterPredicate struct {
 	fieldName string
@@ -192,22 +407,19 @@ type filterPredicate struct {
 }
 
 func (fp *filterPredicate) String() string {
-	var op string
-	switch fp.op {
-	case equals:
-		op = "eq"
-	case notEquals:
-		op = "ne"
-	default:
+	op, ok := opStrings[fp.op]
+	if !ok {
 		op = "invalidOp"
 	}
 
 	var value string
 	switch {
+	case fp.s != nil && (fp.op == equals || fp.op == notEquals):
+		value = quoteRegexp(*fp.s)
+	case fp.s != nil && fp.op == has && *fp.s == "*":
+		value = "*"
 	case fp.s != nil:
-		// There does not seem to be any sort of escaping as specified in the
-		// document. This means it's possible to create malformed expressions.
-		value = *fp.s
+		value = quote(*fp.s)
 	case fp.i != nil:
 		value = fmt.Sprintf("%d", *fp.i)
 	case fp.b != nil:
@@ -216,50 +428,201 @@ func (fp *filterPredicate) String() string {
 		value = "invalidValue"
 	}
 
+	if fp.op == has {
+		return fmt.Sprintf("%s:%s", fp.fieldName, value)
+	}
 	return fmt.Sprintf("%s %s %s", fp.fieldName, op, value)
 }
 
+// quote v as a double quoted string literal. Backslash and double quote are
+// escaped with a backslash.
+func quote(v string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(v) + `"`
+}
+
+// quoteRegexp quotes the regexp v of an eq or ne predicate if it cannot be
+// written as is, i.e. if it is empty, contains whitespace, parentheses or
+// quotes, or ends in a backslash.
+func quoteRegexp(v string) string {
+	if v == "" || strings.ContainsAny(v, " \t\n()\"") || strings.HasSuffix(v, `\`) {
+		return quote(v)
+	}
+	return v
+}
+
 func (fp *filterPredicate) match(o interface{}) bool {
-	v, err := extractValue(fp.fieldName, o)
-	glog.V(6).Infof("extractValue(%q, %#v) = %v, %v", fp.fieldName, o, v, err)
-	if err != nil {
+	values, err := extractValues(fp.fieldName, o)
+	glog.V(6).Infof("extractValues(%q, %#v) = %v, %v", fp.fieldName, o, values, err)
+	if err != nil || len(values) == 0 {
 		return false
 	}
 
+	// Repeated fields match if any of the elements match. The negated
+	// operators match if none of the elements match the positive form.
+	var op filterOp
+	switch fp.op {
+	case notEquals:
+		op = equals
+	case notExactly:
+		op = exactly
+	default:
+		op = fp.op
+	}
 	var match bool
-	switch x := v.(type) {
-	case string:
-		if fp.s == nil {
+	for _, v := range values {
+		if fp.matchValue(op, v) {
+			match = true
+			break
+		}
+	}
+	if op != fp.op {
+		return !match
+	}
+	return match
+}
+
+// matchValue returns true if the single value v matches the predicate with
+// the operator op.
+func (fp *filterPredicate) matchValue(op filterOp, v interface{}) bool {
+	if op == has {
+		return fp.matchHas(v)
+	}
+	if op == equals && fp.s != nil {
+		// Bools and numbers are matched in their formatted form.
+		var x string
+		switch v := v.(type) {
+		case string:
+			x = v
+		case bool:
+			x = strconv.FormatBool(v)
+		case int64:
+			x = strconv.FormatInt(v, 10)
+		case uint64:
+			x = strconv.FormatUint(v, 10)
+		case float64:
+			x = strconv.FormatFloat(v, 'f', -1, 64)
+		default:
 			return false
 		}
-		re, err := regexp.Compile(*fp.s)
+		re, err := compileRegexp(*fp.s)
 		if err != nil {
 			glog.Errorf("Match regexp %q is invalid: %v", *fp.s, err)
 			return false
 		}
-		match = re.Match([]byte(x))
-	case int:
-		if fp.i == nil {
+		return re.MatchString(x)
+	}
+
+	// cmp is the result of comparing the field to the value, -1, 0 or 1.
+	var cmp int
+	switch x := v.(type) {
+	case string:
+		if fp.s == nil {
 			return false
 		}
-		match = x == *fp.i
+		cmp = strings.Compare(x, *fp.s)
 	case bool:
 		if fp.b == nil {
 			return false
 		}
-		match = x == *fp.b
+		if x != *fp.b {
+			cmp = 1
+		}
+	case int64, uint64, float64:
+		if fp.i == nil {
+			return false
+		}
+		cmp = compareNumber(x, *fp.i)
+	default:
+		return false
 	}
 
-	switch fp.op {
-	case equals:
-		return match
-	case notEquals:
-		return !match
+	switch op {
+	case equals, exactly:
+		return cmp == 0
+	case lessThan:
+		return cmp < 0
+	case lessThanOrEqual:
+		return cmp <= 0
+	case greaterThan:
+		return cmp > 0
+	case greaterThanOrEqual:
+		return cmp >= 0
 	}
-
 	return false
 }
 
+// compareNumber compares x (an int64, uint64 or float64) with i exactly,
+// returning -1, 0 or 1.
+func compareNumber(x interface{}, i int) int {
+	sign := func(b, c bool) int {
+		switch {
+		case b:
+			return -1
+		case c:
+			return 1
+		}
+		return 0
+	}
+	switch x := x.(type) {
+	case int64:
+		return sign(x < int64(i), x > int64(i))
+	case uint64:
+		if i < 0 {
+			return 1
+		}
+		return sign(x < uint64(i), x > uint64(i))
+	case float64:
+		return sign(x < float64(i), x > float64(i))
+	}
+	return 0
+}
+
+// matchHas evaluates ":" for the field value v. "*" matches any value that
+// is present, otherwise a string field must contain the literal.
+func (fp *filterPredicate) matchHas(v interface{}) bool {
+	if fp.s == nil {
+		return false
+	}
+	if *fp.s == "*" {
+		return true
+	}
+	x, ok := v.(string)
+	return ok && strings.Contains(x, *fp.s)
+}
+
+// maxCachedRegexps bounds the size of regexpCache.
+const maxCachedRegexps = 1024
+
+// regexpCache caches the compiled regexps used by Match(), as the same filter
+// is typically applied to every object in a List.
+var regexpCache = struct {
+	sync.RWMutex
+	m map[string]*regexp.Regexp
+}{m: map[string]*regexp.Regexp{}}
+
+// compileRegexp compiles the filter regexp s. The compute API requires the
+// regexp to match the entire field so s is anchored.
+func compileRegexp(s string) (*regexp.Regexp, error) {
+	regexpCache.RLock()
+	re, ok := regexpCache.m[s]
+	regexpCache.RUnlock()
+	if ok {
+		return re, nil
+	}
+
+	re, err := regexp.Compile("^(?:" + s + ")$")
+	if err != nil {
+		return nil, err
+	}
+	regexpCache.Lock()
+	defer regexpCache.Unlock()
+	if len(regexpCache.m) >= maxCachedRegexps {
+		regexpCache.m = map[string]*regexp.Regexp{}
+	}
+	regexpCache.m[s] = re
+	return re, nil
+}
+
 // snakeToCamelCase converts from "names_like_this" to "NamesLikeThis" to
 // interoperate between proto and Golang naming conventions.
 func snakeToCamelCase(s string) string {
@@ -271,33 +634,95 @@ func snakeToCamelCase(s string) string {
 	return ret
 }
 
-// extractValue returns the value of the field named by path in object o if it exists.
-func extractValue(path string, o interface{}) (interface{}, error) {
-	parts := strings.Split(path, ".")
-	for _, f := range parts {
-		v := reflect.ValueOf(o)
-		// Dereference Ptr to handle *struct.
-		if v.Kind() == reflect.Ptr {
-			if v.IsNil() {
-				return nil, errors.New("field is nil")
+// extractValues returns the values of the field named by path in object o.
+// Repeated fields along the path contribute a value for each element. Map
+// keys (e.g. labels.env) in path are used as is. Pointers are dereferenced
+// and numbers are converted to int64, uint64 or float64. Fields that are not
+// set (nil pointers, missing map keys) contribute no value.
+func extractValues(path string, o interface{}) ([]interface{}, error) {
+	values := flatten(reflect.ValueOf(o))
+	for _, f := range strings.Split(path, ".") {
+		var next []reflect.Value
+		for _, v := range values {
+			x, err := fieldValue(v, f, o)
+			if err != nil {
+				return nil, err
 			}
-			v = v.Elem()
+			next = append(next, flatten(x)...)
+		}
+		values = next
+	}
+
+	var ret []interface{}
+	for _, v := range values {
+		x, err := leafValue(v)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, x)
+	}
+	return ret, nil
+}
+
+// flatten dereferences pointers and expands slices in v. Invalid values and
+// nil pointers are dropped.
+func flatten(v reflect.Value) []reflect.Value {
+	switch v.Kind() {
+	case reflect.Invalid:
+		return nil
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
 		}
-		if v.Kind() != reflect.Struct {
-			return nil, fmt.Errorf("cannot get field from non-struct (%T)", o)
+		return flatten(v.Elem())
+	case reflect.Slice, reflect.Array:
+		var ret []reflect.Value
+		for i := 0; i < v.Len(); i++ {
+			ret = append(ret, flatten(v.Index(i))...)
 		}
-		v = v.FieldByName(snakeToCamelCase(f))
-		if !v.IsValid() {
-			return nil, fmt.Errorf("cannot get field %q as it is not a valid field in %T", f, o)
+		return ret
+	}
+	return []reflect.Value{v}
+}
+
+// fieldValue returns the field (or map key) f of v. The returned Value is
+// invalid if the map key is not present. o is used for error messages.
+func fieldValue(v reflect.Value, f string, o interface{}) (reflect.Value, error) {
+	switch v.Kind() {
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return reflect.Value{}, fmt.Errorf("cannot get key %q from map of type %v", f, v.Type())
+		}
+		return v.MapIndex(reflect.ValueOf(f).Convert(v.Type().Key())), nil
+	case reflect.Struct:
+		x := v.FieldByName(snakeToCamelCase(f))
+		if !x.IsValid() {
+			return reflect.Value{}, fmt.Errorf("cannot get field %q as it is not a valid field in %T", f, o)
 		}
-		if !v.CanInterface() {
-			return nil, fmt.Errorf("cannot get field %q in obj of type %T", f, o)
+		if !x.CanInterface() {
+			return reflect.Value{}, fmt.Errorf("cannot get field %q in obj of type %T", f, o)
 		}
-		o = v.Interface()
+		return x, nil
 	}
-	switch o.(type) {
-	case string, int, bool:
-		return o, nil
+	return reflect.Value{}, fmt.Errorf("cannot get field %q from non-struct (%v) in %T", f, v.Type(), o)
+}
+
+// leafValue converts v to a value for comparison.
+func leafValue(v reflect.Value) (interface{}, error) {
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return v.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint(), nil
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), nil
+	case reflect.Map, reflect.Struct:
+		// Only useful for presence (":*").
+		return v.Interface(), nil
 	}
-	return nil, fmt.Errorf("unhandled object of type %T", o)
+	return nil, fmt.Errorf("unhandled value of type %v", v.Type())
 }
@@ -37,6 +37,34 @@ func TestFilterToString(t *testing.T) {
 		{Regexp("field1", "abc").AndRegexp("field2", "def"), `(field1 eq abc) (field2 eq def)`},
 		{Regexp("field1", "abc").AndNotEqualInt("field2", 17), `(field1 eq abc) (field2 ne 17)`},
 		{Regexp("field1", "abc").And(EqualInt("field2", 17)), `(field1 eq abc) (field2 eq 17)`},
+		{Regexp("field1", "a b"), `field1 eq "a b"`},
+		{Regexp("field1", "a) OR (x eq y"), `field1 eq "a) OR (x eq y"`},
+		{Regexp("field1", `a"b`), `field1 eq "a\"b"`},
+		{Regexp("field1", `a\d+`), `field1 eq a\d+`},
+		{Regexp("field1", `a\`), `field1 eq "a\\"`},
+		{Regexp("field1", "13"), `field1 eq 13`},
+		{Regexp("field1", "true"), `field1 eq true`},
+		{Regexp("field1", ""), `field1 eq ""`},
+		{Exact("field1", "my-cluster.1"), `field1 eq my-cluster\.1`},
+		{Exact("field1", "a (b)"), `field1 eq "a \\(b\\)"`},
+		{NotExact("field1", "a*"), `field1 ne a\*`},
+		{CompareInt("field1", LessThan, 4), `field1 < 4`},
+		{CompareInt("field1", GreaterThanOrEqual, -4), `field1 >= -4`},
+		{CompareString("ts", GreaterThan, "2018-01-01T00:00:00Z"), `ts > "2018-01-01T00:00:00Z"`},
+		{HasLabel("env"), `labels.env:*`},
+		{LabelEquals("env", "prod"), `labels.env = "prod"`},
+		{LabelEquals("env", `a"b\c`), `labels.env = "a\"b\\c"`},
+		{Or(LabelEquals("env", "a"), LabelEquals("env", "b")), `(labels.env = "a") OR (labels.env = "b")`},
+		{Or(LabelEquals("env", "a"), None), ``},
+		{LabelEquals("env", "a").Or(HasLabel("x")), `(labels.env = "a") OR (labels.x:*)`},
+		{Not(HasLabel("env")), `NOT (labels.env:*)`},
+		{Not(HasLabel("a").AndHasLabel("b")), `NOT ((labels.a:*) (labels.b:*))`},
+		{Not(nil), `name ne .*`},
+		{Not(&F{}), `name ne .*`},
+		{
+			HasLabel("a").And(Or(CompareInt("x", LessThan, 1), Not(CompareInt("y", GreaterThan, 2)))),
+			`(labels.a:*) ((x < 1) OR (NOT (y > 2)))`,
+		},
 	} {
 		if tc.f.String() != tc.want {
 			t.Errorf("filter %#v String() = %q, want %q", tc.f, tc.f.String(), tc.want)
@@ -44,6 +72,24 @@ func TestFilterToString(t *testing.T) {
 	}
 }
 
+func TestFilterUnknownComparison(t *testing.T) {
+	t.Parallel()
+
+	for _, f := range []func(){
+		func() { CompareInt("field1", "=<", 4) },
+		func() { CompareString("field1", "", "a") },
+	} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("comparison with an unknown op did not panic")
+				}
+			}()
+			f()
+		}()
+	}
+}
+
 func TestFilterMatch(t *testing.T) {
 	t.Parallel()
 
@@ -56,7 +102,16 @@ func TestFilterMatch(t *testing.T) {
 		B           bool
 		Unhandled   struct{}
 		NestedField *inner
+		Labels      map[string]string
+		I64         int64
+		U64         uint64
+		F64         float64
+		BoolPtr     *bool
+		Strs        []string
+		Inners      []*inner
 	}
+	yes := true
+	labels := map[string]string{"env": "prod", "team": "x"}
 
 	for _, tc := range []struct {
 		f    *F
@@ -64,6 +119,9 @@ func TestFilterMatch(t *testing.T) {
 		want bool
 	}{
 		{f: None, o: &S{}, want: true},
+		{f: Not(None), o: &S{}},
+		{f: Not(None), o: &struct{ Name string }{Name: "x"}},
+		{f: Not(Not(None)), o: &struct{ Name string }{Name: "x"}, want: true},
 		{f: Regexp("s", "abc"), o: &S{}},
 		{f: EqualInt("i", 10), o: &S{}},
 		{f: EqualBool("b", true), o: &S{}},
@@ -72,6 +130,13 @@ func TestFilterMatch(t *testing.T) {
 		{f: NotEqualBool("b", true), o: &S{}, want: true},
 		{f: Regexp("s", "abc").AndEqualBool("b", true), o: &S{}},
 		{f: Regexp("s", "abc"), o: &S{S: "abc"}, want: true},
+		{f: Regexp("s", "123"), o: &S{S: "123"}, want: true},
+		{f: Regexp("i", "1."), o: &S{I: 10}, want: true},
+		{f: Regexp("i", "1"), o: &S{I: 10}},
+		{f: Regexp("b", "true"), o: &S{B: true}, want: true},
+		{f: NotRegexp("b", "true"), o: &S{B: true}},
+		{f: Regexp("f64", "1\\.5"), o: &S{F64: 1.5}, want: true},
+		{f: Regexp("u64", "[0-9]+"), o: &S{U64: 7}, want: true},
 		{f: Regexp("s", "a.*"), o: &S{S: "abc"}, want: true},
 		{f: Regexp("s", "a((("), o: &S{S: "abc"}},
 		{f: NotRegexp("s", "abc"), o: &S{S: "abc"}},
@@ -87,6 +152,60 @@ func TestFilterMatch(t *testing.T) {
 		{f: NotRegexp("nested_field.x", "xyz"), o: &S{NestedField: &inner{"xyz"}}},
 		{f: Regexp("nested_field.y", "xyz"), o: &S{NestedField: &inner{"xyz"}}},
 		{f: Regexp("nested_field", "xyz"), o: &S{NestedField: &inner{"xyz"}}},
+		{f: HasLabel("env"), o: &S{}},
+		{f: HasLabel("env"), o: &S{Labels: labels}, want: true},
+		{f: HasLabel("zone"), o: &S{Labels: labels}},
+		{f: LabelEquals("env", "prod"), o: &S{Labels: labels}, want: true},
+		{f: LabelEquals("env", "pro"), o: &S{Labels: labels}},
+		{f: LabelEquals("env", "pro.*"), o: &S{Labels: labels}},
+		{f: CompareInt("i", LessThan, 10), o: &S{I: 9}, want: true},
+		{f: CompareInt("i", LessThan, 10), o: &S{I: 10}},
+		{f: CompareInt("i", LessThanOrEqual, 10), o: &S{I: 10}, want: true},
+		{f: CompareInt("i", GreaterThan, 10), o: &S{I: 10}},
+		{f: CompareInt("i", GreaterThanOrEqual, 10), o: &S{I: 10}, want: true},
+		{f: CompareInt("i", GreaterThan, 10), o: &S{I: 11}, want: true},
+		{f: CompareInt("s", GreaterThan, 10), o: &S{I: 11}},
+		{f: CompareString("s", LessThan, "2018-02-01T00:00:00Z"), o: &S{S: "2018-01-01T00:00:00Z"}, want: true},
+		{f: CompareString("s", GreaterThan, "2018-02-01T00:00:00Z"), o: &S{S: "2018-01-01T00:00:00Z"}},
+		{f: Or(LabelEquals("env", "dev"), LabelEquals("env", "prod")), o: &S{Labels: labels}, want: true},
+		{f: Or(LabelEquals("env", "dev"), LabelEquals("env", "test")), o: &S{Labels: labels}},
+		{f: LabelEquals("env", "dev").Or(EqualInt("i", 3)), o: &S{I: 3}, want: true},
+		{f: Or(HasLabel("zone"), None), o: &S{}, want: true},
+		{f: Not(HasLabel("env")), o: &S{Labels: labels}},
+		{f: Not(HasLabel("zone")), o: &S{Labels: labels}, want: true},
+		{f: Not(HasLabel("env").AndEqualInt("i", 3)), o: &S{Labels: labels}, want: true},
+		{f: HasLabel("team").And(Not(LabelEquals("env", "dev"))), o: &S{Labels: labels}, want: true},
+		{f: Exact("s", "a.c"), o: &S{S: "abc"}},
+		{f: Exact("s", "a.c"), o: &S{S: "a.c"}, want: true},
+		{f: Exact("s", "a (b)"), o: &S{S: "a (b)"}, want: true},
+		{f: NotExact("s", "a (b)"), o: &S{S: "a (b)"}},
+		// Regexps match the entire field.
+		{f: Regexp("s", "b"), o: &S{S: "abc"}},
+		{f: Regexp("s", "a|x"), o: &S{S: "abc"}},
+		{f: Regexp("s", "a.*|x"), o: &S{S: "abc"}, want: true},
+		// Numbers of all kinds.
+		{f: EqualInt("i64", 1000), o: &S{I64: 1000}, want: true},
+		{f: EqualInt("i64", -1), o: &S{I64: -1}, want: true},
+		{f: NotEqualInt("i64", 1000), o: &S{I64: 1000}},
+		{f: EqualInt("u64", 7), o: &S{U64: 7}, want: true},
+		{f: CompareInt("u64", GreaterThan, -1), o: &S{U64: 0}, want: true},
+		{f: CompareInt("u64", LessThan, 1), o: &S{U64: 1 << 63}},
+		{f: CompareInt("f64", LessThan, 1), o: &S{F64: 0.5}, want: true},
+		{f: EqualInt("f64", 1), o: &S{F64: 0.5}},
+		{f: EqualBool("bool_ptr", true), o: &S{BoolPtr: &yes}, want: true},
+		{f: EqualBool("bool_ptr", true), o: &S{}},
+		{f: Regexp("i64", "1000"), o: &S{I64: 1000}, want: true},
+		{f: Regexp("i64", "100"), o: &S{I64: 1000}},
+		// Repeated fields match if any element matches.
+		{f: Regexp("strs", "b"), o: &S{Strs: []string{"a", "b"}}, want: true},
+		{f: Regexp("strs", "c"), o: &S{Strs: []string{"a", "b"}}},
+		{f: NotRegexp("strs", "b"), o: &S{Strs: []string{"a", "b"}}},
+		{f: NotRegexp("strs", "c"), o: &S{Strs: []string{"a", "b"}}, want: true},
+		{f: Regexp("strs", ".*"), o: &S{}},
+		{f: Regexp("inners.x", "y"), o: &S{Inners: []*inner{{"x"}, nil, {"y"}}}, want: true},
+		{f: Regexp("inners.x", "z"), o: &S{Inners: []*inner{{"x"}, {"y"}}}},
+		{f: (&F{}).AndCompareString("inners.x", GreaterThan, "x"), o: &S{Inners: []*inner{{"x"}, {"y"}}}, want: true},
+		{f: Regexp("inners.nope", "z"), o: &S{Inners: []*inner{{"x"}}}},
 	} {
 		got := tc.f.Match(tc.o)
 		if got != tc.want {
@@ -116,7 +235,7 @@ func TestFilterSnakeToCamelCase(t *testing.T) {
 	}
 }
 
-func TestFilterExtractValue(t *testing.T) {
+func TestFilterExtractValues(t *testing.T) {
 	t.Parallel()
 
 	type nest2 struct {
@@ -132,45 +251,84 @@ func TestFilterExtractValue(t *testing.T) {
 		F       bool
 		Nest    nest
 		NestPtr *nest
+		I64     int64
+		U64     uint64
+		F64     float64
+		BoolPtr *bool
+		Strs    []string
+		Nests   []*nest
+		Labels  map[string]string
 
-		Unhandled float64
+		Unhandled chan int
 	}{
-		"abc",
-		13,
-		true,
-		nest{"xyz", nest2{"zzz"}},
-		&nest{"yyy", nest2{}},
-		0.0,
+		S:       "abc",
+		I:       13,
+		F:       true,
+		Nest:    nest{"xyz", nest2{"zzz"}},
+		NestPtr: &nest{"yyy", nest2{}},
+		I64:     -64,
+		U64:     1 << 63,
+		F64:     0.5,
+		Strs:    []string{"a", "b"},
+		Nests:   []*nest{{X: "n1"}, nil, {X: "n2", Nest2: nest2{"y2"}}},
+		Labels:  map[string]string{"env": "prod"},
 	}
 
 	for _, tc := range []struct {
 		path    string
 		o       interface{}
-		want    interface{}
+		want    []interface{}
 		wantErr bool
 	}{
-		{path: "s", o: st, want: "abc"},
-		{path: "i", o: st, want: 13},
-		{path: "f", o: st, want: true},
-		{path: "nest.x", o: st, want: "xyz"},
-		{path: "nest_ptr.x", o: st, want: "yyy"},
+		{path: "s", o: st, want: []interface{}{"abc"}},
+		{path: "i", o: st, want: []interface{}{int64(13)}},
+		{path: "f", o: st, want: []interface{}{true}},
+		{path: "nest.x", o: st, want: []interface{}{"xyz"}},
+		{path: "nest_ptr.x", o: st, want: []interface{}{"yyy"}},
+		{path: "i64", o: st, want: []interface{}{int64(-64)}},
+		{path: "u64", o: st, want: []interface{}{uint64(1 << 63)}},
+		{path: "f64", o: st, want: []interface{}{0.5}},
+		{path: "strs", o: st, want: []interface{}{"a", "b"}},
+		{path: "nests.x", o: st, want: []interface{}{"n1", "n2"}},
+		{path: "nests.nest2.y", o: st, want: []interface{}{"", "y2"}},
+		{path: "labels.env", o: st, want: []interface{}{"prod"}},
+		// Unset fields have no values.
+		{path: "bool_ptr", o: st},
+		{path: "labels.zone", o: st},
+		{path: "nest.x", o: &struct{ Nest *nest }{}},
 		// Error cases.
 		{path: "", o: st, wantErr: true},
 		{path: "no_such_field", o: st, wantErr: true},
 		{path: "s.invalid_type", o: st, wantErr: true},
 		{path: "unhandled", o: st, wantErr: true},
-		{path: "nest.x", o: &struct{ Nest *nest }{}, wantErr: true},
+		{path: "m.1", o: &struct{ M map[int]string }{map[int]string{1: "a"}}, wantErr: true},
 	} {
-		o, err := extractValue(tc.path, tc.o)
+		values, err := extractValues(tc.path, tc.o)
 		gotErr := err != nil
 		if gotErr != tc.wantErr {
-			t.Errorf("extractValue(%v, %+v) = %v, %v; gotErr = %v, tc.wantErr = %v", tc.path, tc.o, o, err, gotErr, tc.wantErr)
+			t.Errorf("extractValues(%v, %+v) = %v, %v; gotErr = %v, tc.wantErr = %v", tc.path, tc.o, values, err, gotErr, tc.wantErr)
 		}
 		if err != nil {
 			continue
 		}
-		if !reflect.DeepEqual(o, tc.want) {
-			t.Errorf("extractValue(%v, %+v) = %v, nil; want %v, nil", tc.path, tc.o, o, tc.want)
+		if !reflect.DeepEqual(values, tc.want) {
+			t.Errorf("extractValues(%v, %+v) = %v, nil; want %v, nil", tc.path, tc.o, values, tc.want)
 		}
 	}
 }
+
+func TestFilterRegexpCache(t *testing.T) {
+	t.Parallel()
+
+	re1, err := compileRegexp("abc.*")
+	if err != nil {
+		t.Fatalf("compileRegexp() = _, %v, want nil", err)
+	}
+	re2, _ := compileRegexp("abc.*")
+	if re1 != re2 {
+		t.Errorf("compileRegexp() did not return the cached regexp")
+	}
+	if _, err := compileRegexp("a((("); err == nil {
+		t.Errorf("compileRegexp(%q) = _, nil, want error", "a(((")
+	}
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"reflect"
+	"testing"
+)
+
+// validFieldName is true if s can be used as a field name in a filter.
+func validFieldName(s string) bool {
+	if s == "" || s == "AND" || s == "OR" || s == "NOT" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if !isFieldChar(s[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// FuzzStringRoundTrip checks that filters built with arbitrary values are
+// serialized by String() to a filter that parses back to the same
+// predicates.
+func FuzzStringRoundTrip(f *testing.F) {
+	for _, v := range []string{
+		"", "abc", "a b", "a(b", "a)b", `a"b`, `a\`, `a\)`, "13", "true", "-4",
+		" lead", "trail ", "x OR y", "NOT", `"quoted"`, "tab\there", "new\nline",
+		"a) (b eq c", `\"`, "my-cluster.1",
+	} {
+		f.Add("name", v, uint8(0))
+	}
+	f.Fuzz(func(t *testing.T, field, v string, kind uint8) {
+		if !validFieldName(field) {
+			t.Skip()
+		}
+		var fl *F
+		switch kind % 10 {
+		case 0:
+			fl = Regexp(field, v)
+		case 1:
+			fl = NotRegexp(field, v)
+		case 2:
+			fl = Exact(field, v)
+		case 3:
+			fl = CompareString(field, LessThan, v)
+		case 4:
+			fl = LabelEquals(field, v)
+		case 5:
+			fl = HasLabel(field).AndNotExact(field, v)
+		case 6:
+			fl = Or(Regexp(field, v), NotRegexp(field, v+v))
+		case 7:
+			fl = Not(Regexp(field, v).AndExact(field, v))
+		case 8:
+			fl = HasLabel(field).And(Or(Exact(field, v), Not(LabelEquals(field, v))))
+		case 9:
+			fl = (&F{}).AndCompareString(field, GreaterThanOrEqual, v).Or(EqualInt(field, len(v)))
+		}
+		s := fl.String()
+		got, err := Parse(s)
+		if err != nil {
+			t.Fatalf("Parse(%q) = _, %v; want nil", s, err)
+		}
+		if !reflect.DeepEqual(got, fl) {
+			t.Fatalf("Parse(%q) = %v, want %v", s, got, fl)
+		}
+	})
+}
+
+// FuzzParse checks that Parse() does not panic and that any filter it accepts
+// round trips through String().
+func FuzzParse(f *testing.F) {
+	for _, s := range []string{
+		"",
+		"name eq abc.*",
+		"(name eq (a|b)) (zone ne us-.*)",
+		`name eq "a b"`,
+		`labels.env = "prod"`,
+		"labels.env:*",
+		"(cpus < 4) OR (NOT (cpus >= 8))",
+		`x:abc AND y != "q\"q"`,
+		"NOT NOT a = true",
+		"((a:*) OR (b:*)) OR c:*",
+	} {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		fl, err := Parse(s)
+		if err != nil {
+			if _, ok := err.(*ParseError); !ok {
+				t.Fatalf("Parse(%q) = _, %v; want *ParseError", s, err)
+			}
+			return
+		}
+		s2 := fl.String()
+		got, err := Parse(s2)
+		if err != nil {
+			t.Fatalf("Parse(%q) = %v; Parse(%q) = _, %v; want nil", s, fl, s2, err)
+		}
+		if !reflect.DeepEqual(got, fl) {
+			t.Fatalf("Parse(%q) = %v; Parse(%q) = %v, want equal", s, fl, s2, got)
+		}
+	})
+}
@@ -0,0 +1,342 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseError is returned by Parse() for a malformed filter.
+type ParseError struct {
+	// Input is the string being parsed.
+	Input string
+	// Column is the 1-based byte offset in Input of the error.
+	Column int
+	// Msg describes the error.
+	Msg string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("filter %q: column %d: %s", e.Input, e.Column, e.Msg)
+}
+
+// Parse a filter in either the legacy ("name eq abc.*", "(a eq x) (b ne y)")
+// or the newer ("(labels.env = "prod") OR (NOT (cpu < 4))") syntax. The
+// empty string parses to a filter that matches everything. Parse(f.String())
+// returns a filter equal to f.
+//
+// The value of a legacy eq or ne predicate is a regular expression that
+// extends to the end of the enclosing parentheses (or the end of the input),
+// hence "name eq a OR b" compares name to "a OR b". Parenthesize the
+// operands of OR when mixing the two. It is a regular expression even if it
+// looks like a number or a bool, so "name eq 123" matches the name "123";
+// number and bool fields are matched in their formatted form.
+func Parse(s string) (*F, error) {
+	p := &parser{s: s}
+	p.skipSpace()
+	if p.done() {
+		return &F{}, nil
+	}
+	f, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if !p.done() {
+		return nil, p.errorf("unexpected %q", p.s[p.pos:])
+	}
+	return f, nil
+}
+
+// parser is a recursive descent parser for the grammar:
+//
+//  expr      = and { "OR" and }
+//  and       = unary { [ "AND" ] unary }
+//  unary     = "NOT" unary | "(" expr ")" | predicate
+//  predicate = field ( "eq" | "ne" ) regexp
+//            | field ( "=" | "!=" | "<" | "<=" | ">" | ">=" ) value
+//            | field ":" value
+type parser struct {
+	s   string
+	pos int
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return p.errorAt(p.pos, format, args...)
+}
+
+func (p *parser) errorAt(pos int, format string, args ...interface{}) error {
+	return &ParseError{Input: p.s, Column: pos + 1, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.s)
+}
+
+func (p *parser) peek() byte {
+	if p.done() {
+		return 0
+	}
+	return p.s[p.pos]
+}
+
+func (p *parser) skipSpace() {
+	for !p.done() && isSpace(p.s[p.pos]) {
+		p.pos++
+	}
+}
+
+// atKeyword is true if the keyword kw is next in the input.
+func (p *parser) atKeyword(kw string) bool {
+	if !strings.HasPrefix(p.s[p.pos:], kw) {
+		return false
+	}
+	end := p.pos + len(kw)
+	return end == len(p.s) || isSpace(p.s[end]) || p.s[end] == '('
+}
+
+// keyword consumes the keyword kw if it is next in the input.
+func (p *parser) keyword(kw string) bool {
+	if !p.atKeyword(kw) {
+		return false
+	}
+	p.pos += len(kw)
+	return true
+}
+
+func (p *parser) parseExpr() (*F, error) {
+	f, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	or := &orTerm{fs: []*F{f}}
+	for {
+		p.skipSpace()
+		if !p.keyword("OR") {
+			break
+		}
+		f, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		or.fs = append(or.fs, f)
+	}
+	if len(or.fs) == 1 {
+		return f, nil
+	}
+	return &F{terms: []term{or}}, nil
+}
+
+func (p *parser) parseAnd() (*F, error) {
+	var ret *F
+	for {
+		p.skipSpace()
+		if p.done() || p.peek() == ')' || p.atKeyword("OR") {
+			break
+		}
+		if ret != nil {
+			p.keyword("AND")
+			p.skipSpace()
+		}
+		f, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if ret == nil {
+			ret = f
+		} else {
+			ret = &F{terms: append(append([]term(nil), ret.terms...), f.terms...)}
+		}
+	}
+	if ret == nil {
+		return nil, p.errorf("expected an expression")
+	}
+	return ret, nil
+}
+
+func (p *parser) parseUnary() (*F, error) {
+	if p.keyword("NOT") {
+		p.skipSpace()
+		f, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return Not(f), nil
+	}
+	if p.peek() == '(' {
+		open := p.pos
+		p.pos++
+		p.skipSpace()
+		if p.peek() == ')' {
+			return nil, p.errorf("expected an expression")
+		}
+		f, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+		if p.peek() != ')' {
+			return nil, p.errorAt(open, "unbalanced '('")
+		}
+		p.pos++
+		return f, nil
+	}
+	fp, err := p.parsePredicate()
+	if err != nil {
+		return nil, err
+	}
+	return &F{terms: []term{fp}}, nil
+}
+
+func (p *parser) parsePredicate() (*filterPredicate, error) {
+	start := p.pos
+	for !p.done() && isFieldChar(p.s[p.pos]) {
+		p.pos++
+	}
+	if p.pos == start {
+		return nil, p.errorf("expected a field name")
+	}
+	fp := &filterPredicate{fieldName: p.s[start:p.pos]}
+	switch fp.fieldName {
+	case "AND", "OR", "NOT":
+		return nil, p.errorAt(start, "%s is a reserved word", fp.fieldName)
+	}
+
+	p.skipSpace()
+	opPos := p.pos
+	switch {
+	case p.keyword("eq"):
+		fp.op = equals
+	case p.keyword("ne"):
+		fp.op = notEquals
+	default:
+		// Longest match first.
+		for _, op := range []filterOp{notExactly, lessThanOrEqual, greaterThanOrEqual, exactly, lessThan, greaterThan, has} {
+			if strings.HasPrefix(p.s[p.pos:], opStrings[op]) {
+				fp.op = op
+				p.pos += len(opStrings[op])
+				break
+			}
+		}
+	}
+	if p.pos == opPos {
+		return nil, p.errorf("expected an operator after field %q", fp.fieldName)
+	}
+
+	p.skipSpace()
+	valuePos := p.pos
+	var (
+		v      string
+		quoted bool
+		err    error
+	)
+	switch {
+	case p.peek() == '"':
+		v, err = p.parseQuoted()
+		quoted = true
+	case fp.op == equals || fp.op == notEquals:
+		v = p.parseRegexp()
+	default:
+		for !p.done() && !isSpace(p.s[p.pos]) && p.s[p.pos] != ')' && p.s[p.pos] != '(' {
+			p.pos++
+		}
+		v = p.s[valuePos:p.pos]
+	}
+	if err != nil {
+		return nil, err
+	}
+	if v == "" && !quoted {
+		return nil, p.errorAt(valuePos, "expected a value for field %q", fp.fieldName)
+	}
+
+	// The values of eq and ne are regexps, even if they look like numbers
+	// or bools (e.g. "name eq 123"); those match the formatted value of
+	// number and bool fields.
+	switch {
+	case quoted || fp.op == has || fp.op == equals || fp.op == notEquals:
+		fp.s = &v
+	case v == "true" || v == "false":
+		b := v == "true"
+		fp.b = &b
+	default:
+		if i, err := strconv.Atoi(v); err == nil {
+			fp.i = &i
+		} else {
+			fp.s = &v
+		}
+	}
+	return fp, nil
+}
+
+// parseQuoted parses a double quoted string. Backslash escapes the following
+// character.
+func (p *parser) parseQuoted() (string, error) {
+	open := p.pos
+	p.pos++
+	var b strings.Builder
+	for !p.done() {
+		c := p.s[p.pos]
+		p.pos++
+		switch c {
+		case '"':
+			return b.String(), nil
+		case '\\':
+			if p.done() {
+				return "", p.errorAt(p.pos-1, "unterminated escape")
+			}
+			b.WriteByte(p.s[p.pos])
+			p.pos++
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", p.errorAt(open, "unterminated string")
+}
+
+// parseRegexp parses the unquoted value of a legacy predicate. This extends
+// until the closing parenthesis of the enclosing expression or the end of
+// input. Parentheses within the regexp must be balanced or escaped.
+func (p *parser) parseRegexp() string {
+	start := p.pos
+	depth := 0
+	for ; !p.done(); p.pos++ {
+		switch p.s[p.pos] {
+		case '\\':
+			if p.pos+1 < len(p.s) {
+				p.pos++
+			}
+		case '(':
+			depth++
+		case ')':
+			if depth == 0 {
+				return strings.TrimRight(p.s[start:p.pos], " \t\n")
+			}
+			depth--
+		}
+	}
+	return strings.TrimRight(p.s[start:], " \t\n")
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n'
+}
+
+func isFieldChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-' || c == '.'
+}
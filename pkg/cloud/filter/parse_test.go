@@ -0,0 +1,191 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"reflect"
+	"testing"
+
+	ga "google.golang.org/api/compute/v1"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		s    string
+		want *F
+	}{
+		{"", &F{}},
+		{"  ", &F{}},
+		{"name eq abc.*", Regexp("name", "abc.*")},
+		{"name ne abc", NotRegexp("name", "abc")},
+		{"name eq (a|b)-[0-9]+", Regexp("name", "(a|b)-[0-9]+")},
+		{`name eq a\)b`, Regexp("name", `a\)b`)},
+		{`name eq "a b"`, Regexp("name", "a b")},
+		{"name eq a OR b", Regexp("name", "a OR b")},
+		{"size eq 10", Regexp("size", "10")},
+		{"size eq 10", EqualInt("size", 10)},
+		{"auto ne false", NotEqualBool("auto", false)},
+		{"name eq 123", Regexp("name", "123")},
+		{"auto ne true", NotRegexp("auto", "true")},
+		{"(name eq abc) (size ne 10)", Regexp("name", "abc").AndNotRegexp("size", "10")},
+		{"(name eq (a|b)) (zone eq us-.*)", Regexp("name", "(a|b)").AndRegexp("zone", "us-.*")},
+		{"(name eq abc)AND(size ne 10)", Regexp("name", "abc").AndNotRegexp("size", "10")},
+		{`labels.env = "prod"`, LabelEquals("env", "prod")},
+		{`labels.env="prod"`, LabelEquals("env", "prod")},
+		{`labels.env = prod`, LabelEquals("env", "prod")},
+		{`labels.env:*`, HasLabel("env")},
+		{`labels.env : *`, HasLabel("env")},
+		{"cpus < 4", CompareInt("cpus", LessThan, 4)},
+		{"cpus <= -4", CompareInt("cpus", LessThanOrEqual, -4)},
+		{`creationTimestamp > "2018-01-01T00:00:00Z"`, CompareString("creationTimestamp", GreaterThan, "2018-01-01T00:00:00Z")},
+		{`labels.env = "a\"b\\c"`, LabelEquals("env", `a"b\c`)},
+		{
+			`(labels.env = "a") OR (labels.env = "b") OR labels.x:*`,
+			Or(LabelEquals("env", "a"), LabelEquals("env", "b"), HasLabel("x")),
+		},
+		{
+			`labels.a:* labels.b:* OR labels.c:*`,
+			Or(HasLabel("a").AndHasLabel("b"), HasLabel("c")),
+		},
+		{
+			`labels.a:* AND labels.b:*`,
+			HasLabel("a").AndHasLabel("b"),
+		},
+		{"NOT labels.env:*", Not(HasLabel("env"))},
+		{"NOT NOT labels.env:*", Not(Not(HasLabel("env")))},
+		{"NOT(labels.env:*)", Not(HasLabel("env"))},
+		{"NOTE:*", (&F{}).And(&F{terms: []term{&filterPredicate{fieldName: "NOTE", op: has, s: strPtr("*")}}})},
+		{
+			`(labels.a:*) ((x < 1) OR (NOT (y > 2)))`,
+			HasLabel("a").And(Or(CompareInt("x", LessThan, 1), Not(CompareInt("y", GreaterThan, 2)))),
+		},
+	} {
+		got, err := Parse(tc.s)
+		if err != nil {
+			t.Errorf("Parse(%q) = _, %v, want nil", tc.s, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("Parse(%q) = %v, want %v", tc.s, got, tc.want)
+		}
+	}
+}
+
+func TestParseRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for _, f := range []*F{
+		&F{},
+		Regexp("name", "abc.*"),
+		NotRegexp("name", "(a|b)"),
+		Regexp("size", "10"),
+		NotRegexp("auto", "false"),
+		Regexp("name", "abc").AndNotRegexp("size", "10").AndRegexp("auto", "true"),
+		EqualInt("size", 10),
+		NotEqualInt("size", -10),
+		EqualBool("auto", true),
+		NotEqualBool("auto", false),
+		Regexp("name", "abc").AndNotEqualInt("size", 10).AndEqualBool("auto", true),
+		CompareInt("cpus", GreaterThanOrEqual, 2),
+		CompareString("ts", LessThan, `odd "value" \ here`),
+		HasLabel("env"),
+		LabelEquals("env", "prod"),
+		Or(LabelEquals("env", "a"), HasLabel("b").AndHasLabel("c")),
+		Or(Or(HasLabel("a"), HasLabel("b")), HasLabel("c")),
+		HasLabel("a").And(Or(CompareInt("x", LessThan, 1), Not(CompareInt("y", GreaterThan, 2)))),
+		Not(HasLabel("a").AndHasLabel("b")),
+		HasLabel("a").And(Not(Not(HasLabel("b")))),
+	} {
+		got, err := Parse(f.String())
+		if err != nil {
+			t.Errorf("Parse(%q) = _, %v, want nil", f.String(), err)
+			continue
+		}
+		if !reflect.DeepEqual(got, f) {
+			t.Errorf("Parse(%q) = %v, want %v", f.String(), got, f)
+		}
+	}
+}
+
+func TestParseError(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		s       string
+		wantCol int
+	}{
+		{"name", 5},
+		{"name ~ x", 6},
+		{"name eq", 8},
+		{"name eq ", 9},
+		{"= x", 1},
+		{"(name eq x", 1},
+		{"(a = 1) (b = 2", 9},
+		{"a = 1)", 6},
+		{"()", 2},
+		{`a = "abc`, 5},
+		{`a = "abc\`, 9},
+		{"a = 1 OR", 9},
+		{"NOT", 4},
+		{"(a = 1) OR OR (b = 2)", 12},
+		{"labels.env:", 12},
+	} {
+		_, err := Parse(tc.s)
+		pe, ok := err.(*ParseError)
+		if !ok {
+			t.Errorf("Parse(%q) = _, %v, want *ParseError", tc.s, err)
+			continue
+		}
+		if pe.Column != tc.wantCol || pe.Input != tc.s {
+			t.Errorf("Parse(%q) = _, %v; want error at column %d", tc.s, err, tc.wantCol)
+		}
+	}
+}
+
+func TestParseNumericValues(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		s    string
+		obj  *ga.Instance
+		want bool
+	}{
+		{"name eq 123", &ga.Instance{Name: "123"}, true},
+		{"name eq 123", &ga.Instance{Name: "1234"}, false},
+		{"name ne 123", &ga.Instance{Name: "124"}, true},
+		{"name eq true", &ga.Instance{Name: "true"}, true},
+		{"id eq 12.*", &ga.Instance{Id: 1234}, true},
+		{"canIpForward eq true", &ga.Instance{CanIpForward: true}, true},
+		{"canIpForward ne true", &ga.Instance{CanIpForward: true}, false},
+	} {
+		f, err := Parse(tc.s)
+		if err != nil {
+			t.Errorf("Parse(%q) = _, %v, want nil", tc.s, err)
+			continue
+		}
+		if err := Validate(f, reflect.TypeOf(tc.obj)); err != nil {
+			t.Errorf("Validate(Parse(%q)) = %v, want nil", tc.s, err)
+		}
+		if got := f.Match(tc.obj); got != tc.want {
+			t.Errorf("Parse(%q).Match(%+v) = %t, want %t", tc.s, tc.obj, got, tc.want)
+		}
+	}
+}
+
+func strPtr(s string) *string { return &s }
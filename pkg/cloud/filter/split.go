@@ -0,0 +1,133 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Split fl into a server part that can be sent in the filter argument of a
+// List() call and a client part that must be applied to the results with
+// Match(). The two parts ANDed together are equivalent to fl. Either part is
+// None if it is empty.
+//
+// objType is the type of the objects listed (e.g. reflect.TypeOf(ga.Firewall{}))
+// and may be nil if it is not known. The client part holds the terms that the
+// compute API rejects or evaluates differently from Match():
+//
+//  - predicates on fields that are repeated or nested in a repeated field, or
+//    that are not fields of objType.
+//  - ":" with a value other than "*".
+//  - regexps (eq, ne) within OR and NOT.
+//  - terms in a different syntax from the first server term, as the compute
+//    API does not accept regexps mixed with the newer syntax.
+func (fl *F) Split(objType reflect.Type) (server, client *F) {
+	if fl == nil {
+		return None, None
+	}
+	server, client = &F{}, &F{}
+	var serverLegacy bool
+	for _, t := range fl.terms {
+		switch {
+		case !pushable(t, objType, false):
+			client.terms = append(client.terms, t)
+		case len(server.terms) == 0:
+			serverLegacy = isLegacy(t)
+			server.terms = append(server.terms, t)
+		case isLegacy(t) == serverLegacy:
+			server.terms = append(server.terms, t)
+		default:
+			client.terms = append(client.terms, t)
+		}
+	}
+	if len(server.terms) == 0 {
+		server = None
+	}
+	if len(client.terms) == 0 {
+		client = None
+	}
+	return server, client
+}
+
+// isLegacy is true if t is in the legacy (eq, ne) syntax.
+func isLegacy(t term) bool {
+	fp, ok := t.(*filterPredicate)
+	return ok && (fp.op == equals || fp.op == notEquals)
+}
+
+// pushable is true if t can be evaluated by the server. inGroup is true if t
+// is within an OR or NOT.
+func pushable(t term, objType reflect.Type, inGroup bool) bool {
+	var fs []*F
+	switch t := t.(type) {
+	case *filterPredicate:
+		if inGroup && isLegacy(t) {
+			return false
+		}
+		if t.op == has && (t.s == nil || *t.s != "*") {
+			return false
+		}
+		return objType == nil || !hasRepeatedField(t.fieldName, objType)
+	case *orTerm:
+		fs = t.fs
+	case *notTerm:
+		fs = []*F{t.f}
+	default:
+		return false
+	}
+	for _, f := range fs {
+		if f == nil {
+			return false
+		}
+		for _, sub := range f.terms {
+			if !pushable(sub, objType, true) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// hasRepeatedField is true if a field along the JSON path in t is repeated.
+// Invalid paths are considered to be repeated so that they are handled by
+// Match().
+func hasRepeatedField(path string, t reflect.Type) bool {
+	for _, name := range strings.Split(path, ".") {
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		switch t.Kind() {
+		case reflect.Slice, reflect.Array:
+			return true
+		case reflect.Map:
+			t = t.Elem()
+		case reflect.Struct:
+			f, ok := fieldByJSONName(t, name)
+			if !ok {
+				return true
+			}
+			t = f.Type
+		default:
+			return true
+		}
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Slice || t.Kind() == reflect.Array
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"reflect"
+	"testing"
+
+	ga "google.golang.org/api/compute/v1"
+)
+
+func TestSplit(t *testing.T) {
+	t.Parallel()
+
+	instance := reflect.TypeOf(ga.Instance{})
+	for _, tc := range []struct {
+		desc       string
+		f          *F
+		objType    reflect.Type
+		wantServer *F
+		wantClient *F
+	}{
+		{
+			desc: "nil",
+		},
+		{
+			desc:       "all server",
+			f:          Regexp("name", "a.*").AndEqualBool("canIpForward", true),
+			objType:    instance,
+			wantServer: Regexp("name", "a.*").AndEqualBool("canIpForward", true),
+		},
+		{
+			desc:       "new syntax with groups",
+			f:          HasLabel("env").And(Or(LabelEquals("env", "a"), Not(CompareInt("id", LessThan, 3)))),
+			objType:    instance,
+			wantServer: HasLabel("env").And(Or(LabelEquals("env", "a"), Not(CompareInt("id", LessThan, 3)))),
+		},
+		{
+			desc:       "repeated field",
+			f:          Regexp("name", "a.*").AndRegexp("networkInterfaces.network", ".*default"),
+			objType:    instance,
+			wantServer: Regexp("name", "a.*"),
+			wantClient: Regexp("networkInterfaces.network", ".*default"),
+		},
+		{
+			desc:       "repeated field without type",
+			f:          Regexp("name", "a.*").AndRegexp("networkInterfaces.network", ".*default"),
+			wantServer: Regexp("name", "a.*").AndRegexp("networkInterfaces.network", ".*default"),
+		},
+		{
+			desc:       "repeated leaf",
+			f:          Regexp("tags.items", "web").AndRegexp("name", "a"),
+			objType:    instance,
+			wantServer: Regexp("name", "a"),
+			wantClient: Regexp("tags.items", "web"),
+		},
+		{
+			desc:       "unknown field",
+			f:          Regexp("nmae", "a"),
+			objType:    instance,
+			wantClient: Regexp("nmae", "a"),
+		},
+		{
+			desc:       "mixed syntax",
+			f:          LabelEquals("env", "prod").AndRegexp("name", "a.*").AndHasLabel("x"),
+			objType:    instance,
+			wantServer: LabelEquals("env", "prod").AndHasLabel("x"),
+			wantClient: Regexp("name", "a.*"),
+		},
+		{
+			desc:       "mixed syntax, legacy first",
+			f:          Regexp("name", "a.*").AndLabelEquals("env", "prod"),
+			objType:    instance,
+			wantServer: Regexp("name", "a.*"),
+			wantClient: LabelEquals("env", "prod"),
+		},
+		{
+			desc:       "regexp in OR",
+			f:          Or(Regexp("name", "a.*"), LabelEquals("env", "prod")).AndHasLabel("x"),
+			objType:    instance,
+			wantServer: HasLabel("x"),
+			wantClient: Or(Regexp("name", "a.*"), LabelEquals("env", "prod")),
+		},
+		{
+			desc:       "repeated field in NOT",
+			f:          Not(Exact("disks.deviceName", "d")),
+			objType:    instance,
+			wantClient: Not(Exact("disks.deviceName", "d")),
+		},
+		{
+			desc:       "has with a literal",
+			f:          (&F{}).And(&F{terms: []term{&filterPredicate{fieldName: "name", op: has, s: strPtr("abc")}}}).AndHasLabel("x"),
+			objType:    instance,
+			wantServer: HasLabel("x"),
+			wantClient: &F{terms: []term{&filterPredicate{fieldName: "name", op: has, s: strPtr("abc")}}},
+		},
+	} {
+		server, client := tc.f.Split(tc.objType)
+		if !reflect.DeepEqual(server, tc.wantServer) || !reflect.DeepEqual(client, tc.wantClient) {
+			t.Errorf("%s: (%v).Split() = %v, %v; want %v, %v", tc.desc, tc.f, server, client, tc.wantServer, tc.wantClient)
+		}
+	}
+}
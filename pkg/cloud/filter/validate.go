@@ -0,0 +1,190 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// Validate checks that f can be applied to objects of type objType (e.g.
+// reflect.TypeOf(ga.Firewall{})). Field paths are given by the JSON names of
+// the fields (e.g. "scheduling.automaticRestart"). Validate checks that:
+//
+//  - each field in the path exists. Map fields (e.g. labels) take any key.
+//    Repeated fields are checked against the type of their elements.
+//  - the type of the value in the predicate matches the kind of the field.
+//    Enum fields are strings in the API.
+//  - regexps given to eq and ne compile.
+//
+// A nil filter is always valid.
+func Validate(f *F, objType reflect.Type) error {
+	if f == nil {
+		return nil
+	}
+	for _, t := range f.terms {
+		var err error
+		switch t := t.(type) {
+		case *filterPredicate:
+			err = validatePredicate(t, objType)
+		case *orTerm:
+			for _, sub := range t.fs {
+				if err = Validate(sub, objType); err != nil {
+					break
+				}
+			}
+		case *notTerm:
+			err = Validate(t.f, objType)
+		default:
+			err = fmt.Errorf("filter: unknown term %T", t)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validatePredicate(fp *filterPredicate, objType reflect.Type) error {
+	fieldType, err := fieldTypeOf(fp.fieldName, objType)
+	if err != nil {
+		return err
+	}
+	kind := fieldType.Kind()
+
+	errorf := func(format string, args ...interface{}) error {
+		return fmt.Errorf("filter: %q: %s", fp.String(), fmt.Sprintf(format, args...))
+	}
+
+	if fp.op == has {
+		if fp.s != nil && *fp.s == "*" {
+			return nil
+		}
+		if kind != reflect.String {
+			return errorf("field %q is %v, \":\" with a value needs a string field", fp.fieldName, fieldType)
+		}
+		return nil
+	}
+
+	switch {
+	case fp.s != nil && (fp.op == equals || fp.op == notEquals):
+		if kind != reflect.String && kind != reflect.Bool && !isNumberKind(kind) {
+			return errorf("field %q is %v, not a string, number or bool", fp.fieldName, fieldType)
+		}
+		if _, err := regexp.Compile(*fp.s); err != nil {
+			return errorf("invalid regexp: %v", err)
+		}
+	case fp.s != nil:
+		if kind != reflect.String {
+			return errorf("field %q is %v, not a string", fp.fieldName, fieldType)
+		}
+	case fp.i != nil:
+		if !isNumberKind(kind) {
+			return errorf("field %q is %v, not a number", fp.fieldName, fieldType)
+		}
+	case fp.b != nil:
+		if kind != reflect.Bool {
+			return errorf("field %q is %v, not a bool", fp.fieldName, fieldType)
+		}
+		if fp.op != equals && fp.op != notEquals && fp.op != exactly && fp.op != notExactly {
+			return errorf("bool field %q cannot be compared with %q", fp.fieldName, opStrings[fp.op])
+		}
+	default:
+		return errorf("predicate has no value")
+	}
+	return nil
+}
+
+// fieldTypeOf returns the type of the field named by the JSON path in t.
+// Pointers and repeated fields are replaced by the type of their elements.
+func fieldTypeOf(path string, t reflect.Type) (reflect.Type, error) {
+	root := t
+	for _, name := range strings.Split(path, ".") {
+		t = elemType(t)
+		switch t.Kind() {
+		case reflect.Map:
+			if t.Key().Kind() != reflect.String {
+				return nil, fmt.Errorf("filter: field path %q: %v does not have string keys", path, t)
+			}
+			t = t.Elem()
+		case reflect.Struct:
+			f, ok := fieldByJSONName(t, name)
+			if !ok {
+				return nil, fmt.Errorf("filter: field path %q: %q is not a field of %v%s", path, name, t, suggest(t, name))
+			}
+			t = f.Type
+		default:
+			return nil, fmt.Errorf("filter: field path %q: cannot get field %q of %v in %v", path, name, t, root)
+		}
+	}
+	return elemType(t), nil
+}
+
+// elemType removes pointers and slices from t.
+func elemType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	return t
+}
+
+// jsonName returns the name of the field in the JSON encoding or "" if the
+// field is not encoded.
+func jsonName(f reflect.StructField) string {
+	if f.PkgPath != "" {
+		return ""
+	}
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return ""
+	}
+	if name := strings.Split(tag, ",")[0]; name != "" {
+		return name
+	}
+	return f.Name
+}
+
+func fieldByJSONName(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		if f := t.Field(i); jsonName(f) == name {
+			return f, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// suggest a field of t with a similar name to name.
+func suggest(t reflect.Type, name string) string {
+	for i := 0; i < t.NumField(); i++ {
+		if n := jsonName(t.Field(i)); n != "" && strings.EqualFold(n, strings.Replace(name, "_", "", -1)) {
+			return fmt.Sprintf(" (did you mean %q?)", n)
+		}
+	}
+	return ""
+}
+
+func isNumberKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
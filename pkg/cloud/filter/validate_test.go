@@ -0,0 +1,80 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	ga "google.golang.org/api/compute/v1"
+)
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	instance := reflect.TypeOf(ga.Instance{})
+	for _, tc := range []struct {
+		f       *F
+		objType reflect.Type
+		// wantErr is a substring of the expected error, "" if there should
+		// be no error.
+		wantErr string
+	}{
+		{f: None, objType: instance},
+		{f: &F{}, objType: instance},
+		{f: Regexp("name", "abc.*"), objType: instance},
+		{f: Regexp("name", "abc.*"), objType: reflect.TypeOf(&ga.Instance{})},
+		{f: Regexp("status", "RUNNING"), objType: instance},
+		{f: EqualInt("id", 123), objType: instance},
+		{f: EqualBool("canIpForward", true), objType: instance},
+		{f: EqualBool("scheduling.automaticRestart", true), objType: instance},
+		{f: EqualBool("scheduling.preemptible", false), objType: instance},
+		{f: Regexp("networkInterfaces.network", ".*default"), objType: instance},
+		{f: Regexp("metadata.items.key", "startup-script"), objType: instance},
+		{f: HasLabel("env").AndLabelEquals("team", "x"), objType: instance},
+		{f: CompareString("creationTimestamp", GreaterThan, "2018-01-01"), objType: instance},
+		{f: CompareInt("id", LessThan, 4), objType: instance},
+		{f: HasLabel("env"), objType: instance},
+		{f: Or(Regexp("name", "a"), Not(EqualBool("canIpForward", true))), objType: instance},
+		{f: Regexp("name", "123"), objType: instance},
+		{f: Regexp("id", "12.*"), objType: instance},
+		{f: NotRegexp("canIpForward", "true"), objType: instance},
+		// Error cases.
+		{f: Regexp("nmae", "abc"), objType: instance, wantErr: `"nmae" is not a field of compute.Instance`},
+		{f: Regexp("Name", "abc"), objType: instance, wantErr: `did you mean "name"`},
+		{f: Regexp("can_ip_forward", "abc"), objType: instance, wantErr: `did you mean "canIpForward"`},
+		{f: Regexp("scheduling.automatic_restart", "abc"), objType: instance, wantErr: "compute.Scheduling"},
+		{f: Regexp("name.x", "abc"), objType: instance, wantErr: `cannot get field "x" of string`},
+		{f: Regexp("name", "a((("), objType: instance, wantErr: "invalid regexp"},
+		{f: CompareInt("name", LessThan, 1), objType: instance, wantErr: "not a number"},
+		{f: &F{terms: []term{&filterPredicate{fieldName: "name", op: exactly, b: new(bool)}}}, objType: instance, wantErr: "not a bool"},
+		{f: LabelEquals("env", "prod").AndRegexp("scheduling", "x"), objType: instance, wantErr: "not a string"},
+		{f: &F{terms: []term{&filterPredicate{fieldName: "canIpForward", op: lessThan, b: new(bool)}}}, objType: instance, wantErr: "cannot be compared"},
+		{f: Or(Regexp("name", "a"), Regexp("nmae", "b")), objType: instance, wantErr: "nmae"},
+		{f: Not(Regexp("nmae", "b")), objType: instance, wantErr: "nmae"},
+		{f: (&F{}).AndRegexp("metadata.items", "x"), objType: instance, wantErr: "not a string"},
+	} {
+		err := Validate(tc.f, tc.objType)
+		switch {
+		case tc.wantErr == "" && err != nil:
+			t.Errorf("Validate(%v, %v) = %v, want nil", tc.f, tc.objType, err)
+		case tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)):
+			t.Errorf("Validate(%v, %v) = %v, want error containing %q", tc.f, tc.objType, err, tc.wantErr)
+		}
+	}
+}
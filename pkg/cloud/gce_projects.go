@@ -37,7 +37,18 @@ type MockProjectOpsState struct {
 	metadata map[string]*compute.Metadata
 }
 
-func (m *MockProjects) Get(ctx context.Context, projectID string) (*compute.Project, error) {
+func (m *MockProjects) Get(ctx context.Context, projectID string) (_ *compute.Project, err error) {
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("ga"),
+			Service:   "Projects",
+		},
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
@@ -50,14 +61,18 @@ func (m *MockProjects) Get(ctx context.Context, projectID string) (*compute.Proj
 	}
 }
 
-func (g *GCEProjects) Get(ctx context.Context, projectID string) (*compute.Project, error) {
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Get",
-		Version:   meta.Version("ga"),
-		Service:   "Projects",
+func (g *GCEProjects) Get(ctx context.Context, projectID string) (_ *compute.Project, err error) {
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("ga"),
+			Service:   "Projects",
+		},
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.GA.Projects.Get(projectID)
@@ -65,25 +80,48 @@ func (g *GCEProjects) Get(ctx context.Context, projectID string) (*compute.Proje
 	return call.Do()
 }
 
-func (m *MockProjects) SetCommonInstanceMetadata(ctx context.Context, projectID string, meta *compute.Metadata) error {
+func (m *MockProjects) SetCommonInstanceMetadata(ctx context.Context, projectID string, metadata *compute.Metadata) (err error) {
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "SetCommonInstanceMetadata",
+			Version:   meta.Version("ga"),
+			Service:   "Projects",
+		},
+		Request: metadata,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
 	if m.X == nil {
 		m.X = &MockProjectOpsState{metadata: map[string]*compute.Metadata{}}
 	}
 	state := m.X.(*MockProjectOpsState)
-	state.metadata[projectID] = meta
+	state.metadata[projectID] = metadata
 	return nil
 }
 
-func (g *GCEProjects) SetCommonInstanceMetadata(ctx context.Context, projectID string, m *compute.Metadata) error {
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "SetCommonInstanceMetadata",
-		Version:   meta.Version("ga"),
-		Service:   "Projects",
+func (g *GCEProjects) SetCommonInstanceMetadata(ctx context.Context, projectID string, m *compute.Metadata) (err error) {
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "SetCommonInstanceMetadata",
+			Version:   meta.Version("ga"),
+			Service:   "Projects",
+		},
+		Request: m,
+		Mutates: true,
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.GA.Projects.SetCommonInstanceMetadata(projectID, m)
 	call.Context(ctx)
 
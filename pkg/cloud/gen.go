@@ -23,6 +23,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"reflect"
 	"sync"
 
 	"github.com/golang/glog"
@@ -247,8 +248,8 @@ func (gce *GCE) Zones() Zones {
 	return gce.gceZones
 }
 
-// NewMockGCE returns a new mock for GCE.
-func NewMockGCE() *MockGCE {
+// NewMockGCE returns a new mock for GCE, configured with opts.
+func NewMockGCE(opts ...MockOption) *MockGCE {
 	mockAddressesObjs := map[meta.Key]*MockAddressesObj{}
 	mockBackendServicesObjs := map[meta.Key]*MockBackendServicesObj{}
 	mockDisksObjs := map[meta.Key]*MockDisksObj{}
@@ -308,6 +309,9 @@ func NewMockGCE() *MockGCE {
 		MockUrlMaps:                    NewMockUrlMaps(mockUrlMapsObjs),
 		MockZones:                      NewMockZones(mockZonesObjs),
 	}
+	for _, opt := range opts {
+		opt(mock)
+	}
 	return mock
 }
 
@@ -348,6 +352,9 @@ type MockGCE struct {
 	MockTargetPools                *MockTargetPools
 	MockUrlMaps                    *MockUrlMaps
 	MockZones                      *MockZones
+
+	// informers created for the mock, see PollInformers().
+	informers informerSet
 }
 
 func (mock *MockGCE) Addresses() Addresses {
@@ -478,6 +485,274 @@ func (mock *MockGCE) Zones() Zones {
 	return mock.MockZones
 }
 
+// SetObserver sets the CallObserver for all of the services in the mock.
+func (mock *MockGCE) SetObserver(o CallObserver) {
+	mock.MockAddresses.Observer = o
+	mock.MockAlphaAddresses.Observer = o
+	mock.MockBetaAddresses.Observer = o
+	mock.MockGlobalAddresses.Observer = o
+	mock.MockBackendServices.Observer = o
+	mock.MockAlphaBackendServices.Observer = o
+	mock.MockAlphaRegionBackendServices.Observer = o
+	mock.MockDisks.Observer = o
+	mock.MockAlphaDisks.Observer = o
+	mock.MockAlphaRegionDisks.Observer = o
+	mock.MockFirewalls.Observer = o
+	mock.MockForwardingRules.Observer = o
+	mock.MockAlphaForwardingRules.Observer = o
+	mock.MockGlobalForwardingRules.Observer = o
+	mock.MockHealthChecks.Observer = o
+	mock.MockAlphaHealthChecks.Observer = o
+	mock.MockHttpHealthChecks.Observer = o
+	mock.MockHttpsHealthChecks.Observer = o
+	mock.MockInstanceGroups.Observer = o
+	mock.MockInstances.Observer = o
+	mock.MockBetaInstances.Observer = o
+	mock.MockAlphaInstances.Observer = o
+	mock.MockAlphaNetworkEndpointGroups.Observer = o
+	mock.MockProjects.Observer = o
+	mock.MockRegions.Observer = o
+	mock.MockRoutes.Observer = o
+	mock.MockSslCertificates.Observer = o
+	mock.MockTargetHttpProxies.Observer = o
+	mock.MockTargetHttpsProxies.Observer = o
+	mock.MockTargetPools.Observer = o
+	mock.MockUrlMaps.Observer = o
+	mock.MockZones.Observer = o
+}
+
+// SetProjectRouter sets the ProjectRouter for all of the services in the
+// mock.
+func (mock *MockGCE) SetProjectRouter(r ProjectRouter) {
+	mock.MockAddresses.ProjectRouter = r
+	mock.MockAlphaAddresses.ProjectRouter = r
+	mock.MockBetaAddresses.ProjectRouter = r
+	mock.MockGlobalAddresses.ProjectRouter = r
+	mock.MockBackendServices.ProjectRouter = r
+	mock.MockAlphaBackendServices.ProjectRouter = r
+	mock.MockAlphaRegionBackendServices.ProjectRouter = r
+	mock.MockDisks.ProjectRouter = r
+	mock.MockAlphaDisks.ProjectRouter = r
+	mock.MockAlphaRegionDisks.ProjectRouter = r
+	mock.MockFirewalls.ProjectRouter = r
+	mock.MockForwardingRules.ProjectRouter = r
+	mock.MockAlphaForwardingRules.ProjectRouter = r
+	mock.MockGlobalForwardingRules.ProjectRouter = r
+	mock.MockHealthChecks.ProjectRouter = r
+	mock.MockAlphaHealthChecks.ProjectRouter = r
+	mock.MockHttpHealthChecks.ProjectRouter = r
+	mock.MockHttpsHealthChecks.ProjectRouter = r
+	mock.MockInstanceGroups.ProjectRouter = r
+	mock.MockInstances.ProjectRouter = r
+	mock.MockBetaInstances.ProjectRouter = r
+	mock.MockAlphaInstances.ProjectRouter = r
+	mock.MockAlphaNetworkEndpointGroups.ProjectRouter = r
+	mock.MockProjects.ProjectRouter = r
+	mock.MockRegions.ProjectRouter = r
+	mock.MockRoutes.ProjectRouter = r
+	mock.MockSslCertificates.ProjectRouter = r
+	mock.MockTargetHttpProxies.ProjectRouter = r
+	mock.MockTargetHttpsProxies.ProjectRouter = r
+	mock.MockTargetPools.ProjectRouter = r
+	mock.MockUrlMaps.ProjectRouter = r
+	mock.MockZones.ProjectRouter = r
+}
+
+// SetDebug sets Debug for all of the services in the mock.
+func (mock *MockGCE) SetDebug(debug bool) {
+	mock.MockAddresses.Debug = debug
+	mock.MockAlphaAddresses.Debug = debug
+	mock.MockBetaAddresses.Debug = debug
+	mock.MockGlobalAddresses.Debug = debug
+	mock.MockBackendServices.Debug = debug
+	mock.MockAlphaBackendServices.Debug = debug
+	mock.MockAlphaRegionBackendServices.Debug = debug
+	mock.MockDisks.Debug = debug
+	mock.MockAlphaDisks.Debug = debug
+	mock.MockAlphaRegionDisks.Debug = debug
+	mock.MockFirewalls.Debug = debug
+	mock.MockForwardingRules.Debug = debug
+	mock.MockAlphaForwardingRules.Debug = debug
+	mock.MockGlobalForwardingRules.Debug = debug
+	mock.MockHealthChecks.Debug = debug
+	mock.MockAlphaHealthChecks.Debug = debug
+	mock.MockHttpHealthChecks.Debug = debug
+	mock.MockHttpsHealthChecks.Debug = debug
+	mock.MockInstanceGroups.Debug = debug
+	mock.MockInstances.Debug = debug
+	mock.MockBetaInstances.Debug = debug
+	mock.MockAlphaInstances.Debug = debug
+	mock.MockAlphaNetworkEndpointGroups.Debug = debug
+	mock.MockProjects.Debug = debug
+	mock.MockRegions.Debug = debug
+	mock.MockRoutes.Debug = debug
+	mock.MockSslCertificates.Debug = debug
+	mock.MockTargetHttpProxies.Debug = debug
+	mock.MockTargetHttpsProxies.Debug = debug
+	mock.MockTargetPools.Debug = debug
+	mock.MockUrlMaps.Debug = debug
+	mock.MockZones.Debug = debug
+}
+
+// setDeleteCheck sets deleteCheck for all of the services in the mock.
+func (mock *MockGCE) setDeleteCheck(f func(id *ResourceID) error) {
+	mock.MockAddresses.deleteCheck = f
+	mock.MockAlphaAddresses.deleteCheck = f
+	mock.MockBetaAddresses.deleteCheck = f
+	mock.MockGlobalAddresses.deleteCheck = f
+	mock.MockBackendServices.deleteCheck = f
+	mock.MockAlphaBackendServices.deleteCheck = f
+	mock.MockAlphaRegionBackendServices.deleteCheck = f
+	mock.MockDisks.deleteCheck = f
+	mock.MockAlphaDisks.deleteCheck = f
+	mock.MockAlphaRegionDisks.deleteCheck = f
+	mock.MockFirewalls.deleteCheck = f
+	mock.MockForwardingRules.deleteCheck = f
+	mock.MockAlphaForwardingRules.deleteCheck = f
+	mock.MockGlobalForwardingRules.deleteCheck = f
+	mock.MockHealthChecks.deleteCheck = f
+	mock.MockAlphaHealthChecks.deleteCheck = f
+	mock.MockHttpHealthChecks.deleteCheck = f
+	mock.MockHttpsHealthChecks.deleteCheck = f
+	mock.MockInstanceGroups.deleteCheck = f
+	mock.MockInstances.deleteCheck = f
+	mock.MockBetaInstances.deleteCheck = f
+	mock.MockAlphaInstances.deleteCheck = f
+	mock.MockAlphaNetworkEndpointGroups.deleteCheck = f
+	mock.MockRoutes.deleteCheck = f
+	mock.MockSslCertificates.deleteCheck = f
+	mock.MockTargetHttpProxies.deleteCheck = f
+	mock.MockTargetHttpsProxies.deleteCheck = f
+	mock.MockTargetPools.deleteCheck = f
+	mock.MockUrlMaps.deleteCheck = f
+}
+
+// objects returns the objects of all of the services in the mock.
+func (mock *MockGCE) objects() []interface{} {
+	var ret []interface{}
+	mock.MockAddresses.Lock.Lock()
+	for _, obj := range mock.MockAddresses.Objects {
+		ret = append(ret, obj.Obj)
+	}
+	mock.MockAddresses.Lock.Unlock()
+	mock.MockBackendServices.Lock.Lock()
+	for _, obj := range mock.MockBackendServices.Objects {
+		ret = append(ret, obj.Obj)
+	}
+	mock.MockBackendServices.Lock.Unlock()
+	mock.MockDisks.Lock.Lock()
+	for _, obj := range mock.MockDisks.Objects {
+		ret = append(ret, obj.Obj)
+	}
+	mock.MockDisks.Lock.Unlock()
+	mock.MockFirewalls.Lock.Lock()
+	for _, obj := range mock.MockFirewalls.Objects {
+		ret = append(ret, obj.Obj)
+	}
+	mock.MockFirewalls.Lock.Unlock()
+	mock.MockForwardingRules.Lock.Lock()
+	for _, obj := range mock.MockForwardingRules.Objects {
+		ret = append(ret, obj.Obj)
+	}
+	mock.MockForwardingRules.Lock.Unlock()
+	mock.MockGlobalAddresses.Lock.Lock()
+	for _, obj := range mock.MockGlobalAddresses.Objects {
+		ret = append(ret, obj.Obj)
+	}
+	mock.MockGlobalAddresses.Lock.Unlock()
+	mock.MockGlobalForwardingRules.Lock.Lock()
+	for _, obj := range mock.MockGlobalForwardingRules.Objects {
+		ret = append(ret, obj.Obj)
+	}
+	mock.MockGlobalForwardingRules.Lock.Unlock()
+	mock.MockHealthChecks.Lock.Lock()
+	for _, obj := range mock.MockHealthChecks.Objects {
+		ret = append(ret, obj.Obj)
+	}
+	mock.MockHealthChecks.Lock.Unlock()
+	mock.MockHttpHealthChecks.Lock.Lock()
+	for _, obj := range mock.MockHttpHealthChecks.Objects {
+		ret = append(ret, obj.Obj)
+	}
+	mock.MockHttpHealthChecks.Lock.Unlock()
+	mock.MockHttpsHealthChecks.Lock.Lock()
+	for _, obj := range mock.MockHttpsHealthChecks.Objects {
+		ret = append(ret, obj.Obj)
+	}
+	mock.MockHttpsHealthChecks.Lock.Unlock()
+	mock.MockInstanceGroups.Lock.Lock()
+	for _, obj := range mock.MockInstanceGroups.Objects {
+		ret = append(ret, obj.Obj)
+	}
+	mock.MockInstanceGroups.Lock.Unlock()
+	mock.MockInstances.Lock.Lock()
+	for _, obj := range mock.MockInstances.Objects {
+		ret = append(ret, obj.Obj)
+	}
+	mock.MockInstances.Lock.Unlock()
+	mock.MockAlphaNetworkEndpointGroups.Lock.Lock()
+	for _, obj := range mock.MockAlphaNetworkEndpointGroups.Objects {
+		ret = append(ret, obj.Obj)
+	}
+	mock.MockAlphaNetworkEndpointGroups.Lock.Unlock()
+	mock.MockProjects.Lock.Lock()
+	for _, obj := range mock.MockProjects.Objects {
+		ret = append(ret, obj.Obj)
+	}
+	mock.MockProjects.Lock.Unlock()
+	mock.MockAlphaRegionBackendServices.Lock.Lock()
+	for _, obj := range mock.MockAlphaRegionBackendServices.Objects {
+		ret = append(ret, obj.Obj)
+	}
+	mock.MockAlphaRegionBackendServices.Lock.Unlock()
+	mock.MockAlphaRegionDisks.Lock.Lock()
+	for _, obj := range mock.MockAlphaRegionDisks.Objects {
+		ret = append(ret, obj.Obj)
+	}
+	mock.MockAlphaRegionDisks.Lock.Unlock()
+	mock.MockRegions.Lock.Lock()
+	for _, obj := range mock.MockRegions.Objects {
+		ret = append(ret, obj.Obj)
+	}
+	mock.MockRegions.Lock.Unlock()
+	mock.MockRoutes.Lock.Lock()
+	for _, obj := range mock.MockRoutes.Objects {
+		ret = append(ret, obj.Obj)
+	}
+	mock.MockRoutes.Lock.Unlock()
+	mock.MockSslCertificates.Lock.Lock()
+	for _, obj := range mock.MockSslCertificates.Objects {
+		ret = append(ret, obj.Obj)
+	}
+	mock.MockSslCertificates.Lock.Unlock()
+	mock.MockTargetHttpProxies.Lock.Lock()
+	for _, obj := range mock.MockTargetHttpProxies.Objects {
+		ret = append(ret, obj.Obj)
+	}
+	mock.MockTargetHttpProxies.Lock.Unlock()
+	mock.MockTargetHttpsProxies.Lock.Lock()
+	for _, obj := range mock.MockTargetHttpsProxies.Objects {
+		ret = append(ret, obj.Obj)
+	}
+	mock.MockTargetHttpsProxies.Lock.Unlock()
+	mock.MockTargetPools.Lock.Lock()
+	for _, obj := range mock.MockTargetPools.Objects {
+		ret = append(ret, obj.Obj)
+	}
+	mock.MockTargetPools.Lock.Unlock()
+	mock.MockUrlMaps.Lock.Lock()
+	for _, obj := range mock.MockUrlMaps.Objects {
+		ret = append(ret, obj.Obj)
+	}
+	mock.MockUrlMaps.Lock.Unlock()
+	mock.MockZones.Lock.Lock()
+	for _, obj := range mock.MockZones.Objects {
+		ret = append(ret, obj.Obj)
+	}
+	mock.MockZones.Lock.Unlock()
+	return ret
+}
+
 // MockAddressesObj is used to store the various object versions in the shared
 // map of mocked objects. This allows for multiple API versions to co-exist and
 // share the same "view" of the objects in the backend.
@@ -1104,13 +1379,46 @@ type MockAddresses struct {
 	InsertHook func(m *MockAddresses, ctx context.Context, key meta.Key, obj *ga.Address) (bool, error)
 	DeleteHook func(m *MockAddresses, ctx context.Context, key meta.Key) (bool, error)
 
+	// Observer, if set, is invoked around each call to the mock.
+	Observer CallObserver
+
+	// ProjectRouter, if set, routes the calls with keys that do not have a
+	// project. Calls are made to "mock-project" if it is not set.
+	ProjectRouter ProjectRouter
+
+	// Debug enables additional checks of the arguments to the mock, such as
+	// the validation of List() filters with filter.Validate().
+	Debug bool
+
+	// deleteCheck, if set, is called by Delete() with the resource being
+	// deleted. Delete() returns the error if it is not nil. See
+	// WithReferenceChecking().
+	deleteCheck func(id *ResourceID) error
+
 	// X is extra state that can be used as part of the mock. Generated code
 	// will not use this field.
 	X interface{}
 }
 
 // Get returns the object from the mock.
-func (m *MockAddresses) Get(ctx context.Context, key meta.Key) (*ga.Address, error) {
+func (m *MockAddresses) Get(ctx context.Context, key meta.Key) (_ *ga.Address, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "Addresses")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("ga"),
+			Service:   "Addresses",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("regional"); err != nil {
+		return nil, err
+	}
+
 	if m.GetHook != nil {
 		if intercept, obj, err := m.GetHook(m, ctx, key); intercept {
 			glog.V(5).Infof("MockAddresses.Get(%v, %s) = %+v, %v", ctx, key, obj, err)
@@ -1121,17 +1429,18 @@ func (m *MockAddresses) Get(ctx context.Context, key meta.Key) (*ga.Address, err
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.GetError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.GetError[skey]; ok {
 		glog.V(5).Infof("MockAddresses.Get(%v, %s) = nil, %v", ctx, key, err)
 		return nil, err
 	}
-	if obj, ok := m.Objects[key]; ok {
+	if obj, ok := m.Objects[skey]; ok {
 		typedObj := obj.ToGA()
 		glog.V(5).Infof("MockAddresses.Get(%v, %s) = %+v, nil", ctx, key, typedObj)
 		return typedObj, nil
 	}
 
-	err := &googleapi.Error{
+	err = &googleapi.Error{
 		Code:    http.StatusNotFound,
 		Message: fmt.Sprintf("MockAddresses %v not found", key),
 	}
@@ -1140,7 +1449,26 @@ func (m *MockAddresses) Get(ctx context.Context, key meta.Key) (*ga.Address, err
 }
 
 // List all of the objects in the mock in the given region.
-func (m *MockAddresses) List(ctx context.Context, region string, fl *filter.F) ([]*ga.Address, error) {
+func (m *MockAddresses) List(ctx context.Context, region string, fl *filter.F) (_ []*ga.Address, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, nil, "ga", "Addresses")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("ga"),
+			Service:   "Addresses",
+		},
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if m.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(ga.Address{})); err != nil {
+			glog.V(5).Infof("MockAddresses.List(%v, %v) = nil, %v", ctx, fl, err)
+			return nil, err
+		}
+	}
+
 	if m.ListHook != nil {
 		if intercept, objs, err := m.ListHook(m, ctx, region, fl); intercept {
 			glog.V(5).Infof("MockAddresses.List(%v, %q, %v) = [%v items], %v", ctx, region, fl, len(objs), err)
@@ -1160,6 +1488,10 @@ func (m *MockAddresses) List(ctx context.Context, region string, fl *filter.F) (
 
 	var objs []*ga.Address
 	for key, obj := range m.Objects {
+		// Objects in other projects are not listed.
+		if key.Project != mockStorageProject(projectID) {
+			continue
+		}
 		if key.Region != region {
 			continue
 		}
@@ -1174,7 +1506,26 @@ func (m *MockAddresses) List(ctx context.Context, region string, fl *filter.F) (
 }
 
 // Insert is a mock for inserting/creating a new object.
-func (m *MockAddresses) Insert(ctx context.Context, key meta.Key, obj *ga.Address) error {
+func (m *MockAddresses) Insert(ctx context.Context, key meta.Key, obj *ga.Address) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "Addresses")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Insert",
+			Version:   meta.Version("ga"),
+			Service:   "Addresses",
+		},
+		Key:     &key,
+		Request: obj,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("regional"); err != nil {
+		return err
+	}
+
 	if m.InsertHook != nil {
 		if intercept, err := m.InsertHook(m, ctx, key, obj); intercept {
 			glog.V(5).Infof("MockAddresses.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
@@ -1185,11 +1536,12 @@ func (m *MockAddresses) Insert(ctx context.Context, key meta.Key, obj *ga.Addres
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.InsertError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.InsertError[skey]; ok {
 		glog.V(5).Infof("MockAddresses.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
 		return err
 	}
-	if _, ok := m.Objects[key]; ok {
+	if _, ok := m.Objects[skey]; ok {
 		err := &googleapi.Error{
 			Code:    http.StatusConflict,
 			Message: fmt.Sprintf("MockAddresses %v exists", key),
@@ -1200,16 +1552,34 @@ func (m *MockAddresses) Insert(ctx context.Context, key meta.Key, obj *ga.Addres
 
 	obj.Name = key.Name
 	if obj.SelfLink == "" {
-		obj.SelfLink = SelfLink(meta.VersionGA, "mock-project", "addresses", key)
+		obj.SelfLink = SelfLink(meta.VersionGA, projectID, "addresses", key)
 	}
 
-	m.Objects[key] = &MockAddressesObj{obj}
+	m.Objects[skey] = &MockAddressesObj{obj}
 	glog.V(5).Infof("MockAddresses.Insert(%v, %v, %+v) = nil", ctx, key, obj)
 	return nil
 }
 
 // Delete is a mock for deleting the object.
-func (m *MockAddresses) Delete(ctx context.Context, key meta.Key) error {
+func (m *MockAddresses) Delete(ctx context.Context, key meta.Key) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "Addresses")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Delete",
+			Version:   meta.Version("ga"),
+			Service:   "Addresses",
+		},
+		Key:     &key,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("regional"); err != nil {
+		return err
+	}
+
 	if m.DeleteHook != nil {
 		if intercept, err := m.DeleteHook(m, ctx, key); intercept {
 			glog.V(5).Infof("MockAddresses.Delete(%v, %v) = %v", ctx, key, err)
@@ -1217,14 +1587,22 @@ func (m *MockAddresses) Delete(ctx context.Context, key meta.Key) error {
 		}
 	}
 
+	if m.deleteCheck != nil {
+		if err := m.deleteCheck(NewResourceID("addresses", *key.WithProject(projectID))); err != nil {
+			glog.V(5).Infof("MockAddresses.Delete(%v, %v) = %v", ctx, key, err)
+			return err
+		}
+	}
+
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.DeleteError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.DeleteError[skey]; ok {
 		glog.V(5).Infof("MockAddresses.Delete(%v, %v) = %v", ctx, key, err)
 		return err
 	}
-	if _, ok := m.Objects[key]; !ok {
+	if _, ok := m.Objects[skey]; !ok {
 		err := &googleapi.Error{
 			Code:    http.StatusNotFound,
 			Message: fmt.Sprintf("MockAddresses %v not found", key),
@@ -1233,7 +1611,7 @@ func (m *MockAddresses) Delete(ctx context.Context, key meta.Key) error {
 		return err
 	}
 
-	delete(m.Objects, key)
+	delete(m.Objects, skey)
 	glog.V(5).Infof("MockAddresses.Delete(%v, %v) = nil", ctx, key)
 	return nil
 }
@@ -1249,15 +1627,23 @@ type GCEAddresses struct {
 }
 
 // Get the Address named by key.
-func (g *GCEAddresses) Get(ctx context.Context, key meta.Key) (*ga.Address, error) {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "Addresses")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Get",
-		Version:   meta.Version("ga"),
-		Service:   "Addresses",
+func (g *GCEAddresses) Get(ctx context.Context, key meta.Key) (_ *ga.Address, err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "Addresses")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("ga"),
+			Service:   "Addresses",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("regional"); err != nil {
+		return nil, err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.GA.Addresses.Get(projectID, key.Region, key.Name)
@@ -1266,24 +1652,38 @@ func (g *GCEAddresses) Get(ctx context.Context, key meta.Key) (*ga.Address, erro
 }
 
 // List all Address objects.
-func (g *GCEAddresses) List(ctx context.Context, region string, fl *filter.F) ([]*ga.Address, error) {
+func (g *GCEAddresses) List(ctx context.Context, region string, fl *filter.F) (_ []*ga.Address, err error) {
 	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "Addresses")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "List",
-		Version:   meta.Version("ga"),
-		Service:   "Addresses",
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("ga"),
+			Service:   "Addresses",
+		},
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if g.s.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(ga.Address{})); err != nil {
+			return nil, err
+		}
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.GA.Addresses.List(projectID, region)
-	if fl != filter.None {
-		call.Filter(fl.String())
+	serverFl, clientFl := fl.Split(reflect.TypeOf(ga.Address{}))
+	if serverFl != filter.None {
+		call.Filter(serverFl.String())
 	}
 	var all []*ga.Address
 	f := func(l *ga.AddressList) error {
-		all = append(all, l.Items...)
+		for _, obj := range l.Items {
+			if clientFl.Match(obj) {
+				all = append(all, obj)
+			}
+		}
 		return nil
 	}
 	if err := call.Pages(ctx, f); err != nil {
@@ -1293,18 +1693,32 @@ func (g *GCEAddresses) List(ctx context.Context, region string, fl *filter.F) ([
 }
 
 // Insert Address with key of value obj.
-func (g *GCEAddresses) Insert(ctx context.Context, key meta.Key, obj *ga.Address) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "Addresses")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Insert",
-		Version:   meta.Version("ga"),
-		Service:   "Addresses",
+func (g *GCEAddresses) Insert(ctx context.Context, key meta.Key, obj *ga.Address) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "Addresses")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Insert",
+			Version:   meta.Version("ga"),
+			Service:   "Addresses",
+		},
+		Key:     &key,
+		Request: obj,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("regional"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
 	obj.Name = key.Name
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.GA.Addresses.Insert(projectID, key.Region, obj)
 	call.Context(ctx)
 
@@ -1316,17 +1730,30 @@ func (g *GCEAddresses) Insert(ctx context.Context, key meta.Key, obj *ga.Address
 }
 
 // Delete the Address referenced by key.
-func (g *GCEAddresses) Delete(ctx context.Context, key meta.Key) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "Addresses")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Delete",
-		Version:   meta.Version("ga"),
-		Service:   "Addresses",
+func (g *GCEAddresses) Delete(ctx context.Context, key meta.Key) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "Addresses")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Delete",
+			Version:   meta.Version("ga"),
+			Service:   "Addresses",
+		},
+		Key:     &key,
+		Mutates: true,
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("regional"); err != nil {
 		return err
 	}
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
+		return err
+	}
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.GA.Addresses.Delete(projectID, key.Region, key.Name)
 	call.Context(ctx)
 
@@ -1379,13 +1806,46 @@ type MockAlphaAddresses struct {
 	InsertHook func(m *MockAlphaAddresses, ctx context.Context, key meta.Key, obj *alpha.Address) (bool, error)
 	DeleteHook func(m *MockAlphaAddresses, ctx context.Context, key meta.Key) (bool, error)
 
+	// Observer, if set, is invoked around each call to the mock.
+	Observer CallObserver
+
+	// ProjectRouter, if set, routes the calls with keys that do not have a
+	// project. Calls are made to "mock-project" if it is not set.
+	ProjectRouter ProjectRouter
+
+	// Debug enables additional checks of the arguments to the mock, such as
+	// the validation of List() filters with filter.Validate().
+	Debug bool
+
+	// deleteCheck, if set, is called by Delete() with the resource being
+	// deleted. Delete() returns the error if it is not nil. See
+	// WithReferenceChecking().
+	deleteCheck func(id *ResourceID) error
+
 	// X is extra state that can be used as part of the mock. Generated code
 	// will not use this field.
 	X interface{}
 }
 
 // Get returns the object from the mock.
-func (m *MockAlphaAddresses) Get(ctx context.Context, key meta.Key) (*alpha.Address, error) {
+func (m *MockAlphaAddresses) Get(ctx context.Context, key meta.Key) (_ *alpha.Address, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "alpha", "Addresses")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("alpha"),
+			Service:   "Addresses",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("regional"); err != nil {
+		return nil, err
+	}
+
 	if m.GetHook != nil {
 		if intercept, obj, err := m.GetHook(m, ctx, key); intercept {
 			glog.V(5).Infof("MockAlphaAddresses.Get(%v, %s) = %+v, %v", ctx, key, obj, err)
@@ -1396,17 +1856,18 @@ func (m *MockAlphaAddresses) Get(ctx context.Context, key meta.Key) (*alpha.Addr
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.GetError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.GetError[skey]; ok {
 		glog.V(5).Infof("MockAlphaAddresses.Get(%v, %s) = nil, %v", ctx, key, err)
 		return nil, err
 	}
-	if obj, ok := m.Objects[key]; ok {
+	if obj, ok := m.Objects[skey]; ok {
 		typedObj := obj.ToAlpha()
 		glog.V(5).Infof("MockAlphaAddresses.Get(%v, %s) = %+v, nil", ctx, key, typedObj)
 		return typedObj, nil
 	}
 
-	err := &googleapi.Error{
+	err = &googleapi.Error{
 		Code:    http.StatusNotFound,
 		Message: fmt.Sprintf("MockAlphaAddresses %v not found", key),
 	}
@@ -1415,7 +1876,26 @@ func (m *MockAlphaAddresses) Get(ctx context.Context, key meta.Key) (*alpha.Addr
 }
 
 // List all of the objects in the mock in the given region.
-func (m *MockAlphaAddresses) List(ctx context.Context, region string, fl *filter.F) ([]*alpha.Address, error) {
+func (m *MockAlphaAddresses) List(ctx context.Context, region string, fl *filter.F) (_ []*alpha.Address, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, nil, "alpha", "Addresses")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("alpha"),
+			Service:   "Addresses",
+		},
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if m.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(alpha.Address{})); err != nil {
+			glog.V(5).Infof("MockAlphaAddresses.List(%v, %v) = nil, %v", ctx, fl, err)
+			return nil, err
+		}
+	}
+
 	if m.ListHook != nil {
 		if intercept, objs, err := m.ListHook(m, ctx, region, fl); intercept {
 			glog.V(5).Infof("MockAlphaAddresses.List(%v, %q, %v) = [%v items], %v", ctx, region, fl, len(objs), err)
@@ -1435,6 +1915,10 @@ func (m *MockAlphaAddresses) List(ctx context.Context, region string, fl *filter
 
 	var objs []*alpha.Address
 	for key, obj := range m.Objects {
+		// Objects in other projects are not listed.
+		if key.Project != mockStorageProject(projectID) {
+			continue
+		}
 		if key.Region != region {
 			continue
 		}
@@ -1449,7 +1933,26 @@ func (m *MockAlphaAddresses) List(ctx context.Context, region string, fl *filter
 }
 
 // Insert is a mock for inserting/creating a new object.
-func (m *MockAlphaAddresses) Insert(ctx context.Context, key meta.Key, obj *alpha.Address) error {
+func (m *MockAlphaAddresses) Insert(ctx context.Context, key meta.Key, obj *alpha.Address) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "alpha", "Addresses")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Insert",
+			Version:   meta.Version("alpha"),
+			Service:   "Addresses",
+		},
+		Key:     &key,
+		Request: obj,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("regional"); err != nil {
+		return err
+	}
+
 	if m.InsertHook != nil {
 		if intercept, err := m.InsertHook(m, ctx, key, obj); intercept {
 			glog.V(5).Infof("MockAlphaAddresses.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
@@ -1460,11 +1963,12 @@ func (m *MockAlphaAddresses) Insert(ctx context.Context, key meta.Key, obj *alph
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.InsertError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.InsertError[skey]; ok {
 		glog.V(5).Infof("MockAlphaAddresses.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
 		return err
 	}
-	if _, ok := m.Objects[key]; ok {
+	if _, ok := m.Objects[skey]; ok {
 		err := &googleapi.Error{
 			Code:    http.StatusConflict,
 			Message: fmt.Sprintf("MockAlphaAddresses %v exists", key),
@@ -1475,16 +1979,34 @@ func (m *MockAlphaAddresses) Insert(ctx context.Context, key meta.Key, obj *alph
 
 	obj.Name = key.Name
 	if obj.SelfLink == "" {
-		obj.SelfLink = SelfLink(meta.VersionAlpha, "mock-project", "addresses", key)
+		obj.SelfLink = SelfLink(meta.VersionAlpha, projectID, "addresses", key)
 	}
 
-	m.Objects[key] = &MockAddressesObj{obj}
+	m.Objects[skey] = &MockAddressesObj{obj}
 	glog.V(5).Infof("MockAlphaAddresses.Insert(%v, %v, %+v) = nil", ctx, key, obj)
 	return nil
 }
 
 // Delete is a mock for deleting the object.
-func (m *MockAlphaAddresses) Delete(ctx context.Context, key meta.Key) error {
+func (m *MockAlphaAddresses) Delete(ctx context.Context, key meta.Key) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "alpha", "Addresses")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Delete",
+			Version:   meta.Version("alpha"),
+			Service:   "Addresses",
+		},
+		Key:     &key,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("regional"); err != nil {
+		return err
+	}
+
 	if m.DeleteHook != nil {
 		if intercept, err := m.DeleteHook(m, ctx, key); intercept {
 			glog.V(5).Infof("MockAlphaAddresses.Delete(%v, %v) = %v", ctx, key, err)
@@ -1492,14 +2014,22 @@ func (m *MockAlphaAddresses) Delete(ctx context.Context, key meta.Key) error {
 		}
 	}
 
+	if m.deleteCheck != nil {
+		if err := m.deleteCheck(NewResourceID("addresses", *key.WithProject(projectID))); err != nil {
+			glog.V(5).Infof("MockAlphaAddresses.Delete(%v, %v) = %v", ctx, key, err)
+			return err
+		}
+	}
+
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.DeleteError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.DeleteError[skey]; ok {
 		glog.V(5).Infof("MockAlphaAddresses.Delete(%v, %v) = %v", ctx, key, err)
 		return err
 	}
-	if _, ok := m.Objects[key]; !ok {
+	if _, ok := m.Objects[skey]; !ok {
 		err := &googleapi.Error{
 			Code:    http.StatusNotFound,
 			Message: fmt.Sprintf("MockAlphaAddresses %v not found", key),
@@ -1508,7 +2038,7 @@ func (m *MockAlphaAddresses) Delete(ctx context.Context, key meta.Key) error {
 		return err
 	}
 
-	delete(m.Objects, key)
+	delete(m.Objects, skey)
 	glog.V(5).Infof("MockAlphaAddresses.Delete(%v, %v) = nil", ctx, key)
 	return nil
 }
@@ -1524,15 +2054,23 @@ type GCEAlphaAddresses struct {
 }
 
 // Get the Address named by key.
-func (g *GCEAlphaAddresses) Get(ctx context.Context, key meta.Key) (*alpha.Address, error) {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "alpha", "Addresses")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Get",
-		Version:   meta.Version("alpha"),
-		Service:   "Addresses",
+func (g *GCEAlphaAddresses) Get(ctx context.Context, key meta.Key) (_ *alpha.Address, err error) {
+	projectID := g.s.keyProjectID(ctx, key, "alpha", "Addresses")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("alpha"),
+			Service:   "Addresses",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("regional"); err != nil {
+		return nil, err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.Alpha.Addresses.Get(projectID, key.Region, key.Name)
@@ -1541,24 +2079,38 @@ func (g *GCEAlphaAddresses) Get(ctx context.Context, key meta.Key) (*alpha.Addre
 }
 
 // List all Address objects.
-func (g *GCEAlphaAddresses) List(ctx context.Context, region string, fl *filter.F) ([]*alpha.Address, error) {
+func (g *GCEAlphaAddresses) List(ctx context.Context, region string, fl *filter.F) (_ []*alpha.Address, err error) {
 	projectID := g.s.ProjectRouter.ProjectID(ctx, "alpha", "Addresses")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "List",
-		Version:   meta.Version("alpha"),
-		Service:   "Addresses",
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("alpha"),
+			Service:   "Addresses",
+		},
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if g.s.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(alpha.Address{})); err != nil {
+			return nil, err
+		}
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.Alpha.Addresses.List(projectID, region)
-	if fl != filter.None {
-		call.Filter(fl.String())
+	serverFl, clientFl := fl.Split(reflect.TypeOf(alpha.Address{}))
+	if serverFl != filter.None {
+		call.Filter(serverFl.String())
 	}
 	var all []*alpha.Address
 	f := func(l *alpha.AddressList) error {
-		all = append(all, l.Items...)
+		for _, obj := range l.Items {
+			if clientFl.Match(obj) {
+				all = append(all, obj)
+			}
+		}
 		return nil
 	}
 	if err := call.Pages(ctx, f); err != nil {
@@ -1568,18 +2120,32 @@ func (g *GCEAlphaAddresses) List(ctx context.Context, region string, fl *filter.
 }
 
 // Insert Address with key of value obj.
-func (g *GCEAlphaAddresses) Insert(ctx context.Context, key meta.Key, obj *alpha.Address) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "alpha", "Addresses")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Insert",
-		Version:   meta.Version("alpha"),
-		Service:   "Addresses",
+func (g *GCEAlphaAddresses) Insert(ctx context.Context, key meta.Key, obj *alpha.Address) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "alpha", "Addresses")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Insert",
+			Version:   meta.Version("alpha"),
+			Service:   "Addresses",
+		},
+		Key:     &key,
+		Request: obj,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("regional"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
 	obj.Name = key.Name
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.Alpha.Addresses.Insert(projectID, key.Region, obj)
 	call.Context(ctx)
 
@@ -1591,17 +2157,30 @@ func (g *GCEAlphaAddresses) Insert(ctx context.Context, key meta.Key, obj *alpha
 }
 
 // Delete the Address referenced by key.
-func (g *GCEAlphaAddresses) Delete(ctx context.Context, key meta.Key) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "alpha", "Addresses")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Delete",
-		Version:   meta.Version("alpha"),
-		Service:   "Addresses",
+func (g *GCEAlphaAddresses) Delete(ctx context.Context, key meta.Key) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "alpha", "Addresses")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Delete",
+			Version:   meta.Version("alpha"),
+			Service:   "Addresses",
+		},
+		Key:     &key,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("regional"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.Alpha.Addresses.Delete(projectID, key.Region, key.Name)
 	call.Context(ctx)
 
@@ -1654,13 +2233,46 @@ type MockBetaAddresses struct {
 	InsertHook func(m *MockBetaAddresses, ctx context.Context, key meta.Key, obj *beta.Address) (bool, error)
 	DeleteHook func(m *MockBetaAddresses, ctx context.Context, key meta.Key) (bool, error)
 
+	// Observer, if set, is invoked around each call to the mock.
+	Observer CallObserver
+
+	// ProjectRouter, if set, routes the calls with keys that do not have a
+	// project. Calls are made to "mock-project" if it is not set.
+	ProjectRouter ProjectRouter
+
+	// Debug enables additional checks of the arguments to the mock, such as
+	// the validation of List() filters with filter.Validate().
+	Debug bool
+
+	// deleteCheck, if set, is called by Delete() with the resource being
+	// deleted. Delete() returns the error if it is not nil. See
+	// WithReferenceChecking().
+	deleteCheck func(id *ResourceID) error
+
 	// X is extra state that can be used as part of the mock. Generated code
 	// will not use this field.
 	X interface{}
 }
 
 // Get returns the object from the mock.
-func (m *MockBetaAddresses) Get(ctx context.Context, key meta.Key) (*beta.Address, error) {
+func (m *MockBetaAddresses) Get(ctx context.Context, key meta.Key) (_ *beta.Address, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "beta", "Addresses")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("beta"),
+			Service:   "Addresses",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("regional"); err != nil {
+		return nil, err
+	}
+
 	if m.GetHook != nil {
 		if intercept, obj, err := m.GetHook(m, ctx, key); intercept {
 			glog.V(5).Infof("MockBetaAddresses.Get(%v, %s) = %+v, %v", ctx, key, obj, err)
@@ -1671,17 +2283,18 @@ func (m *MockBetaAddresses) Get(ctx context.Context, key meta.Key) (*beta.Addres
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.GetError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.GetError[skey]; ok {
 		glog.V(5).Infof("MockBetaAddresses.Get(%v, %s) = nil, %v", ctx, key, err)
 		return nil, err
 	}
-	if obj, ok := m.Objects[key]; ok {
+	if obj, ok := m.Objects[skey]; ok {
 		typedObj := obj.ToBeta()
 		glog.V(5).Infof("MockBetaAddresses.Get(%v, %s) = %+v, nil", ctx, key, typedObj)
 		return typedObj, nil
 	}
 
-	err := &googleapi.Error{
+	err = &googleapi.Error{
 		Code:    http.StatusNotFound,
 		Message: fmt.Sprintf("MockBetaAddresses %v not found", key),
 	}
@@ -1690,7 +2303,26 @@ func (m *MockBetaAddresses) Get(ctx context.Context, key meta.Key) (*beta.Addres
 }
 
 // List all of the objects in the mock in the given region.
-func (m *MockBetaAddresses) List(ctx context.Context, region string, fl *filter.F) ([]*beta.Address, error) {
+func (m *MockBetaAddresses) List(ctx context.Context, region string, fl *filter.F) (_ []*beta.Address, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, nil, "beta", "Addresses")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("beta"),
+			Service:   "Addresses",
+		},
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if m.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(beta.Address{})); err != nil {
+			glog.V(5).Infof("MockBetaAddresses.List(%v, %v) = nil, %v", ctx, fl, err)
+			return nil, err
+		}
+	}
+
 	if m.ListHook != nil {
 		if intercept, objs, err := m.ListHook(m, ctx, region, fl); intercept {
 			glog.V(5).Infof("MockBetaAddresses.List(%v, %q, %v) = [%v items], %v", ctx, region, fl, len(objs), err)
@@ -1710,6 +2342,10 @@ func (m *MockBetaAddresses) List(ctx context.Context, region string, fl *filter.
 
 	var objs []*beta.Address
 	for key, obj := range m.Objects {
+		// Objects in other projects are not listed.
+		if key.Project != mockStorageProject(projectID) {
+			continue
+		}
 		if key.Region != region {
 			continue
 		}
@@ -1724,7 +2360,26 @@ func (m *MockBetaAddresses) List(ctx context.Context, region string, fl *filter.
 }
 
 // Insert is a mock for inserting/creating a new object.
-func (m *MockBetaAddresses) Insert(ctx context.Context, key meta.Key, obj *beta.Address) error {
+func (m *MockBetaAddresses) Insert(ctx context.Context, key meta.Key, obj *beta.Address) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "beta", "Addresses")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Insert",
+			Version:   meta.Version("beta"),
+			Service:   "Addresses",
+		},
+		Key:     &key,
+		Request: obj,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("regional"); err != nil {
+		return err
+	}
+
 	if m.InsertHook != nil {
 		if intercept, err := m.InsertHook(m, ctx, key, obj); intercept {
 			glog.V(5).Infof("MockBetaAddresses.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
@@ -1735,11 +2390,12 @@ func (m *MockBetaAddresses) Insert(ctx context.Context, key meta.Key, obj *beta.
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.InsertError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.InsertError[skey]; ok {
 		glog.V(5).Infof("MockBetaAddresses.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
 		return err
 	}
-	if _, ok := m.Objects[key]; ok {
+	if _, ok := m.Objects[skey]; ok {
 		err := &googleapi.Error{
 			Code:    http.StatusConflict,
 			Message: fmt.Sprintf("MockBetaAddresses %v exists", key),
@@ -1750,16 +2406,34 @@ func (m *MockBetaAddresses) Insert(ctx context.Context, key meta.Key, obj *beta.
 
 	obj.Name = key.Name
 	if obj.SelfLink == "" {
-		obj.SelfLink = SelfLink(meta.VersionBeta, "mock-project", "addresses", key)
+		obj.SelfLink = SelfLink(meta.VersionBeta, projectID, "addresses", key)
 	}
 
-	m.Objects[key] = &MockAddressesObj{obj}
+	m.Objects[skey] = &MockAddressesObj{obj}
 	glog.V(5).Infof("MockBetaAddresses.Insert(%v, %v, %+v) = nil", ctx, key, obj)
 	return nil
 }
 
 // Delete is a mock for deleting the object.
-func (m *MockBetaAddresses) Delete(ctx context.Context, key meta.Key) error {
+func (m *MockBetaAddresses) Delete(ctx context.Context, key meta.Key) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "beta", "Addresses")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Delete",
+			Version:   meta.Version("beta"),
+			Service:   "Addresses",
+		},
+		Key:     &key,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("regional"); err != nil {
+		return err
+	}
+
 	if m.DeleteHook != nil {
 		if intercept, err := m.DeleteHook(m, ctx, key); intercept {
 			glog.V(5).Infof("MockBetaAddresses.Delete(%v, %v) = %v", ctx, key, err)
@@ -1767,14 +2441,22 @@ func (m *MockBetaAddresses) Delete(ctx context.Context, key meta.Key) error {
 		}
 	}
 
+	if m.deleteCheck != nil {
+		if err := m.deleteCheck(NewResourceID("addresses", *key.WithProject(projectID))); err != nil {
+			glog.V(5).Infof("MockBetaAddresses.Delete(%v, %v) = %v", ctx, key, err)
+			return err
+		}
+	}
+
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.DeleteError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.DeleteError[skey]; ok {
 		glog.V(5).Infof("MockBetaAddresses.Delete(%v, %v) = %v", ctx, key, err)
 		return err
 	}
-	if _, ok := m.Objects[key]; !ok {
+	if _, ok := m.Objects[skey]; !ok {
 		err := &googleapi.Error{
 			Code:    http.StatusNotFound,
 			Message: fmt.Sprintf("MockBetaAddresses %v not found", key),
@@ -1783,7 +2465,7 @@ func (m *MockBetaAddresses) Delete(ctx context.Context, key meta.Key) error {
 		return err
 	}
 
-	delete(m.Objects, key)
+	delete(m.Objects, skey)
 	glog.V(5).Infof("MockBetaAddresses.Delete(%v, %v) = nil", ctx, key)
 	return nil
 }
@@ -1799,15 +2481,23 @@ type GCEBetaAddresses struct {
 }
 
 // Get the Address named by key.
-func (g *GCEBetaAddresses) Get(ctx context.Context, key meta.Key) (*beta.Address, error) {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "beta", "Addresses")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Get",
-		Version:   meta.Version("beta"),
-		Service:   "Addresses",
+func (g *GCEBetaAddresses) Get(ctx context.Context, key meta.Key) (_ *beta.Address, err error) {
+	projectID := g.s.keyProjectID(ctx, key, "beta", "Addresses")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("beta"),
+			Service:   "Addresses",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("regional"); err != nil {
+		return nil, err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.Beta.Addresses.Get(projectID, key.Region, key.Name)
@@ -1816,24 +2506,38 @@ func (g *GCEBetaAddresses) Get(ctx context.Context, key meta.Key) (*beta.Address
 }
 
 // List all Address objects.
-func (g *GCEBetaAddresses) List(ctx context.Context, region string, fl *filter.F) ([]*beta.Address, error) {
+func (g *GCEBetaAddresses) List(ctx context.Context, region string, fl *filter.F) (_ []*beta.Address, err error) {
 	projectID := g.s.ProjectRouter.ProjectID(ctx, "beta", "Addresses")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "List",
-		Version:   meta.Version("beta"),
-		Service:   "Addresses",
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("beta"),
+			Service:   "Addresses",
+		},
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if g.s.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(beta.Address{})); err != nil {
+			return nil, err
+		}
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.Beta.Addresses.List(projectID, region)
-	if fl != filter.None {
-		call.Filter(fl.String())
+	serverFl, clientFl := fl.Split(reflect.TypeOf(beta.Address{}))
+	if serverFl != filter.None {
+		call.Filter(serverFl.String())
 	}
 	var all []*beta.Address
 	f := func(l *beta.AddressList) error {
-		all = append(all, l.Items...)
+		for _, obj := range l.Items {
+			if clientFl.Match(obj) {
+				all = append(all, obj)
+			}
+		}
 		return nil
 	}
 	if err := call.Pages(ctx, f); err != nil {
@@ -1843,18 +2547,32 @@ func (g *GCEBetaAddresses) List(ctx context.Context, region string, fl *filter.F
 }
 
 // Insert Address with key of value obj.
-func (g *GCEBetaAddresses) Insert(ctx context.Context, key meta.Key, obj *beta.Address) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "beta", "Addresses")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Insert",
-		Version:   meta.Version("beta"),
-		Service:   "Addresses",
+func (g *GCEBetaAddresses) Insert(ctx context.Context, key meta.Key, obj *beta.Address) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "beta", "Addresses")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Insert",
+			Version:   meta.Version("beta"),
+			Service:   "Addresses",
+		},
+		Key:     &key,
+		Request: obj,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("regional"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
 	obj.Name = key.Name
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.Beta.Addresses.Insert(projectID, key.Region, obj)
 	call.Context(ctx)
 
@@ -1866,17 +2584,30 @@ func (g *GCEBetaAddresses) Insert(ctx context.Context, key meta.Key, obj *beta.A
 }
 
 // Delete the Address referenced by key.
-func (g *GCEBetaAddresses) Delete(ctx context.Context, key meta.Key) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "beta", "Addresses")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Delete",
-		Version:   meta.Version("beta"),
-		Service:   "Addresses",
+func (g *GCEBetaAddresses) Delete(ctx context.Context, key meta.Key) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "beta", "Addresses")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Delete",
+			Version:   meta.Version("beta"),
+			Service:   "Addresses",
+		},
+		Key:     &key,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("regional"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.Beta.Addresses.Delete(projectID, key.Region, key.Name)
 	call.Context(ctx)
 
@@ -1929,13 +2660,46 @@ type MockGlobalAddresses struct {
 	InsertHook func(m *MockGlobalAddresses, ctx context.Context, key meta.Key, obj *ga.Address) (bool, error)
 	DeleteHook func(m *MockGlobalAddresses, ctx context.Context, key meta.Key) (bool, error)
 
+	// Observer, if set, is invoked around each call to the mock.
+	Observer CallObserver
+
+	// ProjectRouter, if set, routes the calls with keys that do not have a
+	// project. Calls are made to "mock-project" if it is not set.
+	ProjectRouter ProjectRouter
+
+	// Debug enables additional checks of the arguments to the mock, such as
+	// the validation of List() filters with filter.Validate().
+	Debug bool
+
+	// deleteCheck, if set, is called by Delete() with the resource being
+	// deleted. Delete() returns the error if it is not nil. See
+	// WithReferenceChecking().
+	deleteCheck func(id *ResourceID) error
+
 	// X is extra state that can be used as part of the mock. Generated code
 	// will not use this field.
 	X interface{}
 }
 
 // Get returns the object from the mock.
-func (m *MockGlobalAddresses) Get(ctx context.Context, key meta.Key) (*ga.Address, error) {
+func (m *MockGlobalAddresses) Get(ctx context.Context, key meta.Key) (_ *ga.Address, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "GlobalAddresses")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("ga"),
+			Service:   "GlobalAddresses",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("global"); err != nil {
+		return nil, err
+	}
+
 	if m.GetHook != nil {
 		if intercept, obj, err := m.GetHook(m, ctx, key); intercept {
 			glog.V(5).Infof("MockGlobalAddresses.Get(%v, %s) = %+v, %v", ctx, key, obj, err)
@@ -1946,17 +2710,18 @@ func (m *MockGlobalAddresses) Get(ctx context.Context, key meta.Key) (*ga.Addres
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.GetError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.GetError[skey]; ok {
 		glog.V(5).Infof("MockGlobalAddresses.Get(%v, %s) = nil, %v", ctx, key, err)
 		return nil, err
 	}
-	if obj, ok := m.Objects[key]; ok {
+	if obj, ok := m.Objects[skey]; ok {
 		typedObj := obj.ToGA()
 		glog.V(5).Infof("MockGlobalAddresses.Get(%v, %s) = %+v, nil", ctx, key, typedObj)
 		return typedObj, nil
 	}
 
-	err := &googleapi.Error{
+	err = &googleapi.Error{
 		Code:    http.StatusNotFound,
 		Message: fmt.Sprintf("MockGlobalAddresses %v not found", key),
 	}
@@ -1965,7 +2730,26 @@ func (m *MockGlobalAddresses) Get(ctx context.Context, key meta.Key) (*ga.Addres
 }
 
 // List all of the objects in the mock.
-func (m *MockGlobalAddresses) List(ctx context.Context, fl *filter.F) ([]*ga.Address, error) {
+func (m *MockGlobalAddresses) List(ctx context.Context, fl *filter.F) (_ []*ga.Address, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, nil, "ga", "GlobalAddresses")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("ga"),
+			Service:   "GlobalAddresses",
+		},
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if m.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(ga.Address{})); err != nil {
+			glog.V(5).Infof("MockGlobalAddresses.List(%v, %v) = nil, %v", ctx, fl, err)
+			return nil, err
+		}
+	}
+
 	if m.ListHook != nil {
 		if intercept, objs, err := m.ListHook(m, ctx, fl); intercept {
 			glog.V(5).Infof("MockGlobalAddresses.List(%v, %v) = [%v items], %v", ctx, fl, len(objs), err)
@@ -1984,7 +2768,11 @@ func (m *MockGlobalAddresses) List(ctx context.Context, fl *filter.F) ([]*ga.Add
 	}
 
 	var objs []*ga.Address
-	for _, obj := range m.Objects {
+	for key, obj := range m.Objects {
+		// Objects in other projects are not listed.
+		if key.Project != mockStorageProject(projectID) {
+			continue
+		}
 		if !fl.Match(obj.ToGA()) {
 			continue
 		}
@@ -1996,7 +2784,26 @@ func (m *MockGlobalAddresses) List(ctx context.Context, fl *filter.F) ([]*ga.Add
 }
 
 // Insert is a mock for inserting/creating a new object.
-func (m *MockGlobalAddresses) Insert(ctx context.Context, key meta.Key, obj *ga.Address) error {
+func (m *MockGlobalAddresses) Insert(ctx context.Context, key meta.Key, obj *ga.Address) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "GlobalAddresses")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Insert",
+			Version:   meta.Version("ga"),
+			Service:   "GlobalAddresses",
+		},
+		Key:     &key,
+		Request: obj,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("global"); err != nil {
+		return err
+	}
+
 	if m.InsertHook != nil {
 		if intercept, err := m.InsertHook(m, ctx, key, obj); intercept {
 			glog.V(5).Infof("MockGlobalAddresses.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
@@ -2007,11 +2814,12 @@ func (m *MockGlobalAddresses) Insert(ctx context.Context, key meta.Key, obj *ga.
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.InsertError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.InsertError[skey]; ok {
 		glog.V(5).Infof("MockGlobalAddresses.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
 		return err
 	}
-	if _, ok := m.Objects[key]; ok {
+	if _, ok := m.Objects[skey]; ok {
 		err := &googleapi.Error{
 			Code:    http.StatusConflict,
 			Message: fmt.Sprintf("MockGlobalAddresses %v exists", key),
@@ -2022,16 +2830,34 @@ func (m *MockGlobalAddresses) Insert(ctx context.Context, key meta.Key, obj *ga.
 
 	obj.Name = key.Name
 	if obj.SelfLink == "" {
-		obj.SelfLink = SelfLink(meta.VersionGA, "mock-project", "addresses", key)
+		obj.SelfLink = SelfLink(meta.VersionGA, projectID, "addresses", key)
 	}
 
-	m.Objects[key] = &MockGlobalAddressesObj{obj}
+	m.Objects[skey] = &MockGlobalAddressesObj{obj}
 	glog.V(5).Infof("MockGlobalAddresses.Insert(%v, %v, %+v) = nil", ctx, key, obj)
 	return nil
 }
 
 // Delete is a mock for deleting the object.
-func (m *MockGlobalAddresses) Delete(ctx context.Context, key meta.Key) error {
+func (m *MockGlobalAddresses) Delete(ctx context.Context, key meta.Key) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "GlobalAddresses")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Delete",
+			Version:   meta.Version("ga"),
+			Service:   "GlobalAddresses",
+		},
+		Key:     &key,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("global"); err != nil {
+		return err
+	}
+
 	if m.DeleteHook != nil {
 		if intercept, err := m.DeleteHook(m, ctx, key); intercept {
 			glog.V(5).Infof("MockGlobalAddresses.Delete(%v, %v) = %v", ctx, key, err)
@@ -2039,14 +2865,22 @@ func (m *MockGlobalAddresses) Delete(ctx context.Context, key meta.Key) error {
 		}
 	}
 
+	if m.deleteCheck != nil {
+		if err := m.deleteCheck(NewResourceID("addresses", *key.WithProject(projectID))); err != nil {
+			glog.V(5).Infof("MockGlobalAddresses.Delete(%v, %v) = %v", ctx, key, err)
+			return err
+		}
+	}
+
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.DeleteError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.DeleteError[skey]; ok {
 		glog.V(5).Infof("MockGlobalAddresses.Delete(%v, %v) = %v", ctx, key, err)
 		return err
 	}
-	if _, ok := m.Objects[key]; !ok {
+	if _, ok := m.Objects[skey]; !ok {
 		err := &googleapi.Error{
 			Code:    http.StatusNotFound,
 			Message: fmt.Sprintf("MockGlobalAddresses %v not found", key),
@@ -2055,7 +2889,7 @@ func (m *MockGlobalAddresses) Delete(ctx context.Context, key meta.Key) error {
 		return err
 	}
 
-	delete(m.Objects, key)
+	delete(m.Objects, skey)
 	glog.V(5).Infof("MockGlobalAddresses.Delete(%v, %v) = nil", ctx, key)
 	return nil
 }
@@ -2071,15 +2905,23 @@ type GCEGlobalAddresses struct {
 }
 
 // Get the Address named by key.
-func (g *GCEGlobalAddresses) Get(ctx context.Context, key meta.Key) (*ga.Address, error) {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "GlobalAddresses")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Get",
-		Version:   meta.Version("ga"),
-		Service:   "GlobalAddresses",
+func (g *GCEGlobalAddresses) Get(ctx context.Context, key meta.Key) (_ *ga.Address, err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "GlobalAddresses")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("ga"),
+			Service:   "GlobalAddresses",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("global"); err != nil {
+		return nil, err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.GA.GlobalAddresses.Get(projectID, key.Name)
@@ -2088,24 +2930,38 @@ func (g *GCEGlobalAddresses) Get(ctx context.Context, key meta.Key) (*ga.Address
 }
 
 // List all Address objects.
-func (g *GCEGlobalAddresses) List(ctx context.Context, fl *filter.F) ([]*ga.Address, error) {
+func (g *GCEGlobalAddresses) List(ctx context.Context, fl *filter.F) (_ []*ga.Address, err error) {
 	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "GlobalAddresses")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "List",
-		Version:   meta.Version("ga"),
-		Service:   "GlobalAddresses",
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("ga"),
+			Service:   "GlobalAddresses",
+		},
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if g.s.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(ga.Address{})); err != nil {
+			return nil, err
+		}
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.GA.GlobalAddresses.List(projectID)
-	if fl != filter.None {
-		call.Filter(fl.String())
+	serverFl, clientFl := fl.Split(reflect.TypeOf(ga.Address{}))
+	if serverFl != filter.None {
+		call.Filter(serverFl.String())
 	}
 	var all []*ga.Address
 	f := func(l *ga.AddressList) error {
-		all = append(all, l.Items...)
+		for _, obj := range l.Items {
+			if clientFl.Match(obj) {
+				all = append(all, obj)
+			}
+		}
 		return nil
 	}
 	if err := call.Pages(ctx, f); err != nil {
@@ -2115,18 +2971,32 @@ func (g *GCEGlobalAddresses) List(ctx context.Context, fl *filter.F) ([]*ga.Addr
 }
 
 // Insert Address with key of value obj.
-func (g *GCEGlobalAddresses) Insert(ctx context.Context, key meta.Key, obj *ga.Address) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "GlobalAddresses")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Insert",
-		Version:   meta.Version("ga"),
-		Service:   "GlobalAddresses",
+func (g *GCEGlobalAddresses) Insert(ctx context.Context, key meta.Key, obj *ga.Address) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "GlobalAddresses")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Insert",
+			Version:   meta.Version("ga"),
+			Service:   "GlobalAddresses",
+		},
+		Key:     &key,
+		Request: obj,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("global"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
 	obj.Name = key.Name
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.GA.GlobalAddresses.Insert(projectID, obj)
 	call.Context(ctx)
 
@@ -2138,17 +3008,30 @@ func (g *GCEGlobalAddresses) Insert(ctx context.Context, key meta.Key, obj *ga.A
 }
 
 // Delete the Address referenced by key.
-func (g *GCEGlobalAddresses) Delete(ctx context.Context, key meta.Key) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "GlobalAddresses")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Delete",
-		Version:   meta.Version("ga"),
-		Service:   "GlobalAddresses",
+func (g *GCEGlobalAddresses) Delete(ctx context.Context, key meta.Key) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "GlobalAddresses")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Delete",
+			Version:   meta.Version("ga"),
+			Service:   "GlobalAddresses",
+		},
+		Key:     &key,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("global"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.GA.GlobalAddresses.Delete(projectID, key.Name)
 
 	call.Context(ctx)
@@ -2206,13 +3089,46 @@ type MockBackendServices struct {
 	GetHealthHook func(*MockBackendServices, context.Context, meta.Key, *ga.ResourceGroupReference) (*ga.BackendServiceGroupHealth, error)
 	UpdateHook    func(*MockBackendServices, context.Context, meta.Key, *ga.BackendService) error
 
+	// Observer, if set, is invoked around each call to the mock.
+	Observer CallObserver
+
+	// ProjectRouter, if set, routes the calls with keys that do not have a
+	// project. Calls are made to "mock-project" if it is not set.
+	ProjectRouter ProjectRouter
+
+	// Debug enables additional checks of the arguments to the mock, such as
+	// the validation of List() filters with filter.Validate().
+	Debug bool
+
+	// deleteCheck, if set, is called by Delete() with the resource being
+	// deleted. Delete() returns the error if it is not nil. See
+	// WithReferenceChecking().
+	deleteCheck func(id *ResourceID) error
+
 	// X is extra state that can be used as part of the mock. Generated code
 	// will not use this field.
 	X interface{}
 }
 
 // Get returns the object from the mock.
-func (m *MockBackendServices) Get(ctx context.Context, key meta.Key) (*ga.BackendService, error) {
+func (m *MockBackendServices) Get(ctx context.Context, key meta.Key) (_ *ga.BackendService, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "BackendServices")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("ga"),
+			Service:   "BackendServices",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("global"); err != nil {
+		return nil, err
+	}
+
 	if m.GetHook != nil {
 		if intercept, obj, err := m.GetHook(m, ctx, key); intercept {
 			glog.V(5).Infof("MockBackendServices.Get(%v, %s) = %+v, %v", ctx, key, obj, err)
@@ -2223,17 +3139,18 @@ func (m *MockBackendServices) Get(ctx context.Context, key meta.Key) (*ga.Backen
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.GetError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.GetError[skey]; ok {
 		glog.V(5).Infof("MockBackendServices.Get(%v, %s) = nil, %v", ctx, key, err)
 		return nil, err
 	}
-	if obj, ok := m.Objects[key]; ok {
+	if obj, ok := m.Objects[skey]; ok {
 		typedObj := obj.ToGA()
 		glog.V(5).Infof("MockBackendServices.Get(%v, %s) = %+v, nil", ctx, key, typedObj)
 		return typedObj, nil
 	}
 
-	err := &googleapi.Error{
+	err = &googleapi.Error{
 		Code:    http.StatusNotFound,
 		Message: fmt.Sprintf("MockBackendServices %v not found", key),
 	}
@@ -2242,7 +3159,26 @@ func (m *MockBackendServices) Get(ctx context.Context, key meta.Key) (*ga.Backen
 }
 
 // List all of the objects in the mock.
-func (m *MockBackendServices) List(ctx context.Context, fl *filter.F) ([]*ga.BackendService, error) {
+func (m *MockBackendServices) List(ctx context.Context, fl *filter.F) (_ []*ga.BackendService, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, nil, "ga", "BackendServices")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("ga"),
+			Service:   "BackendServices",
+		},
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if m.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(ga.BackendService{})); err != nil {
+			glog.V(5).Infof("MockBackendServices.List(%v, %v) = nil, %v", ctx, fl, err)
+			return nil, err
+		}
+	}
+
 	if m.ListHook != nil {
 		if intercept, objs, err := m.ListHook(m, ctx, fl); intercept {
 			glog.V(5).Infof("MockBackendServices.List(%v, %v) = [%v items], %v", ctx, fl, len(objs), err)
@@ -2261,7 +3197,11 @@ func (m *MockBackendServices) List(ctx context.Context, fl *filter.F) ([]*ga.Bac
 	}
 
 	var objs []*ga.BackendService
-	for _, obj := range m.Objects {
+	for key, obj := range m.Objects {
+		// Objects in other projects are not listed.
+		if key.Project != mockStorageProject(projectID) {
+			continue
+		}
 		if !fl.Match(obj.ToGA()) {
 			continue
 		}
@@ -2273,7 +3213,26 @@ func (m *MockBackendServices) List(ctx context.Context, fl *filter.F) ([]*ga.Bac
 }
 
 // Insert is a mock for inserting/creating a new object.
-func (m *MockBackendServices) Insert(ctx context.Context, key meta.Key, obj *ga.BackendService) error {
+func (m *MockBackendServices) Insert(ctx context.Context, key meta.Key, obj *ga.BackendService) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "BackendServices")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Insert",
+			Version:   meta.Version("ga"),
+			Service:   "BackendServices",
+		},
+		Key:     &key,
+		Request: obj,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("global"); err != nil {
+		return err
+	}
+
 	if m.InsertHook != nil {
 		if intercept, err := m.InsertHook(m, ctx, key, obj); intercept {
 			glog.V(5).Infof("MockBackendServices.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
@@ -2284,11 +3243,12 @@ func (m *MockBackendServices) Insert(ctx context.Context, key meta.Key, obj *ga.
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.InsertError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.InsertError[skey]; ok {
 		glog.V(5).Infof("MockBackendServices.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
 		return err
 	}
-	if _, ok := m.Objects[key]; ok {
+	if _, ok := m.Objects[skey]; ok {
 		err := &googleapi.Error{
 			Code:    http.StatusConflict,
 			Message: fmt.Sprintf("MockBackendServices %v exists", key),
@@ -2299,16 +3259,34 @@ func (m *MockBackendServices) Insert(ctx context.Context, key meta.Key, obj *ga.
 
 	obj.Name = key.Name
 	if obj.SelfLink == "" {
-		obj.SelfLink = SelfLink(meta.VersionGA, "mock-project", "backendServices", key)
+		obj.SelfLink = SelfLink(meta.VersionGA, projectID, "backendServices", key)
 	}
 
-	m.Objects[key] = &MockBackendServicesObj{obj}
+	m.Objects[skey] = &MockBackendServicesObj{obj}
 	glog.V(5).Infof("MockBackendServices.Insert(%v, %v, %+v) = nil", ctx, key, obj)
 	return nil
 }
 
 // Delete is a mock for deleting the object.
-func (m *MockBackendServices) Delete(ctx context.Context, key meta.Key) error {
+func (m *MockBackendServices) Delete(ctx context.Context, key meta.Key) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "BackendServices")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Delete",
+			Version:   meta.Version("ga"),
+			Service:   "BackendServices",
+		},
+		Key:     &key,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("global"); err != nil {
+		return err
+	}
+
 	if m.DeleteHook != nil {
 		if intercept, err := m.DeleteHook(m, ctx, key); intercept {
 			glog.V(5).Infof("MockBackendServices.Delete(%v, %v) = %v", ctx, key, err)
@@ -2316,14 +3294,22 @@ func (m *MockBackendServices) Delete(ctx context.Context, key meta.Key) error {
 		}
 	}
 
+	if m.deleteCheck != nil {
+		if err := m.deleteCheck(NewResourceID("backendServices", *key.WithProject(projectID))); err != nil {
+			glog.V(5).Infof("MockBackendServices.Delete(%v, %v) = %v", ctx, key, err)
+			return err
+		}
+	}
+
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.DeleteError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.DeleteError[skey]; ok {
 		glog.V(5).Infof("MockBackendServices.Delete(%v, %v) = %v", ctx, key, err)
 		return err
 	}
-	if _, ok := m.Objects[key]; !ok {
+	if _, ok := m.Objects[skey]; !ok {
 		err := &googleapi.Error{
 			Code:    http.StatusNotFound,
 			Message: fmt.Sprintf("MockBackendServices %v not found", key),
@@ -2332,7 +3318,7 @@ func (m *MockBackendServices) Delete(ctx context.Context, key meta.Key) error {
 		return err
 	}
 
-	delete(m.Objects, key)
+	delete(m.Objects, skey)
 	glog.V(5).Infof("MockBackendServices.Delete(%v, %v) = nil", ctx, key)
 	return nil
 }
@@ -2343,18 +3329,77 @@ func (m *MockBackendServices) Obj(o *ga.BackendService) *MockBackendServicesObj
 }
 
 // GetHealth is a mock for the corresponding method.
-func (m *MockBackendServices) GetHealth(ctx context.Context, key meta.Key, arg0 *ga.ResourceGroupReference) (*ga.BackendServiceGroupHealth, error) {
+func (m *MockBackendServices) GetHealth(ctx context.Context, key meta.Key, arg0 *ga.ResourceGroupReference) (_ *ga.BackendServiceGroupHealth, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "BackendServices")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "GetHealth",
+			Version:   meta.Version("ga"),
+			Service:   "BackendServices",
+		},
+		Key:     &key,
+		Request: arg0,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("global"); err != nil {
+		return nil, err
+	}
+
 	if m.GetHealthHook != nil {
 		return m.GetHealthHook(m, ctx, key, arg0)
 	}
 	return nil, fmt.Errorf("GetHealthHook must be set")
 }
 
-// Update is a mock for the corresponding method.
-func (m *MockBackendServices) Update(ctx context.Context, key meta.Key, arg0 *ga.BackendService) error {
+// Update is a mock for the corresponding method. The object is replaced
+// with arg0 if UpdateHook is not set.
+func (m *MockBackendServices) Update(ctx context.Context, key meta.Key, arg0 *ga.BackendService) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "BackendServices")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Update",
+			Version:   meta.Version("ga"),
+			Service:   "BackendServices",
+		},
+		Key:     &key,
+		Request: arg0,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("global"); err != nil {
+		return err
+	}
+
 	if m.UpdateHook != nil {
 		return m.UpdateHook(m, ctx, key, arg0)
 	}
+
+	m.Lock.Lock()
+	defer m.Lock.Unlock()
+
+	skey := mockStorageKey(key, projectID)
+	if _, ok := m.Objects[skey]; !ok {
+		err := &googleapi.Error{
+			Code:    http.StatusNotFound,
+			Message: fmt.Sprintf("MockBackendServices %v not found", key),
+		}
+		glog.V(5).Infof("MockBackendServices.Update(%v, %v, %+v) = %v", ctx, key, arg0, err)
+		return err
+	}
+
+	arg0.Name = key.Name
+	if arg0.SelfLink == "" {
+		arg0.SelfLink = SelfLink(meta.VersionGA, projectID, "backendServices", key)
+	}
+	// The fields that the version of arg0 does not have are kept, as in GCE.
+	m.Objects[skey] = &MockBackendServicesObj{mergeVersion(m.Objects[skey].Obj, arg0)}
+	glog.V(5).Infof("MockBackendServices.Update(%v, %v, %+v) = nil", ctx, key, arg0)
 	return nil
 }
 
@@ -2364,15 +3409,23 @@ type GCEBackendServices struct {
 }
 
 // Get the BackendService named by key.
-func (g *GCEBackendServices) Get(ctx context.Context, key meta.Key) (*ga.BackendService, error) {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "BackendServices")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Get",
-		Version:   meta.Version("ga"),
-		Service:   "BackendServices",
+func (g *GCEBackendServices) Get(ctx context.Context, key meta.Key) (_ *ga.BackendService, err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "BackendServices")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("ga"),
+			Service:   "BackendServices",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("global"); err != nil {
+		return nil, err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.GA.BackendServices.Get(projectID, key.Name)
@@ -2381,24 +3434,38 @@ func (g *GCEBackendServices) Get(ctx context.Context, key meta.Key) (*ga.Backend
 }
 
 // List all BackendService objects.
-func (g *GCEBackendServices) List(ctx context.Context, fl *filter.F) ([]*ga.BackendService, error) {
+func (g *GCEBackendServices) List(ctx context.Context, fl *filter.F) (_ []*ga.BackendService, err error) {
 	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "BackendServices")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "List",
-		Version:   meta.Version("ga"),
-		Service:   "BackendServices",
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("ga"),
+			Service:   "BackendServices",
+		},
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if g.s.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(ga.BackendService{})); err != nil {
+			return nil, err
+		}
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.GA.BackendServices.List(projectID)
-	if fl != filter.None {
-		call.Filter(fl.String())
+	serverFl, clientFl := fl.Split(reflect.TypeOf(ga.BackendService{}))
+	if serverFl != filter.None {
+		call.Filter(serverFl.String())
 	}
 	var all []*ga.BackendService
 	f := func(l *ga.BackendServiceList) error {
-		all = append(all, l.Items...)
+		for _, obj := range l.Items {
+			if clientFl.Match(obj) {
+				all = append(all, obj)
+			}
+		}
 		return nil
 	}
 	if err := call.Pages(ctx, f); err != nil {
@@ -2408,18 +3475,32 @@ func (g *GCEBackendServices) List(ctx context.Context, fl *filter.F) ([]*ga.Back
 }
 
 // Insert BackendService with key of value obj.
-func (g *GCEBackendServices) Insert(ctx context.Context, key meta.Key, obj *ga.BackendService) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "BackendServices")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Insert",
-		Version:   meta.Version("ga"),
-		Service:   "BackendServices",
+func (g *GCEBackendServices) Insert(ctx context.Context, key meta.Key, obj *ga.BackendService) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "BackendServices")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Insert",
+			Version:   meta.Version("ga"),
+			Service:   "BackendServices",
+		},
+		Key:     &key,
+		Request: obj,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("global"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
 	obj.Name = key.Name
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.GA.BackendServices.Insert(projectID, obj)
 	call.Context(ctx)
 
@@ -2431,17 +3512,30 @@ func (g *GCEBackendServices) Insert(ctx context.Context, key meta.Key, obj *ga.B
 }
 
 // Delete the BackendService referenced by key.
-func (g *GCEBackendServices) Delete(ctx context.Context, key meta.Key) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "BackendServices")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Delete",
-		Version:   meta.Version("ga"),
-		Service:   "BackendServices",
+func (g *GCEBackendServices) Delete(ctx context.Context, key meta.Key) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "BackendServices")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Delete",
+			Version:   meta.Version("ga"),
+			Service:   "BackendServices",
+		},
+		Key:     &key,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("global"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.GA.BackendServices.Delete(projectID, key.Name)
 
 	call.Context(ctx)
@@ -2454,15 +3548,24 @@ func (g *GCEBackendServices) Delete(ctx context.Context, key meta.Key) error {
 }
 
 // GetHealth is a method on GCEBackendServices.
-func (g *GCEBackendServices) GetHealth(ctx context.Context, key meta.Key, arg0 *ga.ResourceGroupReference) (*ga.BackendServiceGroupHealth, error) {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "BackendServices")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "GetHealth",
-		Version:   meta.Version("ga"),
-		Service:   "BackendServices",
+func (g *GCEBackendServices) GetHealth(ctx context.Context, key meta.Key, arg0 *ga.ResourceGroupReference) (_ *ga.BackendServiceGroupHealth, err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "BackendServices")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "GetHealth",
+			Version:   meta.Version("ga"),
+			Service:   "BackendServices",
+		},
+		Key:     &key,
+		Request: arg0,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("global"); err != nil {
+		return nil, err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.GA.BackendServices.GetHealth(projectID, key.Name, arg0)
@@ -2471,17 +3574,31 @@ func (g *GCEBackendServices) GetHealth(ctx context.Context, key meta.Key, arg0 *
 }
 
 // Update is a method on GCEBackendServices.
-func (g *GCEBackendServices) Update(ctx context.Context, key meta.Key, arg0 *ga.BackendService) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "BackendServices")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Update",
-		Version:   meta.Version("ga"),
-		Service:   "BackendServices",
+func (g *GCEBackendServices) Update(ctx context.Context, key meta.Key, arg0 *ga.BackendService) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "BackendServices")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Update",
+			Version:   meta.Version("ga"),
+			Service:   "BackendServices",
+		},
+		Key:     &key,
+		Request: arg0,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("global"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.GA.BackendServices.Update(projectID, key.Name, arg0)
 	call.Context(ctx)
 	op, err := call.Do()
@@ -2535,13 +3652,46 @@ type MockAlphaBackendServices struct {
 	DeleteHook func(m *MockAlphaBackendServices, ctx context.Context, key meta.Key) (bool, error)
 	UpdateHook func(*MockAlphaBackendServices, context.Context, meta.Key, *alpha.BackendService) error
 
+	// Observer, if set, is invoked around each call to the mock.
+	Observer CallObserver
+
+	// ProjectRouter, if set, routes the calls with keys that do not have a
+	// project. Calls are made to "mock-project" if it is not set.
+	ProjectRouter ProjectRouter
+
+	// Debug enables additional checks of the arguments to the mock, such as
+	// the validation of List() filters with filter.Validate().
+	Debug bool
+
+	// deleteCheck, if set, is called by Delete() with the resource being
+	// deleted. Delete() returns the error if it is not nil. See
+	// WithReferenceChecking().
+	deleteCheck func(id *ResourceID) error
+
 	// X is extra state that can be used as part of the mock. Generated code
 	// will not use this field.
 	X interface{}
 }
 
 // Get returns the object from the mock.
-func (m *MockAlphaBackendServices) Get(ctx context.Context, key meta.Key) (*alpha.BackendService, error) {
+func (m *MockAlphaBackendServices) Get(ctx context.Context, key meta.Key) (_ *alpha.BackendService, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "alpha", "BackendServices")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("alpha"),
+			Service:   "BackendServices",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("global"); err != nil {
+		return nil, err
+	}
+
 	if m.GetHook != nil {
 		if intercept, obj, err := m.GetHook(m, ctx, key); intercept {
 			glog.V(5).Infof("MockAlphaBackendServices.Get(%v, %s) = %+v, %v", ctx, key, obj, err)
@@ -2552,17 +3702,18 @@ func (m *MockAlphaBackendServices) Get(ctx context.Context, key meta.Key) (*alph
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.GetError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.GetError[skey]; ok {
 		glog.V(5).Infof("MockAlphaBackendServices.Get(%v, %s) = nil, %v", ctx, key, err)
 		return nil, err
 	}
-	if obj, ok := m.Objects[key]; ok {
+	if obj, ok := m.Objects[skey]; ok {
 		typedObj := obj.ToAlpha()
 		glog.V(5).Infof("MockAlphaBackendServices.Get(%v, %s) = %+v, nil", ctx, key, typedObj)
 		return typedObj, nil
 	}
 
-	err := &googleapi.Error{
+	err = &googleapi.Error{
 		Code:    http.StatusNotFound,
 		Message: fmt.Sprintf("MockAlphaBackendServices %v not found", key),
 	}
@@ -2571,7 +3722,26 @@ func (m *MockAlphaBackendServices) Get(ctx context.Context, key meta.Key) (*alph
 }
 
 // List all of the objects in the mock.
-func (m *MockAlphaBackendServices) List(ctx context.Context, fl *filter.F) ([]*alpha.BackendService, error) {
+func (m *MockAlphaBackendServices) List(ctx context.Context, fl *filter.F) (_ []*alpha.BackendService, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, nil, "alpha", "BackendServices")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("alpha"),
+			Service:   "BackendServices",
+		},
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if m.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(alpha.BackendService{})); err != nil {
+			glog.V(5).Infof("MockAlphaBackendServices.List(%v, %v) = nil, %v", ctx, fl, err)
+			return nil, err
+		}
+	}
+
 	if m.ListHook != nil {
 		if intercept, objs, err := m.ListHook(m, ctx, fl); intercept {
 			glog.V(5).Infof("MockAlphaBackendServices.List(%v, %v) = [%v items], %v", ctx, fl, len(objs), err)
@@ -2590,7 +3760,11 @@ func (m *MockAlphaBackendServices) List(ctx context.Context, fl *filter.F) ([]*a
 	}
 
 	var objs []*alpha.BackendService
-	for _, obj := range m.Objects {
+	for key, obj := range m.Objects {
+		// Objects in other projects are not listed.
+		if key.Project != mockStorageProject(projectID) {
+			continue
+		}
 		if !fl.Match(obj.ToAlpha()) {
 			continue
 		}
@@ -2602,7 +3776,26 @@ func (m *MockAlphaBackendServices) List(ctx context.Context, fl *filter.F) ([]*a
 }
 
 // Insert is a mock for inserting/creating a new object.
-func (m *MockAlphaBackendServices) Insert(ctx context.Context, key meta.Key, obj *alpha.BackendService) error {
+func (m *MockAlphaBackendServices) Insert(ctx context.Context, key meta.Key, obj *alpha.BackendService) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "alpha", "BackendServices")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Insert",
+			Version:   meta.Version("alpha"),
+			Service:   "BackendServices",
+		},
+		Key:     &key,
+		Request: obj,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("global"); err != nil {
+		return err
+	}
+
 	if m.InsertHook != nil {
 		if intercept, err := m.InsertHook(m, ctx, key, obj); intercept {
 			glog.V(5).Infof("MockAlphaBackendServices.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
@@ -2613,11 +3806,12 @@ func (m *MockAlphaBackendServices) Insert(ctx context.Context, key meta.Key, obj
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.InsertError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.InsertError[skey]; ok {
 		glog.V(5).Infof("MockAlphaBackendServices.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
 		return err
 	}
-	if _, ok := m.Objects[key]; ok {
+	if _, ok := m.Objects[skey]; ok {
 		err := &googleapi.Error{
 			Code:    http.StatusConflict,
 			Message: fmt.Sprintf("MockAlphaBackendServices %v exists", key),
@@ -2628,16 +3822,34 @@ func (m *MockAlphaBackendServices) Insert(ctx context.Context, key meta.Key, obj
 
 	obj.Name = key.Name
 	if obj.SelfLink == "" {
-		obj.SelfLink = SelfLink(meta.VersionAlpha, "mock-project", "backendServices", key)
+		obj.SelfLink = SelfLink(meta.VersionAlpha, projectID, "backendServices", key)
 	}
 
-	m.Objects[key] = &MockBackendServicesObj{obj}
+	m.Objects[skey] = &MockBackendServicesObj{obj}
 	glog.V(5).Infof("MockAlphaBackendServices.Insert(%v, %v, %+v) = nil", ctx, key, obj)
 	return nil
 }
 
 // Delete is a mock for deleting the object.
-func (m *MockAlphaBackendServices) Delete(ctx context.Context, key meta.Key) error {
+func (m *MockAlphaBackendServices) Delete(ctx context.Context, key meta.Key) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "alpha", "BackendServices")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Delete",
+			Version:   meta.Version("alpha"),
+			Service:   "BackendServices",
+		},
+		Key:     &key,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("global"); err != nil {
+		return err
+	}
+
 	if m.DeleteHook != nil {
 		if intercept, err := m.DeleteHook(m, ctx, key); intercept {
 			glog.V(5).Infof("MockAlphaBackendServices.Delete(%v, %v) = %v", ctx, key, err)
@@ -2645,14 +3857,22 @@ func (m *MockAlphaBackendServices) Delete(ctx context.Context, key meta.Key) err
 		}
 	}
 
+	if m.deleteCheck != nil {
+		if err := m.deleteCheck(NewResourceID("backendServices", *key.WithProject(projectID))); err != nil {
+			glog.V(5).Infof("MockAlphaBackendServices.Delete(%v, %v) = %v", ctx, key, err)
+			return err
+		}
+	}
+
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.DeleteError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.DeleteError[skey]; ok {
 		glog.V(5).Infof("MockAlphaBackendServices.Delete(%v, %v) = %v", ctx, key, err)
 		return err
 	}
-	if _, ok := m.Objects[key]; !ok {
+	if _, ok := m.Objects[skey]; !ok {
 		err := &googleapi.Error{
 			Code:    http.StatusNotFound,
 			Message: fmt.Sprintf("MockAlphaBackendServices %v not found", key),
@@ -2661,7 +3881,7 @@ func (m *MockAlphaBackendServices) Delete(ctx context.Context, key meta.Key) err
 		return err
 	}
 
-	delete(m.Objects, key)
+	delete(m.Objects, skey)
 	glog.V(5).Infof("MockAlphaBackendServices.Delete(%v, %v) = nil", ctx, key)
 	return nil
 }
@@ -2671,11 +3891,52 @@ func (m *MockAlphaBackendServices) Obj(o *alpha.BackendService) *MockBackendServ
 	return &MockBackendServicesObj{o}
 }
 
-// Update is a mock for the corresponding method.
-func (m *MockAlphaBackendServices) Update(ctx context.Context, key meta.Key, arg0 *alpha.BackendService) error {
+// Update is a mock for the corresponding method. The object is replaced
+// with arg0 if UpdateHook is not set.
+func (m *MockAlphaBackendServices) Update(ctx context.Context, key meta.Key, arg0 *alpha.BackendService) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "alpha", "BackendServices")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Update",
+			Version:   meta.Version("alpha"),
+			Service:   "BackendServices",
+		},
+		Key:     &key,
+		Request: arg0,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("global"); err != nil {
+		return err
+	}
+
 	if m.UpdateHook != nil {
 		return m.UpdateHook(m, ctx, key, arg0)
 	}
+
+	m.Lock.Lock()
+	defer m.Lock.Unlock()
+
+	skey := mockStorageKey(key, projectID)
+	if _, ok := m.Objects[skey]; !ok {
+		err := &googleapi.Error{
+			Code:    http.StatusNotFound,
+			Message: fmt.Sprintf("MockAlphaBackendServices %v not found", key),
+		}
+		glog.V(5).Infof("MockAlphaBackendServices.Update(%v, %v, %+v) = %v", ctx, key, arg0, err)
+		return err
+	}
+
+	arg0.Name = key.Name
+	if arg0.SelfLink == "" {
+		arg0.SelfLink = SelfLink(meta.VersionAlpha, projectID, "backendServices", key)
+	}
+	// The fields that the version of arg0 does not have are kept, as in GCE.
+	m.Objects[skey] = &MockBackendServicesObj{mergeVersion(m.Objects[skey].Obj, arg0)}
+	glog.V(5).Infof("MockAlphaBackendServices.Update(%v, %v, %+v) = nil", ctx, key, arg0)
 	return nil
 }
 
@@ -2685,15 +3946,23 @@ type GCEAlphaBackendServices struct {
 }
 
 // Get the BackendService named by key.
-func (g *GCEAlphaBackendServices) Get(ctx context.Context, key meta.Key) (*alpha.BackendService, error) {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "alpha", "BackendServices")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Get",
-		Version:   meta.Version("alpha"),
-		Service:   "BackendServices",
+func (g *GCEAlphaBackendServices) Get(ctx context.Context, key meta.Key) (_ *alpha.BackendService, err error) {
+	projectID := g.s.keyProjectID(ctx, key, "alpha", "BackendServices")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("alpha"),
+			Service:   "BackendServices",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("global"); err != nil {
+		return nil, err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.Alpha.BackendServices.Get(projectID, key.Name)
@@ -2702,24 +3971,38 @@ func (g *GCEAlphaBackendServices) Get(ctx context.Context, key meta.Key) (*alpha
 }
 
 // List all BackendService objects.
-func (g *GCEAlphaBackendServices) List(ctx context.Context, fl *filter.F) ([]*alpha.BackendService, error) {
+func (g *GCEAlphaBackendServices) List(ctx context.Context, fl *filter.F) (_ []*alpha.BackendService, err error) {
 	projectID := g.s.ProjectRouter.ProjectID(ctx, "alpha", "BackendServices")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "List",
-		Version:   meta.Version("alpha"),
-		Service:   "BackendServices",
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("alpha"),
+			Service:   "BackendServices",
+		},
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if g.s.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(alpha.BackendService{})); err != nil {
+			return nil, err
+		}
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.Alpha.BackendServices.List(projectID)
-	if fl != filter.None {
-		call.Filter(fl.String())
+	serverFl, clientFl := fl.Split(reflect.TypeOf(alpha.BackendService{}))
+	if serverFl != filter.None {
+		call.Filter(serverFl.String())
 	}
 	var all []*alpha.BackendService
 	f := func(l *alpha.BackendServiceList) error {
-		all = append(all, l.Items...)
+		for _, obj := range l.Items {
+			if clientFl.Match(obj) {
+				all = append(all, obj)
+			}
+		}
 		return nil
 	}
 	if err := call.Pages(ctx, f); err != nil {
@@ -2729,18 +4012,32 @@ func (g *GCEAlphaBackendServices) List(ctx context.Context, fl *filter.F) ([]*al
 }
 
 // Insert BackendService with key of value obj.
-func (g *GCEAlphaBackendServices) Insert(ctx context.Context, key meta.Key, obj *alpha.BackendService) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "alpha", "BackendServices")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Insert",
-		Version:   meta.Version("alpha"),
-		Service:   "BackendServices",
+func (g *GCEAlphaBackendServices) Insert(ctx context.Context, key meta.Key, obj *alpha.BackendService) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "alpha", "BackendServices")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Insert",
+			Version:   meta.Version("alpha"),
+			Service:   "BackendServices",
+		},
+		Key:     &key,
+		Request: obj,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("global"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
 	obj.Name = key.Name
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.Alpha.BackendServices.Insert(projectID, obj)
 	call.Context(ctx)
 
@@ -2752,17 +4049,30 @@ func (g *GCEAlphaBackendServices) Insert(ctx context.Context, key meta.Key, obj
 }
 
 // Delete the BackendService referenced by key.
-func (g *GCEAlphaBackendServices) Delete(ctx context.Context, key meta.Key) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "alpha", "BackendServices")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Delete",
-		Version:   meta.Version("alpha"),
-		Service:   "BackendServices",
+func (g *GCEAlphaBackendServices) Delete(ctx context.Context, key meta.Key) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "alpha", "BackendServices")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Delete",
+			Version:   meta.Version("alpha"),
+			Service:   "BackendServices",
+		},
+		Key:     &key,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("global"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.Alpha.BackendServices.Delete(projectID, key.Name)
 
 	call.Context(ctx)
@@ -2775,17 +4085,31 @@ func (g *GCEAlphaBackendServices) Delete(ctx context.Context, key meta.Key) erro
 }
 
 // Update is a method on GCEAlphaBackendServices.
-func (g *GCEAlphaBackendServices) Update(ctx context.Context, key meta.Key, arg0 *alpha.BackendService) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "alpha", "BackendServices")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Update",
-		Version:   meta.Version("alpha"),
-		Service:   "BackendServices",
+func (g *GCEAlphaBackendServices) Update(ctx context.Context, key meta.Key, arg0 *alpha.BackendService) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "alpha", "BackendServices")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Update",
+			Version:   meta.Version("alpha"),
+			Service:   "BackendServices",
+		},
+		Key:     &key,
+		Request: arg0,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("global"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.Alpha.BackendServices.Update(projectID, key.Name, arg0)
 	call.Context(ctx)
 	op, err := call.Do()
@@ -2841,13 +4165,46 @@ type MockAlphaRegionBackendServices struct {
 	GetHealthHook func(*MockAlphaRegionBackendServices, context.Context, meta.Key, *alpha.ResourceGroupReference) (*alpha.BackendServiceGroupHealth, error)
 	UpdateHook    func(*MockAlphaRegionBackendServices, context.Context, meta.Key, *alpha.BackendService) error
 
+	// Observer, if set, is invoked around each call to the mock.
+	Observer CallObserver
+
+	// ProjectRouter, if set, routes the calls with keys that do not have a
+	// project. Calls are made to "mock-project" if it is not set.
+	ProjectRouter ProjectRouter
+
+	// Debug enables additional checks of the arguments to the mock, such as
+	// the validation of List() filters with filter.Validate().
+	Debug bool
+
+	// deleteCheck, if set, is called by Delete() with the resource being
+	// deleted. Delete() returns the error if it is not nil. See
+	// WithReferenceChecking().
+	deleteCheck func(id *ResourceID) error
+
 	// X is extra state that can be used as part of the mock. Generated code
 	// will not use this field.
 	X interface{}
 }
 
 // Get returns the object from the mock.
-func (m *MockAlphaRegionBackendServices) Get(ctx context.Context, key meta.Key) (*alpha.BackendService, error) {
+func (m *MockAlphaRegionBackendServices) Get(ctx context.Context, key meta.Key) (_ *alpha.BackendService, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "alpha", "RegionBackendServices")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("alpha"),
+			Service:   "RegionBackendServices",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("regional"); err != nil {
+		return nil, err
+	}
+
 	if m.GetHook != nil {
 		if intercept, obj, err := m.GetHook(m, ctx, key); intercept {
 			glog.V(5).Infof("MockAlphaRegionBackendServices.Get(%v, %s) = %+v, %v", ctx, key, obj, err)
@@ -2858,17 +4215,18 @@ func (m *MockAlphaRegionBackendServices) Get(ctx context.Context, key meta.Key)
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.GetError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.GetError[skey]; ok {
 		glog.V(5).Infof("MockAlphaRegionBackendServices.Get(%v, %s) = nil, %v", ctx, key, err)
 		return nil, err
 	}
-	if obj, ok := m.Objects[key]; ok {
+	if obj, ok := m.Objects[skey]; ok {
 		typedObj := obj.ToAlpha()
 		glog.V(5).Infof("MockAlphaRegionBackendServices.Get(%v, %s) = %+v, nil", ctx, key, typedObj)
 		return typedObj, nil
 	}
 
-	err := &googleapi.Error{
+	err = &googleapi.Error{
 		Code:    http.StatusNotFound,
 		Message: fmt.Sprintf("MockAlphaRegionBackendServices %v not found", key),
 	}
@@ -2877,7 +4235,26 @@ func (m *MockAlphaRegionBackendServices) Get(ctx context.Context, key meta.Key)
 }
 
 // List all of the objects in the mock in the given region.
-func (m *MockAlphaRegionBackendServices) List(ctx context.Context, region string, fl *filter.F) ([]*alpha.BackendService, error) {
+func (m *MockAlphaRegionBackendServices) List(ctx context.Context, region string, fl *filter.F) (_ []*alpha.BackendService, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, nil, "alpha", "RegionBackendServices")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("alpha"),
+			Service:   "RegionBackendServices",
+		},
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if m.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(alpha.BackendService{})); err != nil {
+			glog.V(5).Infof("MockAlphaRegionBackendServices.List(%v, %v) = nil, %v", ctx, fl, err)
+			return nil, err
+		}
+	}
+
 	if m.ListHook != nil {
 		if intercept, objs, err := m.ListHook(m, ctx, region, fl); intercept {
 			glog.V(5).Infof("MockAlphaRegionBackendServices.List(%v, %q, %v) = [%v items], %v", ctx, region, fl, len(objs), err)
@@ -2897,6 +4274,10 @@ func (m *MockAlphaRegionBackendServices) List(ctx context.Context, region string
 
 	var objs []*alpha.BackendService
 	for key, obj := range m.Objects {
+		// Objects in other projects are not listed.
+		if key.Project != mockStorageProject(projectID) {
+			continue
+		}
 		if key.Region != region {
 			continue
 		}
@@ -2911,7 +4292,26 @@ func (m *MockAlphaRegionBackendServices) List(ctx context.Context, region string
 }
 
 // Insert is a mock for inserting/creating a new object.
-func (m *MockAlphaRegionBackendServices) Insert(ctx context.Context, key meta.Key, obj *alpha.BackendService) error {
+func (m *MockAlphaRegionBackendServices) Insert(ctx context.Context, key meta.Key, obj *alpha.BackendService) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "alpha", "RegionBackendServices")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Insert",
+			Version:   meta.Version("alpha"),
+			Service:   "RegionBackendServices",
+		},
+		Key:     &key,
+		Request: obj,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("regional"); err != nil {
+		return err
+	}
+
 	if m.InsertHook != nil {
 		if intercept, err := m.InsertHook(m, ctx, key, obj); intercept {
 			glog.V(5).Infof("MockAlphaRegionBackendServices.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
@@ -2922,11 +4322,12 @@ func (m *MockAlphaRegionBackendServices) Insert(ctx context.Context, key meta.Ke
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.InsertError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.InsertError[skey]; ok {
 		glog.V(5).Infof("MockAlphaRegionBackendServices.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
 		return err
 	}
-	if _, ok := m.Objects[key]; ok {
+	if _, ok := m.Objects[skey]; ok {
 		err := &googleapi.Error{
 			Code:    http.StatusConflict,
 			Message: fmt.Sprintf("MockAlphaRegionBackendServices %v exists", key),
@@ -2937,16 +4338,34 @@ func (m *MockAlphaRegionBackendServices) Insert(ctx context.Context, key meta.Ke
 
 	obj.Name = key.Name
 	if obj.SelfLink == "" {
-		obj.SelfLink = SelfLink(meta.VersionAlpha, "mock-project", "backendServices", key)
+		obj.SelfLink = SelfLink(meta.VersionAlpha, projectID, "backendServices", key)
 	}
 
-	m.Objects[key] = &MockRegionBackendServicesObj{obj}
+	m.Objects[skey] = &MockRegionBackendServicesObj{obj}
 	glog.V(5).Infof("MockAlphaRegionBackendServices.Insert(%v, %v, %+v) = nil", ctx, key, obj)
 	return nil
 }
 
 // Delete is a mock for deleting the object.
-func (m *MockAlphaRegionBackendServices) Delete(ctx context.Context, key meta.Key) error {
+func (m *MockAlphaRegionBackendServices) Delete(ctx context.Context, key meta.Key) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "alpha", "RegionBackendServices")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Delete",
+			Version:   meta.Version("alpha"),
+			Service:   "RegionBackendServices",
+		},
+		Key:     &key,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("regional"); err != nil {
+		return err
+	}
+
 	if m.DeleteHook != nil {
 		if intercept, err := m.DeleteHook(m, ctx, key); intercept {
 			glog.V(5).Infof("MockAlphaRegionBackendServices.Delete(%v, %v) = %v", ctx, key, err)
@@ -2954,14 +4373,22 @@ func (m *MockAlphaRegionBackendServices) Delete(ctx context.Context, key meta.Ke
 		}
 	}
 
+	if m.deleteCheck != nil {
+		if err := m.deleteCheck(NewResourceID("backendServices", *key.WithProject(projectID))); err != nil {
+			glog.V(5).Infof("MockAlphaRegionBackendServices.Delete(%v, %v) = %v", ctx, key, err)
+			return err
+		}
+	}
+
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.DeleteError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.DeleteError[skey]; ok {
 		glog.V(5).Infof("MockAlphaRegionBackendServices.Delete(%v, %v) = %v", ctx, key, err)
 		return err
 	}
-	if _, ok := m.Objects[key]; !ok {
+	if _, ok := m.Objects[skey]; !ok {
 		err := &googleapi.Error{
 			Code:    http.StatusNotFound,
 			Message: fmt.Sprintf("MockAlphaRegionBackendServices %v not found", key),
@@ -2970,7 +4397,7 @@ func (m *MockAlphaRegionBackendServices) Delete(ctx context.Context, key meta.Ke
 		return err
 	}
 
-	delete(m.Objects, key)
+	delete(m.Objects, skey)
 	glog.V(5).Infof("MockAlphaRegionBackendServices.Delete(%v, %v) = nil", ctx, key)
 	return nil
 }
@@ -2981,18 +4408,77 @@ func (m *MockAlphaRegionBackendServices) Obj(o *alpha.BackendService) *MockRegio
 }
 
 // GetHealth is a mock for the corresponding method.
-func (m *MockAlphaRegionBackendServices) GetHealth(ctx context.Context, key meta.Key, arg0 *alpha.ResourceGroupReference) (*alpha.BackendServiceGroupHealth, error) {
+func (m *MockAlphaRegionBackendServices) GetHealth(ctx context.Context, key meta.Key, arg0 *alpha.ResourceGroupReference) (_ *alpha.BackendServiceGroupHealth, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "alpha", "RegionBackendServices")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "GetHealth",
+			Version:   meta.Version("alpha"),
+			Service:   "RegionBackendServices",
+		},
+		Key:     &key,
+		Request: arg0,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("regional"); err != nil {
+		return nil, err
+	}
+
 	if m.GetHealthHook != nil {
 		return m.GetHealthHook(m, ctx, key, arg0)
 	}
 	return nil, fmt.Errorf("GetHealthHook must be set")
 }
 
-// Update is a mock for the corresponding method.
-func (m *MockAlphaRegionBackendServices) Update(ctx context.Context, key meta.Key, arg0 *alpha.BackendService) error {
+// Update is a mock for the corresponding method. The object is replaced
+// with arg0 if UpdateHook is not set.
+func (m *MockAlphaRegionBackendServices) Update(ctx context.Context, key meta.Key, arg0 *alpha.BackendService) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "alpha", "RegionBackendServices")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Update",
+			Version:   meta.Version("alpha"),
+			Service:   "RegionBackendServices",
+		},
+		Key:     &key,
+		Request: arg0,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("regional"); err != nil {
+		return err
+	}
+
 	if m.UpdateHook != nil {
 		return m.UpdateHook(m, ctx, key, arg0)
 	}
+
+	m.Lock.Lock()
+	defer m.Lock.Unlock()
+
+	skey := mockStorageKey(key, projectID)
+	if _, ok := m.Objects[skey]; !ok {
+		err := &googleapi.Error{
+			Code:    http.StatusNotFound,
+			Message: fmt.Sprintf("MockAlphaRegionBackendServices %v not found", key),
+		}
+		glog.V(5).Infof("MockAlphaRegionBackendServices.Update(%v, %v, %+v) = %v", ctx, key, arg0, err)
+		return err
+	}
+
+	arg0.Name = key.Name
+	if arg0.SelfLink == "" {
+		arg0.SelfLink = SelfLink(meta.VersionAlpha, projectID, "backendServices", key)
+	}
+	// The fields that the version of arg0 does not have are kept, as in GCE.
+	m.Objects[skey] = &MockRegionBackendServicesObj{mergeVersion(m.Objects[skey].Obj, arg0)}
+	glog.V(5).Infof("MockAlphaRegionBackendServices.Update(%v, %v, %+v) = nil", ctx, key, arg0)
 	return nil
 }
 
@@ -3002,15 +4488,23 @@ type GCEAlphaRegionBackendServices struct {
 }
 
 // Get the BackendService named by key.
-func (g *GCEAlphaRegionBackendServices) Get(ctx context.Context, key meta.Key) (*alpha.BackendService, error) {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "alpha", "RegionBackendServices")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Get",
-		Version:   meta.Version("alpha"),
-		Service:   "RegionBackendServices",
+func (g *GCEAlphaRegionBackendServices) Get(ctx context.Context, key meta.Key) (_ *alpha.BackendService, err error) {
+	projectID := g.s.keyProjectID(ctx, key, "alpha", "RegionBackendServices")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("alpha"),
+			Service:   "RegionBackendServices",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("regional"); err != nil {
+		return nil, err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.Alpha.RegionBackendServices.Get(projectID, key.Region, key.Name)
@@ -3019,24 +4513,38 @@ func (g *GCEAlphaRegionBackendServices) Get(ctx context.Context, key meta.Key) (
 }
 
 // List all BackendService objects.
-func (g *GCEAlphaRegionBackendServices) List(ctx context.Context, region string, fl *filter.F) ([]*alpha.BackendService, error) {
+func (g *GCEAlphaRegionBackendServices) List(ctx context.Context, region string, fl *filter.F) (_ []*alpha.BackendService, err error) {
 	projectID := g.s.ProjectRouter.ProjectID(ctx, "alpha", "RegionBackendServices")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "List",
-		Version:   meta.Version("alpha"),
-		Service:   "RegionBackendServices",
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("alpha"),
+			Service:   "RegionBackendServices",
+		},
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if g.s.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(alpha.BackendService{})); err != nil {
+			return nil, err
+		}
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.Alpha.RegionBackendServices.List(projectID, region)
-	if fl != filter.None {
-		call.Filter(fl.String())
+	serverFl, clientFl := fl.Split(reflect.TypeOf(alpha.BackendService{}))
+	if serverFl != filter.None {
+		call.Filter(serverFl.String())
 	}
 	var all []*alpha.BackendService
 	f := func(l *alpha.BackendServiceList) error {
-		all = append(all, l.Items...)
+		for _, obj := range l.Items {
+			if clientFl.Match(obj) {
+				all = append(all, obj)
+			}
+		}
 		return nil
 	}
 	if err := call.Pages(ctx, f); err != nil {
@@ -3046,18 +4554,32 @@ func (g *GCEAlphaRegionBackendServices) List(ctx context.Context, region string,
 }
 
 // Insert BackendService with key of value obj.
-func (g *GCEAlphaRegionBackendServices) Insert(ctx context.Context, key meta.Key, obj *alpha.BackendService) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "alpha", "RegionBackendServices")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Insert",
-		Version:   meta.Version("alpha"),
-		Service:   "RegionBackendServices",
+func (g *GCEAlphaRegionBackendServices) Insert(ctx context.Context, key meta.Key, obj *alpha.BackendService) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "alpha", "RegionBackendServices")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Insert",
+			Version:   meta.Version("alpha"),
+			Service:   "RegionBackendServices",
+		},
+		Key:     &key,
+		Request: obj,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("regional"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
 	obj.Name = key.Name
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.Alpha.RegionBackendServices.Insert(projectID, key.Region, obj)
 	call.Context(ctx)
 
@@ -3069,17 +4591,30 @@ func (g *GCEAlphaRegionBackendServices) Insert(ctx context.Context, key meta.Key
 }
 
 // Delete the BackendService referenced by key.
-func (g *GCEAlphaRegionBackendServices) Delete(ctx context.Context, key meta.Key) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "alpha", "RegionBackendServices")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Delete",
-		Version:   meta.Version("alpha"),
-		Service:   "RegionBackendServices",
+func (g *GCEAlphaRegionBackendServices) Delete(ctx context.Context, key meta.Key) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "alpha", "RegionBackendServices")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Delete",
+			Version:   meta.Version("alpha"),
+			Service:   "RegionBackendServices",
+		},
+		Key:     &key,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("regional"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.Alpha.RegionBackendServices.Delete(projectID, key.Region, key.Name)
 	call.Context(ctx)
 
@@ -3091,15 +4626,24 @@ func (g *GCEAlphaRegionBackendServices) Delete(ctx context.Context, key meta.Key
 }
 
 // GetHealth is a method on GCEAlphaRegionBackendServices.
-func (g *GCEAlphaRegionBackendServices) GetHealth(ctx context.Context, key meta.Key, arg0 *alpha.ResourceGroupReference) (*alpha.BackendServiceGroupHealth, error) {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "alpha", "RegionBackendServices")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "GetHealth",
-		Version:   meta.Version("alpha"),
-		Service:   "RegionBackendServices",
+func (g *GCEAlphaRegionBackendServices) GetHealth(ctx context.Context, key meta.Key, arg0 *alpha.ResourceGroupReference) (_ *alpha.BackendServiceGroupHealth, err error) {
+	projectID := g.s.keyProjectID(ctx, key, "alpha", "RegionBackendServices")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "GetHealth",
+			Version:   meta.Version("alpha"),
+			Service:   "RegionBackendServices",
+		},
+		Key:     &key,
+		Request: arg0,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("regional"); err != nil {
+		return nil, err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.Alpha.RegionBackendServices.GetHealth(projectID, key.Region, key.Name, arg0)
@@ -3108,17 +4652,31 @@ func (g *GCEAlphaRegionBackendServices) GetHealth(ctx context.Context, key meta.
 }
 
 // Update is a method on GCEAlphaRegionBackendServices.
-func (g *GCEAlphaRegionBackendServices) Update(ctx context.Context, key meta.Key, arg0 *alpha.BackendService) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "alpha", "RegionBackendServices")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Update",
-		Version:   meta.Version("alpha"),
-		Service:   "RegionBackendServices",
+func (g *GCEAlphaRegionBackendServices) Update(ctx context.Context, key meta.Key, arg0 *alpha.BackendService) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "alpha", "RegionBackendServices")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Update",
+			Version:   meta.Version("alpha"),
+			Service:   "RegionBackendServices",
+		},
+		Key:     &key,
+		Request: arg0,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("regional"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.Alpha.RegionBackendServices.Update(projectID, key.Region, key.Name, arg0)
 	call.Context(ctx)
 	op, err := call.Do()
@@ -3170,13 +4728,46 @@ type MockDisks struct {
 	InsertHook func(m *MockDisks, ctx context.Context, key meta.Key, obj *ga.Disk) (bool, error)
 	DeleteHook func(m *MockDisks, ctx context.Context, key meta.Key) (bool, error)
 
+	// Observer, if set, is invoked around each call to the mock.
+	Observer CallObserver
+
+	// ProjectRouter, if set, routes the calls with keys that do not have a
+	// project. Calls are made to "mock-project" if it is not set.
+	ProjectRouter ProjectRouter
+
+	// Debug enables additional checks of the arguments to the mock, such as
+	// the validation of List() filters with filter.Validate().
+	Debug bool
+
+	// deleteCheck, if set, is called by Delete() with the resource being
+	// deleted. Delete() returns the error if it is not nil. See
+	// WithReferenceChecking().
+	deleteCheck func(id *ResourceID) error
+
 	// X is extra state that can be used as part of the mock. Generated code
 	// will not use this field.
 	X interface{}
 }
 
 // Get returns the object from the mock.
-func (m *MockDisks) Get(ctx context.Context, key meta.Key) (*ga.Disk, error) {
+func (m *MockDisks) Get(ctx context.Context, key meta.Key) (_ *ga.Disk, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "Disks")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("ga"),
+			Service:   "Disks",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("zonal"); err != nil {
+		return nil, err
+	}
+
 	if m.GetHook != nil {
 		if intercept, obj, err := m.GetHook(m, ctx, key); intercept {
 			glog.V(5).Infof("MockDisks.Get(%v, %s) = %+v, %v", ctx, key, obj, err)
@@ -3187,17 +4778,18 @@ func (m *MockDisks) Get(ctx context.Context, key meta.Key) (*ga.Disk, error) {
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.GetError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.GetError[skey]; ok {
 		glog.V(5).Infof("MockDisks.Get(%v, %s) = nil, %v", ctx, key, err)
 		return nil, err
 	}
-	if obj, ok := m.Objects[key]; ok {
+	if obj, ok := m.Objects[skey]; ok {
 		typedObj := obj.ToGA()
 		glog.V(5).Infof("MockDisks.Get(%v, %s) = %+v, nil", ctx, key, typedObj)
 		return typedObj, nil
 	}
 
-	err := &googleapi.Error{
+	err = &googleapi.Error{
 		Code:    http.StatusNotFound,
 		Message: fmt.Sprintf("MockDisks %v not found", key),
 	}
@@ -3206,7 +4798,26 @@ func (m *MockDisks) Get(ctx context.Context, key meta.Key) (*ga.Disk, error) {
 }
 
 // List all of the objects in the mock in the given zone.
-func (m *MockDisks) List(ctx context.Context, zone string, fl *filter.F) ([]*ga.Disk, error) {
+func (m *MockDisks) List(ctx context.Context, zone string, fl *filter.F) (_ []*ga.Disk, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, nil, "ga", "Disks")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("ga"),
+			Service:   "Disks",
+		},
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if m.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(ga.Disk{})); err != nil {
+			glog.V(5).Infof("MockDisks.List(%v, %v) = nil, %v", ctx, fl, err)
+			return nil, err
+		}
+	}
+
 	if m.ListHook != nil {
 		if intercept, objs, err := m.ListHook(m, ctx, zone, fl); intercept {
 			glog.V(5).Infof("MockDisks.List(%v, %q, %v) = [%v items], %v", ctx, zone, fl, len(objs), err)
@@ -3226,6 +4837,10 @@ func (m *MockDisks) List(ctx context.Context, zone string, fl *filter.F) ([]*ga.
 
 	var objs []*ga.Disk
 	for key, obj := range m.Objects {
+		// Objects in other projects are not listed.
+		if key.Project != mockStorageProject(projectID) {
+			continue
+		}
 		if key.Zone != zone {
 			continue
 		}
@@ -3240,7 +4855,26 @@ func (m *MockDisks) List(ctx context.Context, zone string, fl *filter.F) ([]*ga.
 }
 
 // Insert is a mock for inserting/creating a new object.
-func (m *MockDisks) Insert(ctx context.Context, key meta.Key, obj *ga.Disk) error {
+func (m *MockDisks) Insert(ctx context.Context, key meta.Key, obj *ga.Disk) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "Disks")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Insert",
+			Version:   meta.Version("ga"),
+			Service:   "Disks",
+		},
+		Key:     &key,
+		Request: obj,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("zonal"); err != nil {
+		return err
+	}
+
 	if m.InsertHook != nil {
 		if intercept, err := m.InsertHook(m, ctx, key, obj); intercept {
 			glog.V(5).Infof("MockDisks.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
@@ -3251,11 +4885,12 @@ func (m *MockDisks) Insert(ctx context.Context, key meta.Key, obj *ga.Disk) erro
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.InsertError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.InsertError[skey]; ok {
 		glog.V(5).Infof("MockDisks.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
 		return err
 	}
-	if _, ok := m.Objects[key]; ok {
+	if _, ok := m.Objects[skey]; ok {
 		err := &googleapi.Error{
 			Code:    http.StatusConflict,
 			Message: fmt.Sprintf("MockDisks %v exists", key),
@@ -3266,16 +4901,34 @@ func (m *MockDisks) Insert(ctx context.Context, key meta.Key, obj *ga.Disk) erro
 
 	obj.Name = key.Name
 	if obj.SelfLink == "" {
-		obj.SelfLink = SelfLink(meta.VersionGA, "mock-project", "disks", key)
+		obj.SelfLink = SelfLink(meta.VersionGA, projectID, "disks", key)
 	}
 
-	m.Objects[key] = &MockDisksObj{obj}
+	m.Objects[skey] = &MockDisksObj{obj}
 	glog.V(5).Infof("MockDisks.Insert(%v, %v, %+v) = nil", ctx, key, obj)
 	return nil
 }
 
 // Delete is a mock for deleting the object.
-func (m *MockDisks) Delete(ctx context.Context, key meta.Key) error {
+func (m *MockDisks) Delete(ctx context.Context, key meta.Key) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "Disks")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Delete",
+			Version:   meta.Version("ga"),
+			Service:   "Disks",
+		},
+		Key:     &key,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("zonal"); err != nil {
+		return err
+	}
+
 	if m.DeleteHook != nil {
 		if intercept, err := m.DeleteHook(m, ctx, key); intercept {
 			glog.V(5).Infof("MockDisks.Delete(%v, %v) = %v", ctx, key, err)
@@ -3283,14 +4936,22 @@ func (m *MockDisks) Delete(ctx context.Context, key meta.Key) error {
 		}
 	}
 
+	if m.deleteCheck != nil {
+		if err := m.deleteCheck(NewResourceID("disks", *key.WithProject(projectID))); err != nil {
+			glog.V(5).Infof("MockDisks.Delete(%v, %v) = %v", ctx, key, err)
+			return err
+		}
+	}
+
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.DeleteError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.DeleteError[skey]; ok {
 		glog.V(5).Infof("MockDisks.Delete(%v, %v) = %v", ctx, key, err)
 		return err
 	}
-	if _, ok := m.Objects[key]; !ok {
+	if _, ok := m.Objects[skey]; !ok {
 		err := &googleapi.Error{
 			Code:    http.StatusNotFound,
 			Message: fmt.Sprintf("MockDisks %v not found", key),
@@ -3299,7 +4960,7 @@ func (m *MockDisks) Delete(ctx context.Context, key meta.Key) error {
 		return err
 	}
 
-	delete(m.Objects, key)
+	delete(m.Objects, skey)
 	glog.V(5).Infof("MockDisks.Delete(%v, %v) = nil", ctx, key)
 	return nil
 }
@@ -3315,15 +4976,23 @@ type GCEDisks struct {
 }
 
 // Get the Disk named by key.
-func (g *GCEDisks) Get(ctx context.Context, key meta.Key) (*ga.Disk, error) {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "Disks")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Get",
-		Version:   meta.Version("ga"),
-		Service:   "Disks",
+func (g *GCEDisks) Get(ctx context.Context, key meta.Key) (_ *ga.Disk, err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "Disks")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("ga"),
+			Service:   "Disks",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("zonal"); err != nil {
+		return nil, err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.GA.Disks.Get(projectID, key.Zone, key.Name)
@@ -3332,24 +5001,38 @@ func (g *GCEDisks) Get(ctx context.Context, key meta.Key) (*ga.Disk, error) {
 }
 
 // List all Disk objects.
-func (g *GCEDisks) List(ctx context.Context, zone string, fl *filter.F) ([]*ga.Disk, error) {
+func (g *GCEDisks) List(ctx context.Context, zone string, fl *filter.F) (_ []*ga.Disk, err error) {
 	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "Disks")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "List",
-		Version:   meta.Version("ga"),
-		Service:   "Disks",
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("ga"),
+			Service:   "Disks",
+		},
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if g.s.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(ga.Disk{})); err != nil {
+			return nil, err
+		}
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.GA.Disks.List(projectID, zone)
-	if fl != filter.None {
-		call.Filter(fl.String())
+	serverFl, clientFl := fl.Split(reflect.TypeOf(ga.Disk{}))
+	if serverFl != filter.None {
+		call.Filter(serverFl.String())
 	}
 	var all []*ga.Disk
 	f := func(l *ga.DiskList) error {
-		all = append(all, l.Items...)
+		for _, obj := range l.Items {
+			if clientFl.Match(obj) {
+				all = append(all, obj)
+			}
+		}
 		return nil
 	}
 	if err := call.Pages(ctx, f); err != nil {
@@ -3359,18 +5042,32 @@ func (g *GCEDisks) List(ctx context.Context, zone string, fl *filter.F) ([]*ga.D
 }
 
 // Insert Disk with key of value obj.
-func (g *GCEDisks) Insert(ctx context.Context, key meta.Key, obj *ga.Disk) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "Disks")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Insert",
-		Version:   meta.Version("ga"),
-		Service:   "Disks",
+func (g *GCEDisks) Insert(ctx context.Context, key meta.Key, obj *ga.Disk) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "Disks")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Insert",
+			Version:   meta.Version("ga"),
+			Service:   "Disks",
+		},
+		Key:     &key,
+		Request: obj,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("zonal"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
 	obj.Name = key.Name
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.GA.Disks.Insert(projectID, key.Zone, obj)
 	call.Context(ctx)
 
@@ -3382,17 +5079,30 @@ func (g *GCEDisks) Insert(ctx context.Context, key meta.Key, obj *ga.Disk) error
 }
 
 // Delete the Disk referenced by key.
-func (g *GCEDisks) Delete(ctx context.Context, key meta.Key) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "Disks")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Delete",
-		Version:   meta.Version("ga"),
-		Service:   "Disks",
+func (g *GCEDisks) Delete(ctx context.Context, key meta.Key) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "Disks")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Delete",
+			Version:   meta.Version("ga"),
+			Service:   "Disks",
+		},
+		Key:     &key,
+		Mutates: true,
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("zonal"); err != nil {
 		return err
 	}
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
+		return err
+	}
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.GA.Disks.Delete(projectID, key.Zone, key.Name)
 	call.Context(ctx)
 
@@ -3445,13 +5155,46 @@ type MockAlphaDisks struct {
 	InsertHook func(m *MockAlphaDisks, ctx context.Context, key meta.Key, obj *alpha.Disk) (bool, error)
 	DeleteHook func(m *MockAlphaDisks, ctx context.Context, key meta.Key) (bool, error)
 
+	// Observer, if set, is invoked around each call to the mock.
+	Observer CallObserver
+
+	// ProjectRouter, if set, routes the calls with keys that do not have a
+	// project. Calls are made to "mock-project" if it is not set.
+	ProjectRouter ProjectRouter
+
+	// Debug enables additional checks of the arguments to the mock, such as
+	// the validation of List() filters with filter.Validate().
+	Debug bool
+
+	// deleteCheck, if set, is called by Delete() with the resource being
+	// deleted. Delete() returns the error if it is not nil. See
+	// WithReferenceChecking().
+	deleteCheck func(id *ResourceID) error
+
 	// X is extra state that can be used as part of the mock. Generated code
 	// will not use this field.
 	X interface{}
 }
 
 // Get returns the object from the mock.
-func (m *MockAlphaDisks) Get(ctx context.Context, key meta.Key) (*alpha.Disk, error) {
+func (m *MockAlphaDisks) Get(ctx context.Context, key meta.Key) (_ *alpha.Disk, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "alpha", "Disks")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("alpha"),
+			Service:   "Disks",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("zonal"); err != nil {
+		return nil, err
+	}
+
 	if m.GetHook != nil {
 		if intercept, obj, err := m.GetHook(m, ctx, key); intercept {
 			glog.V(5).Infof("MockAlphaDisks.Get(%v, %s) = %+v, %v", ctx, key, obj, err)
@@ -3462,17 +5205,18 @@ func (m *MockAlphaDisks) Get(ctx context.Context, key meta.Key) (*alpha.Disk, er
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.GetError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.GetError[skey]; ok {
 		glog.V(5).Infof("MockAlphaDisks.Get(%v, %s) = nil, %v", ctx, key, err)
 		return nil, err
 	}
-	if obj, ok := m.Objects[key]; ok {
+	if obj, ok := m.Objects[skey]; ok {
 		typedObj := obj.ToAlpha()
 		glog.V(5).Infof("MockAlphaDisks.Get(%v, %s) = %+v, nil", ctx, key, typedObj)
 		return typedObj, nil
 	}
 
-	err := &googleapi.Error{
+	err = &googleapi.Error{
 		Code:    http.StatusNotFound,
 		Message: fmt.Sprintf("MockAlphaDisks %v not found", key),
 	}
@@ -3481,7 +5225,26 @@ func (m *MockAlphaDisks) Get(ctx context.Context, key meta.Key) (*alpha.Disk, er
 }
 
 // List all of the objects in the mock in the given zone.
-func (m *MockAlphaDisks) List(ctx context.Context, zone string, fl *filter.F) ([]*alpha.Disk, error) {
+func (m *MockAlphaDisks) List(ctx context.Context, zone string, fl *filter.F) (_ []*alpha.Disk, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, nil, "alpha", "Disks")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("alpha"),
+			Service:   "Disks",
+		},
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if m.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(alpha.Disk{})); err != nil {
+			glog.V(5).Infof("MockAlphaDisks.List(%v, %v) = nil, %v", ctx, fl, err)
+			return nil, err
+		}
+	}
+
 	if m.ListHook != nil {
 		if intercept, objs, err := m.ListHook(m, ctx, zone, fl); intercept {
 			glog.V(5).Infof("MockAlphaDisks.List(%v, %q, %v) = [%v items], %v", ctx, zone, fl, len(objs), err)
@@ -3501,6 +5264,10 @@ func (m *MockAlphaDisks) List(ctx context.Context, zone string, fl *filter.F) ([
 
 	var objs []*alpha.Disk
 	for key, obj := range m.Objects {
+		// Objects in other projects are not listed.
+		if key.Project != mockStorageProject(projectID) {
+			continue
+		}
 		if key.Zone != zone {
 			continue
 		}
@@ -3515,7 +5282,26 @@ func (m *MockAlphaDisks) List(ctx context.Context, zone string, fl *filter.F) ([
 }
 
 // Insert is a mock for inserting/creating a new object.
-func (m *MockAlphaDisks) Insert(ctx context.Context, key meta.Key, obj *alpha.Disk) error {
+func (m *MockAlphaDisks) Insert(ctx context.Context, key meta.Key, obj *alpha.Disk) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "alpha", "Disks")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Insert",
+			Version:   meta.Version("alpha"),
+			Service:   "Disks",
+		},
+		Key:     &key,
+		Request: obj,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("zonal"); err != nil {
+		return err
+	}
+
 	if m.InsertHook != nil {
 		if intercept, err := m.InsertHook(m, ctx, key, obj); intercept {
 			glog.V(5).Infof("MockAlphaDisks.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
@@ -3526,11 +5312,12 @@ func (m *MockAlphaDisks) Insert(ctx context.Context, key meta.Key, obj *alpha.Di
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.InsertError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.InsertError[skey]; ok {
 		glog.V(5).Infof("MockAlphaDisks.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
 		return err
 	}
-	if _, ok := m.Objects[key]; ok {
+	if _, ok := m.Objects[skey]; ok {
 		err := &googleapi.Error{
 			Code:    http.StatusConflict,
 			Message: fmt.Sprintf("MockAlphaDisks %v exists", key),
@@ -3541,16 +5328,34 @@ func (m *MockAlphaDisks) Insert(ctx context.Context, key meta.Key, obj *alpha.Di
 
 	obj.Name = key.Name
 	if obj.SelfLink == "" {
-		obj.SelfLink = SelfLink(meta.VersionAlpha, "mock-project", "disks", key)
+		obj.SelfLink = SelfLink(meta.VersionAlpha, projectID, "disks", key)
 	}
 
-	m.Objects[key] = &MockDisksObj{obj}
+	m.Objects[skey] = &MockDisksObj{obj}
 	glog.V(5).Infof("MockAlphaDisks.Insert(%v, %v, %+v) = nil", ctx, key, obj)
 	return nil
 }
 
 // Delete is a mock for deleting the object.
-func (m *MockAlphaDisks) Delete(ctx context.Context, key meta.Key) error {
+func (m *MockAlphaDisks) Delete(ctx context.Context, key meta.Key) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "alpha", "Disks")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Delete",
+			Version:   meta.Version("alpha"),
+			Service:   "Disks",
+		},
+		Key:     &key,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("zonal"); err != nil {
+		return err
+	}
+
 	if m.DeleteHook != nil {
 		if intercept, err := m.DeleteHook(m, ctx, key); intercept {
 			glog.V(5).Infof("MockAlphaDisks.Delete(%v, %v) = %v", ctx, key, err)
@@ -3558,14 +5363,22 @@ func (m *MockAlphaDisks) Delete(ctx context.Context, key meta.Key) error {
 		}
 	}
 
+	if m.deleteCheck != nil {
+		if err := m.deleteCheck(NewResourceID("disks", *key.WithProject(projectID))); err != nil {
+			glog.V(5).Infof("MockAlphaDisks.Delete(%v, %v) = %v", ctx, key, err)
+			return err
+		}
+	}
+
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.DeleteError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.DeleteError[skey]; ok {
 		glog.V(5).Infof("MockAlphaDisks.Delete(%v, %v) = %v", ctx, key, err)
 		return err
 	}
-	if _, ok := m.Objects[key]; !ok {
+	if _, ok := m.Objects[skey]; !ok {
 		err := &googleapi.Error{
 			Code:    http.StatusNotFound,
 			Message: fmt.Sprintf("MockAlphaDisks %v not found", key),
@@ -3574,7 +5387,7 @@ func (m *MockAlphaDisks) Delete(ctx context.Context, key meta.Key) error {
 		return err
 	}
 
-	delete(m.Objects, key)
+	delete(m.Objects, skey)
 	glog.V(5).Infof("MockAlphaDisks.Delete(%v, %v) = nil", ctx, key)
 	return nil
 }
@@ -3590,15 +5403,23 @@ type GCEAlphaDisks struct {
 }
 
 // Get the Disk named by key.
-func (g *GCEAlphaDisks) Get(ctx context.Context, key meta.Key) (*alpha.Disk, error) {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "alpha", "Disks")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Get",
-		Version:   meta.Version("alpha"),
-		Service:   "Disks",
+func (g *GCEAlphaDisks) Get(ctx context.Context, key meta.Key) (_ *alpha.Disk, err error) {
+	projectID := g.s.keyProjectID(ctx, key, "alpha", "Disks")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("alpha"),
+			Service:   "Disks",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("zonal"); err != nil {
+		return nil, err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.Alpha.Disks.Get(projectID, key.Zone, key.Name)
@@ -3607,24 +5428,38 @@ func (g *GCEAlphaDisks) Get(ctx context.Context, key meta.Key) (*alpha.Disk, err
 }
 
 // List all Disk objects.
-func (g *GCEAlphaDisks) List(ctx context.Context, zone string, fl *filter.F) ([]*alpha.Disk, error) {
+func (g *GCEAlphaDisks) List(ctx context.Context, zone string, fl *filter.F) (_ []*alpha.Disk, err error) {
 	projectID := g.s.ProjectRouter.ProjectID(ctx, "alpha", "Disks")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "List",
-		Version:   meta.Version("alpha"),
-		Service:   "Disks",
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("alpha"),
+			Service:   "Disks",
+		},
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if g.s.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(alpha.Disk{})); err != nil {
+			return nil, err
+		}
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.Alpha.Disks.List(projectID, zone)
-	if fl != filter.None {
-		call.Filter(fl.String())
+	serverFl, clientFl := fl.Split(reflect.TypeOf(alpha.Disk{}))
+	if serverFl != filter.None {
+		call.Filter(serverFl.String())
 	}
 	var all []*alpha.Disk
 	f := func(l *alpha.DiskList) error {
-		all = append(all, l.Items...)
+		for _, obj := range l.Items {
+			if clientFl.Match(obj) {
+				all = append(all, obj)
+			}
+		}
 		return nil
 	}
 	if err := call.Pages(ctx, f); err != nil {
@@ -3634,18 +5469,32 @@ func (g *GCEAlphaDisks) List(ctx context.Context, zone string, fl *filter.F) ([]
 }
 
 // Insert Disk with key of value obj.
-func (g *GCEAlphaDisks) Insert(ctx context.Context, key meta.Key, obj *alpha.Disk) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "alpha", "Disks")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Insert",
-		Version:   meta.Version("alpha"),
-		Service:   "Disks",
+func (g *GCEAlphaDisks) Insert(ctx context.Context, key meta.Key, obj *alpha.Disk) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "alpha", "Disks")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Insert",
+			Version:   meta.Version("alpha"),
+			Service:   "Disks",
+		},
+		Key:     &key,
+		Request: obj,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("zonal"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
 	obj.Name = key.Name
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.Alpha.Disks.Insert(projectID, key.Zone, obj)
 	call.Context(ctx)
 
@@ -3657,17 +5506,30 @@ func (g *GCEAlphaDisks) Insert(ctx context.Context, key meta.Key, obj *alpha.Dis
 }
 
 // Delete the Disk referenced by key.
-func (g *GCEAlphaDisks) Delete(ctx context.Context, key meta.Key) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "alpha", "Disks")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Delete",
-		Version:   meta.Version("alpha"),
-		Service:   "Disks",
+func (g *GCEAlphaDisks) Delete(ctx context.Context, key meta.Key) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "alpha", "Disks")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Delete",
+			Version:   meta.Version("alpha"),
+			Service:   "Disks",
+		},
+		Key:     &key,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("zonal"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.Alpha.Disks.Delete(projectID, key.Zone, key.Name)
 	call.Context(ctx)
 
@@ -3720,13 +5582,46 @@ type MockAlphaRegionDisks struct {
 	InsertHook func(m *MockAlphaRegionDisks, ctx context.Context, key meta.Key, obj *alpha.Disk) (bool, error)
 	DeleteHook func(m *MockAlphaRegionDisks, ctx context.Context, key meta.Key) (bool, error)
 
+	// Observer, if set, is invoked around each call to the mock.
+	Observer CallObserver
+
+	// ProjectRouter, if set, routes the calls with keys that do not have a
+	// project. Calls are made to "mock-project" if it is not set.
+	ProjectRouter ProjectRouter
+
+	// Debug enables additional checks of the arguments to the mock, such as
+	// the validation of List() filters with filter.Validate().
+	Debug bool
+
+	// deleteCheck, if set, is called by Delete() with the resource being
+	// deleted. Delete() returns the error if it is not nil. See
+	// WithReferenceChecking().
+	deleteCheck func(id *ResourceID) error
+
 	// X is extra state that can be used as part of the mock. Generated code
 	// will not use this field.
 	X interface{}
 }
 
 // Get returns the object from the mock.
-func (m *MockAlphaRegionDisks) Get(ctx context.Context, key meta.Key) (*alpha.Disk, error) {
+func (m *MockAlphaRegionDisks) Get(ctx context.Context, key meta.Key) (_ *alpha.Disk, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "alpha", "RegionDisks")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("alpha"),
+			Service:   "RegionDisks",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("regional"); err != nil {
+		return nil, err
+	}
+
 	if m.GetHook != nil {
 		if intercept, obj, err := m.GetHook(m, ctx, key); intercept {
 			glog.V(5).Infof("MockAlphaRegionDisks.Get(%v, %s) = %+v, %v", ctx, key, obj, err)
@@ -3737,17 +5632,18 @@ func (m *MockAlphaRegionDisks) Get(ctx context.Context, key meta.Key) (*alpha.Di
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.GetError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.GetError[skey]; ok {
 		glog.V(5).Infof("MockAlphaRegionDisks.Get(%v, %s) = nil, %v", ctx, key, err)
 		return nil, err
 	}
-	if obj, ok := m.Objects[key]; ok {
+	if obj, ok := m.Objects[skey]; ok {
 		typedObj := obj.ToAlpha()
 		glog.V(5).Infof("MockAlphaRegionDisks.Get(%v, %s) = %+v, nil", ctx, key, typedObj)
 		return typedObj, nil
 	}
 
-	err := &googleapi.Error{
+	err = &googleapi.Error{
 		Code:    http.StatusNotFound,
 		Message: fmt.Sprintf("MockAlphaRegionDisks %v not found", key),
 	}
@@ -3756,7 +5652,26 @@ func (m *MockAlphaRegionDisks) Get(ctx context.Context, key meta.Key) (*alpha.Di
 }
 
 // List all of the objects in the mock in the given region.
-func (m *MockAlphaRegionDisks) List(ctx context.Context, region string, fl *filter.F) ([]*alpha.Disk, error) {
+func (m *MockAlphaRegionDisks) List(ctx context.Context, region string, fl *filter.F) (_ []*alpha.Disk, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, nil, "alpha", "RegionDisks")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("alpha"),
+			Service:   "RegionDisks",
+		},
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if m.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(alpha.Disk{})); err != nil {
+			glog.V(5).Infof("MockAlphaRegionDisks.List(%v, %v) = nil, %v", ctx, fl, err)
+			return nil, err
+		}
+	}
+
 	if m.ListHook != nil {
 		if intercept, objs, err := m.ListHook(m, ctx, region, fl); intercept {
 			glog.V(5).Infof("MockAlphaRegionDisks.List(%v, %q, %v) = [%v items], %v", ctx, region, fl, len(objs), err)
@@ -3776,6 +5691,10 @@ func (m *MockAlphaRegionDisks) List(ctx context.Context, region string, fl *filt
 
 	var objs []*alpha.Disk
 	for key, obj := range m.Objects {
+		// Objects in other projects are not listed.
+		if key.Project != mockStorageProject(projectID) {
+			continue
+		}
 		if key.Region != region {
 			continue
 		}
@@ -3790,7 +5709,26 @@ func (m *MockAlphaRegionDisks) List(ctx context.Context, region string, fl *filt
 }
 
 // Insert is a mock for inserting/creating a new object.
-func (m *MockAlphaRegionDisks) Insert(ctx context.Context, key meta.Key, obj *alpha.Disk) error {
+func (m *MockAlphaRegionDisks) Insert(ctx context.Context, key meta.Key, obj *alpha.Disk) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "alpha", "RegionDisks")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Insert",
+			Version:   meta.Version("alpha"),
+			Service:   "RegionDisks",
+		},
+		Key:     &key,
+		Request: obj,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("regional"); err != nil {
+		return err
+	}
+
 	if m.InsertHook != nil {
 		if intercept, err := m.InsertHook(m, ctx, key, obj); intercept {
 			glog.V(5).Infof("MockAlphaRegionDisks.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
@@ -3801,11 +5739,12 @@ func (m *MockAlphaRegionDisks) Insert(ctx context.Context, key meta.Key, obj *al
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.InsertError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.InsertError[skey]; ok {
 		glog.V(5).Infof("MockAlphaRegionDisks.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
 		return err
 	}
-	if _, ok := m.Objects[key]; ok {
+	if _, ok := m.Objects[skey]; ok {
 		err := &googleapi.Error{
 			Code:    http.StatusConflict,
 			Message: fmt.Sprintf("MockAlphaRegionDisks %v exists", key),
@@ -3816,16 +5755,34 @@ func (m *MockAlphaRegionDisks) Insert(ctx context.Context, key meta.Key, obj *al
 
 	obj.Name = key.Name
 	if obj.SelfLink == "" {
-		obj.SelfLink = SelfLink(meta.VersionAlpha, "mock-project", "disks", key)
+		obj.SelfLink = SelfLink(meta.VersionAlpha, projectID, "disks", key)
 	}
 
-	m.Objects[key] = &MockRegionDisksObj{obj}
+	m.Objects[skey] = &MockRegionDisksObj{obj}
 	glog.V(5).Infof("MockAlphaRegionDisks.Insert(%v, %v, %+v) = nil", ctx, key, obj)
 	return nil
 }
 
 // Delete is a mock for deleting the object.
-func (m *MockAlphaRegionDisks) Delete(ctx context.Context, key meta.Key) error {
+func (m *MockAlphaRegionDisks) Delete(ctx context.Context, key meta.Key) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "alpha", "RegionDisks")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Delete",
+			Version:   meta.Version("alpha"),
+			Service:   "RegionDisks",
+		},
+		Key:     &key,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("regional"); err != nil {
+		return err
+	}
+
 	if m.DeleteHook != nil {
 		if intercept, err := m.DeleteHook(m, ctx, key); intercept {
 			glog.V(5).Infof("MockAlphaRegionDisks.Delete(%v, %v) = %v", ctx, key, err)
@@ -3833,14 +5790,22 @@ func (m *MockAlphaRegionDisks) Delete(ctx context.Context, key meta.Key) error {
 		}
 	}
 
+	if m.deleteCheck != nil {
+		if err := m.deleteCheck(NewResourceID("disks", *key.WithProject(projectID))); err != nil {
+			glog.V(5).Infof("MockAlphaRegionDisks.Delete(%v, %v) = %v", ctx, key, err)
+			return err
+		}
+	}
+
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.DeleteError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.DeleteError[skey]; ok {
 		glog.V(5).Infof("MockAlphaRegionDisks.Delete(%v, %v) = %v", ctx, key, err)
 		return err
 	}
-	if _, ok := m.Objects[key]; !ok {
+	if _, ok := m.Objects[skey]; !ok {
 		err := &googleapi.Error{
 			Code:    http.StatusNotFound,
 			Message: fmt.Sprintf("MockAlphaRegionDisks %v not found", key),
@@ -3849,7 +5814,7 @@ func (m *MockAlphaRegionDisks) Delete(ctx context.Context, key meta.Key) error {
 		return err
 	}
 
-	delete(m.Objects, key)
+	delete(m.Objects, skey)
 	glog.V(5).Infof("MockAlphaRegionDisks.Delete(%v, %v) = nil", ctx, key)
 	return nil
 }
@@ -3865,15 +5830,23 @@ type GCEAlphaRegionDisks struct {
 }
 
 // Get the Disk named by key.
-func (g *GCEAlphaRegionDisks) Get(ctx context.Context, key meta.Key) (*alpha.Disk, error) {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "alpha", "RegionDisks")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Get",
-		Version:   meta.Version("alpha"),
-		Service:   "RegionDisks",
+func (g *GCEAlphaRegionDisks) Get(ctx context.Context, key meta.Key) (_ *alpha.Disk, err error) {
+	projectID := g.s.keyProjectID(ctx, key, "alpha", "RegionDisks")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("alpha"),
+			Service:   "RegionDisks",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("regional"); err != nil {
+		return nil, err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.Alpha.RegionDisks.Get(projectID, key.Region, key.Name)
@@ -3882,24 +5855,38 @@ func (g *GCEAlphaRegionDisks) Get(ctx context.Context, key meta.Key) (*alpha.Dis
 }
 
 // List all Disk objects.
-func (g *GCEAlphaRegionDisks) List(ctx context.Context, region string, fl *filter.F) ([]*alpha.Disk, error) {
+func (g *GCEAlphaRegionDisks) List(ctx context.Context, region string, fl *filter.F) (_ []*alpha.Disk, err error) {
 	projectID := g.s.ProjectRouter.ProjectID(ctx, "alpha", "RegionDisks")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "List",
-		Version:   meta.Version("alpha"),
-		Service:   "RegionDisks",
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("alpha"),
+			Service:   "RegionDisks",
+		},
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if g.s.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(alpha.Disk{})); err != nil {
+			return nil, err
+		}
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.Alpha.RegionDisks.List(projectID, region)
-	if fl != filter.None {
-		call.Filter(fl.String())
+	serverFl, clientFl := fl.Split(reflect.TypeOf(alpha.Disk{}))
+	if serverFl != filter.None {
+		call.Filter(serverFl.String())
 	}
 	var all []*alpha.Disk
 	f := func(l *alpha.DiskList) error {
-		all = append(all, l.Items...)
+		for _, obj := range l.Items {
+			if clientFl.Match(obj) {
+				all = append(all, obj)
+			}
+		}
 		return nil
 	}
 	if err := call.Pages(ctx, f); err != nil {
@@ -3909,18 +5896,32 @@ func (g *GCEAlphaRegionDisks) List(ctx context.Context, region string, fl *filte
 }
 
 // Insert Disk with key of value obj.
-func (g *GCEAlphaRegionDisks) Insert(ctx context.Context, key meta.Key, obj *alpha.Disk) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "alpha", "RegionDisks")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Insert",
-		Version:   meta.Version("alpha"),
-		Service:   "RegionDisks",
+func (g *GCEAlphaRegionDisks) Insert(ctx context.Context, key meta.Key, obj *alpha.Disk) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "alpha", "RegionDisks")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Insert",
+			Version:   meta.Version("alpha"),
+			Service:   "RegionDisks",
+		},
+		Key:     &key,
+		Request: obj,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("regional"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
 	obj.Name = key.Name
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.Alpha.RegionDisks.Insert(projectID, key.Region, obj)
 	call.Context(ctx)
 
@@ -3932,19 +5933,32 @@ func (g *GCEAlphaRegionDisks) Insert(ctx context.Context, key meta.Key, obj *alp
 }
 
 // Delete the Disk referenced by key.
-func (g *GCEAlphaRegionDisks) Delete(ctx context.Context, key meta.Key) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "alpha", "RegionDisks")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Delete",
-		Version:   meta.Version("alpha"),
-		Service:   "RegionDisks",
+func (g *GCEAlphaRegionDisks) Delete(ctx context.Context, key meta.Key) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "alpha", "RegionDisks")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Delete",
+			Version:   meta.Version("alpha"),
+			Service:   "RegionDisks",
+		},
+		Key:     &key,
+		Mutates: true,
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("regional"); err != nil {
 		return err
 	}
-	call := g.s.Alpha.RegionDisks.Delete(projectID, key.Region, key.Name)
-	call.Context(ctx)
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
+		return err
+	}
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
+	call := g.s.Alpha.RegionDisks.Delete(projectID, key.Region, key.Name)
+	call.Context(ctx)
 
 	op, err := call.Do()
 	if err != nil {
@@ -3997,13 +6011,46 @@ type MockFirewalls struct {
 	DeleteHook func(m *MockFirewalls, ctx context.Context, key meta.Key) (bool, error)
 	UpdateHook func(*MockFirewalls, context.Context, meta.Key, *ga.Firewall) error
 
+	// Observer, if set, is invoked around each call to the mock.
+	Observer CallObserver
+
+	// ProjectRouter, if set, routes the calls with keys that do not have a
+	// project. Calls are made to "mock-project" if it is not set.
+	ProjectRouter ProjectRouter
+
+	// Debug enables additional checks of the arguments to the mock, such as
+	// the validation of List() filters with filter.Validate().
+	Debug bool
+
+	// deleteCheck, if set, is called by Delete() with the resource being
+	// deleted. Delete() returns the error if it is not nil. See
+	// WithReferenceChecking().
+	deleteCheck func(id *ResourceID) error
+
 	// X is extra state that can be used as part of the mock. Generated code
 	// will not use this field.
 	X interface{}
 }
 
 // Get returns the object from the mock.
-func (m *MockFirewalls) Get(ctx context.Context, key meta.Key) (*ga.Firewall, error) {
+func (m *MockFirewalls) Get(ctx context.Context, key meta.Key) (_ *ga.Firewall, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "Firewalls")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("ga"),
+			Service:   "Firewalls",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("global"); err != nil {
+		return nil, err
+	}
+
 	if m.GetHook != nil {
 		if intercept, obj, err := m.GetHook(m, ctx, key); intercept {
 			glog.V(5).Infof("MockFirewalls.Get(%v, %s) = %+v, %v", ctx, key, obj, err)
@@ -4014,17 +6061,18 @@ func (m *MockFirewalls) Get(ctx context.Context, key meta.Key) (*ga.Firewall, er
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.GetError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.GetError[skey]; ok {
 		glog.V(5).Infof("MockFirewalls.Get(%v, %s) = nil, %v", ctx, key, err)
 		return nil, err
 	}
-	if obj, ok := m.Objects[key]; ok {
+	if obj, ok := m.Objects[skey]; ok {
 		typedObj := obj.ToGA()
 		glog.V(5).Infof("MockFirewalls.Get(%v, %s) = %+v, nil", ctx, key, typedObj)
 		return typedObj, nil
 	}
 
-	err := &googleapi.Error{
+	err = &googleapi.Error{
 		Code:    http.StatusNotFound,
 		Message: fmt.Sprintf("MockFirewalls %v not found", key),
 	}
@@ -4033,7 +6081,26 @@ func (m *MockFirewalls) Get(ctx context.Context, key meta.Key) (*ga.Firewall, er
 }
 
 // List all of the objects in the mock.
-func (m *MockFirewalls) List(ctx context.Context, fl *filter.F) ([]*ga.Firewall, error) {
+func (m *MockFirewalls) List(ctx context.Context, fl *filter.F) (_ []*ga.Firewall, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, nil, "ga", "Firewalls")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("ga"),
+			Service:   "Firewalls",
+		},
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if m.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(ga.Firewall{})); err != nil {
+			glog.V(5).Infof("MockFirewalls.List(%v, %v) = nil, %v", ctx, fl, err)
+			return nil, err
+		}
+	}
+
 	if m.ListHook != nil {
 		if intercept, objs, err := m.ListHook(m, ctx, fl); intercept {
 			glog.V(5).Infof("MockFirewalls.List(%v, %v) = [%v items], %v", ctx, fl, len(objs), err)
@@ -4052,7 +6119,11 @@ func (m *MockFirewalls) List(ctx context.Context, fl *filter.F) ([]*ga.Firewall,
 	}
 
 	var objs []*ga.Firewall
-	for _, obj := range m.Objects {
+	for key, obj := range m.Objects {
+		// Objects in other projects are not listed.
+		if key.Project != mockStorageProject(projectID) {
+			continue
+		}
 		if !fl.Match(obj.ToGA()) {
 			continue
 		}
@@ -4064,7 +6135,26 @@ func (m *MockFirewalls) List(ctx context.Context, fl *filter.F) ([]*ga.Firewall,
 }
 
 // Insert is a mock for inserting/creating a new object.
-func (m *MockFirewalls) Insert(ctx context.Context, key meta.Key, obj *ga.Firewall) error {
+func (m *MockFirewalls) Insert(ctx context.Context, key meta.Key, obj *ga.Firewall) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "Firewalls")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Insert",
+			Version:   meta.Version("ga"),
+			Service:   "Firewalls",
+		},
+		Key:     &key,
+		Request: obj,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("global"); err != nil {
+		return err
+	}
+
 	if m.InsertHook != nil {
 		if intercept, err := m.InsertHook(m, ctx, key, obj); intercept {
 			glog.V(5).Infof("MockFirewalls.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
@@ -4075,11 +6165,12 @@ func (m *MockFirewalls) Insert(ctx context.Context, key meta.Key, obj *ga.Firewa
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.InsertError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.InsertError[skey]; ok {
 		glog.V(5).Infof("MockFirewalls.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
 		return err
 	}
-	if _, ok := m.Objects[key]; ok {
+	if _, ok := m.Objects[skey]; ok {
 		err := &googleapi.Error{
 			Code:    http.StatusConflict,
 			Message: fmt.Sprintf("MockFirewalls %v exists", key),
@@ -4090,16 +6181,34 @@ func (m *MockFirewalls) Insert(ctx context.Context, key meta.Key, obj *ga.Firewa
 
 	obj.Name = key.Name
 	if obj.SelfLink == "" {
-		obj.SelfLink = SelfLink(meta.VersionGA, "mock-project", "firewalls", key)
+		obj.SelfLink = SelfLink(meta.VersionGA, projectID, "firewalls", key)
 	}
 
-	m.Objects[key] = &MockFirewallsObj{obj}
+	m.Objects[skey] = &MockFirewallsObj{obj}
 	glog.V(5).Infof("MockFirewalls.Insert(%v, %v, %+v) = nil", ctx, key, obj)
 	return nil
 }
 
 // Delete is a mock for deleting the object.
-func (m *MockFirewalls) Delete(ctx context.Context, key meta.Key) error {
+func (m *MockFirewalls) Delete(ctx context.Context, key meta.Key) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "Firewalls")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Delete",
+			Version:   meta.Version("ga"),
+			Service:   "Firewalls",
+		},
+		Key:     &key,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("global"); err != nil {
+		return err
+	}
+
 	if m.DeleteHook != nil {
 		if intercept, err := m.DeleteHook(m, ctx, key); intercept {
 			glog.V(5).Infof("MockFirewalls.Delete(%v, %v) = %v", ctx, key, err)
@@ -4107,14 +6216,22 @@ func (m *MockFirewalls) Delete(ctx context.Context, key meta.Key) error {
 		}
 	}
 
+	if m.deleteCheck != nil {
+		if err := m.deleteCheck(NewResourceID("firewalls", *key.WithProject(projectID))); err != nil {
+			glog.V(5).Infof("MockFirewalls.Delete(%v, %v) = %v", ctx, key, err)
+			return err
+		}
+	}
+
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.DeleteError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.DeleteError[skey]; ok {
 		glog.V(5).Infof("MockFirewalls.Delete(%v, %v) = %v", ctx, key, err)
 		return err
 	}
-	if _, ok := m.Objects[key]; !ok {
+	if _, ok := m.Objects[skey]; !ok {
 		err := &googleapi.Error{
 			Code:    http.StatusNotFound,
 			Message: fmt.Sprintf("MockFirewalls %v not found", key),
@@ -4123,7 +6240,7 @@ func (m *MockFirewalls) Delete(ctx context.Context, key meta.Key) error {
 		return err
 	}
 
-	delete(m.Objects, key)
+	delete(m.Objects, skey)
 	glog.V(5).Infof("MockFirewalls.Delete(%v, %v) = nil", ctx, key)
 	return nil
 }
@@ -4133,11 +6250,52 @@ func (m *MockFirewalls) Obj(o *ga.Firewall) *MockFirewallsObj {
 	return &MockFirewallsObj{o}
 }
 
-// Update is a mock for the corresponding method.
-func (m *MockFirewalls) Update(ctx context.Context, key meta.Key, arg0 *ga.Firewall) error {
+// Update is a mock for the corresponding method. The object is replaced
+// with arg0 if UpdateHook is not set.
+func (m *MockFirewalls) Update(ctx context.Context, key meta.Key, arg0 *ga.Firewall) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "Firewalls")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Update",
+			Version:   meta.Version("ga"),
+			Service:   "Firewalls",
+		},
+		Key:     &key,
+		Request: arg0,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("global"); err != nil {
+		return err
+	}
+
 	if m.UpdateHook != nil {
 		return m.UpdateHook(m, ctx, key, arg0)
 	}
+
+	m.Lock.Lock()
+	defer m.Lock.Unlock()
+
+	skey := mockStorageKey(key, projectID)
+	if _, ok := m.Objects[skey]; !ok {
+		err := &googleapi.Error{
+			Code:    http.StatusNotFound,
+			Message: fmt.Sprintf("MockFirewalls %v not found", key),
+		}
+		glog.V(5).Infof("MockFirewalls.Update(%v, %v, %+v) = %v", ctx, key, arg0, err)
+		return err
+	}
+
+	arg0.Name = key.Name
+	if arg0.SelfLink == "" {
+		arg0.SelfLink = SelfLink(meta.VersionGA, projectID, "firewalls", key)
+	}
+	// The fields that the version of arg0 does not have are kept, as in GCE.
+	m.Objects[skey] = &MockFirewallsObj{mergeVersion(m.Objects[skey].Obj, arg0)}
+	glog.V(5).Infof("MockFirewalls.Update(%v, %v, %+v) = nil", ctx, key, arg0)
 	return nil
 }
 
@@ -4147,15 +6305,23 @@ type GCEFirewalls struct {
 }
 
 // Get the Firewall named by key.
-func (g *GCEFirewalls) Get(ctx context.Context, key meta.Key) (*ga.Firewall, error) {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "Firewalls")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Get",
-		Version:   meta.Version("ga"),
-		Service:   "Firewalls",
+func (g *GCEFirewalls) Get(ctx context.Context, key meta.Key) (_ *ga.Firewall, err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "Firewalls")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("ga"),
+			Service:   "Firewalls",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("global"); err != nil {
+		return nil, err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.GA.Firewalls.Get(projectID, key.Name)
@@ -4164,24 +6330,38 @@ func (g *GCEFirewalls) Get(ctx context.Context, key meta.Key) (*ga.Firewall, err
 }
 
 // List all Firewall objects.
-func (g *GCEFirewalls) List(ctx context.Context, fl *filter.F) ([]*ga.Firewall, error) {
+func (g *GCEFirewalls) List(ctx context.Context, fl *filter.F) (_ []*ga.Firewall, err error) {
 	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "Firewalls")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "List",
-		Version:   meta.Version("ga"),
-		Service:   "Firewalls",
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("ga"),
+			Service:   "Firewalls",
+		},
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if g.s.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(ga.Firewall{})); err != nil {
+			return nil, err
+		}
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.GA.Firewalls.List(projectID)
-	if fl != filter.None {
-		call.Filter(fl.String())
+	serverFl, clientFl := fl.Split(reflect.TypeOf(ga.Firewall{}))
+	if serverFl != filter.None {
+		call.Filter(serverFl.String())
 	}
 	var all []*ga.Firewall
 	f := func(l *ga.FirewallList) error {
-		all = append(all, l.Items...)
+		for _, obj := range l.Items {
+			if clientFl.Match(obj) {
+				all = append(all, obj)
+			}
+		}
 		return nil
 	}
 	if err := call.Pages(ctx, f); err != nil {
@@ -4191,18 +6371,32 @@ func (g *GCEFirewalls) List(ctx context.Context, fl *filter.F) ([]*ga.Firewall,
 }
 
 // Insert Firewall with key of value obj.
-func (g *GCEFirewalls) Insert(ctx context.Context, key meta.Key, obj *ga.Firewall) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "Firewalls")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Insert",
-		Version:   meta.Version("ga"),
-		Service:   "Firewalls",
+func (g *GCEFirewalls) Insert(ctx context.Context, key meta.Key, obj *ga.Firewall) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "Firewalls")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Insert",
+			Version:   meta.Version("ga"),
+			Service:   "Firewalls",
+		},
+		Key:     &key,
+		Request: obj,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("global"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
 	obj.Name = key.Name
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.GA.Firewalls.Insert(projectID, obj)
 	call.Context(ctx)
 
@@ -4214,17 +6408,30 @@ func (g *GCEFirewalls) Insert(ctx context.Context, key meta.Key, obj *ga.Firewal
 }
 
 // Delete the Firewall referenced by key.
-func (g *GCEFirewalls) Delete(ctx context.Context, key meta.Key) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "Firewalls")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Delete",
-		Version:   meta.Version("ga"),
-		Service:   "Firewalls",
+func (g *GCEFirewalls) Delete(ctx context.Context, key meta.Key) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "Firewalls")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Delete",
+			Version:   meta.Version("ga"),
+			Service:   "Firewalls",
+		},
+		Key:     &key,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("global"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.GA.Firewalls.Delete(projectID, key.Name)
 
 	call.Context(ctx)
@@ -4237,17 +6444,31 @@ func (g *GCEFirewalls) Delete(ctx context.Context, key meta.Key) error {
 }
 
 // Update is a method on GCEFirewalls.
-func (g *GCEFirewalls) Update(ctx context.Context, key meta.Key, arg0 *ga.Firewall) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "Firewalls")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Update",
-		Version:   meta.Version("ga"),
-		Service:   "Firewalls",
+func (g *GCEFirewalls) Update(ctx context.Context, key meta.Key, arg0 *ga.Firewall) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "Firewalls")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Update",
+			Version:   meta.Version("ga"),
+			Service:   "Firewalls",
+		},
+		Key:     &key,
+		Request: arg0,
+		Mutates: true,
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("global"); err != nil {
 		return err
 	}
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
+		return err
+	}
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.GA.Firewalls.Update(projectID, key.Name, arg0)
 	call.Context(ctx)
 	op, err := call.Do()
@@ -4299,13 +6520,46 @@ type MockForwardingRules struct {
 	InsertHook func(m *MockForwardingRules, ctx context.Context, key meta.Key, obj *ga.ForwardingRule) (bool, error)
 	DeleteHook func(m *MockForwardingRules, ctx context.Context, key meta.Key) (bool, error)
 
+	// Observer, if set, is invoked around each call to the mock.
+	Observer CallObserver
+
+	// ProjectRouter, if set, routes the calls with keys that do not have a
+	// project. Calls are made to "mock-project" if it is not set.
+	ProjectRouter ProjectRouter
+
+	// Debug enables additional checks of the arguments to the mock, such as
+	// the validation of List() filters with filter.Validate().
+	Debug bool
+
+	// deleteCheck, if set, is called by Delete() with the resource being
+	// deleted. Delete() returns the error if it is not nil. See
+	// WithReferenceChecking().
+	deleteCheck func(id *ResourceID) error
+
 	// X is extra state that can be used as part of the mock. Generated code
 	// will not use this field.
 	X interface{}
 }
 
 // Get returns the object from the mock.
-func (m *MockForwardingRules) Get(ctx context.Context, key meta.Key) (*ga.ForwardingRule, error) {
+func (m *MockForwardingRules) Get(ctx context.Context, key meta.Key) (_ *ga.ForwardingRule, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "ForwardingRules")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("ga"),
+			Service:   "ForwardingRules",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("regional"); err != nil {
+		return nil, err
+	}
+
 	if m.GetHook != nil {
 		if intercept, obj, err := m.GetHook(m, ctx, key); intercept {
 			glog.V(5).Infof("MockForwardingRules.Get(%v, %s) = %+v, %v", ctx, key, obj, err)
@@ -4316,17 +6570,18 @@ func (m *MockForwardingRules) Get(ctx context.Context, key meta.Key) (*ga.Forwar
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.GetError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.GetError[skey]; ok {
 		glog.V(5).Infof("MockForwardingRules.Get(%v, %s) = nil, %v", ctx, key, err)
 		return nil, err
 	}
-	if obj, ok := m.Objects[key]; ok {
+	if obj, ok := m.Objects[skey]; ok {
 		typedObj := obj.ToGA()
 		glog.V(5).Infof("MockForwardingRules.Get(%v, %s) = %+v, nil", ctx, key, typedObj)
 		return typedObj, nil
 	}
 
-	err := &googleapi.Error{
+	err = &googleapi.Error{
 		Code:    http.StatusNotFound,
 		Message: fmt.Sprintf("MockForwardingRules %v not found", key),
 	}
@@ -4335,7 +6590,26 @@ func (m *MockForwardingRules) Get(ctx context.Context, key meta.Key) (*ga.Forwar
 }
 
 // List all of the objects in the mock in the given region.
-func (m *MockForwardingRules) List(ctx context.Context, region string, fl *filter.F) ([]*ga.ForwardingRule, error) {
+func (m *MockForwardingRules) List(ctx context.Context, region string, fl *filter.F) (_ []*ga.ForwardingRule, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, nil, "ga", "ForwardingRules")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("ga"),
+			Service:   "ForwardingRules",
+		},
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if m.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(ga.ForwardingRule{})); err != nil {
+			glog.V(5).Infof("MockForwardingRules.List(%v, %v) = nil, %v", ctx, fl, err)
+			return nil, err
+		}
+	}
+
 	if m.ListHook != nil {
 		if intercept, objs, err := m.ListHook(m, ctx, region, fl); intercept {
 			glog.V(5).Infof("MockForwardingRules.List(%v, %q, %v) = [%v items], %v", ctx, region, fl, len(objs), err)
@@ -4355,6 +6629,10 @@ func (m *MockForwardingRules) List(ctx context.Context, region string, fl *filte
 
 	var objs []*ga.ForwardingRule
 	for key, obj := range m.Objects {
+		// Objects in other projects are not listed.
+		if key.Project != mockStorageProject(projectID) {
+			continue
+		}
 		if key.Region != region {
 			continue
 		}
@@ -4369,7 +6647,26 @@ func (m *MockForwardingRules) List(ctx context.Context, region string, fl *filte
 }
 
 // Insert is a mock for inserting/creating a new object.
-func (m *MockForwardingRules) Insert(ctx context.Context, key meta.Key, obj *ga.ForwardingRule) error {
+func (m *MockForwardingRules) Insert(ctx context.Context, key meta.Key, obj *ga.ForwardingRule) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "ForwardingRules")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Insert",
+			Version:   meta.Version("ga"),
+			Service:   "ForwardingRules",
+		},
+		Key:     &key,
+		Request: obj,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("regional"); err != nil {
+		return err
+	}
+
 	if m.InsertHook != nil {
 		if intercept, err := m.InsertHook(m, ctx, key, obj); intercept {
 			glog.V(5).Infof("MockForwardingRules.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
@@ -4380,11 +6677,12 @@ func (m *MockForwardingRules) Insert(ctx context.Context, key meta.Key, obj *ga.
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.InsertError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.InsertError[skey]; ok {
 		glog.V(5).Infof("MockForwardingRules.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
 		return err
 	}
-	if _, ok := m.Objects[key]; ok {
+	if _, ok := m.Objects[skey]; ok {
 		err := &googleapi.Error{
 			Code:    http.StatusConflict,
 			Message: fmt.Sprintf("MockForwardingRules %v exists", key),
@@ -4395,16 +6693,34 @@ func (m *MockForwardingRules) Insert(ctx context.Context, key meta.Key, obj *ga.
 
 	obj.Name = key.Name
 	if obj.SelfLink == "" {
-		obj.SelfLink = SelfLink(meta.VersionGA, "mock-project", "forwardingRules", key)
+		obj.SelfLink = SelfLink(meta.VersionGA, projectID, "forwardingRules", key)
 	}
 
-	m.Objects[key] = &MockForwardingRulesObj{obj}
+	m.Objects[skey] = &MockForwardingRulesObj{obj}
 	glog.V(5).Infof("MockForwardingRules.Insert(%v, %v, %+v) = nil", ctx, key, obj)
 	return nil
 }
 
 // Delete is a mock for deleting the object.
-func (m *MockForwardingRules) Delete(ctx context.Context, key meta.Key) error {
+func (m *MockForwardingRules) Delete(ctx context.Context, key meta.Key) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "ForwardingRules")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Delete",
+			Version:   meta.Version("ga"),
+			Service:   "ForwardingRules",
+		},
+		Key:     &key,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("regional"); err != nil {
+		return err
+	}
+
 	if m.DeleteHook != nil {
 		if intercept, err := m.DeleteHook(m, ctx, key); intercept {
 			glog.V(5).Infof("MockForwardingRules.Delete(%v, %v) = %v", ctx, key, err)
@@ -4412,14 +6728,22 @@ func (m *MockForwardingRules) Delete(ctx context.Context, key meta.Key) error {
 		}
 	}
 
+	if m.deleteCheck != nil {
+		if err := m.deleteCheck(NewResourceID("forwardingRules", *key.WithProject(projectID))); err != nil {
+			glog.V(5).Infof("MockForwardingRules.Delete(%v, %v) = %v", ctx, key, err)
+			return err
+		}
+	}
+
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.DeleteError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.DeleteError[skey]; ok {
 		glog.V(5).Infof("MockForwardingRules.Delete(%v, %v) = %v", ctx, key, err)
 		return err
 	}
-	if _, ok := m.Objects[key]; !ok {
+	if _, ok := m.Objects[skey]; !ok {
 		err := &googleapi.Error{
 			Code:    http.StatusNotFound,
 			Message: fmt.Sprintf("MockForwardingRules %v not found", key),
@@ -4428,7 +6752,7 @@ func (m *MockForwardingRules) Delete(ctx context.Context, key meta.Key) error {
 		return err
 	}
 
-	delete(m.Objects, key)
+	delete(m.Objects, skey)
 	glog.V(5).Infof("MockForwardingRules.Delete(%v, %v) = nil", ctx, key)
 	return nil
 }
@@ -4444,15 +6768,23 @@ type GCEForwardingRules struct {
 }
 
 // Get the ForwardingRule named by key.
-func (g *GCEForwardingRules) Get(ctx context.Context, key meta.Key) (*ga.ForwardingRule, error) {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "ForwardingRules")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Get",
-		Version:   meta.Version("ga"),
-		Service:   "ForwardingRules",
+func (g *GCEForwardingRules) Get(ctx context.Context, key meta.Key) (_ *ga.ForwardingRule, err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "ForwardingRules")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("ga"),
+			Service:   "ForwardingRules",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("regional"); err != nil {
+		return nil, err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.GA.ForwardingRules.Get(projectID, key.Region, key.Name)
@@ -4461,24 +6793,38 @@ func (g *GCEForwardingRules) Get(ctx context.Context, key meta.Key) (*ga.Forward
 }
 
 // List all ForwardingRule objects.
-func (g *GCEForwardingRules) List(ctx context.Context, region string, fl *filter.F) ([]*ga.ForwardingRule, error) {
+func (g *GCEForwardingRules) List(ctx context.Context, region string, fl *filter.F) (_ []*ga.ForwardingRule, err error) {
 	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "ForwardingRules")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "List",
-		Version:   meta.Version("ga"),
-		Service:   "ForwardingRules",
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("ga"),
+			Service:   "ForwardingRules",
+		},
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if g.s.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(ga.ForwardingRule{})); err != nil {
+			return nil, err
+		}
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.GA.ForwardingRules.List(projectID, region)
-	if fl != filter.None {
-		call.Filter(fl.String())
+	serverFl, clientFl := fl.Split(reflect.TypeOf(ga.ForwardingRule{}))
+	if serverFl != filter.None {
+		call.Filter(serverFl.String())
 	}
 	var all []*ga.ForwardingRule
 	f := func(l *ga.ForwardingRuleList) error {
-		all = append(all, l.Items...)
+		for _, obj := range l.Items {
+			if clientFl.Match(obj) {
+				all = append(all, obj)
+			}
+		}
 		return nil
 	}
 	if err := call.Pages(ctx, f); err != nil {
@@ -4488,18 +6834,32 @@ func (g *GCEForwardingRules) List(ctx context.Context, region string, fl *filter
 }
 
 // Insert ForwardingRule with key of value obj.
-func (g *GCEForwardingRules) Insert(ctx context.Context, key meta.Key, obj *ga.ForwardingRule) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "ForwardingRules")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Insert",
-		Version:   meta.Version("ga"),
-		Service:   "ForwardingRules",
+func (g *GCEForwardingRules) Insert(ctx context.Context, key meta.Key, obj *ga.ForwardingRule) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "ForwardingRules")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Insert",
+			Version:   meta.Version("ga"),
+			Service:   "ForwardingRules",
+		},
+		Key:     &key,
+		Request: obj,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("regional"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
 	obj.Name = key.Name
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.GA.ForwardingRules.Insert(projectID, key.Region, obj)
 	call.Context(ctx)
 
@@ -4511,17 +6871,30 @@ func (g *GCEForwardingRules) Insert(ctx context.Context, key meta.Key, obj *ga.F
 }
 
 // Delete the ForwardingRule referenced by key.
-func (g *GCEForwardingRules) Delete(ctx context.Context, key meta.Key) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "ForwardingRules")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Delete",
-		Version:   meta.Version("ga"),
-		Service:   "ForwardingRules",
+func (g *GCEForwardingRules) Delete(ctx context.Context, key meta.Key) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "ForwardingRules")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Delete",
+			Version:   meta.Version("ga"),
+			Service:   "ForwardingRules",
+		},
+		Key:     &key,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("regional"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.GA.ForwardingRules.Delete(projectID, key.Region, key.Name)
 	call.Context(ctx)
 
@@ -4574,13 +6947,46 @@ type MockAlphaForwardingRules struct {
 	InsertHook func(m *MockAlphaForwardingRules, ctx context.Context, key meta.Key, obj *alpha.ForwardingRule) (bool, error)
 	DeleteHook func(m *MockAlphaForwardingRules, ctx context.Context, key meta.Key) (bool, error)
 
+	// Observer, if set, is invoked around each call to the mock.
+	Observer CallObserver
+
+	// ProjectRouter, if set, routes the calls with keys that do not have a
+	// project. Calls are made to "mock-project" if it is not set.
+	ProjectRouter ProjectRouter
+
+	// Debug enables additional checks of the arguments to the mock, such as
+	// the validation of List() filters with filter.Validate().
+	Debug bool
+
+	// deleteCheck, if set, is called by Delete() with the resource being
+	// deleted. Delete() returns the error if it is not nil. See
+	// WithReferenceChecking().
+	deleteCheck func(id *ResourceID) error
+
 	// X is extra state that can be used as part of the mock. Generated code
 	// will not use this field.
 	X interface{}
 }
 
 // Get returns the object from the mock.
-func (m *MockAlphaForwardingRules) Get(ctx context.Context, key meta.Key) (*alpha.ForwardingRule, error) {
+func (m *MockAlphaForwardingRules) Get(ctx context.Context, key meta.Key) (_ *alpha.ForwardingRule, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "alpha", "ForwardingRules")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("alpha"),
+			Service:   "ForwardingRules",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("regional"); err != nil {
+		return nil, err
+	}
+
 	if m.GetHook != nil {
 		if intercept, obj, err := m.GetHook(m, ctx, key); intercept {
 			glog.V(5).Infof("MockAlphaForwardingRules.Get(%v, %s) = %+v, %v", ctx, key, obj, err)
@@ -4591,17 +6997,18 @@ func (m *MockAlphaForwardingRules) Get(ctx context.Context, key meta.Key) (*alph
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.GetError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.GetError[skey]; ok {
 		glog.V(5).Infof("MockAlphaForwardingRules.Get(%v, %s) = nil, %v", ctx, key, err)
 		return nil, err
 	}
-	if obj, ok := m.Objects[key]; ok {
+	if obj, ok := m.Objects[skey]; ok {
 		typedObj := obj.ToAlpha()
 		glog.V(5).Infof("MockAlphaForwardingRules.Get(%v, %s) = %+v, nil", ctx, key, typedObj)
 		return typedObj, nil
 	}
 
-	err := &googleapi.Error{
+	err = &googleapi.Error{
 		Code:    http.StatusNotFound,
 		Message: fmt.Sprintf("MockAlphaForwardingRules %v not found", key),
 	}
@@ -4610,7 +7017,26 @@ func (m *MockAlphaForwardingRules) Get(ctx context.Context, key meta.Key) (*alph
 }
 
 // List all of the objects in the mock in the given region.
-func (m *MockAlphaForwardingRules) List(ctx context.Context, region string, fl *filter.F) ([]*alpha.ForwardingRule, error) {
+func (m *MockAlphaForwardingRules) List(ctx context.Context, region string, fl *filter.F) (_ []*alpha.ForwardingRule, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, nil, "alpha", "ForwardingRules")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("alpha"),
+			Service:   "ForwardingRules",
+		},
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if m.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(alpha.ForwardingRule{})); err != nil {
+			glog.V(5).Infof("MockAlphaForwardingRules.List(%v, %v) = nil, %v", ctx, fl, err)
+			return nil, err
+		}
+	}
+
 	if m.ListHook != nil {
 		if intercept, objs, err := m.ListHook(m, ctx, region, fl); intercept {
 			glog.V(5).Infof("MockAlphaForwardingRules.List(%v, %q, %v) = [%v items], %v", ctx, region, fl, len(objs), err)
@@ -4630,6 +7056,10 @@ func (m *MockAlphaForwardingRules) List(ctx context.Context, region string, fl *
 
 	var objs []*alpha.ForwardingRule
 	for key, obj := range m.Objects {
+		// Objects in other projects are not listed.
+		if key.Project != mockStorageProject(projectID) {
+			continue
+		}
 		if key.Region != region {
 			continue
 		}
@@ -4644,7 +7074,26 @@ func (m *MockAlphaForwardingRules) List(ctx context.Context, region string, fl *
 }
 
 // Insert is a mock for inserting/creating a new object.
-func (m *MockAlphaForwardingRules) Insert(ctx context.Context, key meta.Key, obj *alpha.ForwardingRule) error {
+func (m *MockAlphaForwardingRules) Insert(ctx context.Context, key meta.Key, obj *alpha.ForwardingRule) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "alpha", "ForwardingRules")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Insert",
+			Version:   meta.Version("alpha"),
+			Service:   "ForwardingRules",
+		},
+		Key:     &key,
+		Request: obj,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("regional"); err != nil {
+		return err
+	}
+
 	if m.InsertHook != nil {
 		if intercept, err := m.InsertHook(m, ctx, key, obj); intercept {
 			glog.V(5).Infof("MockAlphaForwardingRules.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
@@ -4655,11 +7104,12 @@ func (m *MockAlphaForwardingRules) Insert(ctx context.Context, key meta.Key, obj
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.InsertError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.InsertError[skey]; ok {
 		glog.V(5).Infof("MockAlphaForwardingRules.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
 		return err
 	}
-	if _, ok := m.Objects[key]; ok {
+	if _, ok := m.Objects[skey]; ok {
 		err := &googleapi.Error{
 			Code:    http.StatusConflict,
 			Message: fmt.Sprintf("MockAlphaForwardingRules %v exists", key),
@@ -4670,16 +7120,34 @@ func (m *MockAlphaForwardingRules) Insert(ctx context.Context, key meta.Key, obj
 
 	obj.Name = key.Name
 	if obj.SelfLink == "" {
-		obj.SelfLink = SelfLink(meta.VersionAlpha, "mock-project", "forwardingRules", key)
+		obj.SelfLink = SelfLink(meta.VersionAlpha, projectID, "forwardingRules", key)
 	}
 
-	m.Objects[key] = &MockForwardingRulesObj{obj}
+	m.Objects[skey] = &MockForwardingRulesObj{obj}
 	glog.V(5).Infof("MockAlphaForwardingRules.Insert(%v, %v, %+v) = nil", ctx, key, obj)
 	return nil
 }
 
 // Delete is a mock for deleting the object.
-func (m *MockAlphaForwardingRules) Delete(ctx context.Context, key meta.Key) error {
+func (m *MockAlphaForwardingRules) Delete(ctx context.Context, key meta.Key) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "alpha", "ForwardingRules")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Delete",
+			Version:   meta.Version("alpha"),
+			Service:   "ForwardingRules",
+		},
+		Key:     &key,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("regional"); err != nil {
+		return err
+	}
+
 	if m.DeleteHook != nil {
 		if intercept, err := m.DeleteHook(m, ctx, key); intercept {
 			glog.V(5).Infof("MockAlphaForwardingRules.Delete(%v, %v) = %v", ctx, key, err)
@@ -4687,14 +7155,22 @@ func (m *MockAlphaForwardingRules) Delete(ctx context.Context, key meta.Key) err
 		}
 	}
 
+	if m.deleteCheck != nil {
+		if err := m.deleteCheck(NewResourceID("forwardingRules", *key.WithProject(projectID))); err != nil {
+			glog.V(5).Infof("MockAlphaForwardingRules.Delete(%v, %v) = %v", ctx, key, err)
+			return err
+		}
+	}
+
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.DeleteError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.DeleteError[skey]; ok {
 		glog.V(5).Infof("MockAlphaForwardingRules.Delete(%v, %v) = %v", ctx, key, err)
 		return err
 	}
-	if _, ok := m.Objects[key]; !ok {
+	if _, ok := m.Objects[skey]; !ok {
 		err := &googleapi.Error{
 			Code:    http.StatusNotFound,
 			Message: fmt.Sprintf("MockAlphaForwardingRules %v not found", key),
@@ -4703,7 +7179,7 @@ func (m *MockAlphaForwardingRules) Delete(ctx context.Context, key meta.Key) err
 		return err
 	}
 
-	delete(m.Objects, key)
+	delete(m.Objects, skey)
 	glog.V(5).Infof("MockAlphaForwardingRules.Delete(%v, %v) = nil", ctx, key)
 	return nil
 }
@@ -4719,15 +7195,23 @@ type GCEAlphaForwardingRules struct {
 }
 
 // Get the ForwardingRule named by key.
-func (g *GCEAlphaForwardingRules) Get(ctx context.Context, key meta.Key) (*alpha.ForwardingRule, error) {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "alpha", "ForwardingRules")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Get",
-		Version:   meta.Version("alpha"),
-		Service:   "ForwardingRules",
+func (g *GCEAlphaForwardingRules) Get(ctx context.Context, key meta.Key) (_ *alpha.ForwardingRule, err error) {
+	projectID := g.s.keyProjectID(ctx, key, "alpha", "ForwardingRules")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("alpha"),
+			Service:   "ForwardingRules",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("regional"); err != nil {
+		return nil, err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.Alpha.ForwardingRules.Get(projectID, key.Region, key.Name)
@@ -4736,24 +7220,38 @@ func (g *GCEAlphaForwardingRules) Get(ctx context.Context, key meta.Key) (*alpha
 }
 
 // List all ForwardingRule objects.
-func (g *GCEAlphaForwardingRules) List(ctx context.Context, region string, fl *filter.F) ([]*alpha.ForwardingRule, error) {
+func (g *GCEAlphaForwardingRules) List(ctx context.Context, region string, fl *filter.F) (_ []*alpha.ForwardingRule, err error) {
 	projectID := g.s.ProjectRouter.ProjectID(ctx, "alpha", "ForwardingRules")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "List",
-		Version:   meta.Version("alpha"),
-		Service:   "ForwardingRules",
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("alpha"),
+			Service:   "ForwardingRules",
+		},
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if g.s.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(alpha.ForwardingRule{})); err != nil {
+			return nil, err
+		}
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.Alpha.ForwardingRules.List(projectID, region)
-	if fl != filter.None {
-		call.Filter(fl.String())
+	serverFl, clientFl := fl.Split(reflect.TypeOf(alpha.ForwardingRule{}))
+	if serverFl != filter.None {
+		call.Filter(serverFl.String())
 	}
 	var all []*alpha.ForwardingRule
 	f := func(l *alpha.ForwardingRuleList) error {
-		all = append(all, l.Items...)
+		for _, obj := range l.Items {
+			if clientFl.Match(obj) {
+				all = append(all, obj)
+			}
+		}
 		return nil
 	}
 	if err := call.Pages(ctx, f); err != nil {
@@ -4763,18 +7261,32 @@ func (g *GCEAlphaForwardingRules) List(ctx context.Context, region string, fl *f
 }
 
 // Insert ForwardingRule with key of value obj.
-func (g *GCEAlphaForwardingRules) Insert(ctx context.Context, key meta.Key, obj *alpha.ForwardingRule) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "alpha", "ForwardingRules")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Insert",
-		Version:   meta.Version("alpha"),
-		Service:   "ForwardingRules",
+func (g *GCEAlphaForwardingRules) Insert(ctx context.Context, key meta.Key, obj *alpha.ForwardingRule) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "alpha", "ForwardingRules")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Insert",
+			Version:   meta.Version("alpha"),
+			Service:   "ForwardingRules",
+		},
+		Key:     &key,
+		Request: obj,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("regional"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
 	obj.Name = key.Name
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.Alpha.ForwardingRules.Insert(projectID, key.Region, obj)
 	call.Context(ctx)
 
@@ -4786,17 +7298,30 @@ func (g *GCEAlphaForwardingRules) Insert(ctx context.Context, key meta.Key, obj
 }
 
 // Delete the ForwardingRule referenced by key.
-func (g *GCEAlphaForwardingRules) Delete(ctx context.Context, key meta.Key) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "alpha", "ForwardingRules")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Delete",
-		Version:   meta.Version("alpha"),
-		Service:   "ForwardingRules",
+func (g *GCEAlphaForwardingRules) Delete(ctx context.Context, key meta.Key) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "alpha", "ForwardingRules")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Delete",
+			Version:   meta.Version("alpha"),
+			Service:   "ForwardingRules",
+		},
+		Key:     &key,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("regional"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.Alpha.ForwardingRules.Delete(projectID, key.Region, key.Name)
 	call.Context(ctx)
 
@@ -4851,13 +7376,46 @@ type MockGlobalForwardingRules struct {
 	DeleteHook    func(m *MockGlobalForwardingRules, ctx context.Context, key meta.Key) (bool, error)
 	SetTargetHook func(*MockGlobalForwardingRules, context.Context, meta.Key, *ga.TargetReference) error
 
+	// Observer, if set, is invoked around each call to the mock.
+	Observer CallObserver
+
+	// ProjectRouter, if set, routes the calls with keys that do not have a
+	// project. Calls are made to "mock-project" if it is not set.
+	ProjectRouter ProjectRouter
+
+	// Debug enables additional checks of the arguments to the mock, such as
+	// the validation of List() filters with filter.Validate().
+	Debug bool
+
+	// deleteCheck, if set, is called by Delete() with the resource being
+	// deleted. Delete() returns the error if it is not nil. See
+	// WithReferenceChecking().
+	deleteCheck func(id *ResourceID) error
+
 	// X is extra state that can be used as part of the mock. Generated code
 	// will not use this field.
 	X interface{}
 }
 
 // Get returns the object from the mock.
-func (m *MockGlobalForwardingRules) Get(ctx context.Context, key meta.Key) (*ga.ForwardingRule, error) {
+func (m *MockGlobalForwardingRules) Get(ctx context.Context, key meta.Key) (_ *ga.ForwardingRule, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "GlobalForwardingRules")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("ga"),
+			Service:   "GlobalForwardingRules",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("global"); err != nil {
+		return nil, err
+	}
+
 	if m.GetHook != nil {
 		if intercept, obj, err := m.GetHook(m, ctx, key); intercept {
 			glog.V(5).Infof("MockGlobalForwardingRules.Get(%v, %s) = %+v, %v", ctx, key, obj, err)
@@ -4868,17 +7426,18 @@ func (m *MockGlobalForwardingRules) Get(ctx context.Context, key meta.Key) (*ga.
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.GetError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.GetError[skey]; ok {
 		glog.V(5).Infof("MockGlobalForwardingRules.Get(%v, %s) = nil, %v", ctx, key, err)
 		return nil, err
 	}
-	if obj, ok := m.Objects[key]; ok {
+	if obj, ok := m.Objects[skey]; ok {
 		typedObj := obj.ToGA()
 		glog.V(5).Infof("MockGlobalForwardingRules.Get(%v, %s) = %+v, nil", ctx, key, typedObj)
 		return typedObj, nil
 	}
 
-	err := &googleapi.Error{
+	err = &googleapi.Error{
 		Code:    http.StatusNotFound,
 		Message: fmt.Sprintf("MockGlobalForwardingRules %v not found", key),
 	}
@@ -4887,7 +7446,26 @@ func (m *MockGlobalForwardingRules) Get(ctx context.Context, key meta.Key) (*ga.
 }
 
 // List all of the objects in the mock.
-func (m *MockGlobalForwardingRules) List(ctx context.Context, fl *filter.F) ([]*ga.ForwardingRule, error) {
+func (m *MockGlobalForwardingRules) List(ctx context.Context, fl *filter.F) (_ []*ga.ForwardingRule, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, nil, "ga", "GlobalForwardingRules")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("ga"),
+			Service:   "GlobalForwardingRules",
+		},
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if m.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(ga.ForwardingRule{})); err != nil {
+			glog.V(5).Infof("MockGlobalForwardingRules.List(%v, %v) = nil, %v", ctx, fl, err)
+			return nil, err
+		}
+	}
+
 	if m.ListHook != nil {
 		if intercept, objs, err := m.ListHook(m, ctx, fl); intercept {
 			glog.V(5).Infof("MockGlobalForwardingRules.List(%v, %v) = [%v items], %v", ctx, fl, len(objs), err)
@@ -4906,7 +7484,11 @@ func (m *MockGlobalForwardingRules) List(ctx context.Context, fl *filter.F) ([]*
 	}
 
 	var objs []*ga.ForwardingRule
-	for _, obj := range m.Objects {
+	for key, obj := range m.Objects {
+		// Objects in other projects are not listed.
+		if key.Project != mockStorageProject(projectID) {
+			continue
+		}
 		if !fl.Match(obj.ToGA()) {
 			continue
 		}
@@ -4918,7 +7500,26 @@ func (m *MockGlobalForwardingRules) List(ctx context.Context, fl *filter.F) ([]*
 }
 
 // Insert is a mock for inserting/creating a new object.
-func (m *MockGlobalForwardingRules) Insert(ctx context.Context, key meta.Key, obj *ga.ForwardingRule) error {
+func (m *MockGlobalForwardingRules) Insert(ctx context.Context, key meta.Key, obj *ga.ForwardingRule) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "GlobalForwardingRules")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Insert",
+			Version:   meta.Version("ga"),
+			Service:   "GlobalForwardingRules",
+		},
+		Key:     &key,
+		Request: obj,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("global"); err != nil {
+		return err
+	}
+
 	if m.InsertHook != nil {
 		if intercept, err := m.InsertHook(m, ctx, key, obj); intercept {
 			glog.V(5).Infof("MockGlobalForwardingRules.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
@@ -4929,11 +7530,12 @@ func (m *MockGlobalForwardingRules) Insert(ctx context.Context, key meta.Key, ob
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.InsertError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.InsertError[skey]; ok {
 		glog.V(5).Infof("MockGlobalForwardingRules.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
 		return err
 	}
-	if _, ok := m.Objects[key]; ok {
+	if _, ok := m.Objects[skey]; ok {
 		err := &googleapi.Error{
 			Code:    http.StatusConflict,
 			Message: fmt.Sprintf("MockGlobalForwardingRules %v exists", key),
@@ -4944,16 +7546,34 @@ func (m *MockGlobalForwardingRules) Insert(ctx context.Context, key meta.Key, ob
 
 	obj.Name = key.Name
 	if obj.SelfLink == "" {
-		obj.SelfLink = SelfLink(meta.VersionGA, "mock-project", "forwardingRules", key)
+		obj.SelfLink = SelfLink(meta.VersionGA, projectID, "forwardingRules", key)
 	}
 
-	m.Objects[key] = &MockGlobalForwardingRulesObj{obj}
+	m.Objects[skey] = &MockGlobalForwardingRulesObj{obj}
 	glog.V(5).Infof("MockGlobalForwardingRules.Insert(%v, %v, %+v) = nil", ctx, key, obj)
 	return nil
 }
 
 // Delete is a mock for deleting the object.
-func (m *MockGlobalForwardingRules) Delete(ctx context.Context, key meta.Key) error {
+func (m *MockGlobalForwardingRules) Delete(ctx context.Context, key meta.Key) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "GlobalForwardingRules")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Delete",
+			Version:   meta.Version("ga"),
+			Service:   "GlobalForwardingRules",
+		},
+		Key:     &key,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("global"); err != nil {
+		return err
+	}
+
 	if m.DeleteHook != nil {
 		if intercept, err := m.DeleteHook(m, ctx, key); intercept {
 			glog.V(5).Infof("MockGlobalForwardingRules.Delete(%v, %v) = %v", ctx, key, err)
@@ -4961,14 +7581,22 @@ func (m *MockGlobalForwardingRules) Delete(ctx context.Context, key meta.Key) er
 		}
 	}
 
+	if m.deleteCheck != nil {
+		if err := m.deleteCheck(NewResourceID("forwardingRules", *key.WithProject(projectID))); err != nil {
+			glog.V(5).Infof("MockGlobalForwardingRules.Delete(%v, %v) = %v", ctx, key, err)
+			return err
+		}
+	}
+
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.DeleteError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.DeleteError[skey]; ok {
 		glog.V(5).Infof("MockGlobalForwardingRules.Delete(%v, %v) = %v", ctx, key, err)
 		return err
 	}
-	if _, ok := m.Objects[key]; !ok {
+	if _, ok := m.Objects[skey]; !ok {
 		err := &googleapi.Error{
 			Code:    http.StatusNotFound,
 			Message: fmt.Sprintf("MockGlobalForwardingRules %v not found", key),
@@ -4977,7 +7605,7 @@ func (m *MockGlobalForwardingRules) Delete(ctx context.Context, key meta.Key) er
 		return err
 	}
 
-	delete(m.Objects, key)
+	delete(m.Objects, skey)
 	glog.V(5).Infof("MockGlobalForwardingRules.Delete(%v, %v) = nil", ctx, key)
 	return nil
 }
@@ -4988,7 +7616,26 @@ func (m *MockGlobalForwardingRules) Obj(o *ga.ForwardingRule) *MockGlobalForward
 }
 
 // SetTarget is a mock for the corresponding method.
-func (m *MockGlobalForwardingRules) SetTarget(ctx context.Context, key meta.Key, arg0 *ga.TargetReference) error {
+func (m *MockGlobalForwardingRules) SetTarget(ctx context.Context, key meta.Key, arg0 *ga.TargetReference) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "GlobalForwardingRules")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "SetTarget",
+			Version:   meta.Version("ga"),
+			Service:   "GlobalForwardingRules",
+		},
+		Key:     &key,
+		Request: arg0,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("global"); err != nil {
+		return err
+	}
+
 	if m.SetTargetHook != nil {
 		return m.SetTargetHook(m, ctx, key, arg0)
 	}
@@ -5001,15 +7648,23 @@ type GCEGlobalForwardingRules struct {
 }
 
 // Get the ForwardingRule named by key.
-func (g *GCEGlobalForwardingRules) Get(ctx context.Context, key meta.Key) (*ga.ForwardingRule, error) {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "GlobalForwardingRules")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Get",
-		Version:   meta.Version("ga"),
-		Service:   "GlobalForwardingRules",
+func (g *GCEGlobalForwardingRules) Get(ctx context.Context, key meta.Key) (_ *ga.ForwardingRule, err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "GlobalForwardingRules")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("ga"),
+			Service:   "GlobalForwardingRules",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("global"); err != nil {
+		return nil, err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.GA.GlobalForwardingRules.Get(projectID, key.Name)
@@ -5018,24 +7673,38 @@ func (g *GCEGlobalForwardingRules) Get(ctx context.Context, key meta.Key) (*ga.F
 }
 
 // List all ForwardingRule objects.
-func (g *GCEGlobalForwardingRules) List(ctx context.Context, fl *filter.F) ([]*ga.ForwardingRule, error) {
+func (g *GCEGlobalForwardingRules) List(ctx context.Context, fl *filter.F) (_ []*ga.ForwardingRule, err error) {
 	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "GlobalForwardingRules")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "List",
-		Version:   meta.Version("ga"),
-		Service:   "GlobalForwardingRules",
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("ga"),
+			Service:   "GlobalForwardingRules",
+		},
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if g.s.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(ga.ForwardingRule{})); err != nil {
+			return nil, err
+		}
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.GA.GlobalForwardingRules.List(projectID)
-	if fl != filter.None {
-		call.Filter(fl.String())
+	serverFl, clientFl := fl.Split(reflect.TypeOf(ga.ForwardingRule{}))
+	if serverFl != filter.None {
+		call.Filter(serverFl.String())
 	}
 	var all []*ga.ForwardingRule
 	f := func(l *ga.ForwardingRuleList) error {
-		all = append(all, l.Items...)
+		for _, obj := range l.Items {
+			if clientFl.Match(obj) {
+				all = append(all, obj)
+			}
+		}
 		return nil
 	}
 	if err := call.Pages(ctx, f); err != nil {
@@ -5045,18 +7714,32 @@ func (g *GCEGlobalForwardingRules) List(ctx context.Context, fl *filter.F) ([]*g
 }
 
 // Insert ForwardingRule with key of value obj.
-func (g *GCEGlobalForwardingRules) Insert(ctx context.Context, key meta.Key, obj *ga.ForwardingRule) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "GlobalForwardingRules")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Insert",
-		Version:   meta.Version("ga"),
-		Service:   "GlobalForwardingRules",
+func (g *GCEGlobalForwardingRules) Insert(ctx context.Context, key meta.Key, obj *ga.ForwardingRule) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "GlobalForwardingRules")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Insert",
+			Version:   meta.Version("ga"),
+			Service:   "GlobalForwardingRules",
+		},
+		Key:     &key,
+		Request: obj,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("global"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
 	obj.Name = key.Name
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.GA.GlobalForwardingRules.Insert(projectID, obj)
 	call.Context(ctx)
 
@@ -5068,17 +7751,30 @@ func (g *GCEGlobalForwardingRules) Insert(ctx context.Context, key meta.Key, obj
 }
 
 // Delete the ForwardingRule referenced by key.
-func (g *GCEGlobalForwardingRules) Delete(ctx context.Context, key meta.Key) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "GlobalForwardingRules")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Delete",
-		Version:   meta.Version("ga"),
-		Service:   "GlobalForwardingRules",
+func (g *GCEGlobalForwardingRules) Delete(ctx context.Context, key meta.Key) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "GlobalForwardingRules")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Delete",
+			Version:   meta.Version("ga"),
+			Service:   "GlobalForwardingRules",
+		},
+		Key:     &key,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("global"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.GA.GlobalForwardingRules.Delete(projectID, key.Name)
 
 	call.Context(ctx)
@@ -5091,17 +7787,31 @@ func (g *GCEGlobalForwardingRules) Delete(ctx context.Context, key meta.Key) err
 }
 
 // SetTarget is a method on GCEGlobalForwardingRules.
-func (g *GCEGlobalForwardingRules) SetTarget(ctx context.Context, key meta.Key, arg0 *ga.TargetReference) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "GlobalForwardingRules")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "SetTarget",
-		Version:   meta.Version("ga"),
-		Service:   "GlobalForwardingRules",
+func (g *GCEGlobalForwardingRules) SetTarget(ctx context.Context, key meta.Key, arg0 *ga.TargetReference) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "GlobalForwardingRules")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "SetTarget",
+			Version:   meta.Version("ga"),
+			Service:   "GlobalForwardingRules",
+		},
+		Key:     &key,
+		Request: arg0,
+		Mutates: true,
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("global"); err != nil {
 		return err
 	}
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
+		return err
+	}
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.GA.GlobalForwardingRules.SetTarget(projectID, key.Name, arg0)
 	call.Context(ctx)
 	op, err := call.Do()
@@ -5155,13 +7865,46 @@ type MockHealthChecks struct {
 	DeleteHook func(m *MockHealthChecks, ctx context.Context, key meta.Key) (bool, error)
 	UpdateHook func(*MockHealthChecks, context.Context, meta.Key, *ga.HealthCheck) error
 
+	// Observer, if set, is invoked around each call to the mock.
+	Observer CallObserver
+
+	// ProjectRouter, if set, routes the calls with keys that do not have a
+	// project. Calls are made to "mock-project" if it is not set.
+	ProjectRouter ProjectRouter
+
+	// Debug enables additional checks of the arguments to the mock, such as
+	// the validation of List() filters with filter.Validate().
+	Debug bool
+
+	// deleteCheck, if set, is called by Delete() with the resource being
+	// deleted. Delete() returns the error if it is not nil. See
+	// WithReferenceChecking().
+	deleteCheck func(id *ResourceID) error
+
 	// X is extra state that can be used as part of the mock. Generated code
 	// will not use this field.
 	X interface{}
 }
 
 // Get returns the object from the mock.
-func (m *MockHealthChecks) Get(ctx context.Context, key meta.Key) (*ga.HealthCheck, error) {
+func (m *MockHealthChecks) Get(ctx context.Context, key meta.Key) (_ *ga.HealthCheck, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "HealthChecks")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("ga"),
+			Service:   "HealthChecks",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("global"); err != nil {
+		return nil, err
+	}
+
 	if m.GetHook != nil {
 		if intercept, obj, err := m.GetHook(m, ctx, key); intercept {
 			glog.V(5).Infof("MockHealthChecks.Get(%v, %s) = %+v, %v", ctx, key, obj, err)
@@ -5172,17 +7915,18 @@ func (m *MockHealthChecks) Get(ctx context.Context, key meta.Key) (*ga.HealthChe
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.GetError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.GetError[skey]; ok {
 		glog.V(5).Infof("MockHealthChecks.Get(%v, %s) = nil, %v", ctx, key, err)
 		return nil, err
 	}
-	if obj, ok := m.Objects[key]; ok {
+	if obj, ok := m.Objects[skey]; ok {
 		typedObj := obj.ToGA()
 		glog.V(5).Infof("MockHealthChecks.Get(%v, %s) = %+v, nil", ctx, key, typedObj)
 		return typedObj, nil
 	}
 
-	err := &googleapi.Error{
+	err = &googleapi.Error{
 		Code:    http.StatusNotFound,
 		Message: fmt.Sprintf("MockHealthChecks %v not found", key),
 	}
@@ -5191,7 +7935,26 @@ func (m *MockHealthChecks) Get(ctx context.Context, key meta.Key) (*ga.HealthChe
 }
 
 // List all of the objects in the mock.
-func (m *MockHealthChecks) List(ctx context.Context, fl *filter.F) ([]*ga.HealthCheck, error) {
+func (m *MockHealthChecks) List(ctx context.Context, fl *filter.F) (_ []*ga.HealthCheck, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, nil, "ga", "HealthChecks")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("ga"),
+			Service:   "HealthChecks",
+		},
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if m.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(ga.HealthCheck{})); err != nil {
+			glog.V(5).Infof("MockHealthChecks.List(%v, %v) = nil, %v", ctx, fl, err)
+			return nil, err
+		}
+	}
+
 	if m.ListHook != nil {
 		if intercept, objs, err := m.ListHook(m, ctx, fl); intercept {
 			glog.V(5).Infof("MockHealthChecks.List(%v, %v) = [%v items], %v", ctx, fl, len(objs), err)
@@ -5210,7 +7973,11 @@ func (m *MockHealthChecks) List(ctx context.Context, fl *filter.F) ([]*ga.Health
 	}
 
 	var objs []*ga.HealthCheck
-	for _, obj := range m.Objects {
+	for key, obj := range m.Objects {
+		// Objects in other projects are not listed.
+		if key.Project != mockStorageProject(projectID) {
+			continue
+		}
 		if !fl.Match(obj.ToGA()) {
 			continue
 		}
@@ -5222,7 +7989,26 @@ func (m *MockHealthChecks) List(ctx context.Context, fl *filter.F) ([]*ga.Health
 }
 
 // Insert is a mock for inserting/creating a new object.
-func (m *MockHealthChecks) Insert(ctx context.Context, key meta.Key, obj *ga.HealthCheck) error {
+func (m *MockHealthChecks) Insert(ctx context.Context, key meta.Key, obj *ga.HealthCheck) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "HealthChecks")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Insert",
+			Version:   meta.Version("ga"),
+			Service:   "HealthChecks",
+		},
+		Key:     &key,
+		Request: obj,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("global"); err != nil {
+		return err
+	}
+
 	if m.InsertHook != nil {
 		if intercept, err := m.InsertHook(m, ctx, key, obj); intercept {
 			glog.V(5).Infof("MockHealthChecks.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
@@ -5233,11 +8019,12 @@ func (m *MockHealthChecks) Insert(ctx context.Context, key meta.Key, obj *ga.Hea
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.InsertError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.InsertError[skey]; ok {
 		glog.V(5).Infof("MockHealthChecks.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
 		return err
 	}
-	if _, ok := m.Objects[key]; ok {
+	if _, ok := m.Objects[skey]; ok {
 		err := &googleapi.Error{
 			Code:    http.StatusConflict,
 			Message: fmt.Sprintf("MockHealthChecks %v exists", key),
@@ -5248,16 +8035,34 @@ func (m *MockHealthChecks) Insert(ctx context.Context, key meta.Key, obj *ga.Hea
 
 	obj.Name = key.Name
 	if obj.SelfLink == "" {
-		obj.SelfLink = SelfLink(meta.VersionGA, "mock-project", "healthChecks", key)
+		obj.SelfLink = SelfLink(meta.VersionGA, projectID, "healthChecks", key)
 	}
 
-	m.Objects[key] = &MockHealthChecksObj{obj}
+	m.Objects[skey] = &MockHealthChecksObj{obj}
 	glog.V(5).Infof("MockHealthChecks.Insert(%v, %v, %+v) = nil", ctx, key, obj)
 	return nil
 }
 
 // Delete is a mock for deleting the object.
-func (m *MockHealthChecks) Delete(ctx context.Context, key meta.Key) error {
+func (m *MockHealthChecks) Delete(ctx context.Context, key meta.Key) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "HealthChecks")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Delete",
+			Version:   meta.Version("ga"),
+			Service:   "HealthChecks",
+		},
+		Key:     &key,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("global"); err != nil {
+		return err
+	}
+
 	if m.DeleteHook != nil {
 		if intercept, err := m.DeleteHook(m, ctx, key); intercept {
 			glog.V(5).Infof("MockHealthChecks.Delete(%v, %v) = %v", ctx, key, err)
@@ -5265,14 +8070,22 @@ func (m *MockHealthChecks) Delete(ctx context.Context, key meta.Key) error {
 		}
 	}
 
+	if m.deleteCheck != nil {
+		if err := m.deleteCheck(NewResourceID("healthChecks", *key.WithProject(projectID))); err != nil {
+			glog.V(5).Infof("MockHealthChecks.Delete(%v, %v) = %v", ctx, key, err)
+			return err
+		}
+	}
+
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.DeleteError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.DeleteError[skey]; ok {
 		glog.V(5).Infof("MockHealthChecks.Delete(%v, %v) = %v", ctx, key, err)
 		return err
 	}
-	if _, ok := m.Objects[key]; !ok {
+	if _, ok := m.Objects[skey]; !ok {
 		err := &googleapi.Error{
 			Code:    http.StatusNotFound,
 			Message: fmt.Sprintf("MockHealthChecks %v not found", key),
@@ -5281,7 +8094,7 @@ func (m *MockHealthChecks) Delete(ctx context.Context, key meta.Key) error {
 		return err
 	}
 
-	delete(m.Objects, key)
+	delete(m.Objects, skey)
 	glog.V(5).Infof("MockHealthChecks.Delete(%v, %v) = nil", ctx, key)
 	return nil
 }
@@ -5291,29 +8104,78 @@ func (m *MockHealthChecks) Obj(o *ga.HealthCheck) *MockHealthChecksObj {
 	return &MockHealthChecksObj{o}
 }
 
-// Update is a mock for the corresponding method.
-func (m *MockHealthChecks) Update(ctx context.Context, key meta.Key, arg0 *ga.HealthCheck) error {
+// Update is a mock for the corresponding method. The object is replaced
+// with arg0 if UpdateHook is not set.
+func (m *MockHealthChecks) Update(ctx context.Context, key meta.Key, arg0 *ga.HealthCheck) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "HealthChecks")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Update",
+			Version:   meta.Version("ga"),
+			Service:   "HealthChecks",
+		},
+		Key:     &key,
+		Request: arg0,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("global"); err != nil {
+		return err
+	}
+
 	if m.UpdateHook != nil {
 		return m.UpdateHook(m, ctx, key, arg0)
 	}
-	return nil
-}
 
-// GCEHealthChecks is a simplifying adapter for the GCE HealthChecks.
-type GCEHealthChecks struct {
-	s *Service
-}
+	m.Lock.Lock()
+	defer m.Lock.Unlock()
 
-// Get the HealthCheck named by key.
-func (g *GCEHealthChecks) Get(ctx context.Context, key meta.Key) (*ga.HealthCheck, error) {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "HealthChecks")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Get",
-		Version:   meta.Version("ga"),
-		Service:   "HealthChecks",
+	skey := mockStorageKey(key, projectID)
+	if _, ok := m.Objects[skey]; !ok {
+		err := &googleapi.Error{
+			Code:    http.StatusNotFound,
+			Message: fmt.Sprintf("MockHealthChecks %v not found", key),
+		}
+		glog.V(5).Infof("MockHealthChecks.Update(%v, %v, %+v) = %v", ctx, key, arg0, err)
+		return err
+	}
+
+	arg0.Name = key.Name
+	if arg0.SelfLink == "" {
+		arg0.SelfLink = SelfLink(meta.VersionGA, projectID, "healthChecks", key)
+	}
+	// The fields that the version of arg0 does not have are kept, as in GCE.
+	m.Objects[skey] = &MockHealthChecksObj{mergeVersion(m.Objects[skey].Obj, arg0)}
+	glog.V(5).Infof("MockHealthChecks.Update(%v, %v, %+v) = nil", ctx, key, arg0)
+	return nil
+}
+
+// GCEHealthChecks is a simplifying adapter for the GCE HealthChecks.
+type GCEHealthChecks struct {
+	s *Service
+}
+
+// Get the HealthCheck named by key.
+func (g *GCEHealthChecks) Get(ctx context.Context, key meta.Key) (_ *ga.HealthCheck, err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "HealthChecks")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("ga"),
+			Service:   "HealthChecks",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("global"); err != nil {
+		return nil, err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.GA.HealthChecks.Get(projectID, key.Name)
@@ -5322,24 +8184,38 @@ func (g *GCEHealthChecks) Get(ctx context.Context, key meta.Key) (*ga.HealthChec
 }
 
 // List all HealthCheck objects.
-func (g *GCEHealthChecks) List(ctx context.Context, fl *filter.F) ([]*ga.HealthCheck, error) {
+func (g *GCEHealthChecks) List(ctx context.Context, fl *filter.F) (_ []*ga.HealthCheck, err error) {
 	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "HealthChecks")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "List",
-		Version:   meta.Version("ga"),
-		Service:   "HealthChecks",
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("ga"),
+			Service:   "HealthChecks",
+		},
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if g.s.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(ga.HealthCheck{})); err != nil {
+			return nil, err
+		}
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.GA.HealthChecks.List(projectID)
-	if fl != filter.None {
-		call.Filter(fl.String())
+	serverFl, clientFl := fl.Split(reflect.TypeOf(ga.HealthCheck{}))
+	if serverFl != filter.None {
+		call.Filter(serverFl.String())
 	}
 	var all []*ga.HealthCheck
 	f := func(l *ga.HealthCheckList) error {
-		all = append(all, l.Items...)
+		for _, obj := range l.Items {
+			if clientFl.Match(obj) {
+				all = append(all, obj)
+			}
+		}
 		return nil
 	}
 	if err := call.Pages(ctx, f); err != nil {
@@ -5349,18 +8225,32 @@ func (g *GCEHealthChecks) List(ctx context.Context, fl *filter.F) ([]*ga.HealthC
 }
 
 // Insert HealthCheck with key of value obj.
-func (g *GCEHealthChecks) Insert(ctx context.Context, key meta.Key, obj *ga.HealthCheck) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "HealthChecks")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Insert",
-		Version:   meta.Version("ga"),
-		Service:   "HealthChecks",
+func (g *GCEHealthChecks) Insert(ctx context.Context, key meta.Key, obj *ga.HealthCheck) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "HealthChecks")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Insert",
+			Version:   meta.Version("ga"),
+			Service:   "HealthChecks",
+		},
+		Key:     &key,
+		Request: obj,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("global"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
 	obj.Name = key.Name
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.GA.HealthChecks.Insert(projectID, obj)
 	call.Context(ctx)
 
@@ -5372,17 +8262,30 @@ func (g *GCEHealthChecks) Insert(ctx context.Context, key meta.Key, obj *ga.Heal
 }
 
 // Delete the HealthCheck referenced by key.
-func (g *GCEHealthChecks) Delete(ctx context.Context, key meta.Key) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "HealthChecks")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Delete",
-		Version:   meta.Version("ga"),
-		Service:   "HealthChecks",
+func (g *GCEHealthChecks) Delete(ctx context.Context, key meta.Key) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "HealthChecks")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Delete",
+			Version:   meta.Version("ga"),
+			Service:   "HealthChecks",
+		},
+		Key:     &key,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("global"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.GA.HealthChecks.Delete(projectID, key.Name)
 
 	call.Context(ctx)
@@ -5395,17 +8298,31 @@ func (g *GCEHealthChecks) Delete(ctx context.Context, key meta.Key) error {
 }
 
 // Update is a method on GCEHealthChecks.
-func (g *GCEHealthChecks) Update(ctx context.Context, key meta.Key, arg0 *ga.HealthCheck) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "HealthChecks")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Update",
-		Version:   meta.Version("ga"),
-		Service:   "HealthChecks",
+func (g *GCEHealthChecks) Update(ctx context.Context, key meta.Key, arg0 *ga.HealthCheck) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "HealthChecks")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Update",
+			Version:   meta.Version("ga"),
+			Service:   "HealthChecks",
+		},
+		Key:     &key,
+		Request: arg0,
+		Mutates: true,
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("global"); err != nil {
 		return err
 	}
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
+		return err
+	}
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.GA.HealthChecks.Update(projectID, key.Name, arg0)
 	call.Context(ctx)
 	op, err := call.Do()
@@ -5459,13 +8376,46 @@ type MockAlphaHealthChecks struct {
 	DeleteHook func(m *MockAlphaHealthChecks, ctx context.Context, key meta.Key) (bool, error)
 	UpdateHook func(*MockAlphaHealthChecks, context.Context, meta.Key, *alpha.HealthCheck) error
 
+	// Observer, if set, is invoked around each call to the mock.
+	Observer CallObserver
+
+	// ProjectRouter, if set, routes the calls with keys that do not have a
+	// project. Calls are made to "mock-project" if it is not set.
+	ProjectRouter ProjectRouter
+
+	// Debug enables additional checks of the arguments to the mock, such as
+	// the validation of List() filters with filter.Validate().
+	Debug bool
+
+	// deleteCheck, if set, is called by Delete() with the resource being
+	// deleted. Delete() returns the error if it is not nil. See
+	// WithReferenceChecking().
+	deleteCheck func(id *ResourceID) error
+
 	// X is extra state that can be used as part of the mock. Generated code
 	// will not use this field.
 	X interface{}
 }
 
 // Get returns the object from the mock.
-func (m *MockAlphaHealthChecks) Get(ctx context.Context, key meta.Key) (*alpha.HealthCheck, error) {
+func (m *MockAlphaHealthChecks) Get(ctx context.Context, key meta.Key) (_ *alpha.HealthCheck, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "alpha", "HealthChecks")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("alpha"),
+			Service:   "HealthChecks",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("global"); err != nil {
+		return nil, err
+	}
+
 	if m.GetHook != nil {
 		if intercept, obj, err := m.GetHook(m, ctx, key); intercept {
 			glog.V(5).Infof("MockAlphaHealthChecks.Get(%v, %s) = %+v, %v", ctx, key, obj, err)
@@ -5476,17 +8426,18 @@ func (m *MockAlphaHealthChecks) Get(ctx context.Context, key meta.Key) (*alpha.H
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.GetError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.GetError[skey]; ok {
 		glog.V(5).Infof("MockAlphaHealthChecks.Get(%v, %s) = nil, %v", ctx, key, err)
 		return nil, err
 	}
-	if obj, ok := m.Objects[key]; ok {
+	if obj, ok := m.Objects[skey]; ok {
 		typedObj := obj.ToAlpha()
 		glog.V(5).Infof("MockAlphaHealthChecks.Get(%v, %s) = %+v, nil", ctx, key, typedObj)
 		return typedObj, nil
 	}
 
-	err := &googleapi.Error{
+	err = &googleapi.Error{
 		Code:    http.StatusNotFound,
 		Message: fmt.Sprintf("MockAlphaHealthChecks %v not found", key),
 	}
@@ -5495,7 +8446,26 @@ func (m *MockAlphaHealthChecks) Get(ctx context.Context, key meta.Key) (*alpha.H
 }
 
 // List all of the objects in the mock.
-func (m *MockAlphaHealthChecks) List(ctx context.Context, fl *filter.F) ([]*alpha.HealthCheck, error) {
+func (m *MockAlphaHealthChecks) List(ctx context.Context, fl *filter.F) (_ []*alpha.HealthCheck, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, nil, "alpha", "HealthChecks")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("alpha"),
+			Service:   "HealthChecks",
+		},
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if m.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(alpha.HealthCheck{})); err != nil {
+			glog.V(5).Infof("MockAlphaHealthChecks.List(%v, %v) = nil, %v", ctx, fl, err)
+			return nil, err
+		}
+	}
+
 	if m.ListHook != nil {
 		if intercept, objs, err := m.ListHook(m, ctx, fl); intercept {
 			glog.V(5).Infof("MockAlphaHealthChecks.List(%v, %v) = [%v items], %v", ctx, fl, len(objs), err)
@@ -5514,7 +8484,11 @@ func (m *MockAlphaHealthChecks) List(ctx context.Context, fl *filter.F) ([]*alph
 	}
 
 	var objs []*alpha.HealthCheck
-	for _, obj := range m.Objects {
+	for key, obj := range m.Objects {
+		// Objects in other projects are not listed.
+		if key.Project != mockStorageProject(projectID) {
+			continue
+		}
 		if !fl.Match(obj.ToAlpha()) {
 			continue
 		}
@@ -5526,7 +8500,26 @@ func (m *MockAlphaHealthChecks) List(ctx context.Context, fl *filter.F) ([]*alph
 }
 
 // Insert is a mock for inserting/creating a new object.
-func (m *MockAlphaHealthChecks) Insert(ctx context.Context, key meta.Key, obj *alpha.HealthCheck) error {
+func (m *MockAlphaHealthChecks) Insert(ctx context.Context, key meta.Key, obj *alpha.HealthCheck) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "alpha", "HealthChecks")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Insert",
+			Version:   meta.Version("alpha"),
+			Service:   "HealthChecks",
+		},
+		Key:     &key,
+		Request: obj,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("global"); err != nil {
+		return err
+	}
+
 	if m.InsertHook != nil {
 		if intercept, err := m.InsertHook(m, ctx, key, obj); intercept {
 			glog.V(5).Infof("MockAlphaHealthChecks.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
@@ -5537,11 +8530,12 @@ func (m *MockAlphaHealthChecks) Insert(ctx context.Context, key meta.Key, obj *a
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.InsertError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.InsertError[skey]; ok {
 		glog.V(5).Infof("MockAlphaHealthChecks.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
 		return err
 	}
-	if _, ok := m.Objects[key]; ok {
+	if _, ok := m.Objects[skey]; ok {
 		err := &googleapi.Error{
 			Code:    http.StatusConflict,
 			Message: fmt.Sprintf("MockAlphaHealthChecks %v exists", key),
@@ -5552,16 +8546,34 @@ func (m *MockAlphaHealthChecks) Insert(ctx context.Context, key meta.Key, obj *a
 
 	obj.Name = key.Name
 	if obj.SelfLink == "" {
-		obj.SelfLink = SelfLink(meta.VersionAlpha, "mock-project", "healthChecks", key)
+		obj.SelfLink = SelfLink(meta.VersionAlpha, projectID, "healthChecks", key)
 	}
 
-	m.Objects[key] = &MockHealthChecksObj{obj}
+	m.Objects[skey] = &MockHealthChecksObj{obj}
 	glog.V(5).Infof("MockAlphaHealthChecks.Insert(%v, %v, %+v) = nil", ctx, key, obj)
 	return nil
 }
 
 // Delete is a mock for deleting the object.
-func (m *MockAlphaHealthChecks) Delete(ctx context.Context, key meta.Key) error {
+func (m *MockAlphaHealthChecks) Delete(ctx context.Context, key meta.Key) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "alpha", "HealthChecks")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Delete",
+			Version:   meta.Version("alpha"),
+			Service:   "HealthChecks",
+		},
+		Key:     &key,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("global"); err != nil {
+		return err
+	}
+
 	if m.DeleteHook != nil {
 		if intercept, err := m.DeleteHook(m, ctx, key); intercept {
 			glog.V(5).Infof("MockAlphaHealthChecks.Delete(%v, %v) = %v", ctx, key, err)
@@ -5569,14 +8581,22 @@ func (m *MockAlphaHealthChecks) Delete(ctx context.Context, key meta.Key) error
 		}
 	}
 
+	if m.deleteCheck != nil {
+		if err := m.deleteCheck(NewResourceID("healthChecks", *key.WithProject(projectID))); err != nil {
+			glog.V(5).Infof("MockAlphaHealthChecks.Delete(%v, %v) = %v", ctx, key, err)
+			return err
+		}
+	}
+
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.DeleteError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.DeleteError[skey]; ok {
 		glog.V(5).Infof("MockAlphaHealthChecks.Delete(%v, %v) = %v", ctx, key, err)
 		return err
 	}
-	if _, ok := m.Objects[key]; !ok {
+	if _, ok := m.Objects[skey]; !ok {
 		err := &googleapi.Error{
 			Code:    http.StatusNotFound,
 			Message: fmt.Sprintf("MockAlphaHealthChecks %v not found", key),
@@ -5585,7 +8605,7 @@ func (m *MockAlphaHealthChecks) Delete(ctx context.Context, key meta.Key) error
 		return err
 	}
 
-	delete(m.Objects, key)
+	delete(m.Objects, skey)
 	glog.V(5).Infof("MockAlphaHealthChecks.Delete(%v, %v) = nil", ctx, key)
 	return nil
 }
@@ -5595,11 +8615,52 @@ func (m *MockAlphaHealthChecks) Obj(o *alpha.HealthCheck) *MockHealthChecksObj {
 	return &MockHealthChecksObj{o}
 }
 
-// Update is a mock for the corresponding method.
-func (m *MockAlphaHealthChecks) Update(ctx context.Context, key meta.Key, arg0 *alpha.HealthCheck) error {
+// Update is a mock for the corresponding method. The object is replaced
+// with arg0 if UpdateHook is not set.
+func (m *MockAlphaHealthChecks) Update(ctx context.Context, key meta.Key, arg0 *alpha.HealthCheck) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "alpha", "HealthChecks")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Update",
+			Version:   meta.Version("alpha"),
+			Service:   "HealthChecks",
+		},
+		Key:     &key,
+		Request: arg0,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("global"); err != nil {
+		return err
+	}
+
 	if m.UpdateHook != nil {
 		return m.UpdateHook(m, ctx, key, arg0)
 	}
+
+	m.Lock.Lock()
+	defer m.Lock.Unlock()
+
+	skey := mockStorageKey(key, projectID)
+	if _, ok := m.Objects[skey]; !ok {
+		err := &googleapi.Error{
+			Code:    http.StatusNotFound,
+			Message: fmt.Sprintf("MockAlphaHealthChecks %v not found", key),
+		}
+		glog.V(5).Infof("MockAlphaHealthChecks.Update(%v, %v, %+v) = %v", ctx, key, arg0, err)
+		return err
+	}
+
+	arg0.Name = key.Name
+	if arg0.SelfLink == "" {
+		arg0.SelfLink = SelfLink(meta.VersionAlpha, projectID, "healthChecks", key)
+	}
+	// The fields that the version of arg0 does not have are kept, as in GCE.
+	m.Objects[skey] = &MockHealthChecksObj{mergeVersion(m.Objects[skey].Obj, arg0)}
+	glog.V(5).Infof("MockAlphaHealthChecks.Update(%v, %v, %+v) = nil", ctx, key, arg0)
 	return nil
 }
 
@@ -5609,15 +8670,23 @@ type GCEAlphaHealthChecks struct {
 }
 
 // Get the HealthCheck named by key.
-func (g *GCEAlphaHealthChecks) Get(ctx context.Context, key meta.Key) (*alpha.HealthCheck, error) {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "alpha", "HealthChecks")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Get",
-		Version:   meta.Version("alpha"),
-		Service:   "HealthChecks",
+func (g *GCEAlphaHealthChecks) Get(ctx context.Context, key meta.Key) (_ *alpha.HealthCheck, err error) {
+	projectID := g.s.keyProjectID(ctx, key, "alpha", "HealthChecks")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("alpha"),
+			Service:   "HealthChecks",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("global"); err != nil {
+		return nil, err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.Alpha.HealthChecks.Get(projectID, key.Name)
@@ -5626,24 +8695,38 @@ func (g *GCEAlphaHealthChecks) Get(ctx context.Context, key meta.Key) (*alpha.He
 }
 
 // List all HealthCheck objects.
-func (g *GCEAlphaHealthChecks) List(ctx context.Context, fl *filter.F) ([]*alpha.HealthCheck, error) {
+func (g *GCEAlphaHealthChecks) List(ctx context.Context, fl *filter.F) (_ []*alpha.HealthCheck, err error) {
 	projectID := g.s.ProjectRouter.ProjectID(ctx, "alpha", "HealthChecks")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "List",
-		Version:   meta.Version("alpha"),
-		Service:   "HealthChecks",
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("alpha"),
+			Service:   "HealthChecks",
+		},
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if g.s.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(alpha.HealthCheck{})); err != nil {
+			return nil, err
+		}
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.Alpha.HealthChecks.List(projectID)
-	if fl != filter.None {
-		call.Filter(fl.String())
+	serverFl, clientFl := fl.Split(reflect.TypeOf(alpha.HealthCheck{}))
+	if serverFl != filter.None {
+		call.Filter(serverFl.String())
 	}
 	var all []*alpha.HealthCheck
 	f := func(l *alpha.HealthCheckList) error {
-		all = append(all, l.Items...)
+		for _, obj := range l.Items {
+			if clientFl.Match(obj) {
+				all = append(all, obj)
+			}
+		}
 		return nil
 	}
 	if err := call.Pages(ctx, f); err != nil {
@@ -5653,18 +8736,32 @@ func (g *GCEAlphaHealthChecks) List(ctx context.Context, fl *filter.F) ([]*alpha
 }
 
 // Insert HealthCheck with key of value obj.
-func (g *GCEAlphaHealthChecks) Insert(ctx context.Context, key meta.Key, obj *alpha.HealthCheck) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "alpha", "HealthChecks")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Insert",
-		Version:   meta.Version("alpha"),
-		Service:   "HealthChecks",
+func (g *GCEAlphaHealthChecks) Insert(ctx context.Context, key meta.Key, obj *alpha.HealthCheck) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "alpha", "HealthChecks")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Insert",
+			Version:   meta.Version("alpha"),
+			Service:   "HealthChecks",
+		},
+		Key:     &key,
+		Request: obj,
+		Mutates: true,
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("global"); err != nil {
+		return err
+	}
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
 	obj.Name = key.Name
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.Alpha.HealthChecks.Insert(projectID, obj)
 	call.Context(ctx)
 
@@ -5676,17 +8773,30 @@ func (g *GCEAlphaHealthChecks) Insert(ctx context.Context, key meta.Key, obj *al
 }
 
 // Delete the HealthCheck referenced by key.
-func (g *GCEAlphaHealthChecks) Delete(ctx context.Context, key meta.Key) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "alpha", "HealthChecks")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Delete",
-		Version:   meta.Version("alpha"),
-		Service:   "HealthChecks",
+func (g *GCEAlphaHealthChecks) Delete(ctx context.Context, key meta.Key) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "alpha", "HealthChecks")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Delete",
+			Version:   meta.Version("alpha"),
+			Service:   "HealthChecks",
+		},
+		Key:     &key,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("global"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.Alpha.HealthChecks.Delete(projectID, key.Name)
 
 	call.Context(ctx)
@@ -5699,17 +8809,31 @@ func (g *GCEAlphaHealthChecks) Delete(ctx context.Context, key meta.Key) error {
 }
 
 // Update is a method on GCEAlphaHealthChecks.
-func (g *GCEAlphaHealthChecks) Update(ctx context.Context, key meta.Key, arg0 *alpha.HealthCheck) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "alpha", "HealthChecks")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Update",
-		Version:   meta.Version("alpha"),
-		Service:   "HealthChecks",
+func (g *GCEAlphaHealthChecks) Update(ctx context.Context, key meta.Key, arg0 *alpha.HealthCheck) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "alpha", "HealthChecks")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Update",
+			Version:   meta.Version("alpha"),
+			Service:   "HealthChecks",
+		},
+		Key:     &key,
+		Request: arg0,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("global"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.Alpha.HealthChecks.Update(projectID, key.Name, arg0)
 	call.Context(ctx)
 	op, err := call.Do()
@@ -5763,13 +8887,46 @@ type MockHttpHealthChecks struct {
 	DeleteHook func(m *MockHttpHealthChecks, ctx context.Context, key meta.Key) (bool, error)
 	UpdateHook func(*MockHttpHealthChecks, context.Context, meta.Key, *ga.HttpHealthCheck) error
 
+	// Observer, if set, is invoked around each call to the mock.
+	Observer CallObserver
+
+	// ProjectRouter, if set, routes the calls with keys that do not have a
+	// project. Calls are made to "mock-project" if it is not set.
+	ProjectRouter ProjectRouter
+
+	// Debug enables additional checks of the arguments to the mock, such as
+	// the validation of List() filters with filter.Validate().
+	Debug bool
+
+	// deleteCheck, if set, is called by Delete() with the resource being
+	// deleted. Delete() returns the error if it is not nil. See
+	// WithReferenceChecking().
+	deleteCheck func(id *ResourceID) error
+
 	// X is extra state that can be used as part of the mock. Generated code
 	// will not use this field.
 	X interface{}
 }
 
 // Get returns the object from the mock.
-func (m *MockHttpHealthChecks) Get(ctx context.Context, key meta.Key) (*ga.HttpHealthCheck, error) {
+func (m *MockHttpHealthChecks) Get(ctx context.Context, key meta.Key) (_ *ga.HttpHealthCheck, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "HttpHealthChecks")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("ga"),
+			Service:   "HttpHealthChecks",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("global"); err != nil {
+		return nil, err
+	}
+
 	if m.GetHook != nil {
 		if intercept, obj, err := m.GetHook(m, ctx, key); intercept {
 			glog.V(5).Infof("MockHttpHealthChecks.Get(%v, %s) = %+v, %v", ctx, key, obj, err)
@@ -5780,17 +8937,18 @@ func (m *MockHttpHealthChecks) Get(ctx context.Context, key meta.Key) (*ga.HttpH
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.GetError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.GetError[skey]; ok {
 		glog.V(5).Infof("MockHttpHealthChecks.Get(%v, %s) = nil, %v", ctx, key, err)
 		return nil, err
 	}
-	if obj, ok := m.Objects[key]; ok {
+	if obj, ok := m.Objects[skey]; ok {
 		typedObj := obj.ToGA()
 		glog.V(5).Infof("MockHttpHealthChecks.Get(%v, %s) = %+v, nil", ctx, key, typedObj)
 		return typedObj, nil
 	}
 
-	err := &googleapi.Error{
+	err = &googleapi.Error{
 		Code:    http.StatusNotFound,
 		Message: fmt.Sprintf("MockHttpHealthChecks %v not found", key),
 	}
@@ -5799,7 +8957,26 @@ func (m *MockHttpHealthChecks) Get(ctx context.Context, key meta.Key) (*ga.HttpH
 }
 
 // List all of the objects in the mock.
-func (m *MockHttpHealthChecks) List(ctx context.Context, fl *filter.F) ([]*ga.HttpHealthCheck, error) {
+func (m *MockHttpHealthChecks) List(ctx context.Context, fl *filter.F) (_ []*ga.HttpHealthCheck, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, nil, "ga", "HttpHealthChecks")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("ga"),
+			Service:   "HttpHealthChecks",
+		},
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if m.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(ga.HttpHealthCheck{})); err != nil {
+			glog.V(5).Infof("MockHttpHealthChecks.List(%v, %v) = nil, %v", ctx, fl, err)
+			return nil, err
+		}
+	}
+
 	if m.ListHook != nil {
 		if intercept, objs, err := m.ListHook(m, ctx, fl); intercept {
 			glog.V(5).Infof("MockHttpHealthChecks.List(%v, %v) = [%v items], %v", ctx, fl, len(objs), err)
@@ -5818,7 +8995,11 @@ func (m *MockHttpHealthChecks) List(ctx context.Context, fl *filter.F) ([]*ga.Ht
 	}
 
 	var objs []*ga.HttpHealthCheck
-	for _, obj := range m.Objects {
+	for key, obj := range m.Objects {
+		// Objects in other projects are not listed.
+		if key.Project != mockStorageProject(projectID) {
+			continue
+		}
 		if !fl.Match(obj.ToGA()) {
 			continue
 		}
@@ -5830,7 +9011,26 @@ func (m *MockHttpHealthChecks) List(ctx context.Context, fl *filter.F) ([]*ga.Ht
 }
 
 // Insert is a mock for inserting/creating a new object.
-func (m *MockHttpHealthChecks) Insert(ctx context.Context, key meta.Key, obj *ga.HttpHealthCheck) error {
+func (m *MockHttpHealthChecks) Insert(ctx context.Context, key meta.Key, obj *ga.HttpHealthCheck) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "HttpHealthChecks")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Insert",
+			Version:   meta.Version("ga"),
+			Service:   "HttpHealthChecks",
+		},
+		Key:     &key,
+		Request: obj,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("global"); err != nil {
+		return err
+	}
+
 	if m.InsertHook != nil {
 		if intercept, err := m.InsertHook(m, ctx, key, obj); intercept {
 			glog.V(5).Infof("MockHttpHealthChecks.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
@@ -5841,11 +9041,12 @@ func (m *MockHttpHealthChecks) Insert(ctx context.Context, key meta.Key, obj *ga
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.InsertError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.InsertError[skey]; ok {
 		glog.V(5).Infof("MockHttpHealthChecks.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
 		return err
 	}
-	if _, ok := m.Objects[key]; ok {
+	if _, ok := m.Objects[skey]; ok {
 		err := &googleapi.Error{
 			Code:    http.StatusConflict,
 			Message: fmt.Sprintf("MockHttpHealthChecks %v exists", key),
@@ -5856,16 +9057,34 @@ func (m *MockHttpHealthChecks) Insert(ctx context.Context, key meta.Key, obj *ga
 
 	obj.Name = key.Name
 	if obj.SelfLink == "" {
-		obj.SelfLink = SelfLink(meta.VersionGA, "mock-project", "httpHealthChecks", key)
+		obj.SelfLink = SelfLink(meta.VersionGA, projectID, "httpHealthChecks", key)
 	}
 
-	m.Objects[key] = &MockHttpHealthChecksObj{obj}
+	m.Objects[skey] = &MockHttpHealthChecksObj{obj}
 	glog.V(5).Infof("MockHttpHealthChecks.Insert(%v, %v, %+v) = nil", ctx, key, obj)
 	return nil
 }
 
 // Delete is a mock for deleting the object.
-func (m *MockHttpHealthChecks) Delete(ctx context.Context, key meta.Key) error {
+func (m *MockHttpHealthChecks) Delete(ctx context.Context, key meta.Key) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "HttpHealthChecks")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Delete",
+			Version:   meta.Version("ga"),
+			Service:   "HttpHealthChecks",
+		},
+		Key:     &key,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("global"); err != nil {
+		return err
+	}
+
 	if m.DeleteHook != nil {
 		if intercept, err := m.DeleteHook(m, ctx, key); intercept {
 			glog.V(5).Infof("MockHttpHealthChecks.Delete(%v, %v) = %v", ctx, key, err)
@@ -5873,14 +9092,22 @@ func (m *MockHttpHealthChecks) Delete(ctx context.Context, key meta.Key) error {
 		}
 	}
 
+	if m.deleteCheck != nil {
+		if err := m.deleteCheck(NewResourceID("httpHealthChecks", *key.WithProject(projectID))); err != nil {
+			glog.V(5).Infof("MockHttpHealthChecks.Delete(%v, %v) = %v", ctx, key, err)
+			return err
+		}
+	}
+
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.DeleteError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.DeleteError[skey]; ok {
 		glog.V(5).Infof("MockHttpHealthChecks.Delete(%v, %v) = %v", ctx, key, err)
 		return err
 	}
-	if _, ok := m.Objects[key]; !ok {
+	if _, ok := m.Objects[skey]; !ok {
 		err := &googleapi.Error{
 			Code:    http.StatusNotFound,
 			Message: fmt.Sprintf("MockHttpHealthChecks %v not found", key),
@@ -5889,7 +9116,7 @@ func (m *MockHttpHealthChecks) Delete(ctx context.Context, key meta.Key) error {
 		return err
 	}
 
-	delete(m.Objects, key)
+	delete(m.Objects, skey)
 	glog.V(5).Infof("MockHttpHealthChecks.Delete(%v, %v) = nil", ctx, key)
 	return nil
 }
@@ -5899,11 +9126,52 @@ func (m *MockHttpHealthChecks) Obj(o *ga.HttpHealthCheck) *MockHttpHealthChecksO
 	return &MockHttpHealthChecksObj{o}
 }
 
-// Update is a mock for the corresponding method.
-func (m *MockHttpHealthChecks) Update(ctx context.Context, key meta.Key, arg0 *ga.HttpHealthCheck) error {
+// Update is a mock for the corresponding method. The object is replaced
+// with arg0 if UpdateHook is not set.
+func (m *MockHttpHealthChecks) Update(ctx context.Context, key meta.Key, arg0 *ga.HttpHealthCheck) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "HttpHealthChecks")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Update",
+			Version:   meta.Version("ga"),
+			Service:   "HttpHealthChecks",
+		},
+		Key:     &key,
+		Request: arg0,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("global"); err != nil {
+		return err
+	}
+
 	if m.UpdateHook != nil {
 		return m.UpdateHook(m, ctx, key, arg0)
 	}
+
+	m.Lock.Lock()
+	defer m.Lock.Unlock()
+
+	skey := mockStorageKey(key, projectID)
+	if _, ok := m.Objects[skey]; !ok {
+		err := &googleapi.Error{
+			Code:    http.StatusNotFound,
+			Message: fmt.Sprintf("MockHttpHealthChecks %v not found", key),
+		}
+		glog.V(5).Infof("MockHttpHealthChecks.Update(%v, %v, %+v) = %v", ctx, key, arg0, err)
+		return err
+	}
+
+	arg0.Name = key.Name
+	if arg0.SelfLink == "" {
+		arg0.SelfLink = SelfLink(meta.VersionGA, projectID, "httpHealthChecks", key)
+	}
+	// The fields that the version of arg0 does not have are kept, as in GCE.
+	m.Objects[skey] = &MockHttpHealthChecksObj{mergeVersion(m.Objects[skey].Obj, arg0)}
+	glog.V(5).Infof("MockHttpHealthChecks.Update(%v, %v, %+v) = nil", ctx, key, arg0)
 	return nil
 }
 
@@ -5913,15 +9181,23 @@ type GCEHttpHealthChecks struct {
 }
 
 // Get the HttpHealthCheck named by key.
-func (g *GCEHttpHealthChecks) Get(ctx context.Context, key meta.Key) (*ga.HttpHealthCheck, error) {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "HttpHealthChecks")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Get",
-		Version:   meta.Version("ga"),
-		Service:   "HttpHealthChecks",
+func (g *GCEHttpHealthChecks) Get(ctx context.Context, key meta.Key) (_ *ga.HttpHealthCheck, err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "HttpHealthChecks")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("ga"),
+			Service:   "HttpHealthChecks",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("global"); err != nil {
+		return nil, err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.GA.HttpHealthChecks.Get(projectID, key.Name)
@@ -5930,24 +9206,38 @@ func (g *GCEHttpHealthChecks) Get(ctx context.Context, key meta.Key) (*ga.HttpHe
 }
 
 // List all HttpHealthCheck objects.
-func (g *GCEHttpHealthChecks) List(ctx context.Context, fl *filter.F) ([]*ga.HttpHealthCheck, error) {
+func (g *GCEHttpHealthChecks) List(ctx context.Context, fl *filter.F) (_ []*ga.HttpHealthCheck, err error) {
 	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "HttpHealthChecks")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "List",
-		Version:   meta.Version("ga"),
-		Service:   "HttpHealthChecks",
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("ga"),
+			Service:   "HttpHealthChecks",
+		},
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if g.s.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(ga.HttpHealthCheck{})); err != nil {
+			return nil, err
+		}
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.GA.HttpHealthChecks.List(projectID)
-	if fl != filter.None {
-		call.Filter(fl.String())
+	serverFl, clientFl := fl.Split(reflect.TypeOf(ga.HttpHealthCheck{}))
+	if serverFl != filter.None {
+		call.Filter(serverFl.String())
 	}
 	var all []*ga.HttpHealthCheck
 	f := func(l *ga.HttpHealthCheckList) error {
-		all = append(all, l.Items...)
+		for _, obj := range l.Items {
+			if clientFl.Match(obj) {
+				all = append(all, obj)
+			}
+		}
 		return nil
 	}
 	if err := call.Pages(ctx, f); err != nil {
@@ -5957,18 +9247,32 @@ func (g *GCEHttpHealthChecks) List(ctx context.Context, fl *filter.F) ([]*ga.Htt
 }
 
 // Insert HttpHealthCheck with key of value obj.
-func (g *GCEHttpHealthChecks) Insert(ctx context.Context, key meta.Key, obj *ga.HttpHealthCheck) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "HttpHealthChecks")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Insert",
-		Version:   meta.Version("ga"),
-		Service:   "HttpHealthChecks",
+func (g *GCEHttpHealthChecks) Insert(ctx context.Context, key meta.Key, obj *ga.HttpHealthCheck) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "HttpHealthChecks")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Insert",
+			Version:   meta.Version("ga"),
+			Service:   "HttpHealthChecks",
+		},
+		Key:     &key,
+		Request: obj,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("global"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
 	obj.Name = key.Name
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.GA.HttpHealthChecks.Insert(projectID, obj)
 	call.Context(ctx)
 
@@ -5980,17 +9284,30 @@ func (g *GCEHttpHealthChecks) Insert(ctx context.Context, key meta.Key, obj *ga.
 }
 
 // Delete the HttpHealthCheck referenced by key.
-func (g *GCEHttpHealthChecks) Delete(ctx context.Context, key meta.Key) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "HttpHealthChecks")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Delete",
-		Version:   meta.Version("ga"),
-		Service:   "HttpHealthChecks",
+func (g *GCEHttpHealthChecks) Delete(ctx context.Context, key meta.Key) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "HttpHealthChecks")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Delete",
+			Version:   meta.Version("ga"),
+			Service:   "HttpHealthChecks",
+		},
+		Key:     &key,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("global"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.GA.HttpHealthChecks.Delete(projectID, key.Name)
 
 	call.Context(ctx)
@@ -6003,17 +9320,31 @@ func (g *GCEHttpHealthChecks) Delete(ctx context.Context, key meta.Key) error {
 }
 
 // Update is a method on GCEHttpHealthChecks.
-func (g *GCEHttpHealthChecks) Update(ctx context.Context, key meta.Key, arg0 *ga.HttpHealthCheck) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "HttpHealthChecks")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Update",
-		Version:   meta.Version("ga"),
-		Service:   "HttpHealthChecks",
+func (g *GCEHttpHealthChecks) Update(ctx context.Context, key meta.Key, arg0 *ga.HttpHealthCheck) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "HttpHealthChecks")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Update",
+			Version:   meta.Version("ga"),
+			Service:   "HttpHealthChecks",
+		},
+		Key:     &key,
+		Request: arg0,
+		Mutates: true,
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("global"); err != nil {
 		return err
 	}
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
+		return err
+	}
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.GA.HttpHealthChecks.Update(projectID, key.Name, arg0)
 	call.Context(ctx)
 	op, err := call.Do()
@@ -6067,13 +9398,46 @@ type MockHttpsHealthChecks struct {
 	DeleteHook func(m *MockHttpsHealthChecks, ctx context.Context, key meta.Key) (bool, error)
 	UpdateHook func(*MockHttpsHealthChecks, context.Context, meta.Key, *ga.HttpsHealthCheck) error
 
+	// Observer, if set, is invoked around each call to the mock.
+	Observer CallObserver
+
+	// ProjectRouter, if set, routes the calls with keys that do not have a
+	// project. Calls are made to "mock-project" if it is not set.
+	ProjectRouter ProjectRouter
+
+	// Debug enables additional checks of the arguments to the mock, such as
+	// the validation of List() filters with filter.Validate().
+	Debug bool
+
+	// deleteCheck, if set, is called by Delete() with the resource being
+	// deleted. Delete() returns the error if it is not nil. See
+	// WithReferenceChecking().
+	deleteCheck func(id *ResourceID) error
+
 	// X is extra state that can be used as part of the mock. Generated code
 	// will not use this field.
 	X interface{}
 }
 
 // Get returns the object from the mock.
-func (m *MockHttpsHealthChecks) Get(ctx context.Context, key meta.Key) (*ga.HttpsHealthCheck, error) {
+func (m *MockHttpsHealthChecks) Get(ctx context.Context, key meta.Key) (_ *ga.HttpsHealthCheck, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "HttpsHealthChecks")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("ga"),
+			Service:   "HttpsHealthChecks",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("global"); err != nil {
+		return nil, err
+	}
+
 	if m.GetHook != nil {
 		if intercept, obj, err := m.GetHook(m, ctx, key); intercept {
 			glog.V(5).Infof("MockHttpsHealthChecks.Get(%v, %s) = %+v, %v", ctx, key, obj, err)
@@ -6084,17 +9448,18 @@ func (m *MockHttpsHealthChecks) Get(ctx context.Context, key meta.Key) (*ga.Http
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.GetError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.GetError[skey]; ok {
 		glog.V(5).Infof("MockHttpsHealthChecks.Get(%v, %s) = nil, %v", ctx, key, err)
 		return nil, err
 	}
-	if obj, ok := m.Objects[key]; ok {
+	if obj, ok := m.Objects[skey]; ok {
 		typedObj := obj.ToGA()
 		glog.V(5).Infof("MockHttpsHealthChecks.Get(%v, %s) = %+v, nil", ctx, key, typedObj)
 		return typedObj, nil
 	}
 
-	err := &googleapi.Error{
+	err = &googleapi.Error{
 		Code:    http.StatusNotFound,
 		Message: fmt.Sprintf("MockHttpsHealthChecks %v not found", key),
 	}
@@ -6103,7 +9468,26 @@ func (m *MockHttpsHealthChecks) Get(ctx context.Context, key meta.Key) (*ga.Http
 }
 
 // List all of the objects in the mock.
-func (m *MockHttpsHealthChecks) List(ctx context.Context, fl *filter.F) ([]*ga.HttpsHealthCheck, error) {
+func (m *MockHttpsHealthChecks) List(ctx context.Context, fl *filter.F) (_ []*ga.HttpsHealthCheck, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, nil, "ga", "HttpsHealthChecks")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("ga"),
+			Service:   "HttpsHealthChecks",
+		},
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if m.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(ga.HttpsHealthCheck{})); err != nil {
+			glog.V(5).Infof("MockHttpsHealthChecks.List(%v, %v) = nil, %v", ctx, fl, err)
+			return nil, err
+		}
+	}
+
 	if m.ListHook != nil {
 		if intercept, objs, err := m.ListHook(m, ctx, fl); intercept {
 			glog.V(5).Infof("MockHttpsHealthChecks.List(%v, %v) = [%v items], %v", ctx, fl, len(objs), err)
@@ -6122,7 +9506,11 @@ func (m *MockHttpsHealthChecks) List(ctx context.Context, fl *filter.F) ([]*ga.H
 	}
 
 	var objs []*ga.HttpsHealthCheck
-	for _, obj := range m.Objects {
+	for key, obj := range m.Objects {
+		// Objects in other projects are not listed.
+		if key.Project != mockStorageProject(projectID) {
+			continue
+		}
 		if !fl.Match(obj.ToGA()) {
 			continue
 		}
@@ -6134,7 +9522,26 @@ func (m *MockHttpsHealthChecks) List(ctx context.Context, fl *filter.F) ([]*ga.H
 }
 
 // Insert is a mock for inserting/creating a new object.
-func (m *MockHttpsHealthChecks) Insert(ctx context.Context, key meta.Key, obj *ga.HttpsHealthCheck) error {
+func (m *MockHttpsHealthChecks) Insert(ctx context.Context, key meta.Key, obj *ga.HttpsHealthCheck) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "HttpsHealthChecks")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Insert",
+			Version:   meta.Version("ga"),
+			Service:   "HttpsHealthChecks",
+		},
+		Key:     &key,
+		Request: obj,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("global"); err != nil {
+		return err
+	}
+
 	if m.InsertHook != nil {
 		if intercept, err := m.InsertHook(m, ctx, key, obj); intercept {
 			glog.V(5).Infof("MockHttpsHealthChecks.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
@@ -6145,11 +9552,12 @@ func (m *MockHttpsHealthChecks) Insert(ctx context.Context, key meta.Key, obj *g
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.InsertError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.InsertError[skey]; ok {
 		glog.V(5).Infof("MockHttpsHealthChecks.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
 		return err
 	}
-	if _, ok := m.Objects[key]; ok {
+	if _, ok := m.Objects[skey]; ok {
 		err := &googleapi.Error{
 			Code:    http.StatusConflict,
 			Message: fmt.Sprintf("MockHttpsHealthChecks %v exists", key),
@@ -6160,16 +9568,34 @@ func (m *MockHttpsHealthChecks) Insert(ctx context.Context, key meta.Key, obj *g
 
 	obj.Name = key.Name
 	if obj.SelfLink == "" {
-		obj.SelfLink = SelfLink(meta.VersionGA, "mock-project", "httpsHealthChecks", key)
+		obj.SelfLink = SelfLink(meta.VersionGA, projectID, "httpsHealthChecks", key)
 	}
 
-	m.Objects[key] = &MockHttpsHealthChecksObj{obj}
+	m.Objects[skey] = &MockHttpsHealthChecksObj{obj}
 	glog.V(5).Infof("MockHttpsHealthChecks.Insert(%v, %v, %+v) = nil", ctx, key, obj)
 	return nil
 }
 
 // Delete is a mock for deleting the object.
-func (m *MockHttpsHealthChecks) Delete(ctx context.Context, key meta.Key) error {
+func (m *MockHttpsHealthChecks) Delete(ctx context.Context, key meta.Key) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "HttpsHealthChecks")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Delete",
+			Version:   meta.Version("ga"),
+			Service:   "HttpsHealthChecks",
+		},
+		Key:     &key,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("global"); err != nil {
+		return err
+	}
+
 	if m.DeleteHook != nil {
 		if intercept, err := m.DeleteHook(m, ctx, key); intercept {
 			glog.V(5).Infof("MockHttpsHealthChecks.Delete(%v, %v) = %v", ctx, key, err)
@@ -6177,14 +9603,22 @@ func (m *MockHttpsHealthChecks) Delete(ctx context.Context, key meta.Key) error
 		}
 	}
 
+	if m.deleteCheck != nil {
+		if err := m.deleteCheck(NewResourceID("httpsHealthChecks", *key.WithProject(projectID))); err != nil {
+			glog.V(5).Infof("MockHttpsHealthChecks.Delete(%v, %v) = %v", ctx, key, err)
+			return err
+		}
+	}
+
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.DeleteError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.DeleteError[skey]; ok {
 		glog.V(5).Infof("MockHttpsHealthChecks.Delete(%v, %v) = %v", ctx, key, err)
 		return err
 	}
-	if _, ok := m.Objects[key]; !ok {
+	if _, ok := m.Objects[skey]; !ok {
 		err := &googleapi.Error{
 			Code:    http.StatusNotFound,
 			Message: fmt.Sprintf("MockHttpsHealthChecks %v not found", key),
@@ -6193,7 +9627,7 @@ func (m *MockHttpsHealthChecks) Delete(ctx context.Context, key meta.Key) error
 		return err
 	}
 
-	delete(m.Objects, key)
+	delete(m.Objects, skey)
 	glog.V(5).Infof("MockHttpsHealthChecks.Delete(%v, %v) = nil", ctx, key)
 	return nil
 }
@@ -6203,11 +9637,52 @@ func (m *MockHttpsHealthChecks) Obj(o *ga.HttpsHealthCheck) *MockHttpsHealthChec
 	return &MockHttpsHealthChecksObj{o}
 }
 
-// Update is a mock for the corresponding method.
-func (m *MockHttpsHealthChecks) Update(ctx context.Context, key meta.Key, arg0 *ga.HttpsHealthCheck) error {
+// Update is a mock for the corresponding method. The object is replaced
+// with arg0 if UpdateHook is not set.
+func (m *MockHttpsHealthChecks) Update(ctx context.Context, key meta.Key, arg0 *ga.HttpsHealthCheck) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "HttpsHealthChecks")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Update",
+			Version:   meta.Version("ga"),
+			Service:   "HttpsHealthChecks",
+		},
+		Key:     &key,
+		Request: arg0,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("global"); err != nil {
+		return err
+	}
+
 	if m.UpdateHook != nil {
 		return m.UpdateHook(m, ctx, key, arg0)
 	}
+
+	m.Lock.Lock()
+	defer m.Lock.Unlock()
+
+	skey := mockStorageKey(key, projectID)
+	if _, ok := m.Objects[skey]; !ok {
+		err := &googleapi.Error{
+			Code:    http.StatusNotFound,
+			Message: fmt.Sprintf("MockHttpsHealthChecks %v not found", key),
+		}
+		glog.V(5).Infof("MockHttpsHealthChecks.Update(%v, %v, %+v) = %v", ctx, key, arg0, err)
+		return err
+	}
+
+	arg0.Name = key.Name
+	if arg0.SelfLink == "" {
+		arg0.SelfLink = SelfLink(meta.VersionGA, projectID, "httpsHealthChecks", key)
+	}
+	// The fields that the version of arg0 does not have are kept, as in GCE.
+	m.Objects[skey] = &MockHttpsHealthChecksObj{mergeVersion(m.Objects[skey].Obj, arg0)}
+	glog.V(5).Infof("MockHttpsHealthChecks.Update(%v, %v, %+v) = nil", ctx, key, arg0)
 	return nil
 }
 
@@ -6217,15 +9692,23 @@ type GCEHttpsHealthChecks struct {
 }
 
 // Get the HttpsHealthCheck named by key.
-func (g *GCEHttpsHealthChecks) Get(ctx context.Context, key meta.Key) (*ga.HttpsHealthCheck, error) {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "HttpsHealthChecks")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Get",
-		Version:   meta.Version("ga"),
-		Service:   "HttpsHealthChecks",
+func (g *GCEHttpsHealthChecks) Get(ctx context.Context, key meta.Key) (_ *ga.HttpsHealthCheck, err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "HttpsHealthChecks")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("ga"),
+			Service:   "HttpsHealthChecks",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("global"); err != nil {
+		return nil, err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.GA.HttpsHealthChecks.Get(projectID, key.Name)
@@ -6234,24 +9717,38 @@ func (g *GCEHttpsHealthChecks) Get(ctx context.Context, key meta.Key) (*ga.Https
 }
 
 // List all HttpsHealthCheck objects.
-func (g *GCEHttpsHealthChecks) List(ctx context.Context, fl *filter.F) ([]*ga.HttpsHealthCheck, error) {
+func (g *GCEHttpsHealthChecks) List(ctx context.Context, fl *filter.F) (_ []*ga.HttpsHealthCheck, err error) {
 	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "HttpsHealthChecks")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "List",
-		Version:   meta.Version("ga"),
-		Service:   "HttpsHealthChecks",
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("ga"),
+			Service:   "HttpsHealthChecks",
+		},
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if g.s.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(ga.HttpsHealthCheck{})); err != nil {
+			return nil, err
+		}
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.GA.HttpsHealthChecks.List(projectID)
-	if fl != filter.None {
-		call.Filter(fl.String())
+	serverFl, clientFl := fl.Split(reflect.TypeOf(ga.HttpsHealthCheck{}))
+	if serverFl != filter.None {
+		call.Filter(serverFl.String())
 	}
 	var all []*ga.HttpsHealthCheck
 	f := func(l *ga.HttpsHealthCheckList) error {
-		all = append(all, l.Items...)
+		for _, obj := range l.Items {
+			if clientFl.Match(obj) {
+				all = append(all, obj)
+			}
+		}
 		return nil
 	}
 	if err := call.Pages(ctx, f); err != nil {
@@ -6261,18 +9758,32 @@ func (g *GCEHttpsHealthChecks) List(ctx context.Context, fl *filter.F) ([]*ga.Ht
 }
 
 // Insert HttpsHealthCheck with key of value obj.
-func (g *GCEHttpsHealthChecks) Insert(ctx context.Context, key meta.Key, obj *ga.HttpsHealthCheck) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "HttpsHealthChecks")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Insert",
-		Version:   meta.Version("ga"),
-		Service:   "HttpsHealthChecks",
+func (g *GCEHttpsHealthChecks) Insert(ctx context.Context, key meta.Key, obj *ga.HttpsHealthCheck) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "HttpsHealthChecks")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Insert",
+			Version:   meta.Version("ga"),
+			Service:   "HttpsHealthChecks",
+		},
+		Key:     &key,
+		Request: obj,
+		Mutates: true,
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("global"); err != nil {
+		return err
+	}
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
 	obj.Name = key.Name
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.GA.HttpsHealthChecks.Insert(projectID, obj)
 	call.Context(ctx)
 
@@ -6284,17 +9795,30 @@ func (g *GCEHttpsHealthChecks) Insert(ctx context.Context, key meta.Key, obj *ga
 }
 
 // Delete the HttpsHealthCheck referenced by key.
-func (g *GCEHttpsHealthChecks) Delete(ctx context.Context, key meta.Key) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "HttpsHealthChecks")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Delete",
-		Version:   meta.Version("ga"),
-		Service:   "HttpsHealthChecks",
+func (g *GCEHttpsHealthChecks) Delete(ctx context.Context, key meta.Key) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "HttpsHealthChecks")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Delete",
+			Version:   meta.Version("ga"),
+			Service:   "HttpsHealthChecks",
+		},
+		Key:     &key,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("global"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.GA.HttpsHealthChecks.Delete(projectID, key.Name)
 
 	call.Context(ctx)
@@ -6307,17 +9831,31 @@ func (g *GCEHttpsHealthChecks) Delete(ctx context.Context, key meta.Key) error {
 }
 
 // Update is a method on GCEHttpsHealthChecks.
-func (g *GCEHttpsHealthChecks) Update(ctx context.Context, key meta.Key, arg0 *ga.HttpsHealthCheck) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "HttpsHealthChecks")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Update",
-		Version:   meta.Version("ga"),
-		Service:   "HttpsHealthChecks",
+func (g *GCEHttpsHealthChecks) Update(ctx context.Context, key meta.Key, arg0 *ga.HttpsHealthCheck) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "HttpsHealthChecks")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Update",
+			Version:   meta.Version("ga"),
+			Service:   "HttpsHealthChecks",
+		},
+		Key:     &key,
+		Request: arg0,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("global"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.GA.HttpsHealthChecks.Update(projectID, key.Name, arg0)
 	call.Context(ctx)
 	op, err := call.Do()
@@ -6377,13 +9915,46 @@ type MockInstanceGroups struct {
 	RemoveInstancesHook func(*MockInstanceGroups, context.Context, meta.Key, *ga.InstanceGroupsRemoveInstancesRequest) error
 	SetNamedPortsHook   func(*MockInstanceGroups, context.Context, meta.Key, *ga.InstanceGroupsSetNamedPortsRequest) error
 
+	// Observer, if set, is invoked around each call to the mock.
+	Observer CallObserver
+
+	// ProjectRouter, if set, routes the calls with keys that do not have a
+	// project. Calls are made to "mock-project" if it is not set.
+	ProjectRouter ProjectRouter
+
+	// Debug enables additional checks of the arguments to the mock, such as
+	// the validation of List() filters with filter.Validate().
+	Debug bool
+
+	// deleteCheck, if set, is called by Delete() with the resource being
+	// deleted. Delete() returns the error if it is not nil. See
+	// WithReferenceChecking().
+	deleteCheck func(id *ResourceID) error
+
 	// X is extra state that can be used as part of the mock. Generated code
 	// will not use this field.
 	X interface{}
 }
 
 // Get returns the object from the mock.
-func (m *MockInstanceGroups) Get(ctx context.Context, key meta.Key) (*ga.InstanceGroup, error) {
+func (m *MockInstanceGroups) Get(ctx context.Context, key meta.Key) (_ *ga.InstanceGroup, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "InstanceGroups")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("ga"),
+			Service:   "InstanceGroups",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("zonal"); err != nil {
+		return nil, err
+	}
+
 	if m.GetHook != nil {
 		if intercept, obj, err := m.GetHook(m, ctx, key); intercept {
 			glog.V(5).Infof("MockInstanceGroups.Get(%v, %s) = %+v, %v", ctx, key, obj, err)
@@ -6394,17 +9965,18 @@ func (m *MockInstanceGroups) Get(ctx context.Context, key meta.Key) (*ga.Instanc
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.GetError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.GetError[skey]; ok {
 		glog.V(5).Infof("MockInstanceGroups.Get(%v, %s) = nil, %v", ctx, key, err)
 		return nil, err
 	}
-	if obj, ok := m.Objects[key]; ok {
+	if obj, ok := m.Objects[skey]; ok {
 		typedObj := obj.ToGA()
 		glog.V(5).Infof("MockInstanceGroups.Get(%v, %s) = %+v, nil", ctx, key, typedObj)
 		return typedObj, nil
 	}
 
-	err := &googleapi.Error{
+	err = &googleapi.Error{
 		Code:    http.StatusNotFound,
 		Message: fmt.Sprintf("MockInstanceGroups %v not found", key),
 	}
@@ -6413,7 +9985,26 @@ func (m *MockInstanceGroups) Get(ctx context.Context, key meta.Key) (*ga.Instanc
 }
 
 // List all of the objects in the mock in the given zone.
-func (m *MockInstanceGroups) List(ctx context.Context, zone string, fl *filter.F) ([]*ga.InstanceGroup, error) {
+func (m *MockInstanceGroups) List(ctx context.Context, zone string, fl *filter.F) (_ []*ga.InstanceGroup, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, nil, "ga", "InstanceGroups")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("ga"),
+			Service:   "InstanceGroups",
+		},
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if m.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(ga.InstanceGroup{})); err != nil {
+			glog.V(5).Infof("MockInstanceGroups.List(%v, %v) = nil, %v", ctx, fl, err)
+			return nil, err
+		}
+	}
+
 	if m.ListHook != nil {
 		if intercept, objs, err := m.ListHook(m, ctx, zone, fl); intercept {
 			glog.V(5).Infof("MockInstanceGroups.List(%v, %q, %v) = [%v items], %v", ctx, zone, fl, len(objs), err)
@@ -6433,6 +10024,10 @@ func (m *MockInstanceGroups) List(ctx context.Context, zone string, fl *filter.F
 
 	var objs []*ga.InstanceGroup
 	for key, obj := range m.Objects {
+		// Objects in other projects are not listed.
+		if key.Project != mockStorageProject(projectID) {
+			continue
+		}
 		if key.Zone != zone {
 			continue
 		}
@@ -6447,7 +10042,26 @@ func (m *MockInstanceGroups) List(ctx context.Context, zone string, fl *filter.F
 }
 
 // Insert is a mock for inserting/creating a new object.
-func (m *MockInstanceGroups) Insert(ctx context.Context, key meta.Key, obj *ga.InstanceGroup) error {
+func (m *MockInstanceGroups) Insert(ctx context.Context, key meta.Key, obj *ga.InstanceGroup) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "InstanceGroups")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Insert",
+			Version:   meta.Version("ga"),
+			Service:   "InstanceGroups",
+		},
+		Key:     &key,
+		Request: obj,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("zonal"); err != nil {
+		return err
+	}
+
 	if m.InsertHook != nil {
 		if intercept, err := m.InsertHook(m, ctx, key, obj); intercept {
 			glog.V(5).Infof("MockInstanceGroups.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
@@ -6458,11 +10072,12 @@ func (m *MockInstanceGroups) Insert(ctx context.Context, key meta.Key, obj *ga.I
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.InsertError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.InsertError[skey]; ok {
 		glog.V(5).Infof("MockInstanceGroups.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
 		return err
 	}
-	if _, ok := m.Objects[key]; ok {
+	if _, ok := m.Objects[skey]; ok {
 		err := &googleapi.Error{
 			Code:    http.StatusConflict,
 			Message: fmt.Sprintf("MockInstanceGroups %v exists", key),
@@ -6473,31 +10088,57 @@ func (m *MockInstanceGroups) Insert(ctx context.Context, key meta.Key, obj *ga.I
 
 	obj.Name = key.Name
 	if obj.SelfLink == "" {
-		obj.SelfLink = SelfLink(meta.VersionGA, "mock-project", "instanceGroups", key)
+		obj.SelfLink = SelfLink(meta.VersionGA, projectID, "instanceGroups", key)
 	}
 
-	m.Objects[key] = &MockInstanceGroupsObj{obj}
+	m.Objects[skey] = &MockInstanceGroupsObj{obj}
 	glog.V(5).Infof("MockInstanceGroups.Insert(%v, %v, %+v) = nil", ctx, key, obj)
 	return nil
 }
 
 // Delete is a mock for deleting the object.
-func (m *MockInstanceGroups) Delete(ctx context.Context, key meta.Key) error {
-	if m.DeleteHook != nil {
-		if intercept, err := m.DeleteHook(m, ctx, key); intercept {
-			glog.V(5).Infof("MockInstanceGroups.Delete(%v, %v) = %v", ctx, key, err)
-			return err
+func (m *MockInstanceGroups) Delete(ctx context.Context, key meta.Key) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "InstanceGroups")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Delete",
+			Version:   meta.Version("ga"),
+			Service:   "InstanceGroups",
+		},
+		Key:     &key,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("zonal"); err != nil {
+		return err
+	}
+
+	if m.DeleteHook != nil {
+		if intercept, err := m.DeleteHook(m, ctx, key); intercept {
+			glog.V(5).Infof("MockInstanceGroups.Delete(%v, %v) = %v", ctx, key, err)
+			return err
+		}
+	}
+
+	if m.deleteCheck != nil {
+		if err := m.deleteCheck(NewResourceID("instanceGroups", *key.WithProject(projectID))); err != nil {
+			glog.V(5).Infof("MockInstanceGroups.Delete(%v, %v) = %v", ctx, key, err)
+			return err
 		}
 	}
 
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.DeleteError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.DeleteError[skey]; ok {
 		glog.V(5).Infof("MockInstanceGroups.Delete(%v, %v) = %v", ctx, key, err)
 		return err
 	}
-	if _, ok := m.Objects[key]; !ok {
+	if _, ok := m.Objects[skey]; !ok {
 		err := &googleapi.Error{
 			Code:    http.StatusNotFound,
 			Message: fmt.Sprintf("MockInstanceGroups %v not found", key),
@@ -6506,7 +10147,7 @@ func (m *MockInstanceGroups) Delete(ctx context.Context, key meta.Key) error {
 		return err
 	}
 
-	delete(m.Objects, key)
+	delete(m.Objects, skey)
 	glog.V(5).Infof("MockInstanceGroups.Delete(%v, %v) = nil", ctx, key)
 	return nil
 }
@@ -6517,7 +10158,26 @@ func (m *MockInstanceGroups) Obj(o *ga.InstanceGroup) *MockInstanceGroupsObj {
 }
 
 // AddInstances is a mock for the corresponding method.
-func (m *MockInstanceGroups) AddInstances(ctx context.Context, key meta.Key, arg0 *ga.InstanceGroupsAddInstancesRequest) error {
+func (m *MockInstanceGroups) AddInstances(ctx context.Context, key meta.Key, arg0 *ga.InstanceGroupsAddInstancesRequest) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "InstanceGroups")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "AddInstances",
+			Version:   meta.Version("ga"),
+			Service:   "InstanceGroups",
+		},
+		Key:     &key,
+		Request: arg0,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("zonal"); err != nil {
+		return err
+	}
+
 	if m.AddInstancesHook != nil {
 		return m.AddInstancesHook(m, ctx, key, arg0)
 	}
@@ -6525,7 +10185,25 @@ func (m *MockInstanceGroups) AddInstances(ctx context.Context, key meta.Key, arg
 }
 
 // ListInstances is a mock for the corresponding method.
-func (m *MockInstanceGroups) ListInstances(ctx context.Context, key meta.Key, arg0 *ga.InstanceGroupsListInstancesRequest) (*ga.InstanceGroupsListInstances, error) {
+func (m *MockInstanceGroups) ListInstances(ctx context.Context, key meta.Key, arg0 *ga.InstanceGroupsListInstancesRequest) (_ *ga.InstanceGroupsListInstances, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "InstanceGroups")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "ListInstances",
+			Version:   meta.Version("ga"),
+			Service:   "InstanceGroups",
+		},
+		Key:     &key,
+		Request: arg0,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("zonal"); err != nil {
+		return nil, err
+	}
+
 	if m.ListInstancesHook != nil {
 		return m.ListInstancesHook(m, ctx, key, arg0)
 	}
@@ -6533,7 +10211,26 @@ func (m *MockInstanceGroups) ListInstances(ctx context.Context, key meta.Key, ar
 }
 
 // RemoveInstances is a mock for the corresponding method.
-func (m *MockInstanceGroups) RemoveInstances(ctx context.Context, key meta.Key, arg0 *ga.InstanceGroupsRemoveInstancesRequest) error {
+func (m *MockInstanceGroups) RemoveInstances(ctx context.Context, key meta.Key, arg0 *ga.InstanceGroupsRemoveInstancesRequest) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "InstanceGroups")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "RemoveInstances",
+			Version:   meta.Version("ga"),
+			Service:   "InstanceGroups",
+		},
+		Key:     &key,
+		Request: arg0,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("zonal"); err != nil {
+		return err
+	}
+
 	if m.RemoveInstancesHook != nil {
 		return m.RemoveInstancesHook(m, ctx, key, arg0)
 	}
@@ -6541,7 +10238,26 @@ func (m *MockInstanceGroups) RemoveInstances(ctx context.Context, key meta.Key,
 }
 
 // SetNamedPorts is a mock for the corresponding method.
-func (m *MockInstanceGroups) SetNamedPorts(ctx context.Context, key meta.Key, arg0 *ga.InstanceGroupsSetNamedPortsRequest) error {
+func (m *MockInstanceGroups) SetNamedPorts(ctx context.Context, key meta.Key, arg0 *ga.InstanceGroupsSetNamedPortsRequest) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "InstanceGroups")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "SetNamedPorts",
+			Version:   meta.Version("ga"),
+			Service:   "InstanceGroups",
+		},
+		Key:     &key,
+		Request: arg0,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("zonal"); err != nil {
+		return err
+	}
+
 	if m.SetNamedPortsHook != nil {
 		return m.SetNamedPortsHook(m, ctx, key, arg0)
 	}
@@ -6554,15 +10270,23 @@ type GCEInstanceGroups struct {
 }
 
 // Get the InstanceGroup named by key.
-func (g *GCEInstanceGroups) Get(ctx context.Context, key meta.Key) (*ga.InstanceGroup, error) {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "InstanceGroups")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Get",
-		Version:   meta.Version("ga"),
-		Service:   "InstanceGroups",
+func (g *GCEInstanceGroups) Get(ctx context.Context, key meta.Key) (_ *ga.InstanceGroup, err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "InstanceGroups")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("ga"),
+			Service:   "InstanceGroups",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("zonal"); err != nil {
+		return nil, err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.GA.InstanceGroups.Get(projectID, key.Zone, key.Name)
@@ -6571,24 +10295,38 @@ func (g *GCEInstanceGroups) Get(ctx context.Context, key meta.Key) (*ga.Instance
 }
 
 // List all InstanceGroup objects.
-func (g *GCEInstanceGroups) List(ctx context.Context, zone string, fl *filter.F) ([]*ga.InstanceGroup, error) {
+func (g *GCEInstanceGroups) List(ctx context.Context, zone string, fl *filter.F) (_ []*ga.InstanceGroup, err error) {
 	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "InstanceGroups")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "List",
-		Version:   meta.Version("ga"),
-		Service:   "InstanceGroups",
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("ga"),
+			Service:   "InstanceGroups",
+		},
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if g.s.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(ga.InstanceGroup{})); err != nil {
+			return nil, err
+		}
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.GA.InstanceGroups.List(projectID, zone)
-	if fl != filter.None {
-		call.Filter(fl.String())
+	serverFl, clientFl := fl.Split(reflect.TypeOf(ga.InstanceGroup{}))
+	if serverFl != filter.None {
+		call.Filter(serverFl.String())
 	}
 	var all []*ga.InstanceGroup
 	f := func(l *ga.InstanceGroupList) error {
-		all = append(all, l.Items...)
+		for _, obj := range l.Items {
+			if clientFl.Match(obj) {
+				all = append(all, obj)
+			}
+		}
 		return nil
 	}
 	if err := call.Pages(ctx, f); err != nil {
@@ -6598,18 +10336,32 @@ func (g *GCEInstanceGroups) List(ctx context.Context, zone string, fl *filter.F)
 }
 
 // Insert InstanceGroup with key of value obj.
-func (g *GCEInstanceGroups) Insert(ctx context.Context, key meta.Key, obj *ga.InstanceGroup) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "InstanceGroups")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Insert",
-		Version:   meta.Version("ga"),
-		Service:   "InstanceGroups",
+func (g *GCEInstanceGroups) Insert(ctx context.Context, key meta.Key, obj *ga.InstanceGroup) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "InstanceGroups")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Insert",
+			Version:   meta.Version("ga"),
+			Service:   "InstanceGroups",
+		},
+		Key:     &key,
+		Request: obj,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("zonal"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
 	obj.Name = key.Name
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.GA.InstanceGroups.Insert(projectID, key.Zone, obj)
 	call.Context(ctx)
 
@@ -6621,17 +10373,30 @@ func (g *GCEInstanceGroups) Insert(ctx context.Context, key meta.Key, obj *ga.In
 }
 
 // Delete the InstanceGroup referenced by key.
-func (g *GCEInstanceGroups) Delete(ctx context.Context, key meta.Key) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "InstanceGroups")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Delete",
-		Version:   meta.Version("ga"),
-		Service:   "InstanceGroups",
+func (g *GCEInstanceGroups) Delete(ctx context.Context, key meta.Key) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "InstanceGroups")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Delete",
+			Version:   meta.Version("ga"),
+			Service:   "InstanceGroups",
+		},
+		Key:     &key,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("zonal"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.GA.InstanceGroups.Delete(projectID, key.Zone, key.Name)
 	call.Context(ctx)
 
@@ -6643,17 +10408,31 @@ func (g *GCEInstanceGroups) Delete(ctx context.Context, key meta.Key) error {
 }
 
 // AddInstances is a method on GCEInstanceGroups.
-func (g *GCEInstanceGroups) AddInstances(ctx context.Context, key meta.Key, arg0 *ga.InstanceGroupsAddInstancesRequest) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "InstanceGroups")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "AddInstances",
-		Version:   meta.Version("ga"),
-		Service:   "InstanceGroups",
+func (g *GCEInstanceGroups) AddInstances(ctx context.Context, key meta.Key, arg0 *ga.InstanceGroupsAddInstancesRequest) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "InstanceGroups")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "AddInstances",
+			Version:   meta.Version("ga"),
+			Service:   "InstanceGroups",
+		},
+		Key:     &key,
+		Request: arg0,
+		Mutates: true,
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("zonal"); err != nil {
 		return err
 	}
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
+		return err
+	}
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.GA.InstanceGroups.AddInstances(projectID, key.Zone, key.Name, arg0)
 	call.Context(ctx)
 	op, err := call.Do()
@@ -6664,15 +10443,24 @@ func (g *GCEInstanceGroups) AddInstances(ctx context.Context, key meta.Key, arg0
 }
 
 // ListInstances is a method on GCEInstanceGroups.
-func (g *GCEInstanceGroups) ListInstances(ctx context.Context, key meta.Key, arg0 *ga.InstanceGroupsListInstancesRequest) (*ga.InstanceGroupsListInstances, error) {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "InstanceGroups")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "ListInstances",
-		Version:   meta.Version("ga"),
-		Service:   "InstanceGroups",
+func (g *GCEInstanceGroups) ListInstances(ctx context.Context, key meta.Key, arg0 *ga.InstanceGroupsListInstancesRequest) (_ *ga.InstanceGroupsListInstances, err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "InstanceGroups")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "ListInstances",
+			Version:   meta.Version("ga"),
+			Service:   "InstanceGroups",
+		},
+		Key:     &key,
+		Request: arg0,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("zonal"); err != nil {
+		return nil, err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.GA.InstanceGroups.ListInstances(projectID, key.Zone, key.Name, arg0)
@@ -6681,17 +10469,31 @@ func (g *GCEInstanceGroups) ListInstances(ctx context.Context, key meta.Key, arg
 }
 
 // RemoveInstances is a method on GCEInstanceGroups.
-func (g *GCEInstanceGroups) RemoveInstances(ctx context.Context, key meta.Key, arg0 *ga.InstanceGroupsRemoveInstancesRequest) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "InstanceGroups")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "RemoveInstances",
-		Version:   meta.Version("ga"),
-		Service:   "InstanceGroups",
+func (g *GCEInstanceGroups) RemoveInstances(ctx context.Context, key meta.Key, arg0 *ga.InstanceGroupsRemoveInstancesRequest) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "InstanceGroups")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "RemoveInstances",
+			Version:   meta.Version("ga"),
+			Service:   "InstanceGroups",
+		},
+		Key:     &key,
+		Request: arg0,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("zonal"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.GA.InstanceGroups.RemoveInstances(projectID, key.Zone, key.Name, arg0)
 	call.Context(ctx)
 	op, err := call.Do()
@@ -6702,17 +10504,31 @@ func (g *GCEInstanceGroups) RemoveInstances(ctx context.Context, key meta.Key, a
 }
 
 // SetNamedPorts is a method on GCEInstanceGroups.
-func (g *GCEInstanceGroups) SetNamedPorts(ctx context.Context, key meta.Key, arg0 *ga.InstanceGroupsSetNamedPortsRequest) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "InstanceGroups")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "SetNamedPorts",
-		Version:   meta.Version("ga"),
-		Service:   "InstanceGroups",
+func (g *GCEInstanceGroups) SetNamedPorts(ctx context.Context, key meta.Key, arg0 *ga.InstanceGroupsSetNamedPortsRequest) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "InstanceGroups")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "SetNamedPorts",
+			Version:   meta.Version("ga"),
+			Service:   "InstanceGroups",
+		},
+		Key:     &key,
+		Request: arg0,
+		Mutates: true,
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("zonal"); err != nil {
 		return err
 	}
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
+		return err
+	}
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.GA.InstanceGroups.SetNamedPorts(projectID, key.Zone, key.Name, arg0)
 	call.Context(ctx)
 	op, err := call.Do()
@@ -6768,13 +10584,46 @@ type MockInstances struct {
 	AttachDiskHook func(*MockInstances, context.Context, meta.Key, *ga.AttachedDisk) error
 	DetachDiskHook func(*MockInstances, context.Context, meta.Key, string) error
 
+	// Observer, if set, is invoked around each call to the mock.
+	Observer CallObserver
+
+	// ProjectRouter, if set, routes the calls with keys that do not have a
+	// project. Calls are made to "mock-project" if it is not set.
+	ProjectRouter ProjectRouter
+
+	// Debug enables additional checks of the arguments to the mock, such as
+	// the validation of List() filters with filter.Validate().
+	Debug bool
+
+	// deleteCheck, if set, is called by Delete() with the resource being
+	// deleted. Delete() returns the error if it is not nil. See
+	// WithReferenceChecking().
+	deleteCheck func(id *ResourceID) error
+
 	// X is extra state that can be used as part of the mock. Generated code
 	// will not use this field.
 	X interface{}
 }
 
 // Get returns the object from the mock.
-func (m *MockInstances) Get(ctx context.Context, key meta.Key) (*ga.Instance, error) {
+func (m *MockInstances) Get(ctx context.Context, key meta.Key) (_ *ga.Instance, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "Instances")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("ga"),
+			Service:   "Instances",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("zonal"); err != nil {
+		return nil, err
+	}
+
 	if m.GetHook != nil {
 		if intercept, obj, err := m.GetHook(m, ctx, key); intercept {
 			glog.V(5).Infof("MockInstances.Get(%v, %s) = %+v, %v", ctx, key, obj, err)
@@ -6785,17 +10634,18 @@ func (m *MockInstances) Get(ctx context.Context, key meta.Key) (*ga.Instance, er
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.GetError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.GetError[skey]; ok {
 		glog.V(5).Infof("MockInstances.Get(%v, %s) = nil, %v", ctx, key, err)
 		return nil, err
 	}
-	if obj, ok := m.Objects[key]; ok {
+	if obj, ok := m.Objects[skey]; ok {
 		typedObj := obj.ToGA()
 		glog.V(5).Infof("MockInstances.Get(%v, %s) = %+v, nil", ctx, key, typedObj)
 		return typedObj, nil
 	}
 
-	err := &googleapi.Error{
+	err = &googleapi.Error{
 		Code:    http.StatusNotFound,
 		Message: fmt.Sprintf("MockInstances %v not found", key),
 	}
@@ -6804,7 +10654,26 @@ func (m *MockInstances) Get(ctx context.Context, key meta.Key) (*ga.Instance, er
 }
 
 // List all of the objects in the mock in the given zone.
-func (m *MockInstances) List(ctx context.Context, zone string, fl *filter.F) ([]*ga.Instance, error) {
+func (m *MockInstances) List(ctx context.Context, zone string, fl *filter.F) (_ []*ga.Instance, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, nil, "ga", "Instances")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("ga"),
+			Service:   "Instances",
+		},
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if m.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(ga.Instance{})); err != nil {
+			glog.V(5).Infof("MockInstances.List(%v, %v) = nil, %v", ctx, fl, err)
+			return nil, err
+		}
+	}
+
 	if m.ListHook != nil {
 		if intercept, objs, err := m.ListHook(m, ctx, zone, fl); intercept {
 			glog.V(5).Infof("MockInstances.List(%v, %q, %v) = [%v items], %v", ctx, zone, fl, len(objs), err)
@@ -6824,6 +10693,10 @@ func (m *MockInstances) List(ctx context.Context, zone string, fl *filter.F) ([]
 
 	var objs []*ga.Instance
 	for key, obj := range m.Objects {
+		// Objects in other projects are not listed.
+		if key.Project != mockStorageProject(projectID) {
+			continue
+		}
 		if key.Zone != zone {
 			continue
 		}
@@ -6838,7 +10711,26 @@ func (m *MockInstances) List(ctx context.Context, zone string, fl *filter.F) ([]
 }
 
 // Insert is a mock for inserting/creating a new object.
-func (m *MockInstances) Insert(ctx context.Context, key meta.Key, obj *ga.Instance) error {
+func (m *MockInstances) Insert(ctx context.Context, key meta.Key, obj *ga.Instance) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "Instances")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Insert",
+			Version:   meta.Version("ga"),
+			Service:   "Instances",
+		},
+		Key:     &key,
+		Request: obj,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("zonal"); err != nil {
+		return err
+	}
+
 	if m.InsertHook != nil {
 		if intercept, err := m.InsertHook(m, ctx, key, obj); intercept {
 			glog.V(5).Infof("MockInstances.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
@@ -6849,11 +10741,12 @@ func (m *MockInstances) Insert(ctx context.Context, key meta.Key, obj *ga.Instan
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.InsertError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.InsertError[skey]; ok {
 		glog.V(5).Infof("MockInstances.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
 		return err
 	}
-	if _, ok := m.Objects[key]; ok {
+	if _, ok := m.Objects[skey]; ok {
 		err := &googleapi.Error{
 			Code:    http.StatusConflict,
 			Message: fmt.Sprintf("MockInstances %v exists", key),
@@ -6864,16 +10757,34 @@ func (m *MockInstances) Insert(ctx context.Context, key meta.Key, obj *ga.Instan
 
 	obj.Name = key.Name
 	if obj.SelfLink == "" {
-		obj.SelfLink = SelfLink(meta.VersionGA, "mock-project", "instances", key)
+		obj.SelfLink = SelfLink(meta.VersionGA, projectID, "instances", key)
 	}
 
-	m.Objects[key] = &MockInstancesObj{obj}
+	m.Objects[skey] = &MockInstancesObj{obj}
 	glog.V(5).Infof("MockInstances.Insert(%v, %v, %+v) = nil", ctx, key, obj)
 	return nil
 }
 
 // Delete is a mock for deleting the object.
-func (m *MockInstances) Delete(ctx context.Context, key meta.Key) error {
+func (m *MockInstances) Delete(ctx context.Context, key meta.Key) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "Instances")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Delete",
+			Version:   meta.Version("ga"),
+			Service:   "Instances",
+		},
+		Key:     &key,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("zonal"); err != nil {
+		return err
+	}
+
 	if m.DeleteHook != nil {
 		if intercept, err := m.DeleteHook(m, ctx, key); intercept {
 			glog.V(5).Infof("MockInstances.Delete(%v, %v) = %v", ctx, key, err)
@@ -6881,14 +10792,22 @@ func (m *MockInstances) Delete(ctx context.Context, key meta.Key) error {
 		}
 	}
 
+	if m.deleteCheck != nil {
+		if err := m.deleteCheck(NewResourceID("instances", *key.WithProject(projectID))); err != nil {
+			glog.V(5).Infof("MockInstances.Delete(%v, %v) = %v", ctx, key, err)
+			return err
+		}
+	}
+
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.DeleteError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.DeleteError[skey]; ok {
 		glog.V(5).Infof("MockInstances.Delete(%v, %v) = %v", ctx, key, err)
 		return err
 	}
-	if _, ok := m.Objects[key]; !ok {
+	if _, ok := m.Objects[skey]; !ok {
 		err := &googleapi.Error{
 			Code:    http.StatusNotFound,
 			Message: fmt.Sprintf("MockInstances %v not found", key),
@@ -6897,7 +10816,7 @@ func (m *MockInstances) Delete(ctx context.Context, key meta.Key) error {
 		return err
 	}
 
-	delete(m.Objects, key)
+	delete(m.Objects, skey)
 	glog.V(5).Infof("MockInstances.Delete(%v, %v) = nil", ctx, key)
 	return nil
 }
@@ -6908,7 +10827,26 @@ func (m *MockInstances) Obj(o *ga.Instance) *MockInstancesObj {
 }
 
 // AttachDisk is a mock for the corresponding method.
-func (m *MockInstances) AttachDisk(ctx context.Context, key meta.Key, arg0 *ga.AttachedDisk) error {
+func (m *MockInstances) AttachDisk(ctx context.Context, key meta.Key, arg0 *ga.AttachedDisk) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "Instances")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "AttachDisk",
+			Version:   meta.Version("ga"),
+			Service:   "Instances",
+		},
+		Key:     &key,
+		Request: arg0,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("zonal"); err != nil {
+		return err
+	}
+
 	if m.AttachDiskHook != nil {
 		return m.AttachDiskHook(m, ctx, key, arg0)
 	}
@@ -6916,7 +10854,26 @@ func (m *MockInstances) AttachDisk(ctx context.Context, key meta.Key, arg0 *ga.A
 }
 
 // DetachDisk is a mock for the corresponding method.
-func (m *MockInstances) DetachDisk(ctx context.Context, key meta.Key, arg0 string) error {
+func (m *MockInstances) DetachDisk(ctx context.Context, key meta.Key, arg0 string) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "Instances")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "DetachDisk",
+			Version:   meta.Version("ga"),
+			Service:   "Instances",
+		},
+		Key:     &key,
+		Request: arg0,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("zonal"); err != nil {
+		return err
+	}
+
 	if m.DetachDiskHook != nil {
 		return m.DetachDiskHook(m, ctx, key, arg0)
 	}
@@ -6929,15 +10886,23 @@ type GCEInstances struct {
 }
 
 // Get the Instance named by key.
-func (g *GCEInstances) Get(ctx context.Context, key meta.Key) (*ga.Instance, error) {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "Instances")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Get",
-		Version:   meta.Version("ga"),
-		Service:   "Instances",
+func (g *GCEInstances) Get(ctx context.Context, key meta.Key) (_ *ga.Instance, err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "Instances")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("ga"),
+			Service:   "Instances",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("zonal"); err != nil {
+		return nil, err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.GA.Instances.Get(projectID, key.Zone, key.Name)
@@ -6946,24 +10911,38 @@ func (g *GCEInstances) Get(ctx context.Context, key meta.Key) (*ga.Instance, err
 }
 
 // List all Instance objects.
-func (g *GCEInstances) List(ctx context.Context, zone string, fl *filter.F) ([]*ga.Instance, error) {
+func (g *GCEInstances) List(ctx context.Context, zone string, fl *filter.F) (_ []*ga.Instance, err error) {
 	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "Instances")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "List",
-		Version:   meta.Version("ga"),
-		Service:   "Instances",
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("ga"),
+			Service:   "Instances",
+		},
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if g.s.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(ga.Instance{})); err != nil {
+			return nil, err
+		}
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.GA.Instances.List(projectID, zone)
-	if fl != filter.None {
-		call.Filter(fl.String())
+	serverFl, clientFl := fl.Split(reflect.TypeOf(ga.Instance{}))
+	if serverFl != filter.None {
+		call.Filter(serverFl.String())
 	}
 	var all []*ga.Instance
 	f := func(l *ga.InstanceList) error {
-		all = append(all, l.Items...)
+		for _, obj := range l.Items {
+			if clientFl.Match(obj) {
+				all = append(all, obj)
+			}
+		}
 		return nil
 	}
 	if err := call.Pages(ctx, f); err != nil {
@@ -6973,18 +10952,32 @@ func (g *GCEInstances) List(ctx context.Context, zone string, fl *filter.F) ([]*
 }
 
 // Insert Instance with key of value obj.
-func (g *GCEInstances) Insert(ctx context.Context, key meta.Key, obj *ga.Instance) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "Instances")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Insert",
-		Version:   meta.Version("ga"),
-		Service:   "Instances",
+func (g *GCEInstances) Insert(ctx context.Context, key meta.Key, obj *ga.Instance) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "Instances")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Insert",
+			Version:   meta.Version("ga"),
+			Service:   "Instances",
+		},
+		Key:     &key,
+		Request: obj,
+		Mutates: true,
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("zonal"); err != nil {
+		return err
+	}
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
 	obj.Name = key.Name
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.GA.Instances.Insert(projectID, key.Zone, obj)
 	call.Context(ctx)
 
@@ -6996,17 +10989,30 @@ func (g *GCEInstances) Insert(ctx context.Context, key meta.Key, obj *ga.Instanc
 }
 
 // Delete the Instance referenced by key.
-func (g *GCEInstances) Delete(ctx context.Context, key meta.Key) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "Instances")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Delete",
-		Version:   meta.Version("ga"),
-		Service:   "Instances",
+func (g *GCEInstances) Delete(ctx context.Context, key meta.Key) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "Instances")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Delete",
+			Version:   meta.Version("ga"),
+			Service:   "Instances",
+		},
+		Key:     &key,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("zonal"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.GA.Instances.Delete(projectID, key.Zone, key.Name)
 	call.Context(ctx)
 
@@ -7018,17 +11024,31 @@ func (g *GCEInstances) Delete(ctx context.Context, key meta.Key) error {
 }
 
 // AttachDisk is a method on GCEInstances.
-func (g *GCEInstances) AttachDisk(ctx context.Context, key meta.Key, arg0 *ga.AttachedDisk) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "Instances")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "AttachDisk",
-		Version:   meta.Version("ga"),
-		Service:   "Instances",
+func (g *GCEInstances) AttachDisk(ctx context.Context, key meta.Key, arg0 *ga.AttachedDisk) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "Instances")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "AttachDisk",
+			Version:   meta.Version("ga"),
+			Service:   "Instances",
+		},
+		Key:     &key,
+		Request: arg0,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("zonal"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.GA.Instances.AttachDisk(projectID, key.Zone, key.Name, arg0)
 	call.Context(ctx)
 	op, err := call.Do()
@@ -7039,17 +11059,31 @@ func (g *GCEInstances) AttachDisk(ctx context.Context, key meta.Key, arg0 *ga.At
 }
 
 // DetachDisk is a method on GCEInstances.
-func (g *GCEInstances) DetachDisk(ctx context.Context, key meta.Key, arg0 string) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "Instances")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "DetachDisk",
-		Version:   meta.Version("ga"),
-		Service:   "Instances",
+func (g *GCEInstances) DetachDisk(ctx context.Context, key meta.Key, arg0 string) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "Instances")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "DetachDisk",
+			Version:   meta.Version("ga"),
+			Service:   "Instances",
+		},
+		Key:     &key,
+		Request: arg0,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("zonal"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.GA.Instances.DetachDisk(projectID, key.Zone, key.Name, arg0)
 	call.Context(ctx)
 	op, err := call.Do()
@@ -7105,13 +11139,46 @@ type MockBetaInstances struct {
 	AttachDiskHook func(*MockBetaInstances, context.Context, meta.Key, *beta.AttachedDisk) error
 	DetachDiskHook func(*MockBetaInstances, context.Context, meta.Key, string) error
 
+	// Observer, if set, is invoked around each call to the mock.
+	Observer CallObserver
+
+	// ProjectRouter, if set, routes the calls with keys that do not have a
+	// project. Calls are made to "mock-project" if it is not set.
+	ProjectRouter ProjectRouter
+
+	// Debug enables additional checks of the arguments to the mock, such as
+	// the validation of List() filters with filter.Validate().
+	Debug bool
+
+	// deleteCheck, if set, is called by Delete() with the resource being
+	// deleted. Delete() returns the error if it is not nil. See
+	// WithReferenceChecking().
+	deleteCheck func(id *ResourceID) error
+
 	// X is extra state that can be used as part of the mock. Generated code
 	// will not use this field.
 	X interface{}
 }
 
 // Get returns the object from the mock.
-func (m *MockBetaInstances) Get(ctx context.Context, key meta.Key) (*beta.Instance, error) {
+func (m *MockBetaInstances) Get(ctx context.Context, key meta.Key) (_ *beta.Instance, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "beta", "Instances")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("beta"),
+			Service:   "Instances",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("zonal"); err != nil {
+		return nil, err
+	}
+
 	if m.GetHook != nil {
 		if intercept, obj, err := m.GetHook(m, ctx, key); intercept {
 			glog.V(5).Infof("MockBetaInstances.Get(%v, %s) = %+v, %v", ctx, key, obj, err)
@@ -7122,17 +11189,18 @@ func (m *MockBetaInstances) Get(ctx context.Context, key meta.Key) (*beta.Instan
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.GetError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.GetError[skey]; ok {
 		glog.V(5).Infof("MockBetaInstances.Get(%v, %s) = nil, %v", ctx, key, err)
 		return nil, err
 	}
-	if obj, ok := m.Objects[key]; ok {
+	if obj, ok := m.Objects[skey]; ok {
 		typedObj := obj.ToBeta()
 		glog.V(5).Infof("MockBetaInstances.Get(%v, %s) = %+v, nil", ctx, key, typedObj)
 		return typedObj, nil
 	}
 
-	err := &googleapi.Error{
+	err = &googleapi.Error{
 		Code:    http.StatusNotFound,
 		Message: fmt.Sprintf("MockBetaInstances %v not found", key),
 	}
@@ -7141,7 +11209,26 @@ func (m *MockBetaInstances) Get(ctx context.Context, key meta.Key) (*beta.Instan
 }
 
 // List all of the objects in the mock in the given zone.
-func (m *MockBetaInstances) List(ctx context.Context, zone string, fl *filter.F) ([]*beta.Instance, error) {
+func (m *MockBetaInstances) List(ctx context.Context, zone string, fl *filter.F) (_ []*beta.Instance, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, nil, "beta", "Instances")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("beta"),
+			Service:   "Instances",
+		},
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if m.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(beta.Instance{})); err != nil {
+			glog.V(5).Infof("MockBetaInstances.List(%v, %v) = nil, %v", ctx, fl, err)
+			return nil, err
+		}
+	}
+
 	if m.ListHook != nil {
 		if intercept, objs, err := m.ListHook(m, ctx, zone, fl); intercept {
 			glog.V(5).Infof("MockBetaInstances.List(%v, %q, %v) = [%v items], %v", ctx, zone, fl, len(objs), err)
@@ -7161,6 +11248,10 @@ func (m *MockBetaInstances) List(ctx context.Context, zone string, fl *filter.F)
 
 	var objs []*beta.Instance
 	for key, obj := range m.Objects {
+		// Objects in other projects are not listed.
+		if key.Project != mockStorageProject(projectID) {
+			continue
+		}
 		if key.Zone != zone {
 			continue
 		}
@@ -7175,7 +11266,26 @@ func (m *MockBetaInstances) List(ctx context.Context, zone string, fl *filter.F)
 }
 
 // Insert is a mock for inserting/creating a new object.
-func (m *MockBetaInstances) Insert(ctx context.Context, key meta.Key, obj *beta.Instance) error {
+func (m *MockBetaInstances) Insert(ctx context.Context, key meta.Key, obj *beta.Instance) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "beta", "Instances")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Insert",
+			Version:   meta.Version("beta"),
+			Service:   "Instances",
+		},
+		Key:     &key,
+		Request: obj,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("zonal"); err != nil {
+		return err
+	}
+
 	if m.InsertHook != nil {
 		if intercept, err := m.InsertHook(m, ctx, key, obj); intercept {
 			glog.V(5).Infof("MockBetaInstances.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
@@ -7186,11 +11296,12 @@ func (m *MockBetaInstances) Insert(ctx context.Context, key meta.Key, obj *beta.
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.InsertError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.InsertError[skey]; ok {
 		glog.V(5).Infof("MockBetaInstances.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
 		return err
 	}
-	if _, ok := m.Objects[key]; ok {
+	if _, ok := m.Objects[skey]; ok {
 		err := &googleapi.Error{
 			Code:    http.StatusConflict,
 			Message: fmt.Sprintf("MockBetaInstances %v exists", key),
@@ -7201,16 +11312,34 @@ func (m *MockBetaInstances) Insert(ctx context.Context, key meta.Key, obj *beta.
 
 	obj.Name = key.Name
 	if obj.SelfLink == "" {
-		obj.SelfLink = SelfLink(meta.VersionBeta, "mock-project", "instances", key)
+		obj.SelfLink = SelfLink(meta.VersionBeta, projectID, "instances", key)
 	}
 
-	m.Objects[key] = &MockInstancesObj{obj}
+	m.Objects[skey] = &MockInstancesObj{obj}
 	glog.V(5).Infof("MockBetaInstances.Insert(%v, %v, %+v) = nil", ctx, key, obj)
 	return nil
 }
 
 // Delete is a mock for deleting the object.
-func (m *MockBetaInstances) Delete(ctx context.Context, key meta.Key) error {
+func (m *MockBetaInstances) Delete(ctx context.Context, key meta.Key) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "beta", "Instances")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Delete",
+			Version:   meta.Version("beta"),
+			Service:   "Instances",
+		},
+		Key:     &key,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("zonal"); err != nil {
+		return err
+	}
+
 	if m.DeleteHook != nil {
 		if intercept, err := m.DeleteHook(m, ctx, key); intercept {
 			glog.V(5).Infof("MockBetaInstances.Delete(%v, %v) = %v", ctx, key, err)
@@ -7218,14 +11347,22 @@ func (m *MockBetaInstances) Delete(ctx context.Context, key meta.Key) error {
 		}
 	}
 
+	if m.deleteCheck != nil {
+		if err := m.deleteCheck(NewResourceID("instances", *key.WithProject(projectID))); err != nil {
+			glog.V(5).Infof("MockBetaInstances.Delete(%v, %v) = %v", ctx, key, err)
+			return err
+		}
+	}
+
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.DeleteError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.DeleteError[skey]; ok {
 		glog.V(5).Infof("MockBetaInstances.Delete(%v, %v) = %v", ctx, key, err)
 		return err
 	}
-	if _, ok := m.Objects[key]; !ok {
+	if _, ok := m.Objects[skey]; !ok {
 		err := &googleapi.Error{
 			Code:    http.StatusNotFound,
 			Message: fmt.Sprintf("MockBetaInstances %v not found", key),
@@ -7234,7 +11371,7 @@ func (m *MockBetaInstances) Delete(ctx context.Context, key meta.Key) error {
 		return err
 	}
 
-	delete(m.Objects, key)
+	delete(m.Objects, skey)
 	glog.V(5).Infof("MockBetaInstances.Delete(%v, %v) = nil", ctx, key)
 	return nil
 }
@@ -7245,7 +11382,26 @@ func (m *MockBetaInstances) Obj(o *beta.Instance) *MockInstancesObj {
 }
 
 // AttachDisk is a mock for the corresponding method.
-func (m *MockBetaInstances) AttachDisk(ctx context.Context, key meta.Key, arg0 *beta.AttachedDisk) error {
+func (m *MockBetaInstances) AttachDisk(ctx context.Context, key meta.Key, arg0 *beta.AttachedDisk) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "beta", "Instances")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "AttachDisk",
+			Version:   meta.Version("beta"),
+			Service:   "Instances",
+		},
+		Key:     &key,
+		Request: arg0,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("zonal"); err != nil {
+		return err
+	}
+
 	if m.AttachDiskHook != nil {
 		return m.AttachDiskHook(m, ctx, key, arg0)
 	}
@@ -7253,7 +11409,26 @@ func (m *MockBetaInstances) AttachDisk(ctx context.Context, key meta.Key, arg0 *
 }
 
 // DetachDisk is a mock for the corresponding method.
-func (m *MockBetaInstances) DetachDisk(ctx context.Context, key meta.Key, arg0 string) error {
+func (m *MockBetaInstances) DetachDisk(ctx context.Context, key meta.Key, arg0 string) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "beta", "Instances")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "DetachDisk",
+			Version:   meta.Version("beta"),
+			Service:   "Instances",
+		},
+		Key:     &key,
+		Request: arg0,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("zonal"); err != nil {
+		return err
+	}
+
 	if m.DetachDiskHook != nil {
 		return m.DetachDiskHook(m, ctx, key, arg0)
 	}
@@ -7266,15 +11441,23 @@ type GCEBetaInstances struct {
 }
 
 // Get the Instance named by key.
-func (g *GCEBetaInstances) Get(ctx context.Context, key meta.Key) (*beta.Instance, error) {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "beta", "Instances")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Get",
-		Version:   meta.Version("beta"),
-		Service:   "Instances",
+func (g *GCEBetaInstances) Get(ctx context.Context, key meta.Key) (_ *beta.Instance, err error) {
+	projectID := g.s.keyProjectID(ctx, key, "beta", "Instances")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("beta"),
+			Service:   "Instances",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("zonal"); err != nil {
+		return nil, err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.Beta.Instances.Get(projectID, key.Zone, key.Name)
@@ -7283,24 +11466,38 @@ func (g *GCEBetaInstances) Get(ctx context.Context, key meta.Key) (*beta.Instanc
 }
 
 // List all Instance objects.
-func (g *GCEBetaInstances) List(ctx context.Context, zone string, fl *filter.F) ([]*beta.Instance, error) {
+func (g *GCEBetaInstances) List(ctx context.Context, zone string, fl *filter.F) (_ []*beta.Instance, err error) {
 	projectID := g.s.ProjectRouter.ProjectID(ctx, "beta", "Instances")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "List",
-		Version:   meta.Version("beta"),
-		Service:   "Instances",
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("beta"),
+			Service:   "Instances",
+		},
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if g.s.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(beta.Instance{})); err != nil {
+			return nil, err
+		}
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.Beta.Instances.List(projectID, zone)
-	if fl != filter.None {
-		call.Filter(fl.String())
+	serverFl, clientFl := fl.Split(reflect.TypeOf(beta.Instance{}))
+	if serverFl != filter.None {
+		call.Filter(serverFl.String())
 	}
 	var all []*beta.Instance
 	f := func(l *beta.InstanceList) error {
-		all = append(all, l.Items...)
+		for _, obj := range l.Items {
+			if clientFl.Match(obj) {
+				all = append(all, obj)
+			}
+		}
 		return nil
 	}
 	if err := call.Pages(ctx, f); err != nil {
@@ -7310,18 +11507,32 @@ func (g *GCEBetaInstances) List(ctx context.Context, zone string, fl *filter.F)
 }
 
 // Insert Instance with key of value obj.
-func (g *GCEBetaInstances) Insert(ctx context.Context, key meta.Key, obj *beta.Instance) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "beta", "Instances")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Insert",
-		Version:   meta.Version("beta"),
-		Service:   "Instances",
+func (g *GCEBetaInstances) Insert(ctx context.Context, key meta.Key, obj *beta.Instance) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "beta", "Instances")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Insert",
+			Version:   meta.Version("beta"),
+			Service:   "Instances",
+		},
+		Key:     &key,
+		Request: obj,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("zonal"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
 	obj.Name = key.Name
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.Beta.Instances.Insert(projectID, key.Zone, obj)
 	call.Context(ctx)
 
@@ -7333,17 +11544,30 @@ func (g *GCEBetaInstances) Insert(ctx context.Context, key meta.Key, obj *beta.I
 }
 
 // Delete the Instance referenced by key.
-func (g *GCEBetaInstances) Delete(ctx context.Context, key meta.Key) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "beta", "Instances")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Delete",
-		Version:   meta.Version("beta"),
-		Service:   "Instances",
+func (g *GCEBetaInstances) Delete(ctx context.Context, key meta.Key) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "beta", "Instances")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Delete",
+			Version:   meta.Version("beta"),
+			Service:   "Instances",
+		},
+		Key:     &key,
+		Mutates: true,
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("zonal"); err != nil {
 		return err
 	}
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
+		return err
+	}
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.Beta.Instances.Delete(projectID, key.Zone, key.Name)
 	call.Context(ctx)
 
@@ -7355,17 +11579,31 @@ func (g *GCEBetaInstances) Delete(ctx context.Context, key meta.Key) error {
 }
 
 // AttachDisk is a method on GCEBetaInstances.
-func (g *GCEBetaInstances) AttachDisk(ctx context.Context, key meta.Key, arg0 *beta.AttachedDisk) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "beta", "Instances")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "AttachDisk",
-		Version:   meta.Version("beta"),
-		Service:   "Instances",
+func (g *GCEBetaInstances) AttachDisk(ctx context.Context, key meta.Key, arg0 *beta.AttachedDisk) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "beta", "Instances")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "AttachDisk",
+			Version:   meta.Version("beta"),
+			Service:   "Instances",
+		},
+		Key:     &key,
+		Request: arg0,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("zonal"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.Beta.Instances.AttachDisk(projectID, key.Zone, key.Name, arg0)
 	call.Context(ctx)
 	op, err := call.Do()
@@ -7376,17 +11614,31 @@ func (g *GCEBetaInstances) AttachDisk(ctx context.Context, key meta.Key, arg0 *b
 }
 
 // DetachDisk is a method on GCEBetaInstances.
-func (g *GCEBetaInstances) DetachDisk(ctx context.Context, key meta.Key, arg0 string) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "beta", "Instances")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "DetachDisk",
-		Version:   meta.Version("beta"),
-		Service:   "Instances",
+func (g *GCEBetaInstances) DetachDisk(ctx context.Context, key meta.Key, arg0 string) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "beta", "Instances")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "DetachDisk",
+			Version:   meta.Version("beta"),
+			Service:   "Instances",
+		},
+		Key:     &key,
+		Request: arg0,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("zonal"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.Beta.Instances.DetachDisk(projectID, key.Zone, key.Name, arg0)
 	call.Context(ctx)
 	op, err := call.Do()
@@ -7444,13 +11696,46 @@ type MockAlphaInstances struct {
 	DetachDiskHook             func(*MockAlphaInstances, context.Context, meta.Key, string) error
 	UpdateNetworkInterfaceHook func(*MockAlphaInstances, context.Context, meta.Key, string, *alpha.NetworkInterface) error
 
+	// Observer, if set, is invoked around each call to the mock.
+	Observer CallObserver
+
+	// ProjectRouter, if set, routes the calls with keys that do not have a
+	// project. Calls are made to "mock-project" if it is not set.
+	ProjectRouter ProjectRouter
+
+	// Debug enables additional checks of the arguments to the mock, such as
+	// the validation of List() filters with filter.Validate().
+	Debug bool
+
+	// deleteCheck, if set, is called by Delete() with the resource being
+	// deleted. Delete() returns the error if it is not nil. See
+	// WithReferenceChecking().
+	deleteCheck func(id *ResourceID) error
+
 	// X is extra state that can be used as part of the mock. Generated code
 	// will not use this field.
 	X interface{}
 }
 
 // Get returns the object from the mock.
-func (m *MockAlphaInstances) Get(ctx context.Context, key meta.Key) (*alpha.Instance, error) {
+func (m *MockAlphaInstances) Get(ctx context.Context, key meta.Key) (_ *alpha.Instance, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "alpha", "Instances")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("alpha"),
+			Service:   "Instances",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("zonal"); err != nil {
+		return nil, err
+	}
+
 	if m.GetHook != nil {
 		if intercept, obj, err := m.GetHook(m, ctx, key); intercept {
 			glog.V(5).Infof("MockAlphaInstances.Get(%v, %s) = %+v, %v", ctx, key, obj, err)
@@ -7461,17 +11746,18 @@ func (m *MockAlphaInstances) Get(ctx context.Context, key meta.Key) (*alpha.Inst
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.GetError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.GetError[skey]; ok {
 		glog.V(5).Infof("MockAlphaInstances.Get(%v, %s) = nil, %v", ctx, key, err)
 		return nil, err
 	}
-	if obj, ok := m.Objects[key]; ok {
+	if obj, ok := m.Objects[skey]; ok {
 		typedObj := obj.ToAlpha()
 		glog.V(5).Infof("MockAlphaInstances.Get(%v, %s) = %+v, nil", ctx, key, typedObj)
 		return typedObj, nil
 	}
 
-	err := &googleapi.Error{
+	err = &googleapi.Error{
 		Code:    http.StatusNotFound,
 		Message: fmt.Sprintf("MockAlphaInstances %v not found", key),
 	}
@@ -7480,7 +11766,26 @@ func (m *MockAlphaInstances) Get(ctx context.Context, key meta.Key) (*alpha.Inst
 }
 
 // List all of the objects in the mock in the given zone.
-func (m *MockAlphaInstances) List(ctx context.Context, zone string, fl *filter.F) ([]*alpha.Instance, error) {
+func (m *MockAlphaInstances) List(ctx context.Context, zone string, fl *filter.F) (_ []*alpha.Instance, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, nil, "alpha", "Instances")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("alpha"),
+			Service:   "Instances",
+		},
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if m.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(alpha.Instance{})); err != nil {
+			glog.V(5).Infof("MockAlphaInstances.List(%v, %v) = nil, %v", ctx, fl, err)
+			return nil, err
+		}
+	}
+
 	if m.ListHook != nil {
 		if intercept, objs, err := m.ListHook(m, ctx, zone, fl); intercept {
 			glog.V(5).Infof("MockAlphaInstances.List(%v, %q, %v) = [%v items], %v", ctx, zone, fl, len(objs), err)
@@ -7500,6 +11805,10 @@ func (m *MockAlphaInstances) List(ctx context.Context, zone string, fl *filter.F
 
 	var objs []*alpha.Instance
 	for key, obj := range m.Objects {
+		// Objects in other projects are not listed.
+		if key.Project != mockStorageProject(projectID) {
+			continue
+		}
 		if key.Zone != zone {
 			continue
 		}
@@ -7514,7 +11823,26 @@ func (m *MockAlphaInstances) List(ctx context.Context, zone string, fl *filter.F
 }
 
 // Insert is a mock for inserting/creating a new object.
-func (m *MockAlphaInstances) Insert(ctx context.Context, key meta.Key, obj *alpha.Instance) error {
+func (m *MockAlphaInstances) Insert(ctx context.Context, key meta.Key, obj *alpha.Instance) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "alpha", "Instances")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Insert",
+			Version:   meta.Version("alpha"),
+			Service:   "Instances",
+		},
+		Key:     &key,
+		Request: obj,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("zonal"); err != nil {
+		return err
+	}
+
 	if m.InsertHook != nil {
 		if intercept, err := m.InsertHook(m, ctx, key, obj); intercept {
 			glog.V(5).Infof("MockAlphaInstances.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
@@ -7525,11 +11853,12 @@ func (m *MockAlphaInstances) Insert(ctx context.Context, key meta.Key, obj *alph
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.InsertError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.InsertError[skey]; ok {
 		glog.V(5).Infof("MockAlphaInstances.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
 		return err
 	}
-	if _, ok := m.Objects[key]; ok {
+	if _, ok := m.Objects[skey]; ok {
 		err := &googleapi.Error{
 			Code:    http.StatusConflict,
 			Message: fmt.Sprintf("MockAlphaInstances %v exists", key),
@@ -7540,16 +11869,34 @@ func (m *MockAlphaInstances) Insert(ctx context.Context, key meta.Key, obj *alph
 
 	obj.Name = key.Name
 	if obj.SelfLink == "" {
-		obj.SelfLink = SelfLink(meta.VersionAlpha, "mock-project", "instances", key)
+		obj.SelfLink = SelfLink(meta.VersionAlpha, projectID, "instances", key)
 	}
 
-	m.Objects[key] = &MockInstancesObj{obj}
+	m.Objects[skey] = &MockInstancesObj{obj}
 	glog.V(5).Infof("MockAlphaInstances.Insert(%v, %v, %+v) = nil", ctx, key, obj)
 	return nil
 }
 
 // Delete is a mock for deleting the object.
-func (m *MockAlphaInstances) Delete(ctx context.Context, key meta.Key) error {
+func (m *MockAlphaInstances) Delete(ctx context.Context, key meta.Key) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "alpha", "Instances")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Delete",
+			Version:   meta.Version("alpha"),
+			Service:   "Instances",
+		},
+		Key:     &key,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("zonal"); err != nil {
+		return err
+	}
+
 	if m.DeleteHook != nil {
 		if intercept, err := m.DeleteHook(m, ctx, key); intercept {
 			glog.V(5).Infof("MockAlphaInstances.Delete(%v, %v) = %v", ctx, key, err)
@@ -7557,14 +11904,22 @@ func (m *MockAlphaInstances) Delete(ctx context.Context, key meta.Key) error {
 		}
 	}
 
+	if m.deleteCheck != nil {
+		if err := m.deleteCheck(NewResourceID("instances", *key.WithProject(projectID))); err != nil {
+			glog.V(5).Infof("MockAlphaInstances.Delete(%v, %v) = %v", ctx, key, err)
+			return err
+		}
+	}
+
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.DeleteError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.DeleteError[skey]; ok {
 		glog.V(5).Infof("MockAlphaInstances.Delete(%v, %v) = %v", ctx, key, err)
 		return err
 	}
-	if _, ok := m.Objects[key]; !ok {
+	if _, ok := m.Objects[skey]; !ok {
 		err := &googleapi.Error{
 			Code:    http.StatusNotFound,
 			Message: fmt.Sprintf("MockAlphaInstances %v not found", key),
@@ -7573,7 +11928,7 @@ func (m *MockAlphaInstances) Delete(ctx context.Context, key meta.Key) error {
 		return err
 	}
 
-	delete(m.Objects, key)
+	delete(m.Objects, skey)
 	glog.V(5).Infof("MockAlphaInstances.Delete(%v, %v) = nil", ctx, key)
 	return nil
 }
@@ -7584,7 +11939,26 @@ func (m *MockAlphaInstances) Obj(o *alpha.Instance) *MockInstancesObj {
 }
 
 // AttachDisk is a mock for the corresponding method.
-func (m *MockAlphaInstances) AttachDisk(ctx context.Context, key meta.Key, arg0 *alpha.AttachedDisk) error {
+func (m *MockAlphaInstances) AttachDisk(ctx context.Context, key meta.Key, arg0 *alpha.AttachedDisk) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "alpha", "Instances")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "AttachDisk",
+			Version:   meta.Version("alpha"),
+			Service:   "Instances",
+		},
+		Key:     &key,
+		Request: arg0,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("zonal"); err != nil {
+		return err
+	}
+
 	if m.AttachDiskHook != nil {
 		return m.AttachDiskHook(m, ctx, key, arg0)
 	}
@@ -7592,7 +11966,26 @@ func (m *MockAlphaInstances) AttachDisk(ctx context.Context, key meta.Key, arg0
 }
 
 // DetachDisk is a mock for the corresponding method.
-func (m *MockAlphaInstances) DetachDisk(ctx context.Context, key meta.Key, arg0 string) error {
+func (m *MockAlphaInstances) DetachDisk(ctx context.Context, key meta.Key, arg0 string) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "alpha", "Instances")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "DetachDisk",
+			Version:   meta.Version("alpha"),
+			Service:   "Instances",
+		},
+		Key:     &key,
+		Request: arg0,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("zonal"); err != nil {
+		return err
+	}
+
 	if m.DetachDiskHook != nil {
 		return m.DetachDiskHook(m, ctx, key, arg0)
 	}
@@ -7600,7 +11993,26 @@ func (m *MockAlphaInstances) DetachDisk(ctx context.Context, key meta.Key, arg0
 }
 
 // UpdateNetworkInterface is a mock for the corresponding method.
-func (m *MockAlphaInstances) UpdateNetworkInterface(ctx context.Context, key meta.Key, arg0 string, arg1 *alpha.NetworkInterface) error {
+func (m *MockAlphaInstances) UpdateNetworkInterface(ctx context.Context, key meta.Key, arg0 string, arg1 *alpha.NetworkInterface) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "alpha", "Instances")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "UpdateNetworkInterface",
+			Version:   meta.Version("alpha"),
+			Service:   "Instances",
+		},
+		Key:     &key,
+		Request: []interface{}{arg0, arg1},
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("zonal"); err != nil {
+		return err
+	}
+
 	if m.UpdateNetworkInterfaceHook != nil {
 		return m.UpdateNetworkInterfaceHook(m, ctx, key, arg0, arg1)
 	}
@@ -7613,15 +12025,23 @@ type GCEAlphaInstances struct {
 }
 
 // Get the Instance named by key.
-func (g *GCEAlphaInstances) Get(ctx context.Context, key meta.Key) (*alpha.Instance, error) {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "alpha", "Instances")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Get",
-		Version:   meta.Version("alpha"),
-		Service:   "Instances",
+func (g *GCEAlphaInstances) Get(ctx context.Context, key meta.Key) (_ *alpha.Instance, err error) {
+	projectID := g.s.keyProjectID(ctx, key, "alpha", "Instances")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("alpha"),
+			Service:   "Instances",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("zonal"); err != nil {
+		return nil, err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.Alpha.Instances.Get(projectID, key.Zone, key.Name)
@@ -7630,24 +12050,38 @@ func (g *GCEAlphaInstances) Get(ctx context.Context, key meta.Key) (*alpha.Insta
 }
 
 // List all Instance objects.
-func (g *GCEAlphaInstances) List(ctx context.Context, zone string, fl *filter.F) ([]*alpha.Instance, error) {
+func (g *GCEAlphaInstances) List(ctx context.Context, zone string, fl *filter.F) (_ []*alpha.Instance, err error) {
 	projectID := g.s.ProjectRouter.ProjectID(ctx, "alpha", "Instances")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "List",
-		Version:   meta.Version("alpha"),
-		Service:   "Instances",
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("alpha"),
+			Service:   "Instances",
+		},
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if g.s.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(alpha.Instance{})); err != nil {
+			return nil, err
+		}
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.Alpha.Instances.List(projectID, zone)
-	if fl != filter.None {
-		call.Filter(fl.String())
+	serverFl, clientFl := fl.Split(reflect.TypeOf(alpha.Instance{}))
+	if serverFl != filter.None {
+		call.Filter(serverFl.String())
 	}
 	var all []*alpha.Instance
 	f := func(l *alpha.InstanceList) error {
-		all = append(all, l.Items...)
+		for _, obj := range l.Items {
+			if clientFl.Match(obj) {
+				all = append(all, obj)
+			}
+		}
 		return nil
 	}
 	if err := call.Pages(ctx, f); err != nil {
@@ -7657,18 +12091,32 @@ func (g *GCEAlphaInstances) List(ctx context.Context, zone string, fl *filter.F)
 }
 
 // Insert Instance with key of value obj.
-func (g *GCEAlphaInstances) Insert(ctx context.Context, key meta.Key, obj *alpha.Instance) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "alpha", "Instances")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Insert",
-		Version:   meta.Version("alpha"),
-		Service:   "Instances",
+func (g *GCEAlphaInstances) Insert(ctx context.Context, key meta.Key, obj *alpha.Instance) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "alpha", "Instances")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Insert",
+			Version:   meta.Version("alpha"),
+			Service:   "Instances",
+		},
+		Key:     &key,
+		Request: obj,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("zonal"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
 	obj.Name = key.Name
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.Alpha.Instances.Insert(projectID, key.Zone, obj)
 	call.Context(ctx)
 
@@ -7680,17 +12128,30 @@ func (g *GCEAlphaInstances) Insert(ctx context.Context, key meta.Key, obj *alpha
 }
 
 // Delete the Instance referenced by key.
-func (g *GCEAlphaInstances) Delete(ctx context.Context, key meta.Key) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "alpha", "Instances")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Delete",
-		Version:   meta.Version("alpha"),
-		Service:   "Instances",
+func (g *GCEAlphaInstances) Delete(ctx context.Context, key meta.Key) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "alpha", "Instances")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Delete",
+			Version:   meta.Version("alpha"),
+			Service:   "Instances",
+		},
+		Key:     &key,
+		Mutates: true,
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("zonal"); err != nil {
 		return err
 	}
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
+		return err
+	}
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.Alpha.Instances.Delete(projectID, key.Zone, key.Name)
 	call.Context(ctx)
 
@@ -7702,17 +12163,31 @@ func (g *GCEAlphaInstances) Delete(ctx context.Context, key meta.Key) error {
 }
 
 // AttachDisk is a method on GCEAlphaInstances.
-func (g *GCEAlphaInstances) AttachDisk(ctx context.Context, key meta.Key, arg0 *alpha.AttachedDisk) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "alpha", "Instances")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "AttachDisk",
-		Version:   meta.Version("alpha"),
-		Service:   "Instances",
+func (g *GCEAlphaInstances) AttachDisk(ctx context.Context, key meta.Key, arg0 *alpha.AttachedDisk) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "alpha", "Instances")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "AttachDisk",
+			Version:   meta.Version("alpha"),
+			Service:   "Instances",
+		},
+		Key:     &key,
+		Request: arg0,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("zonal"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.Alpha.Instances.AttachDisk(projectID, key.Zone, key.Name, arg0)
 	call.Context(ctx)
 	op, err := call.Do()
@@ -7723,19 +12198,33 @@ func (g *GCEAlphaInstances) AttachDisk(ctx context.Context, key meta.Key, arg0 *
 }
 
 // DetachDisk is a method on GCEAlphaInstances.
-func (g *GCEAlphaInstances) DetachDisk(ctx context.Context, key meta.Key, arg0 string) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "alpha", "Instances")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "DetachDisk",
-		Version:   meta.Version("alpha"),
-		Service:   "Instances",
+func (g *GCEAlphaInstances) DetachDisk(ctx context.Context, key meta.Key, arg0 string) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "alpha", "Instances")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "DetachDisk",
+			Version:   meta.Version("alpha"),
+			Service:   "Instances",
+		},
+		Key:     &key,
+		Request: arg0,
+		Mutates: true,
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("zonal"); err != nil {
 		return err
 	}
-	call := g.s.Alpha.Instances.DetachDisk(projectID, key.Zone, key.Name, arg0)
-	call.Context(ctx)
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
+		return err
+	}
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
+	call := g.s.Alpha.Instances.DetachDisk(projectID, key.Zone, key.Name, arg0)
+	call.Context(ctx)
 	op, err := call.Do()
 	if err != nil {
 		return err
@@ -7744,17 +12233,31 @@ func (g *GCEAlphaInstances) DetachDisk(ctx context.Context, key meta.Key, arg0 s
 }
 
 // UpdateNetworkInterface is a method on GCEAlphaInstances.
-func (g *GCEAlphaInstances) UpdateNetworkInterface(ctx context.Context, key meta.Key, arg0 string, arg1 *alpha.NetworkInterface) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "alpha", "Instances")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "UpdateNetworkInterface",
-		Version:   meta.Version("alpha"),
-		Service:   "Instances",
+func (g *GCEAlphaInstances) UpdateNetworkInterface(ctx context.Context, key meta.Key, arg0 string, arg1 *alpha.NetworkInterface) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "alpha", "Instances")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "UpdateNetworkInterface",
+			Version:   meta.Version("alpha"),
+			Service:   "Instances",
+		},
+		Key:     &key,
+		Request: []interface{}{arg0, arg1},
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("zonal"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.Alpha.Instances.UpdateNetworkInterface(projectID, key.Zone, key.Name, arg0, arg1)
 	call.Context(ctx)
 	op, err := call.Do()
@@ -7813,13 +12316,46 @@ type MockAlphaNetworkEndpointGroups struct {
 	AttachNetworkEndpointsHook func(*MockAlphaNetworkEndpointGroups, context.Context, meta.Key, *alpha.NetworkEndpointGroupsAttachEndpointsRequest) error
 	DetachNetworkEndpointsHook func(*MockAlphaNetworkEndpointGroups, context.Context, meta.Key, *alpha.NetworkEndpointGroupsDetachEndpointsRequest) error
 
+	// Observer, if set, is invoked around each call to the mock.
+	Observer CallObserver
+
+	// ProjectRouter, if set, routes the calls with keys that do not have a
+	// project. Calls are made to "mock-project" if it is not set.
+	ProjectRouter ProjectRouter
+
+	// Debug enables additional checks of the arguments to the mock, such as
+	// the validation of List() filters with filter.Validate().
+	Debug bool
+
+	// deleteCheck, if set, is called by Delete() with the resource being
+	// deleted. Delete() returns the error if it is not nil. See
+	// WithReferenceChecking().
+	deleteCheck func(id *ResourceID) error
+
 	// X is extra state that can be used as part of the mock. Generated code
 	// will not use this field.
 	X interface{}
 }
 
 // Get returns the object from the mock.
-func (m *MockAlphaNetworkEndpointGroups) Get(ctx context.Context, key meta.Key) (*alpha.NetworkEndpointGroup, error) {
+func (m *MockAlphaNetworkEndpointGroups) Get(ctx context.Context, key meta.Key) (_ *alpha.NetworkEndpointGroup, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "alpha", "NetworkEndpointGroups")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("alpha"),
+			Service:   "NetworkEndpointGroups",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("zonal"); err != nil {
+		return nil, err
+	}
+
 	if m.GetHook != nil {
 		if intercept, obj, err := m.GetHook(m, ctx, key); intercept {
 			glog.V(5).Infof("MockAlphaNetworkEndpointGroups.Get(%v, %s) = %+v, %v", ctx, key, obj, err)
@@ -7830,17 +12366,18 @@ func (m *MockAlphaNetworkEndpointGroups) Get(ctx context.Context, key meta.Key)
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.GetError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.GetError[skey]; ok {
 		glog.V(5).Infof("MockAlphaNetworkEndpointGroups.Get(%v, %s) = nil, %v", ctx, key, err)
 		return nil, err
 	}
-	if obj, ok := m.Objects[key]; ok {
+	if obj, ok := m.Objects[skey]; ok {
 		typedObj := obj.ToAlpha()
 		glog.V(5).Infof("MockAlphaNetworkEndpointGroups.Get(%v, %s) = %+v, nil", ctx, key, typedObj)
 		return typedObj, nil
 	}
 
-	err := &googleapi.Error{
+	err = &googleapi.Error{
 		Code:    http.StatusNotFound,
 		Message: fmt.Sprintf("MockAlphaNetworkEndpointGroups %v not found", key),
 	}
@@ -7849,7 +12386,26 @@ func (m *MockAlphaNetworkEndpointGroups) Get(ctx context.Context, key meta.Key)
 }
 
 // List all of the objects in the mock in the given zone.
-func (m *MockAlphaNetworkEndpointGroups) List(ctx context.Context, zone string, fl *filter.F) ([]*alpha.NetworkEndpointGroup, error) {
+func (m *MockAlphaNetworkEndpointGroups) List(ctx context.Context, zone string, fl *filter.F) (_ []*alpha.NetworkEndpointGroup, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, nil, "alpha", "NetworkEndpointGroups")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("alpha"),
+			Service:   "NetworkEndpointGroups",
+		},
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if m.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(alpha.NetworkEndpointGroup{})); err != nil {
+			glog.V(5).Infof("MockAlphaNetworkEndpointGroups.List(%v, %v) = nil, %v", ctx, fl, err)
+			return nil, err
+		}
+	}
+
 	if m.ListHook != nil {
 		if intercept, objs, err := m.ListHook(m, ctx, zone, fl); intercept {
 			glog.V(5).Infof("MockAlphaNetworkEndpointGroups.List(%v, %q, %v) = [%v items], %v", ctx, zone, fl, len(objs), err)
@@ -7869,6 +12425,10 @@ func (m *MockAlphaNetworkEndpointGroups) List(ctx context.Context, zone string,
 
 	var objs []*alpha.NetworkEndpointGroup
 	for key, obj := range m.Objects {
+		// Objects in other projects are not listed.
+		if key.Project != mockStorageProject(projectID) {
+			continue
+		}
 		if key.Zone != zone {
 			continue
 		}
@@ -7883,7 +12443,26 @@ func (m *MockAlphaNetworkEndpointGroups) List(ctx context.Context, zone string,
 }
 
 // Insert is a mock for inserting/creating a new object.
-func (m *MockAlphaNetworkEndpointGroups) Insert(ctx context.Context, key meta.Key, obj *alpha.NetworkEndpointGroup) error {
+func (m *MockAlphaNetworkEndpointGroups) Insert(ctx context.Context, key meta.Key, obj *alpha.NetworkEndpointGroup) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "alpha", "NetworkEndpointGroups")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Insert",
+			Version:   meta.Version("alpha"),
+			Service:   "NetworkEndpointGroups",
+		},
+		Key:     &key,
+		Request: obj,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("zonal"); err != nil {
+		return err
+	}
+
 	if m.InsertHook != nil {
 		if intercept, err := m.InsertHook(m, ctx, key, obj); intercept {
 			glog.V(5).Infof("MockAlphaNetworkEndpointGroups.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
@@ -7894,11 +12473,12 @@ func (m *MockAlphaNetworkEndpointGroups) Insert(ctx context.Context, key meta.Ke
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.InsertError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.InsertError[skey]; ok {
 		glog.V(5).Infof("MockAlphaNetworkEndpointGroups.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
 		return err
 	}
-	if _, ok := m.Objects[key]; ok {
+	if _, ok := m.Objects[skey]; ok {
 		err := &googleapi.Error{
 			Code:    http.StatusConflict,
 			Message: fmt.Sprintf("MockAlphaNetworkEndpointGroups %v exists", key),
@@ -7909,16 +12489,34 @@ func (m *MockAlphaNetworkEndpointGroups) Insert(ctx context.Context, key meta.Ke
 
 	obj.Name = key.Name
 	if obj.SelfLink == "" {
-		obj.SelfLink = SelfLink(meta.VersionAlpha, "mock-project", "networkEndpointGroups", key)
+		obj.SelfLink = SelfLink(meta.VersionAlpha, projectID, "networkEndpointGroups", key)
 	}
 
-	m.Objects[key] = &MockNetworkEndpointGroupsObj{obj}
+	m.Objects[skey] = &MockNetworkEndpointGroupsObj{obj}
 	glog.V(5).Infof("MockAlphaNetworkEndpointGroups.Insert(%v, %v, %+v) = nil", ctx, key, obj)
 	return nil
 }
 
 // Delete is a mock for deleting the object.
-func (m *MockAlphaNetworkEndpointGroups) Delete(ctx context.Context, key meta.Key) error {
+func (m *MockAlphaNetworkEndpointGroups) Delete(ctx context.Context, key meta.Key) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "alpha", "NetworkEndpointGroups")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Delete",
+			Version:   meta.Version("alpha"),
+			Service:   "NetworkEndpointGroups",
+		},
+		Key:     &key,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("zonal"); err != nil {
+		return err
+	}
+
 	if m.DeleteHook != nil {
 		if intercept, err := m.DeleteHook(m, ctx, key); intercept {
 			glog.V(5).Infof("MockAlphaNetworkEndpointGroups.Delete(%v, %v) = %v", ctx, key, err)
@@ -7926,14 +12524,22 @@ func (m *MockAlphaNetworkEndpointGroups) Delete(ctx context.Context, key meta.Ke
 		}
 	}
 
+	if m.deleteCheck != nil {
+		if err := m.deleteCheck(NewResourceID("networkEndpointGroups", *key.WithProject(projectID))); err != nil {
+			glog.V(5).Infof("MockAlphaNetworkEndpointGroups.Delete(%v, %v) = %v", ctx, key, err)
+			return err
+		}
+	}
+
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.DeleteError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.DeleteError[skey]; ok {
 		glog.V(5).Infof("MockAlphaNetworkEndpointGroups.Delete(%v, %v) = %v", ctx, key, err)
 		return err
 	}
-	if _, ok := m.Objects[key]; !ok {
+	if _, ok := m.Objects[skey]; !ok {
 		err := &googleapi.Error{
 			Code:    http.StatusNotFound,
 			Message: fmt.Sprintf("MockAlphaNetworkEndpointGroups %v not found", key),
@@ -7942,13 +12548,32 @@ func (m *MockAlphaNetworkEndpointGroups) Delete(ctx context.Context, key meta.Ke
 		return err
 	}
 
-	delete(m.Objects, key)
+	delete(m.Objects, skey)
 	glog.V(5).Infof("MockAlphaNetworkEndpointGroups.Delete(%v, %v) = nil", ctx, key)
 	return nil
 }
 
 // AggregatedList is a mock for AggregatedList.
-func (m *MockAlphaNetworkEndpointGroups) AggregatedList(ctx context.Context, fl *filter.F) (map[string][]*alpha.NetworkEndpointGroup, error) {
+func (m *MockAlphaNetworkEndpointGroups) AggregatedList(ctx context.Context, fl *filter.F) (_ map[string][]*alpha.NetworkEndpointGroup, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, nil, "alpha", "NetworkEndpointGroups")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "AggregatedList",
+			Version:   meta.Version("alpha"),
+			Service:   "NetworkEndpointGroups",
+		},
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if m.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(alpha.NetworkEndpointGroup{})); err != nil {
+			glog.V(5).Infof("MockAlphaNetworkEndpointGroups.AggregatedList(%v, %v) = nil, %v", ctx, fl, err)
+			return nil, err
+		}
+	}
+
 	if m.AggregatedListHook != nil {
 		if intercept, objs, err := m.AggregatedListHook(m, ctx, fl); intercept {
 			glog.V(5).Infof("MockAlphaNetworkEndpointGroups.AggregatedList(%v, %v) = [%v items], %v", ctx, fl, len(objs), err)
@@ -7966,7 +12591,10 @@ func (m *MockAlphaNetworkEndpointGroups) AggregatedList(ctx context.Context, fl
 	}
 
 	objs := map[string][]*alpha.NetworkEndpointGroup{}
-	for _, obj := range m.Objects {
+	for key, obj := range m.Objects {
+		if key.Project != mockStorageProject(projectID) {
+			continue
+		}
 		res, err := ParseResourceURL(obj.ToAlpha().SelfLink)
 		location := res.Key.Zone
 		if err != nil {
@@ -7988,7 +12616,26 @@ func (m *MockAlphaNetworkEndpointGroups) Obj(o *alpha.NetworkEndpointGroup) *Moc
 }
 
 // AttachNetworkEndpoints is a mock for the corresponding method.
-func (m *MockAlphaNetworkEndpointGroups) AttachNetworkEndpoints(ctx context.Context, key meta.Key, arg0 *alpha.NetworkEndpointGroupsAttachEndpointsRequest) error {
+func (m *MockAlphaNetworkEndpointGroups) AttachNetworkEndpoints(ctx context.Context, key meta.Key, arg0 *alpha.NetworkEndpointGroupsAttachEndpointsRequest) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "alpha", "NetworkEndpointGroups")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "AttachNetworkEndpoints",
+			Version:   meta.Version("alpha"),
+			Service:   "NetworkEndpointGroups",
+		},
+		Key:     &key,
+		Request: arg0,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("zonal"); err != nil {
+		return err
+	}
+
 	if m.AttachNetworkEndpointsHook != nil {
 		return m.AttachNetworkEndpointsHook(m, ctx, key, arg0)
 	}
@@ -7996,7 +12643,26 @@ func (m *MockAlphaNetworkEndpointGroups) AttachNetworkEndpoints(ctx context.Cont
 }
 
 // DetachNetworkEndpoints is a mock for the corresponding method.
-func (m *MockAlphaNetworkEndpointGroups) DetachNetworkEndpoints(ctx context.Context, key meta.Key, arg0 *alpha.NetworkEndpointGroupsDetachEndpointsRequest) error {
+func (m *MockAlphaNetworkEndpointGroups) DetachNetworkEndpoints(ctx context.Context, key meta.Key, arg0 *alpha.NetworkEndpointGroupsDetachEndpointsRequest) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "alpha", "NetworkEndpointGroups")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "DetachNetworkEndpoints",
+			Version:   meta.Version("alpha"),
+			Service:   "NetworkEndpointGroups",
+		},
+		Key:     &key,
+		Request: arg0,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("zonal"); err != nil {
+		return err
+	}
+
 	if m.DetachNetworkEndpointsHook != nil {
 		return m.DetachNetworkEndpointsHook(m, ctx, key, arg0)
 	}
@@ -8009,15 +12675,23 @@ type GCEAlphaNetworkEndpointGroups struct {
 }
 
 // Get the NetworkEndpointGroup named by key.
-func (g *GCEAlphaNetworkEndpointGroups) Get(ctx context.Context, key meta.Key) (*alpha.NetworkEndpointGroup, error) {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "alpha", "NetworkEndpointGroups")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Get",
-		Version:   meta.Version("alpha"),
-		Service:   "NetworkEndpointGroups",
+func (g *GCEAlphaNetworkEndpointGroups) Get(ctx context.Context, key meta.Key) (_ *alpha.NetworkEndpointGroup, err error) {
+	projectID := g.s.keyProjectID(ctx, key, "alpha", "NetworkEndpointGroups")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("alpha"),
+			Service:   "NetworkEndpointGroups",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("zonal"); err != nil {
+		return nil, err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.Alpha.NetworkEndpointGroups.Get(projectID, key.Zone, key.Name)
@@ -8026,24 +12700,38 @@ func (g *GCEAlphaNetworkEndpointGroups) Get(ctx context.Context, key meta.Key) (
 }
 
 // List all NetworkEndpointGroup objects.
-func (g *GCEAlphaNetworkEndpointGroups) List(ctx context.Context, zone string, fl *filter.F) ([]*alpha.NetworkEndpointGroup, error) {
+func (g *GCEAlphaNetworkEndpointGroups) List(ctx context.Context, zone string, fl *filter.F) (_ []*alpha.NetworkEndpointGroup, err error) {
 	projectID := g.s.ProjectRouter.ProjectID(ctx, "alpha", "NetworkEndpointGroups")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "List",
-		Version:   meta.Version("alpha"),
-		Service:   "NetworkEndpointGroups",
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("alpha"),
+			Service:   "NetworkEndpointGroups",
+		},
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if g.s.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(alpha.NetworkEndpointGroup{})); err != nil {
+			return nil, err
+		}
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.Alpha.NetworkEndpointGroups.List(projectID, zone)
-	if fl != filter.None {
-		call.Filter(fl.String())
+	serverFl, clientFl := fl.Split(reflect.TypeOf(alpha.NetworkEndpointGroup{}))
+	if serverFl != filter.None {
+		call.Filter(serverFl.String())
 	}
 	var all []*alpha.NetworkEndpointGroup
 	f := func(l *alpha.NetworkEndpointGroupList) error {
-		all = append(all, l.Items...)
+		for _, obj := range l.Items {
+			if clientFl.Match(obj) {
+				all = append(all, obj)
+			}
+		}
 		return nil
 	}
 	if err := call.Pages(ctx, f); err != nil {
@@ -8053,18 +12741,32 @@ func (g *GCEAlphaNetworkEndpointGroups) List(ctx context.Context, zone string, f
 }
 
 // Insert NetworkEndpointGroup with key of value obj.
-func (g *GCEAlphaNetworkEndpointGroups) Insert(ctx context.Context, key meta.Key, obj *alpha.NetworkEndpointGroup) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "alpha", "NetworkEndpointGroups")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Insert",
-		Version:   meta.Version("alpha"),
-		Service:   "NetworkEndpointGroups",
+func (g *GCEAlphaNetworkEndpointGroups) Insert(ctx context.Context, key meta.Key, obj *alpha.NetworkEndpointGroup) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "alpha", "NetworkEndpointGroups")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Insert",
+			Version:   meta.Version("alpha"),
+			Service:   "NetworkEndpointGroups",
+		},
+		Key:     &key,
+		Request: obj,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("zonal"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
 	obj.Name = key.Name
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.Alpha.NetworkEndpointGroups.Insert(projectID, key.Zone, obj)
 	call.Context(ctx)
 
@@ -8076,17 +12778,30 @@ func (g *GCEAlphaNetworkEndpointGroups) Insert(ctx context.Context, key meta.Key
 }
 
 // Delete the NetworkEndpointGroup referenced by key.
-func (g *GCEAlphaNetworkEndpointGroups) Delete(ctx context.Context, key meta.Key) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "alpha", "NetworkEndpointGroups")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Delete",
-		Version:   meta.Version("alpha"),
-		Service:   "NetworkEndpointGroups",
+func (g *GCEAlphaNetworkEndpointGroups) Delete(ctx context.Context, key meta.Key) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "alpha", "NetworkEndpointGroups")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Delete",
+			Version:   meta.Version("alpha"),
+			Service:   "NetworkEndpointGroups",
+		},
+		Key:     &key,
+		Mutates: true,
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("zonal"); err != nil {
 		return err
 	}
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
+		return err
+	}
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.Alpha.NetworkEndpointGroups.Delete(projectID, key.Zone, key.Name)
 	call.Context(ctx)
 
@@ -8098,28 +12813,42 @@ func (g *GCEAlphaNetworkEndpointGroups) Delete(ctx context.Context, key meta.Key
 }
 
 // AggregatedList lists all resources of the given type across all locations.
-func (g *GCEAlphaNetworkEndpointGroups) AggregatedList(ctx context.Context, fl *filter.F) (map[string][]*alpha.NetworkEndpointGroup, error) {
+func (g *GCEAlphaNetworkEndpointGroups) AggregatedList(ctx context.Context, fl *filter.F) (_ map[string][]*alpha.NetworkEndpointGroup, err error) {
 	projectID := g.s.ProjectRouter.ProjectID(ctx, "alpha", "NetworkEndpointGroups")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "AggregatedList",
-		Version:   meta.Version("alpha"),
-		Service:   "NetworkEndpointGroups",
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "AggregatedList",
+			Version:   meta.Version("alpha"),
+			Service:   "NetworkEndpointGroups",
+		},
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if g.s.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(alpha.NetworkEndpointGroup{})); err != nil {
+			return nil, err
+		}
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 
 	call := g.s.Alpha.NetworkEndpointGroups.AggregatedList(projectID)
 	call.Context(ctx)
-	if fl != filter.None {
-		call.Filter(fl.String())
+	serverFl, clientFl := fl.Split(reflect.TypeOf(alpha.NetworkEndpointGroup{}))
+	if serverFl != filter.None {
+		call.Filter(serverFl.String())
 	}
 
 	all := map[string][]*alpha.NetworkEndpointGroup{}
 	f := func(l *alpha.NetworkEndpointGroupAggregatedList) error {
 		for k, v := range l.Items {
-			all[k] = append(all[k], v.NetworkEndpointGroups...)
+			for _, obj := range v.NetworkEndpointGroups {
+				if clientFl.Match(obj) {
+					all[k] = append(all[k], obj)
+				}
+			}
 		}
 		return nil
 	}
@@ -8130,17 +12859,31 @@ func (g *GCEAlphaNetworkEndpointGroups) AggregatedList(ctx context.Context, fl *
 }
 
 // AttachNetworkEndpoints is a method on GCEAlphaNetworkEndpointGroups.
-func (g *GCEAlphaNetworkEndpointGroups) AttachNetworkEndpoints(ctx context.Context, key meta.Key, arg0 *alpha.NetworkEndpointGroupsAttachEndpointsRequest) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "alpha", "NetworkEndpointGroups")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "AttachNetworkEndpoints",
-		Version:   meta.Version("alpha"),
-		Service:   "NetworkEndpointGroups",
+func (g *GCEAlphaNetworkEndpointGroups) AttachNetworkEndpoints(ctx context.Context, key meta.Key, arg0 *alpha.NetworkEndpointGroupsAttachEndpointsRequest) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "alpha", "NetworkEndpointGroups")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "AttachNetworkEndpoints",
+			Version:   meta.Version("alpha"),
+			Service:   "NetworkEndpointGroups",
+		},
+		Key:     &key,
+		Request: arg0,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("zonal"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.Alpha.NetworkEndpointGroups.AttachNetworkEndpoints(projectID, key.Zone, key.Name, arg0)
 	call.Context(ctx)
 	op, err := call.Do()
@@ -8151,17 +12894,31 @@ func (g *GCEAlphaNetworkEndpointGroups) AttachNetworkEndpoints(ctx context.Conte
 }
 
 // DetachNetworkEndpoints is a method on GCEAlphaNetworkEndpointGroups.
-func (g *GCEAlphaNetworkEndpointGroups) DetachNetworkEndpoints(ctx context.Context, key meta.Key, arg0 *alpha.NetworkEndpointGroupsDetachEndpointsRequest) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "alpha", "NetworkEndpointGroups")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "DetachNetworkEndpoints",
-		Version:   meta.Version("alpha"),
-		Service:   "NetworkEndpointGroups",
+func (g *GCEAlphaNetworkEndpointGroups) DetachNetworkEndpoints(ctx context.Context, key meta.Key, arg0 *alpha.NetworkEndpointGroupsDetachEndpointsRequest) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "alpha", "NetworkEndpointGroups")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "DetachNetworkEndpoints",
+			Version:   meta.Version("alpha"),
+			Service:   "NetworkEndpointGroups",
+		},
+		Key:     &key,
+		Request: arg0,
+		Mutates: true,
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("zonal"); err != nil {
 		return err
 	}
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
+		return err
+	}
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.Alpha.NetworkEndpointGroups.DetachNetworkEndpoints(projectID, key.Zone, key.Name, arg0)
 	call.Context(ctx)
 	op, err := call.Do()
@@ -8201,6 +12958,17 @@ type MockProjects struct {
 	// execution flow of the mock. Return (false, nil, nil) to continue with
 	// normal mock behavior/ after the hook function executes.
 
+	// Observer, if set, is invoked around each call to the mock.
+	Observer CallObserver
+
+	// ProjectRouter, if set, routes the calls with keys that do not have a
+	// project. Calls are made to "mock-project" if it is not set.
+	ProjectRouter ProjectRouter
+
+	// Debug enables additional checks of the arguments to the mock, such as
+	// the validation of List() filters with filter.Validate().
+	Debug bool
+
 	// X is extra state that can be used as part of the mock. Generated code
 	// will not use this field.
 	X interface{}
@@ -8250,13 +13018,41 @@ type MockRegions struct {
 	GetHook  func(m *MockRegions, ctx context.Context, key meta.Key) (bool, *ga.Region, error)
 	ListHook func(m *MockRegions, ctx context.Context, fl *filter.F) (bool, []*ga.Region, error)
 
+	// Observer, if set, is invoked around each call to the mock.
+	Observer CallObserver
+
+	// ProjectRouter, if set, routes the calls with keys that do not have a
+	// project. Calls are made to "mock-project" if it is not set.
+	ProjectRouter ProjectRouter
+
+	// Debug enables additional checks of the arguments to the mock, such as
+	// the validation of List() filters with filter.Validate().
+	Debug bool
+
 	// X is extra state that can be used as part of the mock. Generated code
 	// will not use this field.
 	X interface{}
 }
 
 // Get returns the object from the mock.
-func (m *MockRegions) Get(ctx context.Context, key meta.Key) (*ga.Region, error) {
+func (m *MockRegions) Get(ctx context.Context, key meta.Key) (_ *ga.Region, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "Regions")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("ga"),
+			Service:   "Regions",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("global"); err != nil {
+		return nil, err
+	}
+
 	if m.GetHook != nil {
 		if intercept, obj, err := m.GetHook(m, ctx, key); intercept {
 			glog.V(5).Infof("MockRegions.Get(%v, %s) = %+v, %v", ctx, key, obj, err)
@@ -8267,17 +13063,18 @@ func (m *MockRegions) Get(ctx context.Context, key meta.Key) (*ga.Region, error)
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.GetError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.GetError[skey]; ok {
 		glog.V(5).Infof("MockRegions.Get(%v, %s) = nil, %v", ctx, key, err)
 		return nil, err
 	}
-	if obj, ok := m.Objects[key]; ok {
+	if obj, ok := m.Objects[skey]; ok {
 		typedObj := obj.ToGA()
 		glog.V(5).Infof("MockRegions.Get(%v, %s) = %+v, nil", ctx, key, typedObj)
 		return typedObj, nil
 	}
 
-	err := &googleapi.Error{
+	err = &googleapi.Error{
 		Code:    http.StatusNotFound,
 		Message: fmt.Sprintf("MockRegions %v not found", key),
 	}
@@ -8286,7 +13083,26 @@ func (m *MockRegions) Get(ctx context.Context, key meta.Key) (*ga.Region, error)
 }
 
 // List all of the objects in the mock.
-func (m *MockRegions) List(ctx context.Context, fl *filter.F) ([]*ga.Region, error) {
+func (m *MockRegions) List(ctx context.Context, fl *filter.F) (_ []*ga.Region, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, nil, "ga", "Regions")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("ga"),
+			Service:   "Regions",
+		},
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if m.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(ga.Region{})); err != nil {
+			glog.V(5).Infof("MockRegions.List(%v, %v) = nil, %v", ctx, fl, err)
+			return nil, err
+		}
+	}
+
 	if m.ListHook != nil {
 		if intercept, objs, err := m.ListHook(m, ctx, fl); intercept {
 			glog.V(5).Infof("MockRegions.List(%v, %v) = [%v items], %v", ctx, fl, len(objs), err)
@@ -8305,7 +13121,11 @@ func (m *MockRegions) List(ctx context.Context, fl *filter.F) ([]*ga.Region, err
 	}
 
 	var objs []*ga.Region
-	for _, obj := range m.Objects {
+	for key, obj := range m.Objects {
+		// Objects in other projects are not listed.
+		if key.Project != mockStorageProject(projectID) {
+			continue
+		}
 		if !fl.Match(obj.ToGA()) {
 			continue
 		}
@@ -8327,15 +13147,23 @@ type GCERegions struct {
 }
 
 // Get the Region named by key.
-func (g *GCERegions) Get(ctx context.Context, key meta.Key) (*ga.Region, error) {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "Regions")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Get",
-		Version:   meta.Version("ga"),
-		Service:   "Regions",
+func (g *GCERegions) Get(ctx context.Context, key meta.Key) (_ *ga.Region, err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "Regions")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("ga"),
+			Service:   "Regions",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("global"); err != nil {
+		return nil, err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.GA.Regions.Get(projectID, key.Name)
@@ -8344,24 +13172,38 @@ func (g *GCERegions) Get(ctx context.Context, key meta.Key) (*ga.Region, error)
 }
 
 // List all Region objects.
-func (g *GCERegions) List(ctx context.Context, fl *filter.F) ([]*ga.Region, error) {
+func (g *GCERegions) List(ctx context.Context, fl *filter.F) (_ []*ga.Region, err error) {
 	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "Regions")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "List",
-		Version:   meta.Version("ga"),
-		Service:   "Regions",
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("ga"),
+			Service:   "Regions",
+		},
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if g.s.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(ga.Region{})); err != nil {
+			return nil, err
+		}
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.GA.Regions.List(projectID)
-	if fl != filter.None {
-		call.Filter(fl.String())
+	serverFl, clientFl := fl.Split(reflect.TypeOf(ga.Region{}))
+	if serverFl != filter.None {
+		call.Filter(serverFl.String())
 	}
 	var all []*ga.Region
 	f := func(l *ga.RegionList) error {
-		all = append(all, l.Items...)
+		for _, obj := range l.Items {
+			if clientFl.Match(obj) {
+				all = append(all, obj)
+			}
+		}
 		return nil
 	}
 	if err := call.Pages(ctx, f); err != nil {
@@ -8412,13 +13254,46 @@ type MockRoutes struct {
 	InsertHook func(m *MockRoutes, ctx context.Context, key meta.Key, obj *ga.Route) (bool, error)
 	DeleteHook func(m *MockRoutes, ctx context.Context, key meta.Key) (bool, error)
 
+	// Observer, if set, is invoked around each call to the mock.
+	Observer CallObserver
+
+	// ProjectRouter, if set, routes the calls with keys that do not have a
+	// project. Calls are made to "mock-project" if it is not set.
+	ProjectRouter ProjectRouter
+
+	// Debug enables additional checks of the arguments to the mock, such as
+	// the validation of List() filters with filter.Validate().
+	Debug bool
+
+	// deleteCheck, if set, is called by Delete() with the resource being
+	// deleted. Delete() returns the error if it is not nil. See
+	// WithReferenceChecking().
+	deleteCheck func(id *ResourceID) error
+
 	// X is extra state that can be used as part of the mock. Generated code
 	// will not use this field.
 	X interface{}
 }
 
 // Get returns the object from the mock.
-func (m *MockRoutes) Get(ctx context.Context, key meta.Key) (*ga.Route, error) {
+func (m *MockRoutes) Get(ctx context.Context, key meta.Key) (_ *ga.Route, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "Routes")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("ga"),
+			Service:   "Routes",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("global"); err != nil {
+		return nil, err
+	}
+
 	if m.GetHook != nil {
 		if intercept, obj, err := m.GetHook(m, ctx, key); intercept {
 			glog.V(5).Infof("MockRoutes.Get(%v, %s) = %+v, %v", ctx, key, obj, err)
@@ -8429,17 +13304,18 @@ func (m *MockRoutes) Get(ctx context.Context, key meta.Key) (*ga.Route, error) {
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.GetError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.GetError[skey]; ok {
 		glog.V(5).Infof("MockRoutes.Get(%v, %s) = nil, %v", ctx, key, err)
 		return nil, err
 	}
-	if obj, ok := m.Objects[key]; ok {
+	if obj, ok := m.Objects[skey]; ok {
 		typedObj := obj.ToGA()
 		glog.V(5).Infof("MockRoutes.Get(%v, %s) = %+v, nil", ctx, key, typedObj)
 		return typedObj, nil
 	}
 
-	err := &googleapi.Error{
+	err = &googleapi.Error{
 		Code:    http.StatusNotFound,
 		Message: fmt.Sprintf("MockRoutes %v not found", key),
 	}
@@ -8448,7 +13324,26 @@ func (m *MockRoutes) Get(ctx context.Context, key meta.Key) (*ga.Route, error) {
 }
 
 // List all of the objects in the mock.
-func (m *MockRoutes) List(ctx context.Context, fl *filter.F) ([]*ga.Route, error) {
+func (m *MockRoutes) List(ctx context.Context, fl *filter.F) (_ []*ga.Route, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, nil, "ga", "Routes")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("ga"),
+			Service:   "Routes",
+		},
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if m.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(ga.Route{})); err != nil {
+			glog.V(5).Infof("MockRoutes.List(%v, %v) = nil, %v", ctx, fl, err)
+			return nil, err
+		}
+	}
+
 	if m.ListHook != nil {
 		if intercept, objs, err := m.ListHook(m, ctx, fl); intercept {
 			glog.V(5).Infof("MockRoutes.List(%v, %v) = [%v items], %v", ctx, fl, len(objs), err)
@@ -8467,7 +13362,11 @@ func (m *MockRoutes) List(ctx context.Context, fl *filter.F) ([]*ga.Route, error
 	}
 
 	var objs []*ga.Route
-	for _, obj := range m.Objects {
+	for key, obj := range m.Objects {
+		// Objects in other projects are not listed.
+		if key.Project != mockStorageProject(projectID) {
+			continue
+		}
 		if !fl.Match(obj.ToGA()) {
 			continue
 		}
@@ -8479,7 +13378,26 @@ func (m *MockRoutes) List(ctx context.Context, fl *filter.F) ([]*ga.Route, error
 }
 
 // Insert is a mock for inserting/creating a new object.
-func (m *MockRoutes) Insert(ctx context.Context, key meta.Key, obj *ga.Route) error {
+func (m *MockRoutes) Insert(ctx context.Context, key meta.Key, obj *ga.Route) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "Routes")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Insert",
+			Version:   meta.Version("ga"),
+			Service:   "Routes",
+		},
+		Key:     &key,
+		Request: obj,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("global"); err != nil {
+		return err
+	}
+
 	if m.InsertHook != nil {
 		if intercept, err := m.InsertHook(m, ctx, key, obj); intercept {
 			glog.V(5).Infof("MockRoutes.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
@@ -8490,11 +13408,12 @@ func (m *MockRoutes) Insert(ctx context.Context, key meta.Key, obj *ga.Route) er
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.InsertError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.InsertError[skey]; ok {
 		glog.V(5).Infof("MockRoutes.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
 		return err
 	}
-	if _, ok := m.Objects[key]; ok {
+	if _, ok := m.Objects[skey]; ok {
 		err := &googleapi.Error{
 			Code:    http.StatusConflict,
 			Message: fmt.Sprintf("MockRoutes %v exists", key),
@@ -8505,16 +13424,34 @@ func (m *MockRoutes) Insert(ctx context.Context, key meta.Key, obj *ga.Route) er
 
 	obj.Name = key.Name
 	if obj.SelfLink == "" {
-		obj.SelfLink = SelfLink(meta.VersionGA, "mock-project", "routes", key)
+		obj.SelfLink = SelfLink(meta.VersionGA, projectID, "routes", key)
 	}
 
-	m.Objects[key] = &MockRoutesObj{obj}
+	m.Objects[skey] = &MockRoutesObj{obj}
 	glog.V(5).Infof("MockRoutes.Insert(%v, %v, %+v) = nil", ctx, key, obj)
 	return nil
 }
 
 // Delete is a mock for deleting the object.
-func (m *MockRoutes) Delete(ctx context.Context, key meta.Key) error {
+func (m *MockRoutes) Delete(ctx context.Context, key meta.Key) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "Routes")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Delete",
+			Version:   meta.Version("ga"),
+			Service:   "Routes",
+		},
+		Key:     &key,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("global"); err != nil {
+		return err
+	}
+
 	if m.DeleteHook != nil {
 		if intercept, err := m.DeleteHook(m, ctx, key); intercept {
 			glog.V(5).Infof("MockRoutes.Delete(%v, %v) = %v", ctx, key, err)
@@ -8522,14 +13459,22 @@ func (m *MockRoutes) Delete(ctx context.Context, key meta.Key) error {
 		}
 	}
 
+	if m.deleteCheck != nil {
+		if err := m.deleteCheck(NewResourceID("routes", *key.WithProject(projectID))); err != nil {
+			glog.V(5).Infof("MockRoutes.Delete(%v, %v) = %v", ctx, key, err)
+			return err
+		}
+	}
+
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.DeleteError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.DeleteError[skey]; ok {
 		glog.V(5).Infof("MockRoutes.Delete(%v, %v) = %v", ctx, key, err)
 		return err
 	}
-	if _, ok := m.Objects[key]; !ok {
+	if _, ok := m.Objects[skey]; !ok {
 		err := &googleapi.Error{
 			Code:    http.StatusNotFound,
 			Message: fmt.Sprintf("MockRoutes %v not found", key),
@@ -8538,7 +13483,7 @@ func (m *MockRoutes) Delete(ctx context.Context, key meta.Key) error {
 		return err
 	}
 
-	delete(m.Objects, key)
+	delete(m.Objects, skey)
 	glog.V(5).Infof("MockRoutes.Delete(%v, %v) = nil", ctx, key)
 	return nil
 }
@@ -8554,15 +13499,23 @@ type GCERoutes struct {
 }
 
 // Get the Route named by key.
-func (g *GCERoutes) Get(ctx context.Context, key meta.Key) (*ga.Route, error) {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "Routes")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Get",
-		Version:   meta.Version("ga"),
-		Service:   "Routes",
+func (g *GCERoutes) Get(ctx context.Context, key meta.Key) (_ *ga.Route, err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "Routes")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("ga"),
+			Service:   "Routes",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("global"); err != nil {
+		return nil, err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.GA.Routes.Get(projectID, key.Name)
@@ -8571,24 +13524,38 @@ func (g *GCERoutes) Get(ctx context.Context, key meta.Key) (*ga.Route, error) {
 }
 
 // List all Route objects.
-func (g *GCERoutes) List(ctx context.Context, fl *filter.F) ([]*ga.Route, error) {
+func (g *GCERoutes) List(ctx context.Context, fl *filter.F) (_ []*ga.Route, err error) {
 	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "Routes")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "List",
-		Version:   meta.Version("ga"),
-		Service:   "Routes",
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("ga"),
+			Service:   "Routes",
+		},
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if g.s.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(ga.Route{})); err != nil {
+			return nil, err
+		}
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.GA.Routes.List(projectID)
-	if fl != filter.None {
-		call.Filter(fl.String())
+	serverFl, clientFl := fl.Split(reflect.TypeOf(ga.Route{}))
+	if serverFl != filter.None {
+		call.Filter(serverFl.String())
 	}
 	var all []*ga.Route
 	f := func(l *ga.RouteList) error {
-		all = append(all, l.Items...)
+		for _, obj := range l.Items {
+			if clientFl.Match(obj) {
+				all = append(all, obj)
+			}
+		}
 		return nil
 	}
 	if err := call.Pages(ctx, f); err != nil {
@@ -8598,18 +13565,32 @@ func (g *GCERoutes) List(ctx context.Context, fl *filter.F) ([]*ga.Route, error)
 }
 
 // Insert Route with key of value obj.
-func (g *GCERoutes) Insert(ctx context.Context, key meta.Key, obj *ga.Route) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "Routes")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Insert",
-		Version:   meta.Version("ga"),
-		Service:   "Routes",
+func (g *GCERoutes) Insert(ctx context.Context, key meta.Key, obj *ga.Route) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "Routes")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Insert",
+			Version:   meta.Version("ga"),
+			Service:   "Routes",
+		},
+		Key:     &key,
+		Request: obj,
+		Mutates: true,
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("global"); err != nil {
+		return err
+	}
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
 	obj.Name = key.Name
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.GA.Routes.Insert(projectID, obj)
 	call.Context(ctx)
 
@@ -8621,17 +13602,30 @@ func (g *GCERoutes) Insert(ctx context.Context, key meta.Key, obj *ga.Route) err
 }
 
 // Delete the Route referenced by key.
-func (g *GCERoutes) Delete(ctx context.Context, key meta.Key) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "Routes")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Delete",
-		Version:   meta.Version("ga"),
-		Service:   "Routes",
+func (g *GCERoutes) Delete(ctx context.Context, key meta.Key) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "Routes")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Delete",
+			Version:   meta.Version("ga"),
+			Service:   "Routes",
+		},
+		Key:     &key,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("global"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.GA.Routes.Delete(projectID, key.Name)
 
 	call.Context(ctx)
@@ -8685,13 +13679,46 @@ type MockSslCertificates struct {
 	InsertHook func(m *MockSslCertificates, ctx context.Context, key meta.Key, obj *ga.SslCertificate) (bool, error)
 	DeleteHook func(m *MockSslCertificates, ctx context.Context, key meta.Key) (bool, error)
 
+	// Observer, if set, is invoked around each call to the mock.
+	Observer CallObserver
+
+	// ProjectRouter, if set, routes the calls with keys that do not have a
+	// project. Calls are made to "mock-project" if it is not set.
+	ProjectRouter ProjectRouter
+
+	// Debug enables additional checks of the arguments to the mock, such as
+	// the validation of List() filters with filter.Validate().
+	Debug bool
+
+	// deleteCheck, if set, is called by Delete() with the resource being
+	// deleted. Delete() returns the error if it is not nil. See
+	// WithReferenceChecking().
+	deleteCheck func(id *ResourceID) error
+
 	// X is extra state that can be used as part of the mock. Generated code
 	// will not use this field.
 	X interface{}
 }
 
 // Get returns the object from the mock.
-func (m *MockSslCertificates) Get(ctx context.Context, key meta.Key) (*ga.SslCertificate, error) {
+func (m *MockSslCertificates) Get(ctx context.Context, key meta.Key) (_ *ga.SslCertificate, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "SslCertificates")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("ga"),
+			Service:   "SslCertificates",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("global"); err != nil {
+		return nil, err
+	}
+
 	if m.GetHook != nil {
 		if intercept, obj, err := m.GetHook(m, ctx, key); intercept {
 			glog.V(5).Infof("MockSslCertificates.Get(%v, %s) = %+v, %v", ctx, key, obj, err)
@@ -8702,17 +13729,18 @@ func (m *MockSslCertificates) Get(ctx context.Context, key meta.Key) (*ga.SslCer
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.GetError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.GetError[skey]; ok {
 		glog.V(5).Infof("MockSslCertificates.Get(%v, %s) = nil, %v", ctx, key, err)
 		return nil, err
 	}
-	if obj, ok := m.Objects[key]; ok {
+	if obj, ok := m.Objects[skey]; ok {
 		typedObj := obj.ToGA()
 		glog.V(5).Infof("MockSslCertificates.Get(%v, %s) = %+v, nil", ctx, key, typedObj)
 		return typedObj, nil
 	}
 
-	err := &googleapi.Error{
+	err = &googleapi.Error{
 		Code:    http.StatusNotFound,
 		Message: fmt.Sprintf("MockSslCertificates %v not found", key),
 	}
@@ -8721,7 +13749,26 @@ func (m *MockSslCertificates) Get(ctx context.Context, key meta.Key) (*ga.SslCer
 }
 
 // List all of the objects in the mock.
-func (m *MockSslCertificates) List(ctx context.Context, fl *filter.F) ([]*ga.SslCertificate, error) {
+func (m *MockSslCertificates) List(ctx context.Context, fl *filter.F) (_ []*ga.SslCertificate, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, nil, "ga", "SslCertificates")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("ga"),
+			Service:   "SslCertificates",
+		},
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if m.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(ga.SslCertificate{})); err != nil {
+			glog.V(5).Infof("MockSslCertificates.List(%v, %v) = nil, %v", ctx, fl, err)
+			return nil, err
+		}
+	}
+
 	if m.ListHook != nil {
 		if intercept, objs, err := m.ListHook(m, ctx, fl); intercept {
 			glog.V(5).Infof("MockSslCertificates.List(%v, %v) = [%v items], %v", ctx, fl, len(objs), err)
@@ -8740,7 +13787,11 @@ func (m *MockSslCertificates) List(ctx context.Context, fl *filter.F) ([]*ga.Ssl
 	}
 
 	var objs []*ga.SslCertificate
-	for _, obj := range m.Objects {
+	for key, obj := range m.Objects {
+		// Objects in other projects are not listed.
+		if key.Project != mockStorageProject(projectID) {
+			continue
+		}
 		if !fl.Match(obj.ToGA()) {
 			continue
 		}
@@ -8752,7 +13803,26 @@ func (m *MockSslCertificates) List(ctx context.Context, fl *filter.F) ([]*ga.Ssl
 }
 
 // Insert is a mock for inserting/creating a new object.
-func (m *MockSslCertificates) Insert(ctx context.Context, key meta.Key, obj *ga.SslCertificate) error {
+func (m *MockSslCertificates) Insert(ctx context.Context, key meta.Key, obj *ga.SslCertificate) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "SslCertificates")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Insert",
+			Version:   meta.Version("ga"),
+			Service:   "SslCertificates",
+		},
+		Key:     &key,
+		Request: obj,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("global"); err != nil {
+		return err
+	}
+
 	if m.InsertHook != nil {
 		if intercept, err := m.InsertHook(m, ctx, key, obj); intercept {
 			glog.V(5).Infof("MockSslCertificates.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
@@ -8763,11 +13833,12 @@ func (m *MockSslCertificates) Insert(ctx context.Context, key meta.Key, obj *ga.
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.InsertError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.InsertError[skey]; ok {
 		glog.V(5).Infof("MockSslCertificates.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
 		return err
 	}
-	if _, ok := m.Objects[key]; ok {
+	if _, ok := m.Objects[skey]; ok {
 		err := &googleapi.Error{
 			Code:    http.StatusConflict,
 			Message: fmt.Sprintf("MockSslCertificates %v exists", key),
@@ -8778,16 +13849,34 @@ func (m *MockSslCertificates) Insert(ctx context.Context, key meta.Key, obj *ga.
 
 	obj.Name = key.Name
 	if obj.SelfLink == "" {
-		obj.SelfLink = SelfLink(meta.VersionGA, "mock-project", "sslCertificates", key)
+		obj.SelfLink = SelfLink(meta.VersionGA, projectID, "sslCertificates", key)
 	}
 
-	m.Objects[key] = &MockSslCertificatesObj{obj}
+	m.Objects[skey] = &MockSslCertificatesObj{obj}
 	glog.V(5).Infof("MockSslCertificates.Insert(%v, %v, %+v) = nil", ctx, key, obj)
 	return nil
 }
 
 // Delete is a mock for deleting the object.
-func (m *MockSslCertificates) Delete(ctx context.Context, key meta.Key) error {
+func (m *MockSslCertificates) Delete(ctx context.Context, key meta.Key) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "SslCertificates")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Delete",
+			Version:   meta.Version("ga"),
+			Service:   "SslCertificates",
+		},
+		Key:     &key,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("global"); err != nil {
+		return err
+	}
+
 	if m.DeleteHook != nil {
 		if intercept, err := m.DeleteHook(m, ctx, key); intercept {
 			glog.V(5).Infof("MockSslCertificates.Delete(%v, %v) = %v", ctx, key, err)
@@ -8795,14 +13884,22 @@ func (m *MockSslCertificates) Delete(ctx context.Context, key meta.Key) error {
 		}
 	}
 
+	if m.deleteCheck != nil {
+		if err := m.deleteCheck(NewResourceID("sslCertificates", *key.WithProject(projectID))); err != nil {
+			glog.V(5).Infof("MockSslCertificates.Delete(%v, %v) = %v", ctx, key, err)
+			return err
+		}
+	}
+
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.DeleteError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.DeleteError[skey]; ok {
 		glog.V(5).Infof("MockSslCertificates.Delete(%v, %v) = %v", ctx, key, err)
 		return err
 	}
-	if _, ok := m.Objects[key]; !ok {
+	if _, ok := m.Objects[skey]; !ok {
 		err := &googleapi.Error{
 			Code:    http.StatusNotFound,
 			Message: fmt.Sprintf("MockSslCertificates %v not found", key),
@@ -8811,7 +13908,7 @@ func (m *MockSslCertificates) Delete(ctx context.Context, key meta.Key) error {
 		return err
 	}
 
-	delete(m.Objects, key)
+	delete(m.Objects, skey)
 	glog.V(5).Infof("MockSslCertificates.Delete(%v, %v) = nil", ctx, key)
 	return nil
 }
@@ -8827,15 +13924,23 @@ type GCESslCertificates struct {
 }
 
 // Get the SslCertificate named by key.
-func (g *GCESslCertificates) Get(ctx context.Context, key meta.Key) (*ga.SslCertificate, error) {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "SslCertificates")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Get",
-		Version:   meta.Version("ga"),
-		Service:   "SslCertificates",
+func (g *GCESslCertificates) Get(ctx context.Context, key meta.Key) (_ *ga.SslCertificate, err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "SslCertificates")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("ga"),
+			Service:   "SslCertificates",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("global"); err != nil {
+		return nil, err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.GA.SslCertificates.Get(projectID, key.Name)
@@ -8844,24 +13949,38 @@ func (g *GCESslCertificates) Get(ctx context.Context, key meta.Key) (*ga.SslCert
 }
 
 // List all SslCertificate objects.
-func (g *GCESslCertificates) List(ctx context.Context, fl *filter.F) ([]*ga.SslCertificate, error) {
+func (g *GCESslCertificates) List(ctx context.Context, fl *filter.F) (_ []*ga.SslCertificate, err error) {
 	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "SslCertificates")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "List",
-		Version:   meta.Version("ga"),
-		Service:   "SslCertificates",
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("ga"),
+			Service:   "SslCertificates",
+		},
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if g.s.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(ga.SslCertificate{})); err != nil {
+			return nil, err
+		}
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.GA.SslCertificates.List(projectID)
-	if fl != filter.None {
-		call.Filter(fl.String())
+	serverFl, clientFl := fl.Split(reflect.TypeOf(ga.SslCertificate{}))
+	if serverFl != filter.None {
+		call.Filter(serverFl.String())
 	}
 	var all []*ga.SslCertificate
 	f := func(l *ga.SslCertificateList) error {
-		all = append(all, l.Items...)
+		for _, obj := range l.Items {
+			if clientFl.Match(obj) {
+				all = append(all, obj)
+			}
+		}
 		return nil
 	}
 	if err := call.Pages(ctx, f); err != nil {
@@ -8871,18 +13990,32 @@ func (g *GCESslCertificates) List(ctx context.Context, fl *filter.F) ([]*ga.SslC
 }
 
 // Insert SslCertificate with key of value obj.
-func (g *GCESslCertificates) Insert(ctx context.Context, key meta.Key, obj *ga.SslCertificate) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "SslCertificates")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Insert",
-		Version:   meta.Version("ga"),
-		Service:   "SslCertificates",
+func (g *GCESslCertificates) Insert(ctx context.Context, key meta.Key, obj *ga.SslCertificate) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "SslCertificates")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Insert",
+			Version:   meta.Version("ga"),
+			Service:   "SslCertificates",
+		},
+		Key:     &key,
+		Request: obj,
+		Mutates: true,
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("global"); err != nil {
+		return err
+	}
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
 	obj.Name = key.Name
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.GA.SslCertificates.Insert(projectID, obj)
 	call.Context(ctx)
 
@@ -8894,17 +14027,30 @@ func (g *GCESslCertificates) Insert(ctx context.Context, key meta.Key, obj *ga.S
 }
 
 // Delete the SslCertificate referenced by key.
-func (g *GCESslCertificates) Delete(ctx context.Context, key meta.Key) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "SslCertificates")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Delete",
-		Version:   meta.Version("ga"),
-		Service:   "SslCertificates",
+func (g *GCESslCertificates) Delete(ctx context.Context, key meta.Key) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "SslCertificates")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Delete",
+			Version:   meta.Version("ga"),
+			Service:   "SslCertificates",
+		},
+		Key:     &key,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("global"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.GA.SslCertificates.Delete(projectID, key.Name)
 
 	call.Context(ctx)
@@ -8960,13 +14106,46 @@ type MockTargetHttpProxies struct {
 	DeleteHook    func(m *MockTargetHttpProxies, ctx context.Context, key meta.Key) (bool, error)
 	SetUrlMapHook func(*MockTargetHttpProxies, context.Context, meta.Key, *ga.UrlMapReference) error
 
+	// Observer, if set, is invoked around each call to the mock.
+	Observer CallObserver
+
+	// ProjectRouter, if set, routes the calls with keys that do not have a
+	// project. Calls are made to "mock-project" if it is not set.
+	ProjectRouter ProjectRouter
+
+	// Debug enables additional checks of the arguments to the mock, such as
+	// the validation of List() filters with filter.Validate().
+	Debug bool
+
+	// deleteCheck, if set, is called by Delete() with the resource being
+	// deleted. Delete() returns the error if it is not nil. See
+	// WithReferenceChecking().
+	deleteCheck func(id *ResourceID) error
+
 	// X is extra state that can be used as part of the mock. Generated code
 	// will not use this field.
 	X interface{}
 }
 
 // Get returns the object from the mock.
-func (m *MockTargetHttpProxies) Get(ctx context.Context, key meta.Key) (*ga.TargetHttpProxy, error) {
+func (m *MockTargetHttpProxies) Get(ctx context.Context, key meta.Key) (_ *ga.TargetHttpProxy, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "TargetHttpProxies")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("ga"),
+			Service:   "TargetHttpProxies",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("global"); err != nil {
+		return nil, err
+	}
+
 	if m.GetHook != nil {
 		if intercept, obj, err := m.GetHook(m, ctx, key); intercept {
 			glog.V(5).Infof("MockTargetHttpProxies.Get(%v, %s) = %+v, %v", ctx, key, obj, err)
@@ -8977,17 +14156,18 @@ func (m *MockTargetHttpProxies) Get(ctx context.Context, key meta.Key) (*ga.Targ
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.GetError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.GetError[skey]; ok {
 		glog.V(5).Infof("MockTargetHttpProxies.Get(%v, %s) = nil, %v", ctx, key, err)
 		return nil, err
 	}
-	if obj, ok := m.Objects[key]; ok {
+	if obj, ok := m.Objects[skey]; ok {
 		typedObj := obj.ToGA()
 		glog.V(5).Infof("MockTargetHttpProxies.Get(%v, %s) = %+v, nil", ctx, key, typedObj)
 		return typedObj, nil
 	}
 
-	err := &googleapi.Error{
+	err = &googleapi.Error{
 		Code:    http.StatusNotFound,
 		Message: fmt.Sprintf("MockTargetHttpProxies %v not found", key),
 	}
@@ -8996,7 +14176,26 @@ func (m *MockTargetHttpProxies) Get(ctx context.Context, key meta.Key) (*ga.Targ
 }
 
 // List all of the objects in the mock.
-func (m *MockTargetHttpProxies) List(ctx context.Context, fl *filter.F) ([]*ga.TargetHttpProxy, error) {
+func (m *MockTargetHttpProxies) List(ctx context.Context, fl *filter.F) (_ []*ga.TargetHttpProxy, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, nil, "ga", "TargetHttpProxies")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("ga"),
+			Service:   "TargetHttpProxies",
+		},
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if m.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(ga.TargetHttpProxy{})); err != nil {
+			glog.V(5).Infof("MockTargetHttpProxies.List(%v, %v) = nil, %v", ctx, fl, err)
+			return nil, err
+		}
+	}
+
 	if m.ListHook != nil {
 		if intercept, objs, err := m.ListHook(m, ctx, fl); intercept {
 			glog.V(5).Infof("MockTargetHttpProxies.List(%v, %v) = [%v items], %v", ctx, fl, len(objs), err)
@@ -9015,7 +14214,11 @@ func (m *MockTargetHttpProxies) List(ctx context.Context, fl *filter.F) ([]*ga.T
 	}
 
 	var objs []*ga.TargetHttpProxy
-	for _, obj := range m.Objects {
+	for key, obj := range m.Objects {
+		// Objects in other projects are not listed.
+		if key.Project != mockStorageProject(projectID) {
+			continue
+		}
 		if !fl.Match(obj.ToGA()) {
 			continue
 		}
@@ -9027,7 +14230,26 @@ func (m *MockTargetHttpProxies) List(ctx context.Context, fl *filter.F) ([]*ga.T
 }
 
 // Insert is a mock for inserting/creating a new object.
-func (m *MockTargetHttpProxies) Insert(ctx context.Context, key meta.Key, obj *ga.TargetHttpProxy) error {
+func (m *MockTargetHttpProxies) Insert(ctx context.Context, key meta.Key, obj *ga.TargetHttpProxy) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "TargetHttpProxies")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Insert",
+			Version:   meta.Version("ga"),
+			Service:   "TargetHttpProxies",
+		},
+		Key:     &key,
+		Request: obj,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("global"); err != nil {
+		return err
+	}
+
 	if m.InsertHook != nil {
 		if intercept, err := m.InsertHook(m, ctx, key, obj); intercept {
 			glog.V(5).Infof("MockTargetHttpProxies.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
@@ -9038,11 +14260,12 @@ func (m *MockTargetHttpProxies) Insert(ctx context.Context, key meta.Key, obj *g
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.InsertError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.InsertError[skey]; ok {
 		glog.V(5).Infof("MockTargetHttpProxies.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
 		return err
 	}
-	if _, ok := m.Objects[key]; ok {
+	if _, ok := m.Objects[skey]; ok {
 		err := &googleapi.Error{
 			Code:    http.StatusConflict,
 			Message: fmt.Sprintf("MockTargetHttpProxies %v exists", key),
@@ -9053,16 +14276,34 @@ func (m *MockTargetHttpProxies) Insert(ctx context.Context, key meta.Key, obj *g
 
 	obj.Name = key.Name
 	if obj.SelfLink == "" {
-		obj.SelfLink = SelfLink(meta.VersionGA, "mock-project", "targetHttpProxies", key)
+		obj.SelfLink = SelfLink(meta.VersionGA, projectID, "targetHttpProxies", key)
 	}
 
-	m.Objects[key] = &MockTargetHttpProxiesObj{obj}
+	m.Objects[skey] = &MockTargetHttpProxiesObj{obj}
 	glog.V(5).Infof("MockTargetHttpProxies.Insert(%v, %v, %+v) = nil", ctx, key, obj)
 	return nil
 }
 
 // Delete is a mock for deleting the object.
-func (m *MockTargetHttpProxies) Delete(ctx context.Context, key meta.Key) error {
+func (m *MockTargetHttpProxies) Delete(ctx context.Context, key meta.Key) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "TargetHttpProxies")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Delete",
+			Version:   meta.Version("ga"),
+			Service:   "TargetHttpProxies",
+		},
+		Key:     &key,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("global"); err != nil {
+		return err
+	}
+
 	if m.DeleteHook != nil {
 		if intercept, err := m.DeleteHook(m, ctx, key); intercept {
 			glog.V(5).Infof("MockTargetHttpProxies.Delete(%v, %v) = %v", ctx, key, err)
@@ -9070,14 +14311,22 @@ func (m *MockTargetHttpProxies) Delete(ctx context.Context, key meta.Key) error
 		}
 	}
 
+	if m.deleteCheck != nil {
+		if err := m.deleteCheck(NewResourceID("targetHttpProxies", *key.WithProject(projectID))); err != nil {
+			glog.V(5).Infof("MockTargetHttpProxies.Delete(%v, %v) = %v", ctx, key, err)
+			return err
+		}
+	}
+
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.DeleteError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.DeleteError[skey]; ok {
 		glog.V(5).Infof("MockTargetHttpProxies.Delete(%v, %v) = %v", ctx, key, err)
 		return err
 	}
-	if _, ok := m.Objects[key]; !ok {
+	if _, ok := m.Objects[skey]; !ok {
 		err := &googleapi.Error{
 			Code:    http.StatusNotFound,
 			Message: fmt.Sprintf("MockTargetHttpProxies %v not found", key),
@@ -9086,7 +14335,7 @@ func (m *MockTargetHttpProxies) Delete(ctx context.Context, key meta.Key) error
 		return err
 	}
 
-	delete(m.Objects, key)
+	delete(m.Objects, skey)
 	glog.V(5).Infof("MockTargetHttpProxies.Delete(%v, %v) = nil", ctx, key)
 	return nil
 }
@@ -9097,7 +14346,26 @@ func (m *MockTargetHttpProxies) Obj(o *ga.TargetHttpProxy) *MockTargetHttpProxie
 }
 
 // SetUrlMap is a mock for the corresponding method.
-func (m *MockTargetHttpProxies) SetUrlMap(ctx context.Context, key meta.Key, arg0 *ga.UrlMapReference) error {
+func (m *MockTargetHttpProxies) SetUrlMap(ctx context.Context, key meta.Key, arg0 *ga.UrlMapReference) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "TargetHttpProxies")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "SetUrlMap",
+			Version:   meta.Version("ga"),
+			Service:   "TargetHttpProxies",
+		},
+		Key:     &key,
+		Request: arg0,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("global"); err != nil {
+		return err
+	}
+
 	if m.SetUrlMapHook != nil {
 		return m.SetUrlMapHook(m, ctx, key, arg0)
 	}
@@ -9110,15 +14378,23 @@ type GCETargetHttpProxies struct {
 }
 
 // Get the TargetHttpProxy named by key.
-func (g *GCETargetHttpProxies) Get(ctx context.Context, key meta.Key) (*ga.TargetHttpProxy, error) {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "TargetHttpProxies")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Get",
-		Version:   meta.Version("ga"),
-		Service:   "TargetHttpProxies",
+func (g *GCETargetHttpProxies) Get(ctx context.Context, key meta.Key) (_ *ga.TargetHttpProxy, err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "TargetHttpProxies")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("ga"),
+			Service:   "TargetHttpProxies",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("global"); err != nil {
+		return nil, err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.GA.TargetHttpProxies.Get(projectID, key.Name)
@@ -9127,24 +14403,38 @@ func (g *GCETargetHttpProxies) Get(ctx context.Context, key meta.Key) (*ga.Targe
 }
 
 // List all TargetHttpProxy objects.
-func (g *GCETargetHttpProxies) List(ctx context.Context, fl *filter.F) ([]*ga.TargetHttpProxy, error) {
+func (g *GCETargetHttpProxies) List(ctx context.Context, fl *filter.F) (_ []*ga.TargetHttpProxy, err error) {
 	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "TargetHttpProxies")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "List",
-		Version:   meta.Version("ga"),
-		Service:   "TargetHttpProxies",
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("ga"),
+			Service:   "TargetHttpProxies",
+		},
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if g.s.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(ga.TargetHttpProxy{})); err != nil {
+			return nil, err
+		}
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.GA.TargetHttpProxies.List(projectID)
-	if fl != filter.None {
-		call.Filter(fl.String())
+	serverFl, clientFl := fl.Split(reflect.TypeOf(ga.TargetHttpProxy{}))
+	if serverFl != filter.None {
+		call.Filter(serverFl.String())
 	}
 	var all []*ga.TargetHttpProxy
 	f := func(l *ga.TargetHttpProxyList) error {
-		all = append(all, l.Items...)
+		for _, obj := range l.Items {
+			if clientFl.Match(obj) {
+				all = append(all, obj)
+			}
+		}
 		return nil
 	}
 	if err := call.Pages(ctx, f); err != nil {
@@ -9154,18 +14444,32 @@ func (g *GCETargetHttpProxies) List(ctx context.Context, fl *filter.F) ([]*ga.Ta
 }
 
 // Insert TargetHttpProxy with key of value obj.
-func (g *GCETargetHttpProxies) Insert(ctx context.Context, key meta.Key, obj *ga.TargetHttpProxy) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "TargetHttpProxies")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Insert",
-		Version:   meta.Version("ga"),
-		Service:   "TargetHttpProxies",
+func (g *GCETargetHttpProxies) Insert(ctx context.Context, key meta.Key, obj *ga.TargetHttpProxy) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "TargetHttpProxies")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Insert",
+			Version:   meta.Version("ga"),
+			Service:   "TargetHttpProxies",
+		},
+		Key:     &key,
+		Request: obj,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("global"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
 	obj.Name = key.Name
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.GA.TargetHttpProxies.Insert(projectID, obj)
 	call.Context(ctx)
 
@@ -9177,17 +14481,30 @@ func (g *GCETargetHttpProxies) Insert(ctx context.Context, key meta.Key, obj *ga
 }
 
 // Delete the TargetHttpProxy referenced by key.
-func (g *GCETargetHttpProxies) Delete(ctx context.Context, key meta.Key) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "TargetHttpProxies")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Delete",
-		Version:   meta.Version("ga"),
-		Service:   "TargetHttpProxies",
+func (g *GCETargetHttpProxies) Delete(ctx context.Context, key meta.Key) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "TargetHttpProxies")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Delete",
+			Version:   meta.Version("ga"),
+			Service:   "TargetHttpProxies",
+		},
+		Key:     &key,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("global"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.GA.TargetHttpProxies.Delete(projectID, key.Name)
 
 	call.Context(ctx)
@@ -9200,17 +14517,31 @@ func (g *GCETargetHttpProxies) Delete(ctx context.Context, key meta.Key) error {
 }
 
 // SetUrlMap is a method on GCETargetHttpProxies.
-func (g *GCETargetHttpProxies) SetUrlMap(ctx context.Context, key meta.Key, arg0 *ga.UrlMapReference) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "TargetHttpProxies")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "SetUrlMap",
-		Version:   meta.Version("ga"),
-		Service:   "TargetHttpProxies",
+func (g *GCETargetHttpProxies) SetUrlMap(ctx context.Context, key meta.Key, arg0 *ga.UrlMapReference) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "TargetHttpProxies")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "SetUrlMap",
+			Version:   meta.Version("ga"),
+			Service:   "TargetHttpProxies",
+		},
+		Key:     &key,
+		Request: arg0,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("global"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.GA.TargetHttpProxies.SetUrlMap(projectID, key.Name, arg0)
 	call.Context(ctx)
 	op, err := call.Do()
@@ -9266,13 +14597,46 @@ type MockTargetHttpsProxies struct {
 	SetSslCertificatesHook func(*MockTargetHttpsProxies, context.Context, meta.Key, *ga.TargetHttpsProxiesSetSslCertificatesRequest) error
 	SetUrlMapHook          func(*MockTargetHttpsProxies, context.Context, meta.Key, *ga.UrlMapReference) error
 
+	// Observer, if set, is invoked around each call to the mock.
+	Observer CallObserver
+
+	// ProjectRouter, if set, routes the calls with keys that do not have a
+	// project. Calls are made to "mock-project" if it is not set.
+	ProjectRouter ProjectRouter
+
+	// Debug enables additional checks of the arguments to the mock, such as
+	// the validation of List() filters with filter.Validate().
+	Debug bool
+
+	// deleteCheck, if set, is called by Delete() with the resource being
+	// deleted. Delete() returns the error if it is not nil. See
+	// WithReferenceChecking().
+	deleteCheck func(id *ResourceID) error
+
 	// X is extra state that can be used as part of the mock. Generated code
 	// will not use this field.
 	X interface{}
 }
 
 // Get returns the object from the mock.
-func (m *MockTargetHttpsProxies) Get(ctx context.Context, key meta.Key) (*ga.TargetHttpsProxy, error) {
+func (m *MockTargetHttpsProxies) Get(ctx context.Context, key meta.Key) (_ *ga.TargetHttpsProxy, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "TargetHttpsProxies")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("ga"),
+			Service:   "TargetHttpsProxies",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("global"); err != nil {
+		return nil, err
+	}
+
 	if m.GetHook != nil {
 		if intercept, obj, err := m.GetHook(m, ctx, key); intercept {
 			glog.V(5).Infof("MockTargetHttpsProxies.Get(%v, %s) = %+v, %v", ctx, key, obj, err)
@@ -9283,17 +14647,18 @@ func (m *MockTargetHttpsProxies) Get(ctx context.Context, key meta.Key) (*ga.Tar
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.GetError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.GetError[skey]; ok {
 		glog.V(5).Infof("MockTargetHttpsProxies.Get(%v, %s) = nil, %v", ctx, key, err)
 		return nil, err
 	}
-	if obj, ok := m.Objects[key]; ok {
+	if obj, ok := m.Objects[skey]; ok {
 		typedObj := obj.ToGA()
 		glog.V(5).Infof("MockTargetHttpsProxies.Get(%v, %s) = %+v, nil", ctx, key, typedObj)
 		return typedObj, nil
 	}
 
-	err := &googleapi.Error{
+	err = &googleapi.Error{
 		Code:    http.StatusNotFound,
 		Message: fmt.Sprintf("MockTargetHttpsProxies %v not found", key),
 	}
@@ -9302,9 +14667,28 @@ func (m *MockTargetHttpsProxies) Get(ctx context.Context, key meta.Key) (*ga.Tar
 }
 
 // List all of the objects in the mock.
-func (m *MockTargetHttpsProxies) List(ctx context.Context, fl *filter.F) ([]*ga.TargetHttpsProxy, error) {
-	if m.ListHook != nil {
-		if intercept, objs, err := m.ListHook(m, ctx, fl); intercept {
+func (m *MockTargetHttpsProxies) List(ctx context.Context, fl *filter.F) (_ []*ga.TargetHttpsProxy, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, nil, "ga", "TargetHttpsProxies")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("ga"),
+			Service:   "TargetHttpsProxies",
+		},
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if m.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(ga.TargetHttpsProxy{})); err != nil {
+			glog.V(5).Infof("MockTargetHttpsProxies.List(%v, %v) = nil, %v", ctx, fl, err)
+			return nil, err
+		}
+	}
+
+	if m.ListHook != nil {
+		if intercept, objs, err := m.ListHook(m, ctx, fl); intercept {
 			glog.V(5).Infof("MockTargetHttpsProxies.List(%v, %v) = [%v items], %v", ctx, fl, len(objs), err)
 			return objs, err
 		}
@@ -9321,7 +14705,11 @@ func (m *MockTargetHttpsProxies) List(ctx context.Context, fl *filter.F) ([]*ga.
 	}
 
 	var objs []*ga.TargetHttpsProxy
-	for _, obj := range m.Objects {
+	for key, obj := range m.Objects {
+		// Objects in other projects are not listed.
+		if key.Project != mockStorageProject(projectID) {
+			continue
+		}
 		if !fl.Match(obj.ToGA()) {
 			continue
 		}
@@ -9333,7 +14721,26 @@ func (m *MockTargetHttpsProxies) List(ctx context.Context, fl *filter.F) ([]*ga.
 }
 
 // Insert is a mock for inserting/creating a new object.
-func (m *MockTargetHttpsProxies) Insert(ctx context.Context, key meta.Key, obj *ga.TargetHttpsProxy) error {
+func (m *MockTargetHttpsProxies) Insert(ctx context.Context, key meta.Key, obj *ga.TargetHttpsProxy) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "TargetHttpsProxies")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Insert",
+			Version:   meta.Version("ga"),
+			Service:   "TargetHttpsProxies",
+		},
+		Key:     &key,
+		Request: obj,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("global"); err != nil {
+		return err
+	}
+
 	if m.InsertHook != nil {
 		if intercept, err := m.InsertHook(m, ctx, key, obj); intercept {
 			glog.V(5).Infof("MockTargetHttpsProxies.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
@@ -9344,11 +14751,12 @@ func (m *MockTargetHttpsProxies) Insert(ctx context.Context, key meta.Key, obj *
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.InsertError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.InsertError[skey]; ok {
 		glog.V(5).Infof("MockTargetHttpsProxies.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
 		return err
 	}
-	if _, ok := m.Objects[key]; ok {
+	if _, ok := m.Objects[skey]; ok {
 		err := &googleapi.Error{
 			Code:    http.StatusConflict,
 			Message: fmt.Sprintf("MockTargetHttpsProxies %v exists", key),
@@ -9359,16 +14767,34 @@ func (m *MockTargetHttpsProxies) Insert(ctx context.Context, key meta.Key, obj *
 
 	obj.Name = key.Name
 	if obj.SelfLink == "" {
-		obj.SelfLink = SelfLink(meta.VersionGA, "mock-project", "targetHttpsProxies", key)
+		obj.SelfLink = SelfLink(meta.VersionGA, projectID, "targetHttpsProxies", key)
 	}
 
-	m.Objects[key] = &MockTargetHttpsProxiesObj{obj}
+	m.Objects[skey] = &MockTargetHttpsProxiesObj{obj}
 	glog.V(5).Infof("MockTargetHttpsProxies.Insert(%v, %v, %+v) = nil", ctx, key, obj)
 	return nil
 }
 
 // Delete is a mock for deleting the object.
-func (m *MockTargetHttpsProxies) Delete(ctx context.Context, key meta.Key) error {
+func (m *MockTargetHttpsProxies) Delete(ctx context.Context, key meta.Key) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "TargetHttpsProxies")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Delete",
+			Version:   meta.Version("ga"),
+			Service:   "TargetHttpsProxies",
+		},
+		Key:     &key,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("global"); err != nil {
+		return err
+	}
+
 	if m.DeleteHook != nil {
 		if intercept, err := m.DeleteHook(m, ctx, key); intercept {
 			glog.V(5).Infof("MockTargetHttpsProxies.Delete(%v, %v) = %v", ctx, key, err)
@@ -9376,14 +14802,22 @@ func (m *MockTargetHttpsProxies) Delete(ctx context.Context, key meta.Key) error
 		}
 	}
 
+	if m.deleteCheck != nil {
+		if err := m.deleteCheck(NewResourceID("targetHttpsProxies", *key.WithProject(projectID))); err != nil {
+			glog.V(5).Infof("MockTargetHttpsProxies.Delete(%v, %v) = %v", ctx, key, err)
+			return err
+		}
+	}
+
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.DeleteError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.DeleteError[skey]; ok {
 		glog.V(5).Infof("MockTargetHttpsProxies.Delete(%v, %v) = %v", ctx, key, err)
 		return err
 	}
-	if _, ok := m.Objects[key]; !ok {
+	if _, ok := m.Objects[skey]; !ok {
 		err := &googleapi.Error{
 			Code:    http.StatusNotFound,
 			Message: fmt.Sprintf("MockTargetHttpsProxies %v not found", key),
@@ -9392,7 +14826,7 @@ func (m *MockTargetHttpsProxies) Delete(ctx context.Context, key meta.Key) error
 		return err
 	}
 
-	delete(m.Objects, key)
+	delete(m.Objects, skey)
 	glog.V(5).Infof("MockTargetHttpsProxies.Delete(%v, %v) = nil", ctx, key)
 	return nil
 }
@@ -9403,7 +14837,26 @@ func (m *MockTargetHttpsProxies) Obj(o *ga.TargetHttpsProxy) *MockTargetHttpsPro
 }
 
 // SetSslCertificates is a mock for the corresponding method.
-func (m *MockTargetHttpsProxies) SetSslCertificates(ctx context.Context, key meta.Key, arg0 *ga.TargetHttpsProxiesSetSslCertificatesRequest) error {
+func (m *MockTargetHttpsProxies) SetSslCertificates(ctx context.Context, key meta.Key, arg0 *ga.TargetHttpsProxiesSetSslCertificatesRequest) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "TargetHttpsProxies")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "SetSslCertificates",
+			Version:   meta.Version("ga"),
+			Service:   "TargetHttpsProxies",
+		},
+		Key:     &key,
+		Request: arg0,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("global"); err != nil {
+		return err
+	}
+
 	if m.SetSslCertificatesHook != nil {
 		return m.SetSslCertificatesHook(m, ctx, key, arg0)
 	}
@@ -9411,7 +14864,26 @@ func (m *MockTargetHttpsProxies) SetSslCertificates(ctx context.Context, key met
 }
 
 // SetUrlMap is a mock for the corresponding method.
-func (m *MockTargetHttpsProxies) SetUrlMap(ctx context.Context, key meta.Key, arg0 *ga.UrlMapReference) error {
+func (m *MockTargetHttpsProxies) SetUrlMap(ctx context.Context, key meta.Key, arg0 *ga.UrlMapReference) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "TargetHttpsProxies")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "SetUrlMap",
+			Version:   meta.Version("ga"),
+			Service:   "TargetHttpsProxies",
+		},
+		Key:     &key,
+		Request: arg0,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("global"); err != nil {
+		return err
+	}
+
 	if m.SetUrlMapHook != nil {
 		return m.SetUrlMapHook(m, ctx, key, arg0)
 	}
@@ -9424,15 +14896,23 @@ type GCETargetHttpsProxies struct {
 }
 
 // Get the TargetHttpsProxy named by key.
-func (g *GCETargetHttpsProxies) Get(ctx context.Context, key meta.Key) (*ga.TargetHttpsProxy, error) {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "TargetHttpsProxies")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Get",
-		Version:   meta.Version("ga"),
-		Service:   "TargetHttpsProxies",
+func (g *GCETargetHttpsProxies) Get(ctx context.Context, key meta.Key) (_ *ga.TargetHttpsProxy, err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "TargetHttpsProxies")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("ga"),
+			Service:   "TargetHttpsProxies",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("global"); err != nil {
+		return nil, err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.GA.TargetHttpsProxies.Get(projectID, key.Name)
@@ -9441,24 +14921,38 @@ func (g *GCETargetHttpsProxies) Get(ctx context.Context, key meta.Key) (*ga.Targ
 }
 
 // List all TargetHttpsProxy objects.
-func (g *GCETargetHttpsProxies) List(ctx context.Context, fl *filter.F) ([]*ga.TargetHttpsProxy, error) {
+func (g *GCETargetHttpsProxies) List(ctx context.Context, fl *filter.F) (_ []*ga.TargetHttpsProxy, err error) {
 	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "TargetHttpsProxies")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "List",
-		Version:   meta.Version("ga"),
-		Service:   "TargetHttpsProxies",
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("ga"),
+			Service:   "TargetHttpsProxies",
+		},
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if g.s.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(ga.TargetHttpsProxy{})); err != nil {
+			return nil, err
+		}
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.GA.TargetHttpsProxies.List(projectID)
-	if fl != filter.None {
-		call.Filter(fl.String())
+	serverFl, clientFl := fl.Split(reflect.TypeOf(ga.TargetHttpsProxy{}))
+	if serverFl != filter.None {
+		call.Filter(serverFl.String())
 	}
 	var all []*ga.TargetHttpsProxy
 	f := func(l *ga.TargetHttpsProxyList) error {
-		all = append(all, l.Items...)
+		for _, obj := range l.Items {
+			if clientFl.Match(obj) {
+				all = append(all, obj)
+			}
+		}
 		return nil
 	}
 	if err := call.Pages(ctx, f); err != nil {
@@ -9468,18 +14962,32 @@ func (g *GCETargetHttpsProxies) List(ctx context.Context, fl *filter.F) ([]*ga.T
 }
 
 // Insert TargetHttpsProxy with key of value obj.
-func (g *GCETargetHttpsProxies) Insert(ctx context.Context, key meta.Key, obj *ga.TargetHttpsProxy) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "TargetHttpsProxies")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Insert",
-		Version:   meta.Version("ga"),
-		Service:   "TargetHttpsProxies",
+func (g *GCETargetHttpsProxies) Insert(ctx context.Context, key meta.Key, obj *ga.TargetHttpsProxy) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "TargetHttpsProxies")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Insert",
+			Version:   meta.Version("ga"),
+			Service:   "TargetHttpsProxies",
+		},
+		Key:     &key,
+		Request: obj,
+		Mutates: true,
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("global"); err != nil {
+		return err
+	}
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
 	obj.Name = key.Name
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.GA.TargetHttpsProxies.Insert(projectID, obj)
 	call.Context(ctx)
 
@@ -9491,17 +14999,30 @@ func (g *GCETargetHttpsProxies) Insert(ctx context.Context, key meta.Key, obj *g
 }
 
 // Delete the TargetHttpsProxy referenced by key.
-func (g *GCETargetHttpsProxies) Delete(ctx context.Context, key meta.Key) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "TargetHttpsProxies")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Delete",
-		Version:   meta.Version("ga"),
-		Service:   "TargetHttpsProxies",
+func (g *GCETargetHttpsProxies) Delete(ctx context.Context, key meta.Key) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "TargetHttpsProxies")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Delete",
+			Version:   meta.Version("ga"),
+			Service:   "TargetHttpsProxies",
+		},
+		Key:     &key,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("global"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.GA.TargetHttpsProxies.Delete(projectID, key.Name)
 
 	call.Context(ctx)
@@ -9514,17 +15035,31 @@ func (g *GCETargetHttpsProxies) Delete(ctx context.Context, key meta.Key) error
 }
 
 // SetSslCertificates is a method on GCETargetHttpsProxies.
-func (g *GCETargetHttpsProxies) SetSslCertificates(ctx context.Context, key meta.Key, arg0 *ga.TargetHttpsProxiesSetSslCertificatesRequest) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "TargetHttpsProxies")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "SetSslCertificates",
-		Version:   meta.Version("ga"),
-		Service:   "TargetHttpsProxies",
+func (g *GCETargetHttpsProxies) SetSslCertificates(ctx context.Context, key meta.Key, arg0 *ga.TargetHttpsProxiesSetSslCertificatesRequest) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "TargetHttpsProxies")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "SetSslCertificates",
+			Version:   meta.Version("ga"),
+			Service:   "TargetHttpsProxies",
+		},
+		Key:     &key,
+		Request: arg0,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("global"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.GA.TargetHttpsProxies.SetSslCertificates(projectID, key.Name, arg0)
 	call.Context(ctx)
 	op, err := call.Do()
@@ -9535,17 +15070,31 @@ func (g *GCETargetHttpsProxies) SetSslCertificates(ctx context.Context, key meta
 }
 
 // SetUrlMap is a method on GCETargetHttpsProxies.
-func (g *GCETargetHttpsProxies) SetUrlMap(ctx context.Context, key meta.Key, arg0 *ga.UrlMapReference) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "TargetHttpsProxies")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "SetUrlMap",
-		Version:   meta.Version("ga"),
-		Service:   "TargetHttpsProxies",
+func (g *GCETargetHttpsProxies) SetUrlMap(ctx context.Context, key meta.Key, arg0 *ga.UrlMapReference) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "TargetHttpsProxies")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "SetUrlMap",
+			Version:   meta.Version("ga"),
+			Service:   "TargetHttpsProxies",
+		},
+		Key:     &key,
+		Request: arg0,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("global"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.GA.TargetHttpsProxies.SetUrlMap(projectID, key.Name, arg0)
 	call.Context(ctx)
 	op, err := call.Do()
@@ -9601,13 +15150,46 @@ type MockTargetPools struct {
 	AddInstanceHook    func(*MockTargetPools, context.Context, meta.Key, *ga.TargetPoolsAddInstanceRequest) error
 	RemoveInstanceHook func(*MockTargetPools, context.Context, meta.Key, *ga.TargetPoolsRemoveInstanceRequest) error
 
+	// Observer, if set, is invoked around each call to the mock.
+	Observer CallObserver
+
+	// ProjectRouter, if set, routes the calls with keys that do not have a
+	// project. Calls are made to "mock-project" if it is not set.
+	ProjectRouter ProjectRouter
+
+	// Debug enables additional checks of the arguments to the mock, such as
+	// the validation of List() filters with filter.Validate().
+	Debug bool
+
+	// deleteCheck, if set, is called by Delete() with the resource being
+	// deleted. Delete() returns the error if it is not nil. See
+	// WithReferenceChecking().
+	deleteCheck func(id *ResourceID) error
+
 	// X is extra state that can be used as part of the mock. Generated code
 	// will not use this field.
 	X interface{}
 }
 
 // Get returns the object from the mock.
-func (m *MockTargetPools) Get(ctx context.Context, key meta.Key) (*ga.TargetPool, error) {
+func (m *MockTargetPools) Get(ctx context.Context, key meta.Key) (_ *ga.TargetPool, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "TargetPools")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("ga"),
+			Service:   "TargetPools",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("regional"); err != nil {
+		return nil, err
+	}
+
 	if m.GetHook != nil {
 		if intercept, obj, err := m.GetHook(m, ctx, key); intercept {
 			glog.V(5).Infof("MockTargetPools.Get(%v, %s) = %+v, %v", ctx, key, obj, err)
@@ -9618,17 +15200,18 @@ func (m *MockTargetPools) Get(ctx context.Context, key meta.Key) (*ga.TargetPool
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.GetError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.GetError[skey]; ok {
 		glog.V(5).Infof("MockTargetPools.Get(%v, %s) = nil, %v", ctx, key, err)
 		return nil, err
 	}
-	if obj, ok := m.Objects[key]; ok {
+	if obj, ok := m.Objects[skey]; ok {
 		typedObj := obj.ToGA()
 		glog.V(5).Infof("MockTargetPools.Get(%v, %s) = %+v, nil", ctx, key, typedObj)
 		return typedObj, nil
 	}
 
-	err := &googleapi.Error{
+	err = &googleapi.Error{
 		Code:    http.StatusNotFound,
 		Message: fmt.Sprintf("MockTargetPools %v not found", key),
 	}
@@ -9637,7 +15220,26 @@ func (m *MockTargetPools) Get(ctx context.Context, key meta.Key) (*ga.TargetPool
 }
 
 // List all of the objects in the mock in the given region.
-func (m *MockTargetPools) List(ctx context.Context, region string, fl *filter.F) ([]*ga.TargetPool, error) {
+func (m *MockTargetPools) List(ctx context.Context, region string, fl *filter.F) (_ []*ga.TargetPool, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, nil, "ga", "TargetPools")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("ga"),
+			Service:   "TargetPools",
+		},
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if m.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(ga.TargetPool{})); err != nil {
+			glog.V(5).Infof("MockTargetPools.List(%v, %v) = nil, %v", ctx, fl, err)
+			return nil, err
+		}
+	}
+
 	if m.ListHook != nil {
 		if intercept, objs, err := m.ListHook(m, ctx, region, fl); intercept {
 			glog.V(5).Infof("MockTargetPools.List(%v, %q, %v) = [%v items], %v", ctx, region, fl, len(objs), err)
@@ -9657,6 +15259,10 @@ func (m *MockTargetPools) List(ctx context.Context, region string, fl *filter.F)
 
 	var objs []*ga.TargetPool
 	for key, obj := range m.Objects {
+		// Objects in other projects are not listed.
+		if key.Project != mockStorageProject(projectID) {
+			continue
+		}
 		if key.Region != region {
 			continue
 		}
@@ -9671,7 +15277,26 @@ func (m *MockTargetPools) List(ctx context.Context, region string, fl *filter.F)
 }
 
 // Insert is a mock for inserting/creating a new object.
-func (m *MockTargetPools) Insert(ctx context.Context, key meta.Key, obj *ga.TargetPool) error {
+func (m *MockTargetPools) Insert(ctx context.Context, key meta.Key, obj *ga.TargetPool) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "TargetPools")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Insert",
+			Version:   meta.Version("ga"),
+			Service:   "TargetPools",
+		},
+		Key:     &key,
+		Request: obj,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("regional"); err != nil {
+		return err
+	}
+
 	if m.InsertHook != nil {
 		if intercept, err := m.InsertHook(m, ctx, key, obj); intercept {
 			glog.V(5).Infof("MockTargetPools.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
@@ -9682,11 +15307,12 @@ func (m *MockTargetPools) Insert(ctx context.Context, key meta.Key, obj *ga.Targ
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.InsertError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.InsertError[skey]; ok {
 		glog.V(5).Infof("MockTargetPools.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
 		return err
 	}
-	if _, ok := m.Objects[key]; ok {
+	if _, ok := m.Objects[skey]; ok {
 		err := &googleapi.Error{
 			Code:    http.StatusConflict,
 			Message: fmt.Sprintf("MockTargetPools %v exists", key),
@@ -9697,16 +15323,34 @@ func (m *MockTargetPools) Insert(ctx context.Context, key meta.Key, obj *ga.Targ
 
 	obj.Name = key.Name
 	if obj.SelfLink == "" {
-		obj.SelfLink = SelfLink(meta.VersionGA, "mock-project", "targetPools", key)
+		obj.SelfLink = SelfLink(meta.VersionGA, projectID, "targetPools", key)
 	}
 
-	m.Objects[key] = &MockTargetPoolsObj{obj}
+	m.Objects[skey] = &MockTargetPoolsObj{obj}
 	glog.V(5).Infof("MockTargetPools.Insert(%v, %v, %+v) = nil", ctx, key, obj)
 	return nil
 }
 
 // Delete is a mock for deleting the object.
-func (m *MockTargetPools) Delete(ctx context.Context, key meta.Key) error {
+func (m *MockTargetPools) Delete(ctx context.Context, key meta.Key) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "TargetPools")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Delete",
+			Version:   meta.Version("ga"),
+			Service:   "TargetPools",
+		},
+		Key:     &key,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("regional"); err != nil {
+		return err
+	}
+
 	if m.DeleteHook != nil {
 		if intercept, err := m.DeleteHook(m, ctx, key); intercept {
 			glog.V(5).Infof("MockTargetPools.Delete(%v, %v) = %v", ctx, key, err)
@@ -9714,14 +15358,22 @@ func (m *MockTargetPools) Delete(ctx context.Context, key meta.Key) error {
 		}
 	}
 
+	if m.deleteCheck != nil {
+		if err := m.deleteCheck(NewResourceID("targetPools", *key.WithProject(projectID))); err != nil {
+			glog.V(5).Infof("MockTargetPools.Delete(%v, %v) = %v", ctx, key, err)
+			return err
+		}
+	}
+
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.DeleteError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.DeleteError[skey]; ok {
 		glog.V(5).Infof("MockTargetPools.Delete(%v, %v) = %v", ctx, key, err)
 		return err
 	}
-	if _, ok := m.Objects[key]; !ok {
+	if _, ok := m.Objects[skey]; !ok {
 		err := &googleapi.Error{
 			Code:    http.StatusNotFound,
 			Message: fmt.Sprintf("MockTargetPools %v not found", key),
@@ -9730,7 +15382,7 @@ func (m *MockTargetPools) Delete(ctx context.Context, key meta.Key) error {
 		return err
 	}
 
-	delete(m.Objects, key)
+	delete(m.Objects, skey)
 	glog.V(5).Infof("MockTargetPools.Delete(%v, %v) = nil", ctx, key)
 	return nil
 }
@@ -9741,7 +15393,26 @@ func (m *MockTargetPools) Obj(o *ga.TargetPool) *MockTargetPoolsObj {
 }
 
 // AddInstance is a mock for the corresponding method.
-func (m *MockTargetPools) AddInstance(ctx context.Context, key meta.Key, arg0 *ga.TargetPoolsAddInstanceRequest) error {
+func (m *MockTargetPools) AddInstance(ctx context.Context, key meta.Key, arg0 *ga.TargetPoolsAddInstanceRequest) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "TargetPools")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "AddInstance",
+			Version:   meta.Version("ga"),
+			Service:   "TargetPools",
+		},
+		Key:     &key,
+		Request: arg0,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("regional"); err != nil {
+		return err
+	}
+
 	if m.AddInstanceHook != nil {
 		return m.AddInstanceHook(m, ctx, key, arg0)
 	}
@@ -9749,7 +15420,26 @@ func (m *MockTargetPools) AddInstance(ctx context.Context, key meta.Key, arg0 *g
 }
 
 // RemoveInstance is a mock for the corresponding method.
-func (m *MockTargetPools) RemoveInstance(ctx context.Context, key meta.Key, arg0 *ga.TargetPoolsRemoveInstanceRequest) error {
+func (m *MockTargetPools) RemoveInstance(ctx context.Context, key meta.Key, arg0 *ga.TargetPoolsRemoveInstanceRequest) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "TargetPools")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "RemoveInstance",
+			Version:   meta.Version("ga"),
+			Service:   "TargetPools",
+		},
+		Key:     &key,
+		Request: arg0,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("regional"); err != nil {
+		return err
+	}
+
 	if m.RemoveInstanceHook != nil {
 		return m.RemoveInstanceHook(m, ctx, key, arg0)
 	}
@@ -9762,15 +15452,23 @@ type GCETargetPools struct {
 }
 
 // Get the TargetPool named by key.
-func (g *GCETargetPools) Get(ctx context.Context, key meta.Key) (*ga.TargetPool, error) {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "TargetPools")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Get",
-		Version:   meta.Version("ga"),
-		Service:   "TargetPools",
+func (g *GCETargetPools) Get(ctx context.Context, key meta.Key) (_ *ga.TargetPool, err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "TargetPools")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("ga"),
+			Service:   "TargetPools",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("regional"); err != nil {
+		return nil, err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.GA.TargetPools.Get(projectID, key.Region, key.Name)
@@ -9779,24 +15477,38 @@ func (g *GCETargetPools) Get(ctx context.Context, key meta.Key) (*ga.TargetPool,
 }
 
 // List all TargetPool objects.
-func (g *GCETargetPools) List(ctx context.Context, region string, fl *filter.F) ([]*ga.TargetPool, error) {
+func (g *GCETargetPools) List(ctx context.Context, region string, fl *filter.F) (_ []*ga.TargetPool, err error) {
 	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "TargetPools")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "List",
-		Version:   meta.Version("ga"),
-		Service:   "TargetPools",
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("ga"),
+			Service:   "TargetPools",
+		},
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if g.s.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(ga.TargetPool{})); err != nil {
+			return nil, err
+		}
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.GA.TargetPools.List(projectID, region)
-	if fl != filter.None {
-		call.Filter(fl.String())
+	serverFl, clientFl := fl.Split(reflect.TypeOf(ga.TargetPool{}))
+	if serverFl != filter.None {
+		call.Filter(serverFl.String())
 	}
 	var all []*ga.TargetPool
 	f := func(l *ga.TargetPoolList) error {
-		all = append(all, l.Items...)
+		for _, obj := range l.Items {
+			if clientFl.Match(obj) {
+				all = append(all, obj)
+			}
+		}
 		return nil
 	}
 	if err := call.Pages(ctx, f); err != nil {
@@ -9806,18 +15518,32 @@ func (g *GCETargetPools) List(ctx context.Context, region string, fl *filter.F)
 }
 
 // Insert TargetPool with key of value obj.
-func (g *GCETargetPools) Insert(ctx context.Context, key meta.Key, obj *ga.TargetPool) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "TargetPools")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Insert",
-		Version:   meta.Version("ga"),
-		Service:   "TargetPools",
+func (g *GCETargetPools) Insert(ctx context.Context, key meta.Key, obj *ga.TargetPool) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "TargetPools")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Insert",
+			Version:   meta.Version("ga"),
+			Service:   "TargetPools",
+		},
+		Key:     &key,
+		Request: obj,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("regional"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
 	obj.Name = key.Name
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.GA.TargetPools.Insert(projectID, key.Region, obj)
 	call.Context(ctx)
 
@@ -9829,17 +15555,30 @@ func (g *GCETargetPools) Insert(ctx context.Context, key meta.Key, obj *ga.Targe
 }
 
 // Delete the TargetPool referenced by key.
-func (g *GCETargetPools) Delete(ctx context.Context, key meta.Key) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "TargetPools")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Delete",
-		Version:   meta.Version("ga"),
-		Service:   "TargetPools",
+func (g *GCETargetPools) Delete(ctx context.Context, key meta.Key) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "TargetPools")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Delete",
+			Version:   meta.Version("ga"),
+			Service:   "TargetPools",
+		},
+		Key:     &key,
+		Mutates: true,
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("regional"); err != nil {
 		return err
 	}
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
+		return err
+	}
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.GA.TargetPools.Delete(projectID, key.Region, key.Name)
 	call.Context(ctx)
 
@@ -9851,17 +15590,31 @@ func (g *GCETargetPools) Delete(ctx context.Context, key meta.Key) error {
 }
 
 // AddInstance is a method on GCETargetPools.
-func (g *GCETargetPools) AddInstance(ctx context.Context, key meta.Key, arg0 *ga.TargetPoolsAddInstanceRequest) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "TargetPools")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "AddInstance",
-		Version:   meta.Version("ga"),
-		Service:   "TargetPools",
+func (g *GCETargetPools) AddInstance(ctx context.Context, key meta.Key, arg0 *ga.TargetPoolsAddInstanceRequest) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "TargetPools")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "AddInstance",
+			Version:   meta.Version("ga"),
+			Service:   "TargetPools",
+		},
+		Key:     &key,
+		Request: arg0,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("regional"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.GA.TargetPools.AddInstance(projectID, key.Region, key.Name, arg0)
 	call.Context(ctx)
 	op, err := call.Do()
@@ -9872,17 +15625,31 @@ func (g *GCETargetPools) AddInstance(ctx context.Context, key meta.Key, arg0 *ga
 }
 
 // RemoveInstance is a method on GCETargetPools.
-func (g *GCETargetPools) RemoveInstance(ctx context.Context, key meta.Key, arg0 *ga.TargetPoolsRemoveInstanceRequest) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "TargetPools")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "RemoveInstance",
-		Version:   meta.Version("ga"),
-		Service:   "TargetPools",
+func (g *GCETargetPools) RemoveInstance(ctx context.Context, key meta.Key, arg0 *ga.TargetPoolsRemoveInstanceRequest) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "TargetPools")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "RemoveInstance",
+			Version:   meta.Version("ga"),
+			Service:   "TargetPools",
+		},
+		Key:     &key,
+		Request: arg0,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("regional"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.GA.TargetPools.RemoveInstance(projectID, key.Region, key.Name, arg0)
 	call.Context(ctx)
 	op, err := call.Do()
@@ -9936,13 +15703,46 @@ type MockUrlMaps struct {
 	DeleteHook func(m *MockUrlMaps, ctx context.Context, key meta.Key) (bool, error)
 	UpdateHook func(*MockUrlMaps, context.Context, meta.Key, *ga.UrlMap) error
 
+	// Observer, if set, is invoked around each call to the mock.
+	Observer CallObserver
+
+	// ProjectRouter, if set, routes the calls with keys that do not have a
+	// project. Calls are made to "mock-project" if it is not set.
+	ProjectRouter ProjectRouter
+
+	// Debug enables additional checks of the arguments to the mock, such as
+	// the validation of List() filters with filter.Validate().
+	Debug bool
+
+	// deleteCheck, if set, is called by Delete() with the resource being
+	// deleted. Delete() returns the error if it is not nil. See
+	// WithReferenceChecking().
+	deleteCheck func(id *ResourceID) error
+
 	// X is extra state that can be used as part of the mock. Generated code
 	// will not use this field.
 	X interface{}
 }
 
 // Get returns the object from the mock.
-func (m *MockUrlMaps) Get(ctx context.Context, key meta.Key) (*ga.UrlMap, error) {
+func (m *MockUrlMaps) Get(ctx context.Context, key meta.Key) (_ *ga.UrlMap, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "UrlMaps")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("ga"),
+			Service:   "UrlMaps",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("global"); err != nil {
+		return nil, err
+	}
+
 	if m.GetHook != nil {
 		if intercept, obj, err := m.GetHook(m, ctx, key); intercept {
 			glog.V(5).Infof("MockUrlMaps.Get(%v, %s) = %+v, %v", ctx, key, obj, err)
@@ -9953,17 +15753,18 @@ func (m *MockUrlMaps) Get(ctx context.Context, key meta.Key) (*ga.UrlMap, error)
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.GetError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.GetError[skey]; ok {
 		glog.V(5).Infof("MockUrlMaps.Get(%v, %s) = nil, %v", ctx, key, err)
 		return nil, err
 	}
-	if obj, ok := m.Objects[key]; ok {
+	if obj, ok := m.Objects[skey]; ok {
 		typedObj := obj.ToGA()
 		glog.V(5).Infof("MockUrlMaps.Get(%v, %s) = %+v, nil", ctx, key, typedObj)
 		return typedObj, nil
 	}
 
-	err := &googleapi.Error{
+	err = &googleapi.Error{
 		Code:    http.StatusNotFound,
 		Message: fmt.Sprintf("MockUrlMaps %v not found", key),
 	}
@@ -9972,7 +15773,26 @@ func (m *MockUrlMaps) Get(ctx context.Context, key meta.Key) (*ga.UrlMap, error)
 }
 
 // List all of the objects in the mock.
-func (m *MockUrlMaps) List(ctx context.Context, fl *filter.F) ([]*ga.UrlMap, error) {
+func (m *MockUrlMaps) List(ctx context.Context, fl *filter.F) (_ []*ga.UrlMap, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, nil, "ga", "UrlMaps")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("ga"),
+			Service:   "UrlMaps",
+		},
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if m.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(ga.UrlMap{})); err != nil {
+			glog.V(5).Infof("MockUrlMaps.List(%v, %v) = nil, %v", ctx, fl, err)
+			return nil, err
+		}
+	}
+
 	if m.ListHook != nil {
 		if intercept, objs, err := m.ListHook(m, ctx, fl); intercept {
 			glog.V(5).Infof("MockUrlMaps.List(%v, %v) = [%v items], %v", ctx, fl, len(objs), err)
@@ -9991,7 +15811,11 @@ func (m *MockUrlMaps) List(ctx context.Context, fl *filter.F) ([]*ga.UrlMap, err
 	}
 
 	var objs []*ga.UrlMap
-	for _, obj := range m.Objects {
+	for key, obj := range m.Objects {
+		// Objects in other projects are not listed.
+		if key.Project != mockStorageProject(projectID) {
+			continue
+		}
 		if !fl.Match(obj.ToGA()) {
 			continue
 		}
@@ -10003,7 +15827,26 @@ func (m *MockUrlMaps) List(ctx context.Context, fl *filter.F) ([]*ga.UrlMap, err
 }
 
 // Insert is a mock for inserting/creating a new object.
-func (m *MockUrlMaps) Insert(ctx context.Context, key meta.Key, obj *ga.UrlMap) error {
+func (m *MockUrlMaps) Insert(ctx context.Context, key meta.Key, obj *ga.UrlMap) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "UrlMaps")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Insert",
+			Version:   meta.Version("ga"),
+			Service:   "UrlMaps",
+		},
+		Key:     &key,
+		Request: obj,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("global"); err != nil {
+		return err
+	}
+
 	if m.InsertHook != nil {
 		if intercept, err := m.InsertHook(m, ctx, key, obj); intercept {
 			glog.V(5).Infof("MockUrlMaps.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
@@ -10014,11 +15857,12 @@ func (m *MockUrlMaps) Insert(ctx context.Context, key meta.Key, obj *ga.UrlMap)
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.InsertError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.InsertError[skey]; ok {
 		glog.V(5).Infof("MockUrlMaps.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
 		return err
 	}
-	if _, ok := m.Objects[key]; ok {
+	if _, ok := m.Objects[skey]; ok {
 		err := &googleapi.Error{
 			Code:    http.StatusConflict,
 			Message: fmt.Sprintf("MockUrlMaps %v exists", key),
@@ -10029,16 +15873,34 @@ func (m *MockUrlMaps) Insert(ctx context.Context, key meta.Key, obj *ga.UrlMap)
 
 	obj.Name = key.Name
 	if obj.SelfLink == "" {
-		obj.SelfLink = SelfLink(meta.VersionGA, "mock-project", "urlMaps", key)
+		obj.SelfLink = SelfLink(meta.VersionGA, projectID, "urlMaps", key)
 	}
 
-	m.Objects[key] = &MockUrlMapsObj{obj}
+	m.Objects[skey] = &MockUrlMapsObj{obj}
 	glog.V(5).Infof("MockUrlMaps.Insert(%v, %v, %+v) = nil", ctx, key, obj)
 	return nil
 }
 
 // Delete is a mock for deleting the object.
-func (m *MockUrlMaps) Delete(ctx context.Context, key meta.Key) error {
+func (m *MockUrlMaps) Delete(ctx context.Context, key meta.Key) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "UrlMaps")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Delete",
+			Version:   meta.Version("ga"),
+			Service:   "UrlMaps",
+		},
+		Key:     &key,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("global"); err != nil {
+		return err
+	}
+
 	if m.DeleteHook != nil {
 		if intercept, err := m.DeleteHook(m, ctx, key); intercept {
 			glog.V(5).Infof("MockUrlMaps.Delete(%v, %v) = %v", ctx, key, err)
@@ -10046,14 +15908,22 @@ func (m *MockUrlMaps) Delete(ctx context.Context, key meta.Key) error {
 		}
 	}
 
+	if m.deleteCheck != nil {
+		if err := m.deleteCheck(NewResourceID("urlMaps", *key.WithProject(projectID))); err != nil {
+			glog.V(5).Infof("MockUrlMaps.Delete(%v, %v) = %v", ctx, key, err)
+			return err
+		}
+	}
+
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.DeleteError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.DeleteError[skey]; ok {
 		glog.V(5).Infof("MockUrlMaps.Delete(%v, %v) = %v", ctx, key, err)
 		return err
 	}
-	if _, ok := m.Objects[key]; !ok {
+	if _, ok := m.Objects[skey]; !ok {
 		err := &googleapi.Error{
 			Code:    http.StatusNotFound,
 			Message: fmt.Sprintf("MockUrlMaps %v not found", key),
@@ -10062,7 +15932,7 @@ func (m *MockUrlMaps) Delete(ctx context.Context, key meta.Key) error {
 		return err
 	}
 
-	delete(m.Objects, key)
+	delete(m.Objects, skey)
 	glog.V(5).Infof("MockUrlMaps.Delete(%v, %v) = nil", ctx, key)
 	return nil
 }
@@ -10072,11 +15942,52 @@ func (m *MockUrlMaps) Obj(o *ga.UrlMap) *MockUrlMapsObj {
 	return &MockUrlMapsObj{o}
 }
 
-// Update is a mock for the corresponding method.
-func (m *MockUrlMaps) Update(ctx context.Context, key meta.Key, arg0 *ga.UrlMap) error {
+// Update is a mock for the corresponding method. The object is replaced
+// with arg0 if UpdateHook is not set.
+func (m *MockUrlMaps) Update(ctx context.Context, key meta.Key, arg0 *ga.UrlMap) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "UrlMaps")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Update",
+			Version:   meta.Version("ga"),
+			Service:   "UrlMaps",
+		},
+		Key:     &key,
+		Request: arg0,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("global"); err != nil {
+		return err
+	}
+
 	if m.UpdateHook != nil {
 		return m.UpdateHook(m, ctx, key, arg0)
 	}
+
+	m.Lock.Lock()
+	defer m.Lock.Unlock()
+
+	skey := mockStorageKey(key, projectID)
+	if _, ok := m.Objects[skey]; !ok {
+		err := &googleapi.Error{
+			Code:    http.StatusNotFound,
+			Message: fmt.Sprintf("MockUrlMaps %v not found", key),
+		}
+		glog.V(5).Infof("MockUrlMaps.Update(%v, %v, %+v) = %v", ctx, key, arg0, err)
+		return err
+	}
+
+	arg0.Name = key.Name
+	if arg0.SelfLink == "" {
+		arg0.SelfLink = SelfLink(meta.VersionGA, projectID, "urlMaps", key)
+	}
+	// The fields that the version of arg0 does not have are kept, as in GCE.
+	m.Objects[skey] = &MockUrlMapsObj{mergeVersion(m.Objects[skey].Obj, arg0)}
+	glog.V(5).Infof("MockUrlMaps.Update(%v, %v, %+v) = nil", ctx, key, arg0)
 	return nil
 }
 
@@ -10086,15 +15997,23 @@ type GCEUrlMaps struct {
 }
 
 // Get the UrlMap named by key.
-func (g *GCEUrlMaps) Get(ctx context.Context, key meta.Key) (*ga.UrlMap, error) {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "UrlMaps")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Get",
-		Version:   meta.Version("ga"),
-		Service:   "UrlMaps",
+func (g *GCEUrlMaps) Get(ctx context.Context, key meta.Key) (_ *ga.UrlMap, err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "UrlMaps")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("ga"),
+			Service:   "UrlMaps",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("global"); err != nil {
+		return nil, err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.GA.UrlMaps.Get(projectID, key.Name)
@@ -10103,24 +16022,38 @@ func (g *GCEUrlMaps) Get(ctx context.Context, key meta.Key) (*ga.UrlMap, error)
 }
 
 // List all UrlMap objects.
-func (g *GCEUrlMaps) List(ctx context.Context, fl *filter.F) ([]*ga.UrlMap, error) {
+func (g *GCEUrlMaps) List(ctx context.Context, fl *filter.F) (_ []*ga.UrlMap, err error) {
 	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "UrlMaps")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "List",
-		Version:   meta.Version("ga"),
-		Service:   "UrlMaps",
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("ga"),
+			Service:   "UrlMaps",
+		},
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if g.s.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(ga.UrlMap{})); err != nil {
+			return nil, err
+		}
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.GA.UrlMaps.List(projectID)
-	if fl != filter.None {
-		call.Filter(fl.String())
+	serverFl, clientFl := fl.Split(reflect.TypeOf(ga.UrlMap{}))
+	if serverFl != filter.None {
+		call.Filter(serverFl.String())
 	}
 	var all []*ga.UrlMap
 	f := func(l *ga.UrlMapList) error {
-		all = append(all, l.Items...)
+		for _, obj := range l.Items {
+			if clientFl.Match(obj) {
+				all = append(all, obj)
+			}
+		}
 		return nil
 	}
 	if err := call.Pages(ctx, f); err != nil {
@@ -10130,18 +16063,32 @@ func (g *GCEUrlMaps) List(ctx context.Context, fl *filter.F) ([]*ga.UrlMap, erro
 }
 
 // Insert UrlMap with key of value obj.
-func (g *GCEUrlMaps) Insert(ctx context.Context, key meta.Key, obj *ga.UrlMap) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "UrlMaps")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Insert",
-		Version:   meta.Version("ga"),
-		Service:   "UrlMaps",
+func (g *GCEUrlMaps) Insert(ctx context.Context, key meta.Key, obj *ga.UrlMap) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "UrlMaps")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Insert",
+			Version:   meta.Version("ga"),
+			Service:   "UrlMaps",
+		},
+		Key:     &key,
+		Request: obj,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("global"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
 	obj.Name = key.Name
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.GA.UrlMaps.Insert(projectID, obj)
 	call.Context(ctx)
 
@@ -10153,17 +16100,30 @@ func (g *GCEUrlMaps) Insert(ctx context.Context, key meta.Key, obj *ga.UrlMap) e
 }
 
 // Delete the UrlMap referenced by key.
-func (g *GCEUrlMaps) Delete(ctx context.Context, key meta.Key) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "UrlMaps")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Delete",
-		Version:   meta.Version("ga"),
-		Service:   "UrlMaps",
+func (g *GCEUrlMaps) Delete(ctx context.Context, key meta.Key) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "UrlMaps")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Delete",
+			Version:   meta.Version("ga"),
+			Service:   "UrlMaps",
+		},
+		Key:     &key,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("global"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.GA.UrlMaps.Delete(projectID, key.Name)
 
 	call.Context(ctx)
@@ -10176,17 +16136,31 @@ func (g *GCEUrlMaps) Delete(ctx context.Context, key meta.Key) error {
 }
 
 // Update is a method on GCEUrlMaps.
-func (g *GCEUrlMaps) Update(ctx context.Context, key meta.Key, arg0 *ga.UrlMap) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "UrlMaps")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Update",
-		Version:   meta.Version("ga"),
-		Service:   "UrlMaps",
+func (g *GCEUrlMaps) Update(ctx context.Context, key meta.Key, arg0 *ga.UrlMap) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "UrlMaps")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Update",
+			Version:   meta.Version("ga"),
+			Service:   "UrlMaps",
+		},
+		Key:     &key,
+		Request: arg0,
+		Mutates: true,
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("global"); err != nil {
 		return err
 	}
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
+		return err
+	}
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 	call := g.s.GA.UrlMaps.Update(projectID, key.Name, arg0)
 	call.Context(ctx)
 	op, err := call.Do()
@@ -10230,13 +16204,41 @@ type MockZones struct {
 	GetHook  func(m *MockZones, ctx context.Context, key meta.Key) (bool, *ga.Zone, error)
 	ListHook func(m *MockZones, ctx context.Context, fl *filter.F) (bool, []*ga.Zone, error)
 
+	// Observer, if set, is invoked around each call to the mock.
+	Observer CallObserver
+
+	// ProjectRouter, if set, routes the calls with keys that do not have a
+	// project. Calls are made to "mock-project" if it is not set.
+	ProjectRouter ProjectRouter
+
+	// Debug enables additional checks of the arguments to the mock, such as
+	// the validation of List() filters with filter.Validate().
+	Debug bool
+
 	// X is extra state that can be used as part of the mock. Generated code
 	// will not use this field.
 	X interface{}
 }
 
 // Get returns the object from the mock.
-func (m *MockZones) Get(ctx context.Context, key meta.Key) (*ga.Zone, error) {
+func (m *MockZones) Get(ctx context.Context, key meta.Key) (_ *ga.Zone, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "ga", "Zones")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("ga"),
+			Service:   "Zones",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("global"); err != nil {
+		return nil, err
+	}
+
 	if m.GetHook != nil {
 		if intercept, obj, err := m.GetHook(m, ctx, key); intercept {
 			glog.V(5).Infof("MockZones.Get(%v, %s) = %+v, %v", ctx, key, obj, err)
@@ -10247,17 +16249,18 @@ func (m *MockZones) Get(ctx context.Context, key meta.Key) (*ga.Zone, error) {
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.GetError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.GetError[skey]; ok {
 		glog.V(5).Infof("MockZones.Get(%v, %s) = nil, %v", ctx, key, err)
 		return nil, err
 	}
-	if obj, ok := m.Objects[key]; ok {
+	if obj, ok := m.Objects[skey]; ok {
 		typedObj := obj.ToGA()
 		glog.V(5).Infof("MockZones.Get(%v, %s) = %+v, nil", ctx, key, typedObj)
 		return typedObj, nil
 	}
 
-	err := &googleapi.Error{
+	err = &googleapi.Error{
 		Code:    http.StatusNotFound,
 		Message: fmt.Sprintf("MockZones %v not found", key),
 	}
@@ -10266,7 +16269,26 @@ func (m *MockZones) Get(ctx context.Context, key meta.Key) (*ga.Zone, error) {
 }
 
 // List all of the objects in the mock.
-func (m *MockZones) List(ctx context.Context, fl *filter.F) ([]*ga.Zone, error) {
+func (m *MockZones) List(ctx context.Context, fl *filter.F) (_ []*ga.Zone, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, nil, "ga", "Zones")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("ga"),
+			Service:   "Zones",
+		},
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if m.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(ga.Zone{})); err != nil {
+			glog.V(5).Infof("MockZones.List(%v, %v) = nil, %v", ctx, fl, err)
+			return nil, err
+		}
+	}
+
 	if m.ListHook != nil {
 		if intercept, objs, err := m.ListHook(m, ctx, fl); intercept {
 			glog.V(5).Infof("MockZones.List(%v, %v) = [%v items], %v", ctx, fl, len(objs), err)
@@ -10285,7 +16307,11 @@ func (m *MockZones) List(ctx context.Context, fl *filter.F) ([]*ga.Zone, error)
 	}
 
 	var objs []*ga.Zone
-	for _, obj := range m.Objects {
+	for key, obj := range m.Objects {
+		// Objects in other projects are not listed.
+		if key.Project != mockStorageProject(projectID) {
+			continue
+		}
 		if !fl.Match(obj.ToGA()) {
 			continue
 		}
@@ -10307,15 +16333,23 @@ type GCEZones struct {
 }
 
 // Get the Zone named by key.
-func (g *GCEZones) Get(ctx context.Context, key meta.Key) (*ga.Zone, error) {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "Zones")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Get",
-		Version:   meta.Version("ga"),
-		Service:   "Zones",
+func (g *GCEZones) Get(ctx context.Context, key meta.Key) (_ *ga.Zone, err error) {
+	projectID := g.s.keyProjectID(ctx, key, "ga", "Zones")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version:   meta.Version("ga"),
+			Service:   "Zones",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("global"); err != nil {
+		return nil, err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.GA.Zones.Get(projectID, key.Name)
@@ -10324,24 +16358,38 @@ func (g *GCEZones) Get(ctx context.Context, key meta.Key) (*ga.Zone, error) {
 }
 
 // List all Zone objects.
-func (g *GCEZones) List(ctx context.Context, fl *filter.F) ([]*ga.Zone, error) {
+func (g *GCEZones) List(ctx context.Context, fl *filter.F) (_ []*ga.Zone, err error) {
 	projectID := g.s.ProjectRouter.ProjectID(ctx, "ga", "Zones")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "List",
-		Version:   meta.Version("ga"),
-		Service:   "Zones",
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version:   meta.Version("ga"),
+			Service:   "Zones",
+		},
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if g.s.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf(ga.Zone{})); err != nil {
+			return nil, err
+		}
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 	call := g.s.GA.Zones.List(projectID)
-	if fl != filter.None {
-		call.Filter(fl.String())
+	serverFl, clientFl := fl.Split(reflect.TypeOf(ga.Zone{}))
+	if serverFl != filter.None {
+		call.Filter(serverFl.String())
 	}
 	var all []*ga.Zone
 	f := func(l *ga.ZoneList) error {
-		all = append(all, l.Items...)
+		for _, obj := range l.Items {
+			if clientFl.Match(obj) {
+				all = append(all, obj)
+			}
+		}
 		return nil
 	}
 	if err := call.Pages(ctx, f); err != nil {
@@ -10349,3 +16397,2069 @@ func (g *GCEZones) List(ctx context.Context, fl *filter.F) ([]*ga.Zone, error) {
 	}
 	return all, nil
 }
+
+// NewCachedGCE returns a CachedGCE caching the results of c.
+func NewCachedGCE(c Cloud, opts CacheOptions) *CachedGCE {
+	cache := newCache(opts)
+	return &CachedGCE{
+		cache:                            cache,
+		cachedAddresses:                  &CachedAddresses{c: c.Addresses(), cache: cache.service("Addresses")},
+		cachedAlphaAddresses:             &CachedAlphaAddresses{c: c.AlphaAddresses(), cache: cache.service("Addresses")},
+		cachedBetaAddresses:              &CachedBetaAddresses{c: c.BetaAddresses(), cache: cache.service("Addresses")},
+		cachedGlobalAddresses:            &CachedGlobalAddresses{c: c.GlobalAddresses(), cache: cache.service("GlobalAddresses")},
+		cachedBackendServices:            &CachedBackendServices{c: c.BackendServices(), cache: cache.service("BackendServices")},
+		cachedAlphaBackendServices:       &CachedAlphaBackendServices{c: c.AlphaBackendServices(), cache: cache.service("BackendServices")},
+		cachedAlphaRegionBackendServices: &CachedAlphaRegionBackendServices{c: c.AlphaRegionBackendServices(), cache: cache.service("RegionBackendServices")},
+		cachedDisks:                      &CachedDisks{c: c.Disks(), cache: cache.service("Disks")},
+		cachedAlphaDisks:                 &CachedAlphaDisks{c: c.AlphaDisks(), cache: cache.service("Disks")},
+		cachedAlphaRegionDisks:           &CachedAlphaRegionDisks{c: c.AlphaRegionDisks(), cache: cache.service("RegionDisks")},
+		cachedFirewalls:                  &CachedFirewalls{c: c.Firewalls(), cache: cache.service("Firewalls")},
+		cachedForwardingRules:            &CachedForwardingRules{c: c.ForwardingRules(), cache: cache.service("ForwardingRules")},
+		cachedAlphaForwardingRules:       &CachedAlphaForwardingRules{c: c.AlphaForwardingRules(), cache: cache.service("ForwardingRules")},
+		cachedGlobalForwardingRules:      &CachedGlobalForwardingRules{c: c.GlobalForwardingRules(), cache: cache.service("GlobalForwardingRules")},
+		cachedHealthChecks:               &CachedHealthChecks{c: c.HealthChecks(), cache: cache.service("HealthChecks")},
+		cachedAlphaHealthChecks:          &CachedAlphaHealthChecks{c: c.AlphaHealthChecks(), cache: cache.service("HealthChecks")},
+		cachedHttpHealthChecks:           &CachedHttpHealthChecks{c: c.HttpHealthChecks(), cache: cache.service("HttpHealthChecks")},
+		cachedHttpsHealthChecks:          &CachedHttpsHealthChecks{c: c.HttpsHealthChecks(), cache: cache.service("HttpsHealthChecks")},
+		cachedInstanceGroups:             &CachedInstanceGroups{c: c.InstanceGroups(), cache: cache.service("InstanceGroups")},
+		cachedInstances:                  &CachedInstances{c: c.Instances(), cache: cache.service("Instances")},
+		cachedBetaInstances:              &CachedBetaInstances{c: c.BetaInstances(), cache: cache.service("Instances")},
+		cachedAlphaInstances:             &CachedAlphaInstances{c: c.AlphaInstances(), cache: cache.service("Instances")},
+		cachedAlphaNetworkEndpointGroups: &CachedAlphaNetworkEndpointGroups{c: c.AlphaNetworkEndpointGroups(), cache: cache.service("NetworkEndpointGroups")},
+		cachedProjects:                   &CachedProjects{ProjectsOps: c.Projects(), c: c.Projects(), cache: cache.service("Projects")},
+		cachedRegions:                    &CachedRegions{c: c.Regions(), cache: cache.service("Regions")},
+		cachedRoutes:                     &CachedRoutes{c: c.Routes(), cache: cache.service("Routes")},
+		cachedSslCertificates:            &CachedSslCertificates{c: c.SslCertificates(), cache: cache.service("SslCertificates")},
+		cachedTargetHttpProxies:          &CachedTargetHttpProxies{c: c.TargetHttpProxies(), cache: cache.service("TargetHttpProxies")},
+		cachedTargetHttpsProxies:         &CachedTargetHttpsProxies{c: c.TargetHttpsProxies(), cache: cache.service("TargetHttpsProxies")},
+		cachedTargetPools:                &CachedTargetPools{c: c.TargetPools(), cache: cache.service("TargetPools")},
+		cachedUrlMaps:                    &CachedUrlMaps{c: c.UrlMaps(), cache: cache.service("UrlMaps")},
+		cachedZones:                      &CachedZones{c: c.Zones(), cache: cache.service("Zones")},
+	}
+}
+
+// CachedGCE implements Cloud.
+var _ Cloud = (*CachedGCE)(nil)
+
+// CachedGCE is a Cloud that caches the results of the Get(), List() and
+// AggregatedList() calls of another Cloud, for the TTL of each service (see
+// CacheOptions). Insert(), Delete() and the other methods that mutate an
+// object invalidate its results and the results of List() and
+// AggregatedList(). Identical concurrent calls are made once. The objects
+// returned are copies of the cached ones.
+type CachedGCE struct {
+	cache                            *cache
+	cachedAddresses                  *CachedAddresses
+	cachedAlphaAddresses             *CachedAlphaAddresses
+	cachedBetaAddresses              *CachedBetaAddresses
+	cachedGlobalAddresses            *CachedGlobalAddresses
+	cachedBackendServices            *CachedBackendServices
+	cachedAlphaBackendServices       *CachedAlphaBackendServices
+	cachedAlphaRegionBackendServices *CachedAlphaRegionBackendServices
+	cachedDisks                      *CachedDisks
+	cachedAlphaDisks                 *CachedAlphaDisks
+	cachedAlphaRegionDisks           *CachedAlphaRegionDisks
+	cachedFirewalls                  *CachedFirewalls
+	cachedForwardingRules            *CachedForwardingRules
+	cachedAlphaForwardingRules       *CachedAlphaForwardingRules
+	cachedGlobalForwardingRules      *CachedGlobalForwardingRules
+	cachedHealthChecks               *CachedHealthChecks
+	cachedAlphaHealthChecks          *CachedAlphaHealthChecks
+	cachedHttpHealthChecks           *CachedHttpHealthChecks
+	cachedHttpsHealthChecks          *CachedHttpsHealthChecks
+	cachedInstanceGroups             *CachedInstanceGroups
+	cachedInstances                  *CachedInstances
+	cachedBetaInstances              *CachedBetaInstances
+	cachedAlphaInstances             *CachedAlphaInstances
+	cachedAlphaNetworkEndpointGroups *CachedAlphaNetworkEndpointGroups
+	cachedProjects                   *CachedProjects
+	cachedRegions                    *CachedRegions
+	cachedRoutes                     *CachedRoutes
+	cachedSslCertificates            *CachedSslCertificates
+	cachedTargetHttpProxies          *CachedTargetHttpProxies
+	cachedTargetHttpsProxies         *CachedTargetHttpsProxies
+	cachedTargetPools                *CachedTargetPools
+	cachedUrlMaps                    *CachedUrlMaps
+	cachedZones                      *CachedZones
+}
+
+func (c *CachedGCE) Addresses() Addresses {
+	return c.cachedAddresses
+}
+func (c *CachedGCE) AlphaAddresses() AlphaAddresses {
+	return c.cachedAlphaAddresses
+}
+func (c *CachedGCE) BetaAddresses() BetaAddresses {
+	return c.cachedBetaAddresses
+}
+func (c *CachedGCE) GlobalAddresses() GlobalAddresses {
+	return c.cachedGlobalAddresses
+}
+func (c *CachedGCE) BackendServices() BackendServices {
+	return c.cachedBackendServices
+}
+func (c *CachedGCE) AlphaBackendServices() AlphaBackendServices {
+	return c.cachedAlphaBackendServices
+}
+func (c *CachedGCE) AlphaRegionBackendServices() AlphaRegionBackendServices {
+	return c.cachedAlphaRegionBackendServices
+}
+func (c *CachedGCE) Disks() Disks {
+	return c.cachedDisks
+}
+func (c *CachedGCE) AlphaDisks() AlphaDisks {
+	return c.cachedAlphaDisks
+}
+func (c *CachedGCE) AlphaRegionDisks() AlphaRegionDisks {
+	return c.cachedAlphaRegionDisks
+}
+func (c *CachedGCE) Firewalls() Firewalls {
+	return c.cachedFirewalls
+}
+func (c *CachedGCE) ForwardingRules() ForwardingRules {
+	return c.cachedForwardingRules
+}
+func (c *CachedGCE) AlphaForwardingRules() AlphaForwardingRules {
+	return c.cachedAlphaForwardingRules
+}
+func (c *CachedGCE) GlobalForwardingRules() GlobalForwardingRules {
+	return c.cachedGlobalForwardingRules
+}
+func (c *CachedGCE) HealthChecks() HealthChecks {
+	return c.cachedHealthChecks
+}
+func (c *CachedGCE) AlphaHealthChecks() AlphaHealthChecks {
+	return c.cachedAlphaHealthChecks
+}
+func (c *CachedGCE) HttpHealthChecks() HttpHealthChecks {
+	return c.cachedHttpHealthChecks
+}
+func (c *CachedGCE) HttpsHealthChecks() HttpsHealthChecks {
+	return c.cachedHttpsHealthChecks
+}
+func (c *CachedGCE) InstanceGroups() InstanceGroups {
+	return c.cachedInstanceGroups
+}
+func (c *CachedGCE) Instances() Instances {
+	return c.cachedInstances
+}
+func (c *CachedGCE) BetaInstances() BetaInstances {
+	return c.cachedBetaInstances
+}
+func (c *CachedGCE) AlphaInstances() AlphaInstances {
+	return c.cachedAlphaInstances
+}
+func (c *CachedGCE) AlphaNetworkEndpointGroups() AlphaNetworkEndpointGroups {
+	return c.cachedAlphaNetworkEndpointGroups
+}
+func (c *CachedGCE) Projects() Projects {
+	return c.cachedProjects
+}
+func (c *CachedGCE) Regions() Regions {
+	return c.cachedRegions
+}
+func (c *CachedGCE) Routes() Routes {
+	return c.cachedRoutes
+}
+func (c *CachedGCE) SslCertificates() SslCertificates {
+	return c.cachedSslCertificates
+}
+func (c *CachedGCE) TargetHttpProxies() TargetHttpProxies {
+	return c.cachedTargetHttpProxies
+}
+func (c *CachedGCE) TargetHttpsProxies() TargetHttpsProxies {
+	return c.cachedTargetHttpsProxies
+}
+func (c *CachedGCE) TargetPools() TargetPools {
+	return c.cachedTargetPools
+}
+func (c *CachedGCE) UrlMaps() UrlMaps {
+	return c.cachedUrlMaps
+}
+func (c *CachedGCE) Zones() Zones {
+	return c.cachedZones
+}
+
+// CachedAddresses caches the results of Addresses. See CachedGCE.
+type CachedAddresses struct {
+	c     Addresses
+	cache *serviceCache
+}
+
+// Get returns the cached object for key or gets it.
+func (c *CachedAddresses) Get(ctx context.Context, key meta.Key) (*ga.Address, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "ga", "Get", &key, "", nil), func() (interface{}, error) {
+		return c.c.Get(ctx, key)
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret := &ga.Address{}
+	if err := copyViaJSON(ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// List returns the cached objects in region or lists them.
+func (c *CachedAddresses) List(ctx context.Context, region string, fl *filter.F) ([]*ga.Address, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "ga", "List", nil, region, fl), func() (interface{}, error) {
+		return c.c.List(ctx, region, fl)
+	})
+	if err != nil {
+		return nil, err
+	}
+	var ret []*ga.Address
+	if err := copyViaJSON(&ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// Insert the object and invalidate the cached results for key.
+func (c *CachedAddresses) Insert(ctx context.Context, key meta.Key, obj *ga.Address) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Insert(ctx, key, obj)
+}
+
+// Delete the object and invalidate the cached results for key.
+func (c *CachedAddresses) Delete(ctx context.Context, key meta.Key) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Delete(ctx, key)
+}
+
+// CachedAlphaAddresses caches the results of AlphaAddresses. See CachedGCE.
+type CachedAlphaAddresses struct {
+	c     AlphaAddresses
+	cache *serviceCache
+}
+
+// Get returns the cached object for key or gets it.
+func (c *CachedAlphaAddresses) Get(ctx context.Context, key meta.Key) (*alpha.Address, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "alpha", "Get", &key, "", nil), func() (interface{}, error) {
+		return c.c.Get(ctx, key)
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret := &alpha.Address{}
+	if err := copyViaJSON(ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// List returns the cached objects in region or lists them.
+func (c *CachedAlphaAddresses) List(ctx context.Context, region string, fl *filter.F) ([]*alpha.Address, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "alpha", "List", nil, region, fl), func() (interface{}, error) {
+		return c.c.List(ctx, region, fl)
+	})
+	if err != nil {
+		return nil, err
+	}
+	var ret []*alpha.Address
+	if err := copyViaJSON(&ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// Insert the object and invalidate the cached results for key.
+func (c *CachedAlphaAddresses) Insert(ctx context.Context, key meta.Key, obj *alpha.Address) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Insert(ctx, key, obj)
+}
+
+// Delete the object and invalidate the cached results for key.
+func (c *CachedAlphaAddresses) Delete(ctx context.Context, key meta.Key) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Delete(ctx, key)
+}
+
+// CachedBetaAddresses caches the results of BetaAddresses. See CachedGCE.
+type CachedBetaAddresses struct {
+	c     BetaAddresses
+	cache *serviceCache
+}
+
+// Get returns the cached object for key or gets it.
+func (c *CachedBetaAddresses) Get(ctx context.Context, key meta.Key) (*beta.Address, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "beta", "Get", &key, "", nil), func() (interface{}, error) {
+		return c.c.Get(ctx, key)
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret := &beta.Address{}
+	if err := copyViaJSON(ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// List returns the cached objects in region or lists them.
+func (c *CachedBetaAddresses) List(ctx context.Context, region string, fl *filter.F) ([]*beta.Address, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "beta", "List", nil, region, fl), func() (interface{}, error) {
+		return c.c.List(ctx, region, fl)
+	})
+	if err != nil {
+		return nil, err
+	}
+	var ret []*beta.Address
+	if err := copyViaJSON(&ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// Insert the object and invalidate the cached results for key.
+func (c *CachedBetaAddresses) Insert(ctx context.Context, key meta.Key, obj *beta.Address) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Insert(ctx, key, obj)
+}
+
+// Delete the object and invalidate the cached results for key.
+func (c *CachedBetaAddresses) Delete(ctx context.Context, key meta.Key) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Delete(ctx, key)
+}
+
+// CachedGlobalAddresses caches the results of GlobalAddresses. See CachedGCE.
+type CachedGlobalAddresses struct {
+	c     GlobalAddresses
+	cache *serviceCache
+}
+
+// Get returns the cached object for key or gets it.
+func (c *CachedGlobalAddresses) Get(ctx context.Context, key meta.Key) (*ga.Address, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "ga", "Get", &key, "", nil), func() (interface{}, error) {
+		return c.c.Get(ctx, key)
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret := &ga.Address{}
+	if err := copyViaJSON(ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// List returns the cached objects or lists them.
+func (c *CachedGlobalAddresses) List(ctx context.Context, fl *filter.F) ([]*ga.Address, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "ga", "List", nil, "", fl), func() (interface{}, error) {
+		return c.c.List(ctx, fl)
+	})
+	if err != nil {
+		return nil, err
+	}
+	var ret []*ga.Address
+	if err := copyViaJSON(&ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// Insert the object and invalidate the cached results for key.
+func (c *CachedGlobalAddresses) Insert(ctx context.Context, key meta.Key, obj *ga.Address) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Insert(ctx, key, obj)
+}
+
+// Delete the object and invalidate the cached results for key.
+func (c *CachedGlobalAddresses) Delete(ctx context.Context, key meta.Key) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Delete(ctx, key)
+}
+
+// CachedBackendServices caches the results of BackendServices. See CachedGCE.
+type CachedBackendServices struct {
+	c     BackendServices
+	cache *serviceCache
+}
+
+// Get returns the cached object for key or gets it.
+func (c *CachedBackendServices) Get(ctx context.Context, key meta.Key) (*ga.BackendService, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "ga", "Get", &key, "", nil), func() (interface{}, error) {
+		return c.c.Get(ctx, key)
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret := &ga.BackendService{}
+	if err := copyViaJSON(ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// List returns the cached objects or lists them.
+func (c *CachedBackendServices) List(ctx context.Context, fl *filter.F) ([]*ga.BackendService, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "ga", "List", nil, "", fl), func() (interface{}, error) {
+		return c.c.List(ctx, fl)
+	})
+	if err != nil {
+		return nil, err
+	}
+	var ret []*ga.BackendService
+	if err := copyViaJSON(&ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// Insert the object and invalidate the cached results for key.
+func (c *CachedBackendServices) Insert(ctx context.Context, key meta.Key, obj *ga.BackendService) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Insert(ctx, key, obj)
+}
+
+// Delete the object and invalidate the cached results for key.
+func (c *CachedBackendServices) Delete(ctx context.Context, key meta.Key) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Delete(ctx, key)
+}
+
+// GetHealth calls the method. The result is not cached.
+func (c *CachedBackendServices) GetHealth(ctx context.Context, key meta.Key, arg0 *ga.ResourceGroupReference) (*ga.BackendServiceGroupHealth, error) {
+	return c.c.GetHealth(ctx, key, arg0)
+}
+
+// Update calls the method and invalidates the cached results for key.
+func (c *CachedBackendServices) Update(ctx context.Context, key meta.Key, arg0 *ga.BackendService) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Update(ctx, key, arg0)
+}
+
+// CachedAlphaBackendServices caches the results of AlphaBackendServices. See CachedGCE.
+type CachedAlphaBackendServices struct {
+	c     AlphaBackendServices
+	cache *serviceCache
+}
+
+// Get returns the cached object for key or gets it.
+func (c *CachedAlphaBackendServices) Get(ctx context.Context, key meta.Key) (*alpha.BackendService, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "alpha", "Get", &key, "", nil), func() (interface{}, error) {
+		return c.c.Get(ctx, key)
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret := &alpha.BackendService{}
+	if err := copyViaJSON(ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// List returns the cached objects or lists them.
+func (c *CachedAlphaBackendServices) List(ctx context.Context, fl *filter.F) ([]*alpha.BackendService, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "alpha", "List", nil, "", fl), func() (interface{}, error) {
+		return c.c.List(ctx, fl)
+	})
+	if err != nil {
+		return nil, err
+	}
+	var ret []*alpha.BackendService
+	if err := copyViaJSON(&ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// Insert the object and invalidate the cached results for key.
+func (c *CachedAlphaBackendServices) Insert(ctx context.Context, key meta.Key, obj *alpha.BackendService) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Insert(ctx, key, obj)
+}
+
+// Delete the object and invalidate the cached results for key.
+func (c *CachedAlphaBackendServices) Delete(ctx context.Context, key meta.Key) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Delete(ctx, key)
+}
+
+// Update calls the method and invalidates the cached results for key.
+func (c *CachedAlphaBackendServices) Update(ctx context.Context, key meta.Key, arg0 *alpha.BackendService) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Update(ctx, key, arg0)
+}
+
+// CachedAlphaRegionBackendServices caches the results of AlphaRegionBackendServices. See CachedGCE.
+type CachedAlphaRegionBackendServices struct {
+	c     AlphaRegionBackendServices
+	cache *serviceCache
+}
+
+// Get returns the cached object for key or gets it.
+func (c *CachedAlphaRegionBackendServices) Get(ctx context.Context, key meta.Key) (*alpha.BackendService, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "alpha", "Get", &key, "", nil), func() (interface{}, error) {
+		return c.c.Get(ctx, key)
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret := &alpha.BackendService{}
+	if err := copyViaJSON(ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// List returns the cached objects in region or lists them.
+func (c *CachedAlphaRegionBackendServices) List(ctx context.Context, region string, fl *filter.F) ([]*alpha.BackendService, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "alpha", "List", nil, region, fl), func() (interface{}, error) {
+		return c.c.List(ctx, region, fl)
+	})
+	if err != nil {
+		return nil, err
+	}
+	var ret []*alpha.BackendService
+	if err := copyViaJSON(&ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// Insert the object and invalidate the cached results for key.
+func (c *CachedAlphaRegionBackendServices) Insert(ctx context.Context, key meta.Key, obj *alpha.BackendService) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Insert(ctx, key, obj)
+}
+
+// Delete the object and invalidate the cached results for key.
+func (c *CachedAlphaRegionBackendServices) Delete(ctx context.Context, key meta.Key) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Delete(ctx, key)
+}
+
+// GetHealth calls the method. The result is not cached.
+func (c *CachedAlphaRegionBackendServices) GetHealth(ctx context.Context, key meta.Key, arg0 *alpha.ResourceGroupReference) (*alpha.BackendServiceGroupHealth, error) {
+	return c.c.GetHealth(ctx, key, arg0)
+}
+
+// Update calls the method and invalidates the cached results for key.
+func (c *CachedAlphaRegionBackendServices) Update(ctx context.Context, key meta.Key, arg0 *alpha.BackendService) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Update(ctx, key, arg0)
+}
+
+// CachedDisks caches the results of Disks. See CachedGCE.
+type CachedDisks struct {
+	c     Disks
+	cache *serviceCache
+}
+
+// Get returns the cached object for key or gets it.
+func (c *CachedDisks) Get(ctx context.Context, key meta.Key) (*ga.Disk, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "ga", "Get", &key, "", nil), func() (interface{}, error) {
+		return c.c.Get(ctx, key)
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret := &ga.Disk{}
+	if err := copyViaJSON(ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// List returns the cached objects in zone or lists them.
+func (c *CachedDisks) List(ctx context.Context, zone string, fl *filter.F) ([]*ga.Disk, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "ga", "List", nil, zone, fl), func() (interface{}, error) {
+		return c.c.List(ctx, zone, fl)
+	})
+	if err != nil {
+		return nil, err
+	}
+	var ret []*ga.Disk
+	if err := copyViaJSON(&ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// Insert the object and invalidate the cached results for key.
+func (c *CachedDisks) Insert(ctx context.Context, key meta.Key, obj *ga.Disk) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Insert(ctx, key, obj)
+}
+
+// Delete the object and invalidate the cached results for key.
+func (c *CachedDisks) Delete(ctx context.Context, key meta.Key) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Delete(ctx, key)
+}
+
+// CachedAlphaDisks caches the results of AlphaDisks. See CachedGCE.
+type CachedAlphaDisks struct {
+	c     AlphaDisks
+	cache *serviceCache
+}
+
+// Get returns the cached object for key or gets it.
+func (c *CachedAlphaDisks) Get(ctx context.Context, key meta.Key) (*alpha.Disk, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "alpha", "Get", &key, "", nil), func() (interface{}, error) {
+		return c.c.Get(ctx, key)
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret := &alpha.Disk{}
+	if err := copyViaJSON(ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// List returns the cached objects in zone or lists them.
+func (c *CachedAlphaDisks) List(ctx context.Context, zone string, fl *filter.F) ([]*alpha.Disk, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "alpha", "List", nil, zone, fl), func() (interface{}, error) {
+		return c.c.List(ctx, zone, fl)
+	})
+	if err != nil {
+		return nil, err
+	}
+	var ret []*alpha.Disk
+	if err := copyViaJSON(&ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// Insert the object and invalidate the cached results for key.
+func (c *CachedAlphaDisks) Insert(ctx context.Context, key meta.Key, obj *alpha.Disk) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Insert(ctx, key, obj)
+}
+
+// Delete the object and invalidate the cached results for key.
+func (c *CachedAlphaDisks) Delete(ctx context.Context, key meta.Key) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Delete(ctx, key)
+}
+
+// CachedAlphaRegionDisks caches the results of AlphaRegionDisks. See CachedGCE.
+type CachedAlphaRegionDisks struct {
+	c     AlphaRegionDisks
+	cache *serviceCache
+}
+
+// Get returns the cached object for key or gets it.
+func (c *CachedAlphaRegionDisks) Get(ctx context.Context, key meta.Key) (*alpha.Disk, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "alpha", "Get", &key, "", nil), func() (interface{}, error) {
+		return c.c.Get(ctx, key)
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret := &alpha.Disk{}
+	if err := copyViaJSON(ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// List returns the cached objects in region or lists them.
+func (c *CachedAlphaRegionDisks) List(ctx context.Context, region string, fl *filter.F) ([]*alpha.Disk, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "alpha", "List", nil, region, fl), func() (interface{}, error) {
+		return c.c.List(ctx, region, fl)
+	})
+	if err != nil {
+		return nil, err
+	}
+	var ret []*alpha.Disk
+	if err := copyViaJSON(&ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// Insert the object and invalidate the cached results for key.
+func (c *CachedAlphaRegionDisks) Insert(ctx context.Context, key meta.Key, obj *alpha.Disk) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Insert(ctx, key, obj)
+}
+
+// Delete the object and invalidate the cached results for key.
+func (c *CachedAlphaRegionDisks) Delete(ctx context.Context, key meta.Key) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Delete(ctx, key)
+}
+
+// CachedFirewalls caches the results of Firewalls. See CachedGCE.
+type CachedFirewalls struct {
+	c     Firewalls
+	cache *serviceCache
+}
+
+// Get returns the cached object for key or gets it.
+func (c *CachedFirewalls) Get(ctx context.Context, key meta.Key) (*ga.Firewall, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "ga", "Get", &key, "", nil), func() (interface{}, error) {
+		return c.c.Get(ctx, key)
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret := &ga.Firewall{}
+	if err := copyViaJSON(ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// List returns the cached objects or lists them.
+func (c *CachedFirewalls) List(ctx context.Context, fl *filter.F) ([]*ga.Firewall, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "ga", "List", nil, "", fl), func() (interface{}, error) {
+		return c.c.List(ctx, fl)
+	})
+	if err != nil {
+		return nil, err
+	}
+	var ret []*ga.Firewall
+	if err := copyViaJSON(&ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// Insert the object and invalidate the cached results for key.
+func (c *CachedFirewalls) Insert(ctx context.Context, key meta.Key, obj *ga.Firewall) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Insert(ctx, key, obj)
+}
+
+// Delete the object and invalidate the cached results for key.
+func (c *CachedFirewalls) Delete(ctx context.Context, key meta.Key) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Delete(ctx, key)
+}
+
+// Update calls the method and invalidates the cached results for key.
+func (c *CachedFirewalls) Update(ctx context.Context, key meta.Key, arg0 *ga.Firewall) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Update(ctx, key, arg0)
+}
+
+// CachedForwardingRules caches the results of ForwardingRules. See CachedGCE.
+type CachedForwardingRules struct {
+	c     ForwardingRules
+	cache *serviceCache
+}
+
+// Get returns the cached object for key or gets it.
+func (c *CachedForwardingRules) Get(ctx context.Context, key meta.Key) (*ga.ForwardingRule, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "ga", "Get", &key, "", nil), func() (interface{}, error) {
+		return c.c.Get(ctx, key)
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret := &ga.ForwardingRule{}
+	if err := copyViaJSON(ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// List returns the cached objects in region or lists them.
+func (c *CachedForwardingRules) List(ctx context.Context, region string, fl *filter.F) ([]*ga.ForwardingRule, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "ga", "List", nil, region, fl), func() (interface{}, error) {
+		return c.c.List(ctx, region, fl)
+	})
+	if err != nil {
+		return nil, err
+	}
+	var ret []*ga.ForwardingRule
+	if err := copyViaJSON(&ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// Insert the object and invalidate the cached results for key.
+func (c *CachedForwardingRules) Insert(ctx context.Context, key meta.Key, obj *ga.ForwardingRule) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Insert(ctx, key, obj)
+}
+
+// Delete the object and invalidate the cached results for key.
+func (c *CachedForwardingRules) Delete(ctx context.Context, key meta.Key) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Delete(ctx, key)
+}
+
+// CachedAlphaForwardingRules caches the results of AlphaForwardingRules. See CachedGCE.
+type CachedAlphaForwardingRules struct {
+	c     AlphaForwardingRules
+	cache *serviceCache
+}
+
+// Get returns the cached object for key or gets it.
+func (c *CachedAlphaForwardingRules) Get(ctx context.Context, key meta.Key) (*alpha.ForwardingRule, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "alpha", "Get", &key, "", nil), func() (interface{}, error) {
+		return c.c.Get(ctx, key)
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret := &alpha.ForwardingRule{}
+	if err := copyViaJSON(ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// List returns the cached objects in region or lists them.
+func (c *CachedAlphaForwardingRules) List(ctx context.Context, region string, fl *filter.F) ([]*alpha.ForwardingRule, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "alpha", "List", nil, region, fl), func() (interface{}, error) {
+		return c.c.List(ctx, region, fl)
+	})
+	if err != nil {
+		return nil, err
+	}
+	var ret []*alpha.ForwardingRule
+	if err := copyViaJSON(&ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// Insert the object and invalidate the cached results for key.
+func (c *CachedAlphaForwardingRules) Insert(ctx context.Context, key meta.Key, obj *alpha.ForwardingRule) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Insert(ctx, key, obj)
+}
+
+// Delete the object and invalidate the cached results for key.
+func (c *CachedAlphaForwardingRules) Delete(ctx context.Context, key meta.Key) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Delete(ctx, key)
+}
+
+// CachedGlobalForwardingRules caches the results of GlobalForwardingRules. See CachedGCE.
+type CachedGlobalForwardingRules struct {
+	c     GlobalForwardingRules
+	cache *serviceCache
+}
+
+// Get returns the cached object for key or gets it.
+func (c *CachedGlobalForwardingRules) Get(ctx context.Context, key meta.Key) (*ga.ForwardingRule, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "ga", "Get", &key, "", nil), func() (interface{}, error) {
+		return c.c.Get(ctx, key)
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret := &ga.ForwardingRule{}
+	if err := copyViaJSON(ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// List returns the cached objects or lists them.
+func (c *CachedGlobalForwardingRules) List(ctx context.Context, fl *filter.F) ([]*ga.ForwardingRule, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "ga", "List", nil, "", fl), func() (interface{}, error) {
+		return c.c.List(ctx, fl)
+	})
+	if err != nil {
+		return nil, err
+	}
+	var ret []*ga.ForwardingRule
+	if err := copyViaJSON(&ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// Insert the object and invalidate the cached results for key.
+func (c *CachedGlobalForwardingRules) Insert(ctx context.Context, key meta.Key, obj *ga.ForwardingRule) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Insert(ctx, key, obj)
+}
+
+// Delete the object and invalidate the cached results for key.
+func (c *CachedGlobalForwardingRules) Delete(ctx context.Context, key meta.Key) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Delete(ctx, key)
+}
+
+// SetTarget calls the method and invalidates the cached results for key.
+func (c *CachedGlobalForwardingRules) SetTarget(ctx context.Context, key meta.Key, arg0 *ga.TargetReference) error {
+	defer c.cache.invalidate(&key)
+	return c.c.SetTarget(ctx, key, arg0)
+}
+
+// CachedHealthChecks caches the results of HealthChecks. See CachedGCE.
+type CachedHealthChecks struct {
+	c     HealthChecks
+	cache *serviceCache
+}
+
+// Get returns the cached object for key or gets it.
+func (c *CachedHealthChecks) Get(ctx context.Context, key meta.Key) (*ga.HealthCheck, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "ga", "Get", &key, "", nil), func() (interface{}, error) {
+		return c.c.Get(ctx, key)
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret := &ga.HealthCheck{}
+	if err := copyViaJSON(ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// List returns the cached objects or lists them.
+func (c *CachedHealthChecks) List(ctx context.Context, fl *filter.F) ([]*ga.HealthCheck, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "ga", "List", nil, "", fl), func() (interface{}, error) {
+		return c.c.List(ctx, fl)
+	})
+	if err != nil {
+		return nil, err
+	}
+	var ret []*ga.HealthCheck
+	if err := copyViaJSON(&ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// Insert the object and invalidate the cached results for key.
+func (c *CachedHealthChecks) Insert(ctx context.Context, key meta.Key, obj *ga.HealthCheck) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Insert(ctx, key, obj)
+}
+
+// Delete the object and invalidate the cached results for key.
+func (c *CachedHealthChecks) Delete(ctx context.Context, key meta.Key) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Delete(ctx, key)
+}
+
+// Update calls the method and invalidates the cached results for key.
+func (c *CachedHealthChecks) Update(ctx context.Context, key meta.Key, arg0 *ga.HealthCheck) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Update(ctx, key, arg0)
+}
+
+// CachedAlphaHealthChecks caches the results of AlphaHealthChecks. See CachedGCE.
+type CachedAlphaHealthChecks struct {
+	c     AlphaHealthChecks
+	cache *serviceCache
+}
+
+// Get returns the cached object for key or gets it.
+func (c *CachedAlphaHealthChecks) Get(ctx context.Context, key meta.Key) (*alpha.HealthCheck, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "alpha", "Get", &key, "", nil), func() (interface{}, error) {
+		return c.c.Get(ctx, key)
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret := &alpha.HealthCheck{}
+	if err := copyViaJSON(ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// List returns the cached objects or lists them.
+func (c *CachedAlphaHealthChecks) List(ctx context.Context, fl *filter.F) ([]*alpha.HealthCheck, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "alpha", "List", nil, "", fl), func() (interface{}, error) {
+		return c.c.List(ctx, fl)
+	})
+	if err != nil {
+		return nil, err
+	}
+	var ret []*alpha.HealthCheck
+	if err := copyViaJSON(&ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// Insert the object and invalidate the cached results for key.
+func (c *CachedAlphaHealthChecks) Insert(ctx context.Context, key meta.Key, obj *alpha.HealthCheck) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Insert(ctx, key, obj)
+}
+
+// Delete the object and invalidate the cached results for key.
+func (c *CachedAlphaHealthChecks) Delete(ctx context.Context, key meta.Key) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Delete(ctx, key)
+}
+
+// Update calls the method and invalidates the cached results for key.
+func (c *CachedAlphaHealthChecks) Update(ctx context.Context, key meta.Key, arg0 *alpha.HealthCheck) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Update(ctx, key, arg0)
+}
+
+// CachedHttpHealthChecks caches the results of HttpHealthChecks. See CachedGCE.
+type CachedHttpHealthChecks struct {
+	c     HttpHealthChecks
+	cache *serviceCache
+}
+
+// Get returns the cached object for key or gets it.
+func (c *CachedHttpHealthChecks) Get(ctx context.Context, key meta.Key) (*ga.HttpHealthCheck, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "ga", "Get", &key, "", nil), func() (interface{}, error) {
+		return c.c.Get(ctx, key)
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret := &ga.HttpHealthCheck{}
+	if err := copyViaJSON(ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// List returns the cached objects or lists them.
+func (c *CachedHttpHealthChecks) List(ctx context.Context, fl *filter.F) ([]*ga.HttpHealthCheck, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "ga", "List", nil, "", fl), func() (interface{}, error) {
+		return c.c.List(ctx, fl)
+	})
+	if err != nil {
+		return nil, err
+	}
+	var ret []*ga.HttpHealthCheck
+	if err := copyViaJSON(&ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// Insert the object and invalidate the cached results for key.
+func (c *CachedHttpHealthChecks) Insert(ctx context.Context, key meta.Key, obj *ga.HttpHealthCheck) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Insert(ctx, key, obj)
+}
+
+// Delete the object and invalidate the cached results for key.
+func (c *CachedHttpHealthChecks) Delete(ctx context.Context, key meta.Key) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Delete(ctx, key)
+}
+
+// Update calls the method and invalidates the cached results for key.
+func (c *CachedHttpHealthChecks) Update(ctx context.Context, key meta.Key, arg0 *ga.HttpHealthCheck) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Update(ctx, key, arg0)
+}
+
+// CachedHttpsHealthChecks caches the results of HttpsHealthChecks. See CachedGCE.
+type CachedHttpsHealthChecks struct {
+	c     HttpsHealthChecks
+	cache *serviceCache
+}
+
+// Get returns the cached object for key or gets it.
+func (c *CachedHttpsHealthChecks) Get(ctx context.Context, key meta.Key) (*ga.HttpsHealthCheck, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "ga", "Get", &key, "", nil), func() (interface{}, error) {
+		return c.c.Get(ctx, key)
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret := &ga.HttpsHealthCheck{}
+	if err := copyViaJSON(ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// List returns the cached objects or lists them.
+func (c *CachedHttpsHealthChecks) List(ctx context.Context, fl *filter.F) ([]*ga.HttpsHealthCheck, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "ga", "List", nil, "", fl), func() (interface{}, error) {
+		return c.c.List(ctx, fl)
+	})
+	if err != nil {
+		return nil, err
+	}
+	var ret []*ga.HttpsHealthCheck
+	if err := copyViaJSON(&ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// Insert the object and invalidate the cached results for key.
+func (c *CachedHttpsHealthChecks) Insert(ctx context.Context, key meta.Key, obj *ga.HttpsHealthCheck) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Insert(ctx, key, obj)
+}
+
+// Delete the object and invalidate the cached results for key.
+func (c *CachedHttpsHealthChecks) Delete(ctx context.Context, key meta.Key) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Delete(ctx, key)
+}
+
+// Update calls the method and invalidates the cached results for key.
+func (c *CachedHttpsHealthChecks) Update(ctx context.Context, key meta.Key, arg0 *ga.HttpsHealthCheck) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Update(ctx, key, arg0)
+}
+
+// CachedInstanceGroups caches the results of InstanceGroups. See CachedGCE.
+type CachedInstanceGroups struct {
+	c     InstanceGroups
+	cache *serviceCache
+}
+
+// Get returns the cached object for key or gets it.
+func (c *CachedInstanceGroups) Get(ctx context.Context, key meta.Key) (*ga.InstanceGroup, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "ga", "Get", &key, "", nil), func() (interface{}, error) {
+		return c.c.Get(ctx, key)
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret := &ga.InstanceGroup{}
+	if err := copyViaJSON(ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// List returns the cached objects in zone or lists them.
+func (c *CachedInstanceGroups) List(ctx context.Context, zone string, fl *filter.F) ([]*ga.InstanceGroup, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "ga", "List", nil, zone, fl), func() (interface{}, error) {
+		return c.c.List(ctx, zone, fl)
+	})
+	if err != nil {
+		return nil, err
+	}
+	var ret []*ga.InstanceGroup
+	if err := copyViaJSON(&ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// Insert the object and invalidate the cached results for key.
+func (c *CachedInstanceGroups) Insert(ctx context.Context, key meta.Key, obj *ga.InstanceGroup) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Insert(ctx, key, obj)
+}
+
+// Delete the object and invalidate the cached results for key.
+func (c *CachedInstanceGroups) Delete(ctx context.Context, key meta.Key) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Delete(ctx, key)
+}
+
+// AddInstances calls the method and invalidates the cached results for key.
+func (c *CachedInstanceGroups) AddInstances(ctx context.Context, key meta.Key, arg0 *ga.InstanceGroupsAddInstancesRequest) error {
+	defer c.cache.invalidate(&key)
+	return c.c.AddInstances(ctx, key, arg0)
+}
+
+// ListInstances calls the method. The result is not cached.
+func (c *CachedInstanceGroups) ListInstances(ctx context.Context, key meta.Key, arg0 *ga.InstanceGroupsListInstancesRequest) (*ga.InstanceGroupsListInstances, error) {
+	return c.c.ListInstances(ctx, key, arg0)
+}
+
+// RemoveInstances calls the method and invalidates the cached results for key.
+func (c *CachedInstanceGroups) RemoveInstances(ctx context.Context, key meta.Key, arg0 *ga.InstanceGroupsRemoveInstancesRequest) error {
+	defer c.cache.invalidate(&key)
+	return c.c.RemoveInstances(ctx, key, arg0)
+}
+
+// SetNamedPorts calls the method and invalidates the cached results for key.
+func (c *CachedInstanceGroups) SetNamedPorts(ctx context.Context, key meta.Key, arg0 *ga.InstanceGroupsSetNamedPortsRequest) error {
+	defer c.cache.invalidate(&key)
+	return c.c.SetNamedPorts(ctx, key, arg0)
+}
+
+// CachedInstances caches the results of Instances. See CachedGCE.
+type CachedInstances struct {
+	c     Instances
+	cache *serviceCache
+}
+
+// Get returns the cached object for key or gets it.
+func (c *CachedInstances) Get(ctx context.Context, key meta.Key) (*ga.Instance, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "ga", "Get", &key, "", nil), func() (interface{}, error) {
+		return c.c.Get(ctx, key)
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret := &ga.Instance{}
+	if err := copyViaJSON(ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// List returns the cached objects in zone or lists them.
+func (c *CachedInstances) List(ctx context.Context, zone string, fl *filter.F) ([]*ga.Instance, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "ga", "List", nil, zone, fl), func() (interface{}, error) {
+		return c.c.List(ctx, zone, fl)
+	})
+	if err != nil {
+		return nil, err
+	}
+	var ret []*ga.Instance
+	if err := copyViaJSON(&ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// Insert the object and invalidate the cached results for key.
+func (c *CachedInstances) Insert(ctx context.Context, key meta.Key, obj *ga.Instance) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Insert(ctx, key, obj)
+}
+
+// Delete the object and invalidate the cached results for key.
+func (c *CachedInstances) Delete(ctx context.Context, key meta.Key) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Delete(ctx, key)
+}
+
+// AttachDisk calls the method and invalidates the cached results for key.
+func (c *CachedInstances) AttachDisk(ctx context.Context, key meta.Key, arg0 *ga.AttachedDisk) error {
+	defer c.cache.invalidate(&key)
+	return c.c.AttachDisk(ctx, key, arg0)
+}
+
+// DetachDisk calls the method and invalidates the cached results for key.
+func (c *CachedInstances) DetachDisk(ctx context.Context, key meta.Key, arg0 string) error {
+	defer c.cache.invalidate(&key)
+	return c.c.DetachDisk(ctx, key, arg0)
+}
+
+// CachedBetaInstances caches the results of BetaInstances. See CachedGCE.
+type CachedBetaInstances struct {
+	c     BetaInstances
+	cache *serviceCache
+}
+
+// Get returns the cached object for key or gets it.
+func (c *CachedBetaInstances) Get(ctx context.Context, key meta.Key) (*beta.Instance, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "beta", "Get", &key, "", nil), func() (interface{}, error) {
+		return c.c.Get(ctx, key)
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret := &beta.Instance{}
+	if err := copyViaJSON(ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// List returns the cached objects in zone or lists them.
+func (c *CachedBetaInstances) List(ctx context.Context, zone string, fl *filter.F) ([]*beta.Instance, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "beta", "List", nil, zone, fl), func() (interface{}, error) {
+		return c.c.List(ctx, zone, fl)
+	})
+	if err != nil {
+		return nil, err
+	}
+	var ret []*beta.Instance
+	if err := copyViaJSON(&ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// Insert the object and invalidate the cached results for key.
+func (c *CachedBetaInstances) Insert(ctx context.Context, key meta.Key, obj *beta.Instance) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Insert(ctx, key, obj)
+}
+
+// Delete the object and invalidate the cached results for key.
+func (c *CachedBetaInstances) Delete(ctx context.Context, key meta.Key) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Delete(ctx, key)
+}
+
+// AttachDisk calls the method and invalidates the cached results for key.
+func (c *CachedBetaInstances) AttachDisk(ctx context.Context, key meta.Key, arg0 *beta.AttachedDisk) error {
+	defer c.cache.invalidate(&key)
+	return c.c.AttachDisk(ctx, key, arg0)
+}
+
+// DetachDisk calls the method and invalidates the cached results for key.
+func (c *CachedBetaInstances) DetachDisk(ctx context.Context, key meta.Key, arg0 string) error {
+	defer c.cache.invalidate(&key)
+	return c.c.DetachDisk(ctx, key, arg0)
+}
+
+// CachedAlphaInstances caches the results of AlphaInstances. See CachedGCE.
+type CachedAlphaInstances struct {
+	c     AlphaInstances
+	cache *serviceCache
+}
+
+// Get returns the cached object for key or gets it.
+func (c *CachedAlphaInstances) Get(ctx context.Context, key meta.Key) (*alpha.Instance, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "alpha", "Get", &key, "", nil), func() (interface{}, error) {
+		return c.c.Get(ctx, key)
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret := &alpha.Instance{}
+	if err := copyViaJSON(ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// List returns the cached objects in zone or lists them.
+func (c *CachedAlphaInstances) List(ctx context.Context, zone string, fl *filter.F) ([]*alpha.Instance, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "alpha", "List", nil, zone, fl), func() (interface{}, error) {
+		return c.c.List(ctx, zone, fl)
+	})
+	if err != nil {
+		return nil, err
+	}
+	var ret []*alpha.Instance
+	if err := copyViaJSON(&ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// Insert the object and invalidate the cached results for key.
+func (c *CachedAlphaInstances) Insert(ctx context.Context, key meta.Key, obj *alpha.Instance) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Insert(ctx, key, obj)
+}
+
+// Delete the object and invalidate the cached results for key.
+func (c *CachedAlphaInstances) Delete(ctx context.Context, key meta.Key) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Delete(ctx, key)
+}
+
+// AttachDisk calls the method and invalidates the cached results for key.
+func (c *CachedAlphaInstances) AttachDisk(ctx context.Context, key meta.Key, arg0 *alpha.AttachedDisk) error {
+	defer c.cache.invalidate(&key)
+	return c.c.AttachDisk(ctx, key, arg0)
+}
+
+// DetachDisk calls the method and invalidates the cached results for key.
+func (c *CachedAlphaInstances) DetachDisk(ctx context.Context, key meta.Key, arg0 string) error {
+	defer c.cache.invalidate(&key)
+	return c.c.DetachDisk(ctx, key, arg0)
+}
+
+// UpdateNetworkInterface calls the method and invalidates the cached results for key.
+func (c *CachedAlphaInstances) UpdateNetworkInterface(ctx context.Context, key meta.Key, arg0 string, arg1 *alpha.NetworkInterface) error {
+	defer c.cache.invalidate(&key)
+	return c.c.UpdateNetworkInterface(ctx, key, arg0, arg1)
+}
+
+// CachedAlphaNetworkEndpointGroups caches the results of AlphaNetworkEndpointGroups. See CachedGCE.
+type CachedAlphaNetworkEndpointGroups struct {
+	c     AlphaNetworkEndpointGroups
+	cache *serviceCache
+}
+
+// Get returns the cached object for key or gets it.
+func (c *CachedAlphaNetworkEndpointGroups) Get(ctx context.Context, key meta.Key) (*alpha.NetworkEndpointGroup, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "alpha", "Get", &key, "", nil), func() (interface{}, error) {
+		return c.c.Get(ctx, key)
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret := &alpha.NetworkEndpointGroup{}
+	if err := copyViaJSON(ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// List returns the cached objects in zone or lists them.
+func (c *CachedAlphaNetworkEndpointGroups) List(ctx context.Context, zone string, fl *filter.F) ([]*alpha.NetworkEndpointGroup, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "alpha", "List", nil, zone, fl), func() (interface{}, error) {
+		return c.c.List(ctx, zone, fl)
+	})
+	if err != nil {
+		return nil, err
+	}
+	var ret []*alpha.NetworkEndpointGroup
+	if err := copyViaJSON(&ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// Insert the object and invalidate the cached results for key.
+func (c *CachedAlphaNetworkEndpointGroups) Insert(ctx context.Context, key meta.Key, obj *alpha.NetworkEndpointGroup) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Insert(ctx, key, obj)
+}
+
+// Delete the object and invalidate the cached results for key.
+func (c *CachedAlphaNetworkEndpointGroups) Delete(ctx context.Context, key meta.Key) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Delete(ctx, key)
+}
+
+// AggregatedList returns the cached objects or lists them.
+func (c *CachedAlphaNetworkEndpointGroups) AggregatedList(ctx context.Context, fl *filter.F) (map[string][]*alpha.NetworkEndpointGroup, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "alpha", "AggregatedList", nil, "", fl), func() (interface{}, error) {
+		return c.c.AggregatedList(ctx, fl)
+	})
+	if err != nil {
+		return nil, err
+	}
+	var ret map[string][]*alpha.NetworkEndpointGroup
+	if err := copyViaJSON(&ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// AttachNetworkEndpoints calls the method and invalidates the cached results for key.
+func (c *CachedAlphaNetworkEndpointGroups) AttachNetworkEndpoints(ctx context.Context, key meta.Key, arg0 *alpha.NetworkEndpointGroupsAttachEndpointsRequest) error {
+	defer c.cache.invalidate(&key)
+	return c.c.AttachNetworkEndpoints(ctx, key, arg0)
+}
+
+// DetachNetworkEndpoints calls the method and invalidates the cached results for key.
+func (c *CachedAlphaNetworkEndpointGroups) DetachNetworkEndpoints(ctx context.Context, key meta.Key, arg0 *alpha.NetworkEndpointGroupsDetachEndpointsRequest) error {
+	defer c.cache.invalidate(&key)
+	return c.c.DetachNetworkEndpoints(ctx, key, arg0)
+}
+
+// CachedProjects caches the results of Projects. See CachedGCE.
+type CachedProjects struct {
+	// The calls to ProjectsOps are not cached.
+	ProjectsOps
+	c     Projects
+	cache *serviceCache
+}
+
+// CachedRegions caches the results of Regions. See CachedGCE.
+type CachedRegions struct {
+	c     Regions
+	cache *serviceCache
+}
+
+// Get returns the cached object for key or gets it.
+func (c *CachedRegions) Get(ctx context.Context, key meta.Key) (*ga.Region, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "ga", "Get", &key, "", nil), func() (interface{}, error) {
+		return c.c.Get(ctx, key)
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret := &ga.Region{}
+	if err := copyViaJSON(ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// List returns the cached objects or lists them.
+func (c *CachedRegions) List(ctx context.Context, fl *filter.F) ([]*ga.Region, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "ga", "List", nil, "", fl), func() (interface{}, error) {
+		return c.c.List(ctx, fl)
+	})
+	if err != nil {
+		return nil, err
+	}
+	var ret []*ga.Region
+	if err := copyViaJSON(&ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// CachedRoutes caches the results of Routes. See CachedGCE.
+type CachedRoutes struct {
+	c     Routes
+	cache *serviceCache
+}
+
+// Get returns the cached object for key or gets it.
+func (c *CachedRoutes) Get(ctx context.Context, key meta.Key) (*ga.Route, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "ga", "Get", &key, "", nil), func() (interface{}, error) {
+		return c.c.Get(ctx, key)
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret := &ga.Route{}
+	if err := copyViaJSON(ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// List returns the cached objects or lists them.
+func (c *CachedRoutes) List(ctx context.Context, fl *filter.F) ([]*ga.Route, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "ga", "List", nil, "", fl), func() (interface{}, error) {
+		return c.c.List(ctx, fl)
+	})
+	if err != nil {
+		return nil, err
+	}
+	var ret []*ga.Route
+	if err := copyViaJSON(&ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// Insert the object and invalidate the cached results for key.
+func (c *CachedRoutes) Insert(ctx context.Context, key meta.Key, obj *ga.Route) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Insert(ctx, key, obj)
+}
+
+// Delete the object and invalidate the cached results for key.
+func (c *CachedRoutes) Delete(ctx context.Context, key meta.Key) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Delete(ctx, key)
+}
+
+// CachedSslCertificates caches the results of SslCertificates. See CachedGCE.
+type CachedSslCertificates struct {
+	c     SslCertificates
+	cache *serviceCache
+}
+
+// Get returns the cached object for key or gets it.
+func (c *CachedSslCertificates) Get(ctx context.Context, key meta.Key) (*ga.SslCertificate, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "ga", "Get", &key, "", nil), func() (interface{}, error) {
+		return c.c.Get(ctx, key)
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret := &ga.SslCertificate{}
+	if err := copyViaJSON(ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// List returns the cached objects or lists them.
+func (c *CachedSslCertificates) List(ctx context.Context, fl *filter.F) ([]*ga.SslCertificate, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "ga", "List", nil, "", fl), func() (interface{}, error) {
+		return c.c.List(ctx, fl)
+	})
+	if err != nil {
+		return nil, err
+	}
+	var ret []*ga.SslCertificate
+	if err := copyViaJSON(&ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// Insert the object and invalidate the cached results for key.
+func (c *CachedSslCertificates) Insert(ctx context.Context, key meta.Key, obj *ga.SslCertificate) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Insert(ctx, key, obj)
+}
+
+// Delete the object and invalidate the cached results for key.
+func (c *CachedSslCertificates) Delete(ctx context.Context, key meta.Key) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Delete(ctx, key)
+}
+
+// CachedTargetHttpProxies caches the results of TargetHttpProxies. See CachedGCE.
+type CachedTargetHttpProxies struct {
+	c     TargetHttpProxies
+	cache *serviceCache
+}
+
+// Get returns the cached object for key or gets it.
+func (c *CachedTargetHttpProxies) Get(ctx context.Context, key meta.Key) (*ga.TargetHttpProxy, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "ga", "Get", &key, "", nil), func() (interface{}, error) {
+		return c.c.Get(ctx, key)
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret := &ga.TargetHttpProxy{}
+	if err := copyViaJSON(ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// List returns the cached objects or lists them.
+func (c *CachedTargetHttpProxies) List(ctx context.Context, fl *filter.F) ([]*ga.TargetHttpProxy, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "ga", "List", nil, "", fl), func() (interface{}, error) {
+		return c.c.List(ctx, fl)
+	})
+	if err != nil {
+		return nil, err
+	}
+	var ret []*ga.TargetHttpProxy
+	if err := copyViaJSON(&ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// Insert the object and invalidate the cached results for key.
+func (c *CachedTargetHttpProxies) Insert(ctx context.Context, key meta.Key, obj *ga.TargetHttpProxy) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Insert(ctx, key, obj)
+}
+
+// Delete the object and invalidate the cached results for key.
+func (c *CachedTargetHttpProxies) Delete(ctx context.Context, key meta.Key) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Delete(ctx, key)
+}
+
+// SetUrlMap calls the method and invalidates the cached results for key.
+func (c *CachedTargetHttpProxies) SetUrlMap(ctx context.Context, key meta.Key, arg0 *ga.UrlMapReference) error {
+	defer c.cache.invalidate(&key)
+	return c.c.SetUrlMap(ctx, key, arg0)
+}
+
+// CachedTargetHttpsProxies caches the results of TargetHttpsProxies. See CachedGCE.
+type CachedTargetHttpsProxies struct {
+	c     TargetHttpsProxies
+	cache *serviceCache
+}
+
+// Get returns the cached object for key or gets it.
+func (c *CachedTargetHttpsProxies) Get(ctx context.Context, key meta.Key) (*ga.TargetHttpsProxy, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "ga", "Get", &key, "", nil), func() (interface{}, error) {
+		return c.c.Get(ctx, key)
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret := &ga.TargetHttpsProxy{}
+	if err := copyViaJSON(ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// List returns the cached objects or lists them.
+func (c *CachedTargetHttpsProxies) List(ctx context.Context, fl *filter.F) ([]*ga.TargetHttpsProxy, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "ga", "List", nil, "", fl), func() (interface{}, error) {
+		return c.c.List(ctx, fl)
+	})
+	if err != nil {
+		return nil, err
+	}
+	var ret []*ga.TargetHttpsProxy
+	if err := copyViaJSON(&ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// Insert the object and invalidate the cached results for key.
+func (c *CachedTargetHttpsProxies) Insert(ctx context.Context, key meta.Key, obj *ga.TargetHttpsProxy) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Insert(ctx, key, obj)
+}
+
+// Delete the object and invalidate the cached results for key.
+func (c *CachedTargetHttpsProxies) Delete(ctx context.Context, key meta.Key) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Delete(ctx, key)
+}
+
+// SetSslCertificates calls the method and invalidates the cached results for key.
+func (c *CachedTargetHttpsProxies) SetSslCertificates(ctx context.Context, key meta.Key, arg0 *ga.TargetHttpsProxiesSetSslCertificatesRequest) error {
+	defer c.cache.invalidate(&key)
+	return c.c.SetSslCertificates(ctx, key, arg0)
+}
+
+// SetUrlMap calls the method and invalidates the cached results for key.
+func (c *CachedTargetHttpsProxies) SetUrlMap(ctx context.Context, key meta.Key, arg0 *ga.UrlMapReference) error {
+	defer c.cache.invalidate(&key)
+	return c.c.SetUrlMap(ctx, key, arg0)
+}
+
+// CachedTargetPools caches the results of TargetPools. See CachedGCE.
+type CachedTargetPools struct {
+	c     TargetPools
+	cache *serviceCache
+}
+
+// Get returns the cached object for key or gets it.
+func (c *CachedTargetPools) Get(ctx context.Context, key meta.Key) (*ga.TargetPool, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "ga", "Get", &key, "", nil), func() (interface{}, error) {
+		return c.c.Get(ctx, key)
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret := &ga.TargetPool{}
+	if err := copyViaJSON(ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// List returns the cached objects in region or lists them.
+func (c *CachedTargetPools) List(ctx context.Context, region string, fl *filter.F) ([]*ga.TargetPool, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "ga", "List", nil, region, fl), func() (interface{}, error) {
+		return c.c.List(ctx, region, fl)
+	})
+	if err != nil {
+		return nil, err
+	}
+	var ret []*ga.TargetPool
+	if err := copyViaJSON(&ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// Insert the object and invalidate the cached results for key.
+func (c *CachedTargetPools) Insert(ctx context.Context, key meta.Key, obj *ga.TargetPool) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Insert(ctx, key, obj)
+}
+
+// Delete the object and invalidate the cached results for key.
+func (c *CachedTargetPools) Delete(ctx context.Context, key meta.Key) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Delete(ctx, key)
+}
+
+// AddInstance calls the method and invalidates the cached results for key.
+func (c *CachedTargetPools) AddInstance(ctx context.Context, key meta.Key, arg0 *ga.TargetPoolsAddInstanceRequest) error {
+	defer c.cache.invalidate(&key)
+	return c.c.AddInstance(ctx, key, arg0)
+}
+
+// RemoveInstance calls the method and invalidates the cached results for key.
+func (c *CachedTargetPools) RemoveInstance(ctx context.Context, key meta.Key, arg0 *ga.TargetPoolsRemoveInstanceRequest) error {
+	defer c.cache.invalidate(&key)
+	return c.c.RemoveInstance(ctx, key, arg0)
+}
+
+// CachedUrlMaps caches the results of UrlMaps. See CachedGCE.
+type CachedUrlMaps struct {
+	c     UrlMaps
+	cache *serviceCache
+}
+
+// Get returns the cached object for key or gets it.
+func (c *CachedUrlMaps) Get(ctx context.Context, key meta.Key) (*ga.UrlMap, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "ga", "Get", &key, "", nil), func() (interface{}, error) {
+		return c.c.Get(ctx, key)
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret := &ga.UrlMap{}
+	if err := copyViaJSON(ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// List returns the cached objects or lists them.
+func (c *CachedUrlMaps) List(ctx context.Context, fl *filter.F) ([]*ga.UrlMap, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "ga", "List", nil, "", fl), func() (interface{}, error) {
+		return c.c.List(ctx, fl)
+	})
+	if err != nil {
+		return nil, err
+	}
+	var ret []*ga.UrlMap
+	if err := copyViaJSON(&ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// Insert the object and invalidate the cached results for key.
+func (c *CachedUrlMaps) Insert(ctx context.Context, key meta.Key, obj *ga.UrlMap) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Insert(ctx, key, obj)
+}
+
+// Delete the object and invalidate the cached results for key.
+func (c *CachedUrlMaps) Delete(ctx context.Context, key meta.Key) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Delete(ctx, key)
+}
+
+// Update calls the method and invalidates the cached results for key.
+func (c *CachedUrlMaps) Update(ctx context.Context, key meta.Key, arg0 *ga.UrlMap) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Update(ctx, key, arg0)
+}
+
+// CachedZones caches the results of Zones. See CachedGCE.
+type CachedZones struct {
+	c     Zones
+	cache *serviceCache
+}
+
+// Get returns the cached object for key or gets it.
+func (c *CachedZones) Get(ctx context.Context, key meta.Key) (*ga.Zone, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "ga", "Get", &key, "", nil), func() (interface{}, error) {
+		return c.c.Get(ctx, key)
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret := &ga.Zone{}
+	if err := copyViaJSON(ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// List returns the cached objects or lists them.
+func (c *CachedZones) List(ctx context.Context, fl *filter.F) ([]*ga.Zone, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "ga", "List", nil, "", fl), func() (interface{}, error) {
+		return c.c.List(ctx, fl)
+	})
+	if err != nil {
+		return nil, err
+	}
+	var ret []*ga.Zone
+	if err := copyViaJSON(&ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// ConvertAddressGAToAlpha converts obj to *alpha.Address.
+// lost are the JSON paths of the non-empty fields of obj that the
+// Alpha version does not have.
+func ConvertAddressGAToAlpha(obj *ga.Address) (_ *alpha.Address, lost []string, err error) {
+	ret := &alpha.Address{}
+	if lost, err = convertVersion(ret, obj); err != nil {
+		return nil, nil, err
+	}
+	return ret, lost, nil
+}
+
+// ConvertAddressGAToBeta converts obj to *beta.Address.
+// lost are the JSON paths of the non-empty fields of obj that the
+// Beta version does not have.
+func ConvertAddressGAToBeta(obj *ga.Address) (_ *beta.Address, lost []string, err error) {
+	ret := &beta.Address{}
+	if lost, err = convertVersion(ret, obj); err != nil {
+		return nil, nil, err
+	}
+	return ret, lost, nil
+}
+
+// ConvertAddressAlphaToGA converts obj to *ga.Address.
+// lost are the JSON paths of the non-empty fields of obj that the
+// GA version does not have.
+func ConvertAddressAlphaToGA(obj *alpha.Address) (_ *ga.Address, lost []string, err error) {
+	ret := &ga.Address{}
+	if lost, err = convertVersion(ret, obj); err != nil {
+		return nil, nil, err
+	}
+	return ret, lost, nil
+}
+
+// ConvertAddressAlphaToBeta converts obj to *beta.Address.
+// lost are the JSON paths of the non-empty fields of obj that the
+// Beta version does not have.
+func ConvertAddressAlphaToBeta(obj *alpha.Address) (_ *beta.Address, lost []string, err error) {
+	ret := &beta.Address{}
+	if lost, err = convertVersion(ret, obj); err != nil {
+		return nil, nil, err
+	}
+	return ret, lost, nil
+}
+
+// ConvertAddressBetaToGA converts obj to *ga.Address.
+// lost are the JSON paths of the non-empty fields of obj that the
+// GA version does not have.
+func ConvertAddressBetaToGA(obj *beta.Address) (_ *ga.Address, lost []string, err error) {
+	ret := &ga.Address{}
+	if lost, err = convertVersion(ret, obj); err != nil {
+		return nil, nil, err
+	}
+	return ret, lost, nil
+}
+
+// ConvertAddressBetaToAlpha converts obj to *alpha.Address.
+// lost are the JSON paths of the non-empty fields of obj that the
+// Alpha version does not have.
+func ConvertAddressBetaToAlpha(obj *beta.Address) (_ *alpha.Address, lost []string, err error) {
+	ret := &alpha.Address{}
+	if lost, err = convertVersion(ret, obj); err != nil {
+		return nil, nil, err
+	}
+	return ret, lost, nil
+}
+
+// ConvertBackendServiceGAToAlpha converts obj to *alpha.BackendService.
+// lost are the JSON paths of the non-empty fields of obj that the
+// Alpha version does not have.
+func ConvertBackendServiceGAToAlpha(obj *ga.BackendService) (_ *alpha.BackendService, lost []string, err error) {
+	ret := &alpha.BackendService{}
+	if lost, err = convertVersion(ret, obj); err != nil {
+		return nil, nil, err
+	}
+	return ret, lost, nil
+}
+
+// ConvertBackendServiceAlphaToGA converts obj to *ga.BackendService.
+// lost are the JSON paths of the non-empty fields of obj that the
+// GA version does not have.
+func ConvertBackendServiceAlphaToGA(obj *alpha.BackendService) (_ *ga.BackendService, lost []string, err error) {
+	ret := &ga.BackendService{}
+	if lost, err = convertVersion(ret, obj); err != nil {
+		return nil, nil, err
+	}
+	return ret, lost, nil
+}
+
+// ConvertDiskGAToAlpha converts obj to *alpha.Disk.
+// lost are the JSON paths of the non-empty fields of obj that the
+// Alpha version does not have.
+func ConvertDiskGAToAlpha(obj *ga.Disk) (_ *alpha.Disk, lost []string, err error) {
+	ret := &alpha.Disk{}
+	if lost, err = convertVersion(ret, obj); err != nil {
+		return nil, nil, err
+	}
+	return ret, lost, nil
+}
+
+// ConvertDiskAlphaToGA converts obj to *ga.Disk.
+// lost are the JSON paths of the non-empty fields of obj that the
+// GA version does not have.
+func ConvertDiskAlphaToGA(obj *alpha.Disk) (_ *ga.Disk, lost []string, err error) {
+	ret := &ga.Disk{}
+	if lost, err = convertVersion(ret, obj); err != nil {
+		return nil, nil, err
+	}
+	return ret, lost, nil
+}
+
+// ConvertForwardingRuleGAToAlpha converts obj to *alpha.ForwardingRule.
+// lost are the JSON paths of the non-empty fields of obj that the
+// Alpha version does not have.
+func ConvertForwardingRuleGAToAlpha(obj *ga.ForwardingRule) (_ *alpha.ForwardingRule, lost []string, err error) {
+	ret := &alpha.ForwardingRule{}
+	if lost, err = convertVersion(ret, obj); err != nil {
+		return nil, nil, err
+	}
+	return ret, lost, nil
+}
+
+// ConvertForwardingRuleAlphaToGA converts obj to *ga.ForwardingRule.
+// lost are the JSON paths of the non-empty fields of obj that the
+// GA version does not have.
+func ConvertForwardingRuleAlphaToGA(obj *alpha.ForwardingRule) (_ *ga.ForwardingRule, lost []string, err error) {
+	ret := &ga.ForwardingRule{}
+	if lost, err = convertVersion(ret, obj); err != nil {
+		return nil, nil, err
+	}
+	return ret, lost, nil
+}
+
+// ConvertHealthCheckGAToAlpha converts obj to *alpha.HealthCheck.
+// lost are the JSON paths of the non-empty fields of obj that the
+// Alpha version does not have.
+func ConvertHealthCheckGAToAlpha(obj *ga.HealthCheck) (_ *alpha.HealthCheck, lost []string, err error) {
+	ret := &alpha.HealthCheck{}
+	if lost, err = convertVersion(ret, obj); err != nil {
+		return nil, nil, err
+	}
+	return ret, lost, nil
+}
+
+// ConvertHealthCheckAlphaToGA converts obj to *ga.HealthCheck.
+// lost are the JSON paths of the non-empty fields of obj that the
+// GA version does not have.
+func ConvertHealthCheckAlphaToGA(obj *alpha.HealthCheck) (_ *ga.HealthCheck, lost []string, err error) {
+	ret := &ga.HealthCheck{}
+	if lost, err = convertVersion(ret, obj); err != nil {
+		return nil, nil, err
+	}
+	return ret, lost, nil
+}
+
+// ConvertInstanceGAToAlpha converts obj to *alpha.Instance.
+// lost are the JSON paths of the non-empty fields of obj that the
+// Alpha version does not have.
+func ConvertInstanceGAToAlpha(obj *ga.Instance) (_ *alpha.Instance, lost []string, err error) {
+	ret := &alpha.Instance{}
+	if lost, err = convertVersion(ret, obj); err != nil {
+		return nil, nil, err
+	}
+	return ret, lost, nil
+}
+
+// ConvertInstanceGAToBeta converts obj to *beta.Instance.
+// lost are the JSON paths of the non-empty fields of obj that the
+// Beta version does not have.
+func ConvertInstanceGAToBeta(obj *ga.Instance) (_ *beta.Instance, lost []string, err error) {
+	ret := &beta.Instance{}
+	if lost, err = convertVersion(ret, obj); err != nil {
+		return nil, nil, err
+	}
+	return ret, lost, nil
+}
+
+// ConvertInstanceAlphaToGA converts obj to *ga.Instance.
+// lost are the JSON paths of the non-empty fields of obj that the
+// GA version does not have.
+func ConvertInstanceAlphaToGA(obj *alpha.Instance) (_ *ga.Instance, lost []string, err error) {
+	ret := &ga.Instance{}
+	if lost, err = convertVersion(ret, obj); err != nil {
+		return nil, nil, err
+	}
+	return ret, lost, nil
+}
+
+// ConvertInstanceAlphaToBeta converts obj to *beta.Instance.
+// lost are the JSON paths of the non-empty fields of obj that the
+// Beta version does not have.
+func ConvertInstanceAlphaToBeta(obj *alpha.Instance) (_ *beta.Instance, lost []string, err error) {
+	ret := &beta.Instance{}
+	if lost, err = convertVersion(ret, obj); err != nil {
+		return nil, nil, err
+	}
+	return ret, lost, nil
+}
+
+// ConvertInstanceBetaToGA converts obj to *ga.Instance.
+// lost are the JSON paths of the non-empty fields of obj that the
+// GA version does not have.
+func ConvertInstanceBetaToGA(obj *beta.Instance) (_ *ga.Instance, lost []string, err error) {
+	ret := &ga.Instance{}
+	if lost, err = convertVersion(ret, obj); err != nil {
+		return nil, nil, err
+	}
+	return ret, lost, nil
+}
+
+// ConvertInstanceBetaToAlpha converts obj to *alpha.Instance.
+// lost are the JSON paths of the non-empty fields of obj that the
+// Alpha version does not have.
+func ConvertInstanceBetaToAlpha(obj *beta.Instance) (_ *alpha.Instance, lost []string, err error) {
+	ret := &alpha.Instance{}
+	if lost, err = convertVersion(ret, obj); err != nil {
+		return nil, nil, err
+	}
+	return ret, lost, nil
+}
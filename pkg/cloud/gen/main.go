@@ -27,6 +27,7 @@ import (
 	"io"
 	"os"
 	"os/exec"
+	"sort"
 	"text/template"
 	"time"
 
@@ -63,7 +64,7 @@ func gofmtContent(r io.Reader) string {
 	cmd.Stderr = cmdErr
 
 	if err := cmd.Run(); err != nil {
-		fmt.Fprintf(os.Stderr, cmdErr.String())
+		fmt.Fprint(os.Stderr, cmdErr.String())
 		panic(err)
 	}
 	return out.String()
@@ -96,6 +97,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"reflect"
 	"sync"
 
 	"google.golang.org/api/googleapi"
@@ -172,8 +174,8 @@ func (gce *GCE) {{.WrapType}}() {{.WrapType}} {
 }
 {{- end}}
 
-// NewMockGCE returns a new mock for GCE.
-func NewMockGCE() *MockGCE {
+// NewMockGCE returns a new mock for GCE, configured with opts.
+func NewMockGCE(opts ...MockOption) *MockGCE {
 	{{- range .Groups}}
 	mock{{.Service}}Objs := map[meta.Key]*Mock{{.Service}}Obj{}
 	{{- end}}
@@ -183,6 +185,9 @@ func NewMockGCE() *MockGCE {
 		{{.MockField}}: New{{.MockWrapType}}(mock{{.Service}}Objs),
 	{{- end}}
 	}
+	for _, opt := range opts {
+		opt(mock)
+	}
 	return mock
 }
 
@@ -194,12 +199,58 @@ type MockGCE struct {
 {{- range .All}}
 	{{.MockField}} *{{.MockWrapType}}
 {{- end}}
+
+	// informers created for the mock, see PollInformers().
+	informers informerSet
 }
 {{range .All}}
 func (mock *MockGCE) {{.WrapType}}() {{.WrapType}} {
 	return mock.{{.MockField}}
 }
 {{end}}
+// SetObserver sets the CallObserver for all of the services in the mock.
+func (mock *MockGCE) SetObserver(o CallObserver) {
+	{{- range .All}}
+	mock.{{.MockField}}.Observer = o
+	{{- end}}
+}
+
+// SetProjectRouter sets the ProjectRouter for all of the services in the
+// mock.
+func (mock *MockGCE) SetProjectRouter(r ProjectRouter) {
+	{{- range .All}}
+	mock.{{.MockField}}.ProjectRouter = r
+	{{- end}}
+}
+
+// SetDebug sets Debug for all of the services in the mock.
+func (mock *MockGCE) SetDebug(debug bool) {
+	{{- range .All}}
+	mock.{{.MockField}}.Debug = debug
+	{{- end}}
+}
+
+// setDeleteCheck sets deleteCheck for all of the services in the mock.
+func (mock *MockGCE) setDeleteCheck(f func(id *ResourceID) error) {
+	{{- range .All}}
+	{{- if .GenerateDelete}}
+	mock.{{.MockField}}.deleteCheck = f
+	{{- end}}
+	{{- end}}
+}
+
+// objects returns the objects of all of the services in the mock.
+func (mock *MockGCE) objects() []interface{} {
+	var ret []interface{}
+	{{- range .Groups}}
+	mock.{{.Primary.MockField}}.Lock.Lock()
+	for _, obj := range mock.{{.Primary.MockField}}.Objects {
+		ret = append(ret, obj.Obj)
+	}
+	mock.{{.Primary.MockField}}.Lock.Unlock()
+	{{- end}}
+	return ret
+}
 
 {{range .Groups}}
 // Mock{{.Service}}Obj is used to store the various object versions in the shared
@@ -378,6 +429,24 @@ type {{.MockWrapType}} struct {
 {{- end -}}
 {{- end}}
 
+	// Observer, if set, is invoked around each call to the mock.
+	Observer CallObserver
+
+	// ProjectRouter, if set, routes the calls with keys that do not have a
+	// project. Calls are made to "mock-project" if it is not set.
+	ProjectRouter ProjectRouter
+
+	// Debug enables additional checks of the arguments to the mock, such as
+	// the validation of List() filters with filter.Validate().
+	Debug bool
+{{- if .GenerateDelete}}
+
+	// deleteCheck, if set, is called by Delete() with the resource being
+	// deleted. Delete() returns the error if it is not nil. See
+	// WithReferenceChecking().
+	deleteCheck func(id *ResourceID) error
+{{- end}}
+
 	// X is extra state that can be used as part of the mock. Generated code
 	// will not use this field.
 	X interface{}
@@ -385,7 +454,24 @@ type {{.MockWrapType}} struct {
 
 {{- if .GenerateGet}}
 // Get returns the object from the mock.
-func (m *{{.MockWrapType}}) Get(ctx context.Context, key meta.Key) (*{{.FQObjectType}}, error) {
+func (m *{{.MockWrapType}}) Get(ctx context.Context, key meta.Key) (_ *{{.FQObjectType}}, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "{{.Version}}", "{{.Service}}")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version: meta.Version("{{.Version}}"),
+			Service: "{{.Service}}",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("{{.KeyType}}"); err != nil {
+		return nil, err
+	}
+
 	if m.GetHook != nil {
 		if intercept, obj, err := m.GetHook(m, ctx, key);  intercept {
 			glog.V(5).Infof("{{.MockWrapType}}.Get(%v, %s) = %+v, %v", ctx, key, obj ,err)
@@ -396,17 +482,18 @@ func (m *{{.MockWrapType}}) Get(ctx context.Context, key meta.Key) (*{{.FQObject
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.GetError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.GetError[skey]; ok {
 		glog.V(5).Infof("{{.MockWrapType}}.Get(%v, %s) = nil, %v", ctx, key, err)
 		return nil, err
 	}
-	if obj, ok := m.Objects[key]; ok {
+	if obj, ok := m.Objects[skey]; ok {
 		typedObj := obj.To{{.VersionTitle}}()
 		glog.V(5).Infof("{{.MockWrapType}}.Get(%v, %s) = %+v, nil", ctx, key, typedObj)
 		return typedObj, nil
 	}
 
-	err := &googleapi.Error{
+	err = &googleapi.Error{
 		Code: http.StatusNotFound,
 		Message: fmt.Sprintf("{{.MockWrapType}} %v not found", key),
 	}
@@ -418,16 +505,35 @@ func (m *{{.MockWrapType}}) Get(ctx context.Context, key meta.Key) (*{{.FQObject
 {{- if .GenerateList}}
 {{if .KeyIsGlobal -}}
 // List all of the objects in the mock.
-func (m *{{.MockWrapType}}) List(ctx context.Context, fl *filter.F) ([]*{{.FQObjectType}}, error) {
+func (m *{{.MockWrapType}}) List(ctx context.Context, fl *filter.F) (_ []*{{.FQObjectType}}, err error) {
 {{- end -}}
 {{- if .KeyIsRegional -}}
 // List all of the objects in the mock in the given region.
-func (m *{{.MockWrapType}}) List(ctx context.Context, region string, fl *filter.F) ([]*{{.FQObjectType}}, error) {
+func (m *{{.MockWrapType}}) List(ctx context.Context, region string, fl *filter.F) (_ []*{{.FQObjectType}}, err error) {
 {{- end -}}
 {{- if .KeyIsZonal -}}
 // List all of the objects in the mock in the given zone.
-func (m *{{.MockWrapType}}) List(ctx context.Context, zone string, fl *filter.F) ([]*{{.FQObjectType}}, error) {
+func (m *{{.MockWrapType}}) List(ctx context.Context, zone string, fl *filter.F) (_ []*{{.FQObjectType}}, err error) {
 {{- end}}
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, nil, "{{.Version}}", "{{.Service}}")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version: meta.Version("{{.Version}}"),
+			Service: "{{.Service}}",
+		},
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if m.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf({{.FQObjectType}}{})); err != nil {
+			glog.V(5).Infof("{{.MockWrapType}}.List(%v, %v) = nil, %v", ctx, fl, err)
+			return nil, err
+		}
+	}
+
 	if m.ListHook != nil {
 		{{if .KeyIsGlobal -}}
 		if intercept, objs, err := m.ListHook(m, ctx, fl);  intercept {
@@ -464,11 +570,11 @@ func (m *{{.MockWrapType}}) List(ctx context.Context, zone string, fl *filter.F)
 	}
 
 	var objs []*{{.FQObjectType}}
-{{- if .KeyIsGlobal}}
-	for _, obj := range m.Objects {
-{{- else}}
 	for key, obj := range m.Objects {
-{{- end -}}
+		// Objects in other projects are not listed.
+		if key.Project != mockStorageProject(projectID) {
+			continue
+		}
 {{- if .KeyIsRegional}}
 		if key.Region != region {
 			continue
@@ -500,7 +606,26 @@ func (m *{{.MockWrapType}}) List(ctx context.Context, zone string, fl *filter.F)
 
 {{- if .GenerateInsert}}
 // Insert is a mock for inserting/creating a new object.
-func (m *{{.MockWrapType}}) Insert(ctx context.Context, key meta.Key, obj *{{.FQObjectType}}) error {
+func (m *{{.MockWrapType}}) Insert(ctx context.Context, key meta.Key, obj *{{.FQObjectType}}) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "{{.Version}}", "{{.Service}}")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Insert",
+			Version: meta.Version("{{.Version}}"),
+			Service: "{{.Service}}",
+		},
+		Key: &key,
+		Request: obj,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("{{.KeyType}}"); err != nil {
+		return err
+	}
+
 	if m.InsertHook != nil {
 		if intercept, err := m.InsertHook(m, ctx, key, obj);  intercept {
 			glog.V(5).Infof("{{.MockWrapType}}.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
@@ -511,11 +636,12 @@ func (m *{{.MockWrapType}}) Insert(ctx context.Context, key meta.Key, obj *{{.FQ
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.InsertError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.InsertError[skey]; ok {
 		glog.V(5).Infof("{{.MockWrapType}}.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
 		return err
 	}
-	if _, ok := m.Objects[key]; ok {
+	if _, ok := m.Objects[skey]; ok {
 		err := &googleapi.Error{
 			Code: http.StatusConflict,
 			Message: fmt.Sprintf("{{.MockWrapType}} %v exists", key),
@@ -526,10 +652,10 @@ func (m *{{.MockWrapType}}) Insert(ctx context.Context, key meta.Key, obj *{{.FQ
 
 	obj.Name = key.Name
 	if obj.SelfLink == "" {
-		obj.SelfLink = SelfLink(meta.Version{{.VersionTitle}}, "mock-project", "{{.Resource}}", key)
+		obj.SelfLink = SelfLink(meta.Version{{.VersionTitle}}, projectID, "{{.Resource}}", key)
 	}
 
-	m.Objects[key] = &Mock{{.Service}}Obj{obj}
+	m.Objects[skey] = &Mock{{.Service}}Obj{obj}
 	glog.V(5).Infof("{{.MockWrapType}}.Insert(%v, %v, %+v) = nil", ctx, key, obj)
 	return nil
 }
@@ -537,7 +663,25 @@ func (m *{{.MockWrapType}}) Insert(ctx context.Context, key meta.Key, obj *{{.FQ
 
 {{- if .GenerateDelete}}
 // Delete is a mock for deleting the object.
-func (m *{{.MockWrapType}}) Delete(ctx context.Context, key meta.Key) error {
+func (m *{{.MockWrapType}}) Delete(ctx context.Context, key meta.Key) (err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "{{.Version}}", "{{.Service}}")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Delete",
+			Version: meta.Version("{{.Version}}"),
+			Service: "{{.Service}}",
+		},
+		Key: &key,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("{{.KeyType}}"); err != nil {
+		return err
+	}
+
 	if m.DeleteHook != nil {
 		if intercept, err := m.DeleteHook(m, ctx, key);  intercept {
 			glog.V(5).Infof("{{.MockWrapType}}.Delete(%v, %v) = %v", ctx, key, err)
@@ -545,14 +689,22 @@ func (m *{{.MockWrapType}}) Delete(ctx context.Context, key meta.Key) error {
 		}
 	}
 
+	if m.deleteCheck != nil {
+		if err := m.deleteCheck(NewResourceID("{{.Resource}}", *key.WithProject(projectID))); err != nil {
+			glog.V(5).Infof("{{.MockWrapType}}.Delete(%v, %v) = %v", ctx, key, err)
+			return err
+		}
+	}
+
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
 
-	if err, ok := m.DeleteError[key]; ok {
+	skey := mockStorageKey(key, projectID)
+	if err, ok := m.DeleteError[skey]; ok {
 		glog.V(5).Infof("{{.MockWrapType}}.Delete(%v, %v) = %v", ctx, key, err)
 		return err
 	}
-	if _, ok := m.Objects[key]; !ok {
+	if _, ok := m.Objects[skey]; !ok {
 		err := &googleapi.Error{
 			Code: http.StatusNotFound,
 			Message: fmt.Sprintf("{{.MockWrapType}} %v not found", key),
@@ -561,7 +713,7 @@ func (m *{{.MockWrapType}}) Delete(ctx context.Context, key meta.Key) error {
 		return err
 	}
 
-	delete(m.Objects, key)
+	delete(m.Objects, skey)
 	glog.V(5).Infof("{{.MockWrapType}}.Delete(%v, %v) = nil", ctx, key)
 	return nil
 }
@@ -569,7 +721,26 @@ func (m *{{.MockWrapType}}) Delete(ctx context.Context, key meta.Key) error {
 
 {{- if .AggregatedList}}
 // AggregatedList is a mock for AggregatedList.
-func (m *{{.MockWrapType}}) AggregatedList(ctx context.Context, fl *filter.F) (map[string][]*{{.FQObjectType}}, error) {
+func (m *{{.MockWrapType}}) AggregatedList(ctx context.Context, fl *filter.F) (_ map[string][]*{{.FQObjectType}}, err error) {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, nil, "{{.Version}}", "{{.Service}}")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "AggregatedList",
+			Version: meta.Version("{{.Version}}"),
+			Service: "{{.Service}}",
+		},
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if m.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf({{.FQObjectType}}{})); err != nil {
+			glog.V(5).Infof("{{.MockWrapType}}.AggregatedList(%v, %v) = nil, %v", ctx, fl, err)
+			return nil, err
+		}
+	}
+
 	if m.AggregatedListHook != nil {
 		if intercept, objs, err := m.AggregatedListHook(m, ctx, fl); intercept {
 			glog.V(5).Infof("{{.MockWrapType}}.AggregatedList(%v, %v) = [%v items], %v", ctx, fl, len(objs), err)
@@ -587,7 +758,10 @@ func (m *{{.MockWrapType}}) AggregatedList(ctx context.Context, fl *filter.F) (m
 	}
 
 	objs := map[string][]*{{.FQObjectType}}{}
-	for _, obj := range m.Objects {
+	for key, obj := range m.Objects {
+		if key.Project != mockStorageProject(projectID) {
+			continue
+		}
 		res, err := ParseResourceURL(obj.To{{.VersionTitle}}().SelfLink)
 		{{- if .KeyIsRegional}}
 		location := res.Key.Region
@@ -617,13 +791,71 @@ func (m *{{.MockWrapType}}) Obj(o *{{.FQObjectType}}) *Mock{{.Service}}Obj {
 {{with .Methods -}}
 {{- range .}}
 // {{.Name}} is a mock for the corresponding method.
-func (m *{{.MockWrapType}}) {{.FcnArgs}} {
+{{- if .UpdatesObject}} The object is replaced
+// with arg0 if {{.MockHookName}} is not set.
+{{- end}}
+func (m *{{.MockWrapType}}) {{.NamedFcnArgs}} {
+	projectID := mockCallProjectID(ctx, m.ProjectRouter, &key, "{{.Version}}", "{{.Service}}")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "{{.Name}}",
+			Version: meta.Version("{{.Version}}"),
+			Service: "{{.Service}}",
+		},
+		Key: &key,
+{{- if .RequestArg}}
+		Request: {{.RequestArg}},
+{{- end}}
+{{- if eq .ReturnType "Operation"}}
+		Mutates: true,
+{{- end}}
+	}
+	ctx = startCall(ctx, m.Observer, cc)
+	defer func() { endCall(ctx, m.Observer, cc, err) }()
+
+	if err := key.Validate("{{.KeyType}}"); err != nil {
 {{- if eq .ReturnType "Operation"}}
+		return err
+{{- else}}
+		return nil, err
+{{- end}}
+	}
+{{- if .UpdatesObject}}
+
+	if m.{{.MockHookName}} != nil {
+		return m.{{.MockHookName}}(m, ctx, key {{.CallArgs}})
+	}
+
+	m.Lock.Lock()
+	defer m.Lock.Unlock()
+
+	skey := mockStorageKey(key, projectID)
+	if _, ok := m.Objects[skey]; !ok {
+		err := &googleapi.Error{
+			Code: http.StatusNotFound,
+			Message: fmt.Sprintf("{{.MockWrapType}} %v not found", key),
+		}
+		glog.V(5).Infof("{{.MockWrapType}}.{{.Name}}(%v, %v, %+v) = %v", ctx, key, arg0, err)
+		return err
+	}
+
+	arg0.Name = key.Name
+	if arg0.SelfLink == "" {
+		arg0.SelfLink = SelfLink(meta.Version{{.VersionTitle}}, projectID, "{{.Resource}}", key)
+	}
+	// The fields that the version of arg0 does not have are kept, as in GCE.
+	m.Objects[skey] = &Mock{{.Service}}Obj{mergeVersion(m.Objects[skey].Obj, arg0)}
+	glog.V(5).Infof("{{.MockWrapType}}.{{.Name}}(%v, %v, %+v) = nil", ctx, key, arg0)
+	return nil
+{{- else if eq .ReturnType "Operation"}}
+
 	if m.{{.MockHookName}} != nil {
 		return m.{{.MockHookName}}(m, ctx, key {{.CallArgs}})
 	}
 	return nil
 {{- else}}
+
 	if m.{{.MockHookName}} != nil {
 		return m.{{.MockHookName}}(m, ctx, key {{.CallArgs}})
 	}
@@ -639,15 +871,23 @@ type {{.GCEWrapType}} struct {
 
 {{- if .GenerateGet}}
 // Get the {{.Object}} named by key.
-func (g *{{.GCEWrapType}}) Get(ctx context.Context, key meta.Key) (*{{.FQObjectType}}, error) {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "{{.Version}}", "{{.Service}}")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Get",
-		Version: meta.Version("{{.Version}}"),
-		Service: "{{.Service}}",
+func (g *{{.GCEWrapType}}) Get(ctx context.Context, key meta.Key) (_ *{{.FQObjectType}}, err error) {
+	projectID := g.s.keyProjectID(ctx, key, "{{.Version}}", "{{.Service}}")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Get",
+			Version: meta.Version("{{.Version}}"),
+			Service: "{{.Service}}",
+		},
+		Key: &key,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("{{.KeyType}}"); err != nil {
+		return nil, err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 {{- if .KeyIsGlobal}}
@@ -667,22 +907,31 @@ func (g *{{.GCEWrapType}}) Get(ctx context.Context, key meta.Key) (*{{.FQObjectT
 {{- if .GenerateList}}
 // List all {{.Object}} objects.
 {{- if .KeyIsGlobal}}
-func (g *{{.GCEWrapType}}) List(ctx context.Context, fl *filter.F) ([]*{{.FQObjectType}}, error) {
+func (g *{{.GCEWrapType}}) List(ctx context.Context, fl *filter.F) (_ []*{{.FQObjectType}}, err error) {
 {{- end -}}
 {{- if .KeyIsRegional}}
-func (g *{{.GCEWrapType}}) List(ctx context.Context, region string, fl *filter.F) ([]*{{.FQObjectType}}, error) {
+func (g *{{.GCEWrapType}}) List(ctx context.Context, region string, fl *filter.F) (_ []*{{.FQObjectType}}, err error) {
 {{- end -}}
 {{- if .KeyIsZonal}}
-func (g *{{.GCEWrapType}}) List(ctx context.Context, zone string, fl *filter.F) ([]*{{.FQObjectType}}, error) {
+func (g *{{.GCEWrapType}}) List(ctx context.Context, zone string, fl *filter.F) (_ []*{{.FQObjectType}}, err error) {
 {{- end}}
-projectID := g.s.ProjectRouter.ProjectID(ctx, "{{.Version}}", "{{.Service}}")
-rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "List",
-		Version: meta.Version("{{.Version}}"),
-		Service: "{{.Service}}",
+	projectID := g.s.ProjectRouter.ProjectID(ctx, "{{.Version}}", "{{.Service}}")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "List",
+			Version: meta.Version("{{.Version}}"),
+			Service: "{{.Service}}",
+		},
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if g.s.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf({{.FQObjectType}}{})); err != nil {
+			return nil, err
+		}
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 {{- if .KeyIsGlobal}}
@@ -694,12 +943,17 @@ rk := &RateLimitKey{
 {{- if .KeyIsZonal}}
 	call := g.s.{{.VersionTitle}}.{{.Service}}.List(projectID, zone)
 {{- end}}
-	if fl != filter.None {
-		call.Filter(fl.String())
+	serverFl, clientFl := fl.Split(reflect.TypeOf({{.FQObjectType}}{}))
+	if serverFl != filter.None {
+		call.Filter(serverFl.String())
 	}
 	var all []*{{.FQObjectType}}
 	f := func(l *{{.ObjectListType}}) error {
-		all = append(all, l.Items...)
+		for _, obj := range l.Items {
+			if clientFl.Match(obj) {
+				all = append(all, obj)
+			}
+		}
 		return nil
 	}
 	if err := call.Pages(ctx, f); err != nil {
@@ -711,18 +965,32 @@ rk := &RateLimitKey{
 
 {{- if .GenerateInsert}}
 // Insert {{.Object}} with key of value obj.
-func (g *{{.GCEWrapType}}) Insert(ctx context.Context, key meta.Key, obj *{{.FQObjectType}}) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "{{.Version}}", "{{.Service}}")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Insert",
-		Version: meta.Version("{{.Version}}"),
-		Service: "{{.Service}}",
+func (g *{{.GCEWrapType}}) Insert(ctx context.Context, key meta.Key, obj *{{.FQObjectType}}) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "{{.Version}}", "{{.Service}}")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Insert",
+			Version: meta.Version("{{.Version}}"),
+			Service: "{{.Service}}",
+		},
+		Key: &key,
+		Request: obj,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("{{.KeyType}}"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
 	obj.Name = key.Name
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 {{- if .KeyIsGlobal}}
 	call := g.s.{{.VersionTitle}}.{{.Service}}.Insert(projectID, obj)
 {{- end -}}
@@ -744,17 +1012,30 @@ func (g *{{.GCEWrapType}}) Insert(ctx context.Context, key meta.Key, obj *{{.FQO
 
 {{- if .GenerateDelete}}
 // Delete the {{.Object}} referenced by key.
-func (g *{{.GCEWrapType}}) Delete(ctx context.Context, key meta.Key) error {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "{{.Version}}", "{{.Service}}")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "Delete",
-		Version: meta.Version("{{.Version}}"),
-		Service: "{{.Service}}",
+func (g *{{.GCEWrapType}}) Delete(ctx context.Context, key meta.Key) (err error) {
+	projectID := g.s.keyProjectID(ctx, key, "{{.Version}}", "{{.Service}}")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "Delete",
+			Version: meta.Version("{{.Version}}"),
+			Service: "{{.Service}}",
+		},
+		Key: &key,
+		Mutates: true,
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("{{.KeyType}}"); err != nil {
+		return err
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return err
 	}
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
 {{- if .KeyIsGlobal}}
 	call := g.s.{{.VersionTitle}}.{{.Service}}.Delete(projectID, key.Name)
 {{end -}}
@@ -776,28 +1057,42 @@ func (g *{{.GCEWrapType}}) Delete(ctx context.Context, key meta.Key) error {
 
 {{- if .AggregatedList}}
 // AggregatedList lists all resources of the given type across all locations.
-func (g *{{.GCEWrapType}}) AggregatedList(ctx context.Context, fl *filter.F) (map[string][]*{{.FQObjectType}}, error) {
+func (g *{{.GCEWrapType}}) AggregatedList(ctx context.Context, fl *filter.F) (_ map[string][]*{{.FQObjectType}}, err error) {
 	projectID := g.s.ProjectRouter.ProjectID(ctx, "{{.Version}}", "{{.Service}}")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "AggregatedList",
-		Version: meta.Version("{{.Version}}"),
-		Service: "{{.Service}}",
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "AggregatedList",
+			Version: meta.Version("{{.Version}}"),
+			Service: "{{.Service}}",
+		},
+	}
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if g.s.Debug {
+		if err := filter.Validate(fl, reflect.TypeOf({{.FQObjectType}}{})); err != nil {
+			return nil, err
+		}
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 		return nil, err
 	}
 
 	call := g.s.{{.VersionTitle}}.{{.Service}}.AggregatedList(projectID)
 	call.Context(ctx)
-	if fl != filter.None {
-		call.Filter(fl.String())
+	serverFl, clientFl := fl.Split(reflect.TypeOf({{.FQObjectType}}{}))
+	if serverFl != filter.None {
+		call.Filter(serverFl.String())
 	}
 
 	all := map[string][]*{{.FQObjectType}}{}
 	f := func(l *{{.ObjectAggregatedListType}}) error {
 		for k, v := range l.Items {
-			all[k] = append(all[k], v.{{.AggregatedListField}}...)
+			for _, obj := range v.{{.AggregatedListField}} {
+				if clientFl.Match(obj) {
+					all[k] = append(all[k], obj)
+				}
+			}
 		}
 		return nil
 	}
@@ -811,21 +1106,45 @@ func (g *{{.GCEWrapType}}) AggregatedList(ctx context.Context, fl *filter.F) (ma
 {{- with .Methods -}}
 {{- range .}}
 // {{.Name}} is a method on {{.GCEWrapType}}.
-func (g *{{.GCEWrapType}}) {{.FcnArgs}} {
-	projectID := g.s.ProjectRouter.ProjectID(ctx, "{{.Version}}", "{{.Service}}")
-	rk := &RateLimitKey{
-		ProjectID: projectID,
-		Operation: "{{.Name}}",
-		Version: meta.Version("{{.Version}}"),
-		Service: "{{.Service}}",
+func (g *{{.GCEWrapType}}) {{.NamedFcnArgs}} {
+	projectID := g.s.keyProjectID(ctx, key, "{{.Version}}", "{{.Service}}")
+	cc := &CallContext{
+		RateLimitKey: RateLimitKey{
+			ProjectID: projectID,
+			Operation: "{{.Name}}",
+			Version: meta.Version("{{.Version}}"),
+			Service: "{{.Service}}",
+		},
+		Key: &key,
+{{- if .RequestArg}}
+		Request: {{.RequestArg}},
+{{- end}}
+{{- if eq .ReturnType "Operation"}}
+		Mutates: true,
+{{- end}}
 	}
-	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+	ctx = startCall(ctx, g.s.Observer, cc)
+	defer func() { endCall(ctx, g.s.Observer, cc, err) }()
+	if err := key.Validate("{{.KeyType}}"); err != nil {
+{{- if eq .ReturnType "Operation"}}
+		return err
+{{- else}}
+		return nil, err
+{{- end}}
+	}
+	if err := g.s.acceptRateLimit(ctx, cc); err != nil {
 	{{- if eq .ReturnType "Operation"}}
 		return err
 	{{- else}}
 		return nil, err
 	{{- end}}
 	}
+{{- if eq .ReturnType "Operation"}}
+	if g.s.DryRun != nil {
+		g.s.DryRun.record(cc)
+		return nil
+	}
+{{- end}}
 {{- if .KeyIsGlobal}}
 	call := g.s.{{.VersionTitle}}.{{.Service}}.{{.Name}}(projectID, key.Name {{.CallArgs}})
 {{- end -}}
@@ -857,6 +1176,174 @@ func (g *{{.GCEWrapType}}) {{.FcnArgs}} {
 	}
 }
 
+// genCache generates the caching Cloud.
+func genCache(wr io.Writer) {
+	const text = `
+// NewCachedGCE returns a CachedGCE caching the results of c.
+func NewCachedGCE(c Cloud, opts CacheOptions) *CachedGCE {
+	cache := newCache(opts)
+	return &CachedGCE{
+		cache: cache,
+	{{- range .All}}
+		cached{{.WrapType}}: &Cached{{.WrapType}}{
+		{{- if .GenerateCustomOps}}{{.WrapTypeOps}}: c.{{.WrapType}}(), {{end -}}
+		c: c.{{.WrapType}}(), cache: cache.service("{{.Service}}")},
+	{{- end}}
+	}
+}
+
+// CachedGCE implements Cloud.
+var _ Cloud = (*CachedGCE)(nil)
+
+// CachedGCE is a Cloud that caches the results of the Get(), List() and
+// AggregatedList() calls of another Cloud, for the TTL of each service (see
+// CacheOptions). Insert(), Delete() and the other methods that mutate an
+// object invalidate its results and the results of List() and
+// AggregatedList(). Identical concurrent calls are made once. The objects
+// returned are copies of the cached ones.
+type CachedGCE struct {
+	cache *cache
+{{- range .All}}
+	cached{{.WrapType}} *Cached{{.WrapType}}
+{{- end}}
+}
+{{range .All}}
+func (c *CachedGCE) {{.WrapType}}() {{.WrapType}} {
+	return c.cached{{.WrapType}}
+}
+{{- end}}
+`
+	data := struct {
+		All []*meta.ServiceInfo
+	}{meta.AllServices}
+	tmpl := template.Must(template.New("cache").Parse(text))
+	if err := tmpl.Execute(wr, data); err != nil {
+		panic(err)
+	}
+
+	const serviceText = `
+// Cached{{.WrapType}} caches the results of {{.WrapType}}. See CachedGCE.
+type Cached{{.WrapType}} struct {
+{{- if .GenerateCustomOps}}
+	// The calls to {{.WrapTypeOps}} are not cached.
+	{{.WrapTypeOps}}
+{{- end}}
+	c     {{.WrapType}}
+	cache *serviceCache
+}
+{{- if .GenerateGet}}
+
+// Get returns the cached object for key or gets it.
+func (c *Cached{{.WrapType}}) Get(ctx context.Context, key meta.Key) (*{{.FQObjectType}}, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "{{.Version}}", "Get", &key, "", nil), func() (interface{}, error) {
+		return c.c.Get(ctx, key)
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret := &{{.FQObjectType}}{}
+	if err := copyViaJSON(ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+{{- end}}
+{{- if .GenerateList}}
+{{- if .KeyIsGlobal}}
+
+// List returns the cached objects or lists them.
+func (c *Cached{{.WrapType}}) List(ctx context.Context, fl *filter.F) ([]*{{.FQObjectType}}, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "{{.Version}}", "List", nil, "", fl), func() (interface{}, error) {
+		return c.c.List(ctx, fl)
+	})
+{{- end}}
+{{- if .KeyIsRegional}}
+
+// List returns the cached objects in region or lists them.
+func (c *Cached{{.WrapType}}) List(ctx context.Context, region string, fl *filter.F) ([]*{{.FQObjectType}}, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "{{.Version}}", "List", nil, region, fl), func() (interface{}, error) {
+		return c.c.List(ctx, region, fl)
+	})
+{{- end}}
+{{- if .KeyIsZonal}}
+
+// List returns the cached objects in zone or lists them.
+func (c *Cached{{.WrapType}}) List(ctx context.Context, zone string, fl *filter.F) ([]*{{.FQObjectType}}, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "{{.Version}}", "List", nil, zone, fl), func() (interface{}, error) {
+		return c.c.List(ctx, zone, fl)
+	})
+{{- end}}
+	if err != nil {
+		return nil, err
+	}
+	var ret []*{{.FQObjectType}}
+	if err := copyViaJSON(&ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+{{- end}}
+{{- if .GenerateInsert}}
+
+// Insert the object and invalidate the cached results for key.
+func (c *Cached{{.WrapType}}) Insert(ctx context.Context, key meta.Key, obj *{{.FQObjectType}}) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Insert(ctx, key, obj)
+}
+{{- end}}
+{{- if .GenerateDelete}}
+
+// Delete the object and invalidate the cached results for key.
+func (c *Cached{{.WrapType}}) Delete(ctx context.Context, key meta.Key) error {
+	defer c.cache.invalidate(&key)
+	return c.c.Delete(ctx, key)
+}
+{{- end}}
+{{- if .AggregatedList}}
+
+// AggregatedList returns the cached objects or lists them.
+func (c *Cached{{.WrapType}}) AggregatedList(ctx context.Context, fl *filter.F) (map[string][]*{{.FQObjectType}}, error) {
+	v, err := c.cache.do(ctx, newCacheKey(ctx, "{{.Version}}", "AggregatedList", nil, "", fl), func() (interface{}, error) {
+		return c.c.AggregatedList(ctx, fl)
+	})
+	if err != nil {
+		return nil, err
+	}
+	var ret map[string][]*{{.FQObjectType}}
+	if err := copyViaJSON(&ret, v); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+{{- end}}
+{{- with .Methods -}}
+{{- range .}}
+
+{{- if eq .ReturnType "Operation"}}
+
+// {{.Name}} calls the method and invalidates the cached results for key.
+func (c *Cached{{.WrapType}}) {{.FcnArgs}} {
+	defer c.cache.invalidate(&key)
+	return c.c.{{.Name}}(ctx, key {{.CallArgs}})
+}
+{{- else}}
+
+// {{.Name}} calls the method. The result is not cached.
+func (c *Cached{{.WrapType}}) {{.FcnArgs}} {
+	return c.c.{{.Name}}(ctx, key {{.CallArgs}})
+}
+{{- end}}
+{{- end}}
+{{- end}}
+`
+	serviceTmpl := template.Must(template.New("cacheService").Parse(serviceText))
+	for _, s := range meta.AllServices {
+		if err := serviceTmpl.Execute(wr, s); err != nil {
+			panic(err)
+		}
+	}
+}
+
 func genUnitTestHeader(wr io.Writer) {
 	const text = `/*
 Copyright {{.Year}} The Kubernetes Authors.
@@ -891,8 +1378,6 @@ import (
 	"{{.PackageRoot}}/filter"
 	"{{.PackageRoot}}/meta"
 )
-
-const location = "location"
 `
 	tmpl := template.Must(template.New("header").Parse(text))
 	values := map[string]string{
@@ -912,17 +1397,18 @@ func Test{{.Service}}Group(t *testing.T) {
 	ctx := context.Background()
 	mock := NewMockGCE()
 
+	const location = "{{.Location}}"
 	var key *meta.Key
 {{- if .HasAlpha}}
-	keyAlpha := meta.{{.Alpha.MakeKey "key-alpha" "location"}}
+	keyAlpha := meta.{{.Alpha.MakeKey "key-alpha" .Location}}
 	key = keyAlpha
 {{- end}}
 {{- if .HasBeta}}
-	keyBeta := meta.{{.Beta.MakeKey "key-beta" "location"}}
+	keyBeta := meta.{{.Beta.MakeKey "key-beta" .Location}}
 	key = keyBeta
 {{- end}}
 {{- if .HasGA}}
-	keyGA := meta.{{.GA.MakeKey "key-ga" "location"}}
+	keyGA := meta.{{.GA.MakeKey "key-ga" .Location}}
 	key = keyGA
 {{- end}}
 	// Ignore unused variables.
@@ -1115,6 +1601,117 @@ func Test{{.Service}}Group(t *testing.T) {
 	}
 }
 
+// genUnitTestMergeVersions generates a test for each group with more than one
+// version, checking that the fields that only some versions have survive the
+// calls made with the other versions.
+func genUnitTestMergeVersions(wr io.Writer) {
+	const text = `
+func Test{{.Service}}MergeVersions(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := NewMockGCE()
+{{- $group := .}}
+{{- range $ins := .Versions}}{{if $ins.GenerateInsert}}
+
+	// Insert with {{$ins.VersionTitle}}.
+	{
+		key := meta.{{$ins.MakeKey (printf "key-%s" $ins.Version) $group.Location}}
+		obj := &{{$ins.FQObjectType}}{}
+		fields := setVersionOnlyFields(obj
+		{{- range $group.Versions}}{{if ne .Version $ins.Version}}, &{{.FQObjectType}}{}{{end}}{{end}})
+		if err := mock.{{$ins.WrapType}}().Insert(ctx, *key, obj); err != nil {
+			t.Fatalf("{{$ins.WrapType}}().Insert(%v, %v, %v) = %v; want nil", ctx, key, obj, err)
+		}
+	{{- range $upd := $group.Versions}}{{if and (ne $upd.Version $ins.Version) $upd.GenerateGet}}
+		{{- range $upd.Methods}}{{if eq .Name "Update"}}
+		if got, err := mock.{{$upd.WrapType}}().Get(ctx, *key); err != nil {
+			t.Errorf("{{$upd.WrapType}}().Get(%v, %v) = _, %v; want nil", ctx, key, err)
+		} else if err := mock.{{$upd.WrapType}}().Update(ctx, *key, got); err != nil {
+			t.Errorf("{{$upd.WrapType}}().Update(%v, %v, %v) = %v; want nil", ctx, key, got, err)
+		}
+		{{- end}}{{end}}
+	{{- end}}{{end}}
+		got, err := mock.{{$ins.WrapType}}().Get(ctx, *key)
+		if err != nil {
+			t.Fatalf("{{$ins.WrapType}}().Get(%v, %v) = _, %v; want nil", ctx, key, err)
+		}
+		if lost := lostVersionOnlyFields(got, fields); len(lost) > 0 {
+			t.Errorf("{{$ins.WrapType}}().Get(%v, %v) lost %v; want %v kept", ctx, key, lost, fields)
+		}
+	}
+{{- end}}{{end}}
+}
+`
+	tmpl := template.Must(template.New("unittestMergeVersions").Parse(text))
+	var services []string
+	for s, g := range meta.AllServicesByGroup {
+		if len(g.Versions()) > 1 {
+			services = append(services, s)
+		}
+	}
+	sort.Strings(services)
+	for _, s := range services {
+		if err := tmpl.Execute(wr, meta.AllServicesByGroup[s]); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// genConverters generates the functions converting each object between the
+// versions of the API in which it exists.
+func genConverters(wr io.Writer) {
+	const text = `{{range .}}
+// Convert{{.Object}}{{.From.VersionTitle}}To{{.To.VersionTitle}} converts obj to *{{.To.FQObjectType}}.
+// lost are the JSON paths of the non-empty fields of obj that the
+// {{.To.VersionTitle}} version does not have.
+func Convert{{.Object}}{{.From.VersionTitle}}To{{.To.VersionTitle}}(obj *{{.From.FQObjectType}}) (_ *{{.To.FQObjectType}}, lost []string, err error) {
+	ret := &{{.To.FQObjectType}}{}
+	if lost, err = convertVersion(ret, obj); err != nil {
+		return nil, nil, err
+	}
+	return ret, lost, nil
+}
+{{end}}`
+	type conversion struct {
+		Object   string
+		From, To *meta.ServiceInfo
+	}
+	// Versions of each object, e.g. BackendService is used by the
+	// BackendServices and RegionBackendServices services.
+	versions := map[string]map[meta.Version]*meta.ServiceInfo{}
+	for _, s := range meta.AllServices {
+		if versions[s.Object] == nil {
+			versions[s.Object] = map[meta.Version]*meta.ServiceInfo{}
+		}
+		if _, ok := versions[s.Object][s.Version()]; !ok {
+			versions[s.Object][s.Version()] = s
+		}
+	}
+	var objects []string
+	for o := range versions {
+		objects = append(objects, o)
+	}
+	sort.Strings(objects)
+
+	var data []conversion
+	for _, o := range objects {
+		for _, from := range meta.AllVersions {
+			for _, to := range meta.AllVersions {
+				f, t := versions[o][from], versions[o][to]
+				if from != to && f != nil && t != nil {
+					data = append(data, conversion{Object: o, From: f, To: t})
+				}
+			}
+		}
+	}
+
+	tmpl := template.Must(template.New("converters").Parse(text))
+	if err := tmpl.Execute(wr, data); err != nil {
+		panic(err)
+	}
+}
+
 func main() {
 	flag.Parse()
 
@@ -1125,9 +1722,12 @@ func main() {
 		genHeader(out)
 		genStubs(out)
 		genTypes(out)
+		genCache(out)
+		genConverters(out)
 	case "test":
 		genUnitTestHeader(out)
 		genUnitTestServices(out)
+		genUnitTestMergeVersions(out)
 	default:
 		glog.Fatalf("Invalid -mode: %q", flags.mode)
 	}
@@ -32,18 +32,17 @@ import (
 	"github.com/bowei/gce-gen/pkg/cloud/meta"
 )
 
-const location = "location"
-
 func TestDisksGroup(t *testing.T) {
 	t.Parallel()
 
 	ctx := context.Background()
 	mock := NewMockGCE()
 
+	const location = "us-central1-b"
 	var key *meta.Key
-	keyAlpha := meta.ZonalKey("key-alpha", "location")
+	keyAlpha := meta.ZonalKey("key-alpha", "us-central1-b")
 	key = keyAlpha
-	keyGA := meta.ZonalKey("key-ga", "location")
+	keyGA := meta.ZonalKey("key-ga", "us-central1-b")
 	key = keyGA
 	// Ignore unused variables.
 	_, _, _ = ctx, mock, key
@@ -138,6 +137,7 @@ func TestFirewallsGroup(t *testing.T) {
 	ctx := context.Background()
 	mock := NewMockGCE()
 
+	const location = ""
 	var key *meta.Key
 	keyGA := meta.GlobalKey("key-ga")
 	key = keyGA
@@ -200,12 +200,13 @@ func TestInstancesGroup(t *testing.T) {
 	ctx := context.Background()
 	mock := NewMockGCE()
 
+	const location = "us-central1-b"
 	var key *meta.Key
-	keyAlpha := meta.ZonalKey("key-alpha", "location")
+	keyAlpha := meta.ZonalKey("key-alpha", "us-central1-b")
 	key = keyAlpha
-	keyBeta := meta.ZonalKey("key-beta", "location")
+	keyBeta := meta.ZonalKey("key-beta", "us-central1-b")
 	key = keyBeta
-	keyGA := meta.ZonalKey("key-ga", "location")
+	keyGA := meta.ZonalKey("key-ga", "us-central1-b")
 	key = keyGA
 	// Ignore unused variables.
 	_, _, _ = ctx, mock, key
@@ -334,6 +335,7 @@ func TestProjectsGroup(t *testing.T) {
 	ctx := context.Background()
 	mock := NewMockGCE()
 
+	const location = ""
 	var key *meta.Key
 	keyGA := meta.GlobalKey("key-ga")
 	key = keyGA
@@ -364,6 +366,7 @@ func TestRoutesGroup(t *testing.T) {
 	ctx := context.Background()
 	mock := NewMockGCE()
 
+	const location = ""
 	var key *meta.Key
 	keyGA := meta.GlobalKey("key-ga")
 	key = keyGA
@@ -426,8 +429,9 @@ func TestTargetPoolsGroup(t *testing.T) {
 	ctx := context.Background()
 	mock := NewMockGCE()
 
+	const location = "us-central1"
 	var key *meta.Key
-	keyGA := meta.RegionalKey("key-ga", "location")
+	keyGA := meta.RegionalKey("key-ga", "us-central1")
 	key = keyGA
 	// Ignore unused variables.
 	_, _, _ = ctx, mock, key
@@ -488,6 +492,7 @@ func TestGlobalForwardingRulesGroup(t *testing.T) {
 	ctx := context.Background()
 	mock := NewMockGCE()
 
+	const location = ""
 	var key *meta.Key
 	keyGA := meta.GlobalKey("key-ga")
 	key = keyGA
@@ -550,8 +555,9 @@ func TestNetworkEndpointGroupsGroup(t *testing.T) {
 	ctx := context.Background()
 	mock := NewMockGCE()
 
+	const location = "us-central1-b"
 	var key *meta.Key
-	keyAlpha := meta.ZonalKey("key-alpha", "location")
+	keyAlpha := meta.ZonalKey("key-alpha", "us-central1-b")
 	key = keyAlpha
 	// Ignore unused variables.
 	_, _, _ = ctx, mock, key
@@ -612,6 +618,7 @@ func TestTargetHttpProxiesGroup(t *testing.T) {
 	ctx := context.Background()
 	mock := NewMockGCE()
 
+	const location = ""
 	var key *meta.Key
 	keyGA := meta.GlobalKey("key-ga")
 	key = keyGA
@@ -674,6 +681,7 @@ func TestUrlMapsGroup(t *testing.T) {
 	ctx := context.Background()
 	mock := NewMockGCE()
 
+	const location = ""
 	var key *meta.Key
 	keyGA := meta.GlobalKey("key-ga")
 	key = keyGA
@@ -736,6 +744,7 @@ func TestZonesGroup(t *testing.T) {
 	ctx := context.Background()
 	mock := NewMockGCE()
 
+	const location = ""
 	var key *meta.Key
 	keyGA := meta.GlobalKey("key-ga")
 	key = keyGA
@@ -783,6 +792,7 @@ func TestGlobalAddressesGroup(t *testing.T) {
 	ctx := context.Background()
 	mock := NewMockGCE()
 
+	const location = ""
 	var key *meta.Key
 	keyGA := meta.GlobalKey("key-ga")
 	key = keyGA
@@ -845,6 +855,7 @@ func TestBackendServicesGroup(t *testing.T) {
 	ctx := context.Background()
 	mock := NewMockGCE()
 
+	const location = ""
 	var key *meta.Key
 	keyAlpha := meta.GlobalKey("key-alpha")
 	key = keyAlpha
@@ -943,10 +954,11 @@ func TestForwardingRulesGroup(t *testing.T) {
 	ctx := context.Background()
 	mock := NewMockGCE()
 
+	const location = "us-central1"
 	var key *meta.Key
-	keyAlpha := meta.RegionalKey("key-alpha", "location")
+	keyAlpha := meta.RegionalKey("key-alpha", "us-central1")
 	key = keyAlpha
-	keyGA := meta.RegionalKey("key-ga", "location")
+	keyGA := meta.RegionalKey("key-ga", "us-central1")
 	key = keyGA
 	// Ignore unused variables.
 	_, _, _ = ctx, mock, key
@@ -1041,6 +1053,7 @@ func TestRegionsGroup(t *testing.T) {
 	ctx := context.Background()
 	mock := NewMockGCE()
 
+	const location = ""
 	var key *meta.Key
 	keyGA := meta.GlobalKey("key-ga")
 	key = keyGA
@@ -1088,12 +1101,13 @@ func TestAddressesGroup(t *testing.T) {
 	ctx := context.Background()
 	mock := NewMockGCE()
 
+	const location = "us-central1"
 	var key *meta.Key
-	keyAlpha := meta.RegionalKey("key-alpha", "location")
+	keyAlpha := meta.RegionalKey("key-alpha", "us-central1")
 	key = keyAlpha
-	keyBeta := meta.RegionalKey("key-beta", "location")
+	keyBeta := meta.RegionalKey("key-beta", "us-central1")
 	key = keyBeta
-	keyGA := meta.RegionalKey("key-ga", "location")
+	keyGA := meta.RegionalKey("key-ga", "us-central1")
 	key = keyGA
 	// Ignore unused variables.
 	_, _, _ = ctx, mock, key
@@ -1222,8 +1236,9 @@ func TestRegionBackendServicesGroup(t *testing.T) {
 	ctx := context.Background()
 	mock := NewMockGCE()
 
+	const location = "us-central1"
 	var key *meta.Key
-	keyAlpha := meta.RegionalKey("key-alpha", "location")
+	keyAlpha := meta.RegionalKey("key-alpha", "us-central1")
 	key = keyAlpha
 	// Ignore unused variables.
 	_, _, _ = ctx, mock, key
@@ -1284,8 +1299,9 @@ func TestRegionDisksGroup(t *testing.T) {
 	ctx := context.Background()
 	mock := NewMockGCE()
 
+	const location = "us-central1"
 	var key *meta.Key
-	keyAlpha := meta.RegionalKey("key-alpha", "location")
+	keyAlpha := meta.RegionalKey("key-alpha", "us-central1")
 	key = keyAlpha
 	// Ignore unused variables.
 	_, _, _ = ctx, mock, key
@@ -1346,6 +1362,7 @@ func TestHealthChecksGroup(t *testing.T) {
 	ctx := context.Background()
 	mock := NewMockGCE()
 
+	const location = ""
 	var key *meta.Key
 	keyAlpha := meta.GlobalKey("key-alpha")
 	key = keyAlpha
@@ -1444,6 +1461,7 @@ func TestHttpHealthChecksGroup(t *testing.T) {
 	ctx := context.Background()
 	mock := NewMockGCE()
 
+	const location = ""
 	var key *meta.Key
 	keyGA := meta.GlobalKey("key-ga")
 	key = keyGA
@@ -1506,6 +1524,7 @@ func TestHttpsHealthChecksGroup(t *testing.T) {
 	ctx := context.Background()
 	mock := NewMockGCE()
 
+	const location = ""
 	var key *meta.Key
 	keyGA := meta.GlobalKey("key-ga")
 	key = keyGA
@@ -1568,8 +1587,9 @@ func TestInstanceGroupsGroup(t *testing.T) {
 	ctx := context.Background()
 	mock := NewMockGCE()
 
+	const location = "us-central1-b"
 	var key *meta.Key
-	keyGA := meta.ZonalKey("key-ga", "location")
+	keyGA := meta.ZonalKey("key-ga", "us-central1-b")
 	key = keyGA
 	// Ignore unused variables.
 	_, _, _ = ctx, mock, key
@@ -1630,6 +1650,7 @@ func TestSslCertificatesGroup(t *testing.T) {
 	ctx := context.Background()
 	mock := NewMockGCE()
 
+	const location = ""
 	var key *meta.Key
 	keyGA := meta.GlobalKey("key-ga")
 	key = keyGA
@@ -1692,6 +1713,7 @@ func TestTargetHttpsProxiesGroup(t *testing.T) {
 	ctx := context.Background()
 	mock := NewMockGCE()
 
+	const location = ""
 	var key *meta.Key
 	keyGA := meta.GlobalKey("key-ga")
 	key = keyGA
@@ -1747,3 +1769,303 @@ func TestTargetHttpsProxiesGroup(t *testing.T) {
 		t.Errorf("TargetHttpsProxies().Delete(%v, %v) = nil; want error", ctx, key)
 	}
 }
+
+func TestAddressesMergeVersions(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := NewMockGCE()
+
+	// Insert with GA.
+	{
+		key := meta.RegionalKey("key-ga", "us-central1")
+		obj := &ga.Address{}
+		fields := setVersionOnlyFields(obj, &alpha.Address{}, &beta.Address{})
+		if err := mock.Addresses().Insert(ctx, *key, obj); err != nil {
+			t.Fatalf("Addresses().Insert(%v, %v, %v) = %v; want nil", ctx, key, obj, err)
+		}
+		got, err := mock.Addresses().Get(ctx, *key)
+		if err != nil {
+			t.Fatalf("Addresses().Get(%v, %v) = _, %v; want nil", ctx, key, err)
+		}
+		if lost := lostVersionOnlyFields(got, fields); len(lost) > 0 {
+			t.Errorf("Addresses().Get(%v, %v) lost %v; want %v kept", ctx, key, lost, fields)
+		}
+	}
+
+	// Insert with Alpha.
+	{
+		key := meta.RegionalKey("key-alpha", "us-central1")
+		obj := &alpha.Address{}
+		fields := setVersionOnlyFields(obj, &ga.Address{}, &beta.Address{})
+		if err := mock.AlphaAddresses().Insert(ctx, *key, obj); err != nil {
+			t.Fatalf("AlphaAddresses().Insert(%v, %v, %v) = %v; want nil", ctx, key, obj, err)
+		}
+		got, err := mock.AlphaAddresses().Get(ctx, *key)
+		if err != nil {
+			t.Fatalf("AlphaAddresses().Get(%v, %v) = _, %v; want nil", ctx, key, err)
+		}
+		if lost := lostVersionOnlyFields(got, fields); len(lost) > 0 {
+			t.Errorf("AlphaAddresses().Get(%v, %v) lost %v; want %v kept", ctx, key, lost, fields)
+		}
+	}
+
+	// Insert with Beta.
+	{
+		key := meta.RegionalKey("key-beta", "us-central1")
+		obj := &beta.Address{}
+		fields := setVersionOnlyFields(obj, &ga.Address{}, &alpha.Address{})
+		if err := mock.BetaAddresses().Insert(ctx, *key, obj); err != nil {
+			t.Fatalf("BetaAddresses().Insert(%v, %v, %v) = %v; want nil", ctx, key, obj, err)
+		}
+		got, err := mock.BetaAddresses().Get(ctx, *key)
+		if err != nil {
+			t.Fatalf("BetaAddresses().Get(%v, %v) = _, %v; want nil", ctx, key, err)
+		}
+		if lost := lostVersionOnlyFields(got, fields); len(lost) > 0 {
+			t.Errorf("BetaAddresses().Get(%v, %v) lost %v; want %v kept", ctx, key, lost, fields)
+		}
+	}
+}
+
+func TestBackendServicesMergeVersions(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := NewMockGCE()
+
+	// Insert with GA.
+	{
+		key := meta.GlobalKey("key-ga")
+		obj := &ga.BackendService{}
+		fields := setVersionOnlyFields(obj, &alpha.BackendService{})
+		if err := mock.BackendServices().Insert(ctx, *key, obj); err != nil {
+			t.Fatalf("BackendServices().Insert(%v, %v, %v) = %v; want nil", ctx, key, obj, err)
+		}
+		if got, err := mock.AlphaBackendServices().Get(ctx, *key); err != nil {
+			t.Errorf("AlphaBackendServices().Get(%v, %v) = _, %v; want nil", ctx, key, err)
+		} else if err := mock.AlphaBackendServices().Update(ctx, *key, got); err != nil {
+			t.Errorf("AlphaBackendServices().Update(%v, %v, %v) = %v; want nil", ctx, key, got, err)
+		}
+		got, err := mock.BackendServices().Get(ctx, *key)
+		if err != nil {
+			t.Fatalf("BackendServices().Get(%v, %v) = _, %v; want nil", ctx, key, err)
+		}
+		if lost := lostVersionOnlyFields(got, fields); len(lost) > 0 {
+			t.Errorf("BackendServices().Get(%v, %v) lost %v; want %v kept", ctx, key, lost, fields)
+		}
+	}
+
+	// Insert with Alpha.
+	{
+		key := meta.GlobalKey("key-alpha")
+		obj := &alpha.BackendService{}
+		fields := setVersionOnlyFields(obj, &ga.BackendService{})
+		if err := mock.AlphaBackendServices().Insert(ctx, *key, obj); err != nil {
+			t.Fatalf("AlphaBackendServices().Insert(%v, %v, %v) = %v; want nil", ctx, key, obj, err)
+		}
+		if got, err := mock.BackendServices().Get(ctx, *key); err != nil {
+			t.Errorf("BackendServices().Get(%v, %v) = _, %v; want nil", ctx, key, err)
+		} else if err := mock.BackendServices().Update(ctx, *key, got); err != nil {
+			t.Errorf("BackendServices().Update(%v, %v, %v) = %v; want nil", ctx, key, got, err)
+		}
+		got, err := mock.AlphaBackendServices().Get(ctx, *key)
+		if err != nil {
+			t.Fatalf("AlphaBackendServices().Get(%v, %v) = _, %v; want nil", ctx, key, err)
+		}
+		if lost := lostVersionOnlyFields(got, fields); len(lost) > 0 {
+			t.Errorf("AlphaBackendServices().Get(%v, %v) lost %v; want %v kept", ctx, key, lost, fields)
+		}
+	}
+}
+
+func TestDisksMergeVersions(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := NewMockGCE()
+
+	// Insert with GA.
+	{
+		key := meta.ZonalKey("key-ga", "us-central1-b")
+		obj := &ga.Disk{}
+		fields := setVersionOnlyFields(obj, &alpha.Disk{})
+		if err := mock.Disks().Insert(ctx, *key, obj); err != nil {
+			t.Fatalf("Disks().Insert(%v, %v, %v) = %v; want nil", ctx, key, obj, err)
+		}
+		got, err := mock.Disks().Get(ctx, *key)
+		if err != nil {
+			t.Fatalf("Disks().Get(%v, %v) = _, %v; want nil", ctx, key, err)
+		}
+		if lost := lostVersionOnlyFields(got, fields); len(lost) > 0 {
+			t.Errorf("Disks().Get(%v, %v) lost %v; want %v kept", ctx, key, lost, fields)
+		}
+	}
+
+	// Insert with Alpha.
+	{
+		key := meta.ZonalKey("key-alpha", "us-central1-b")
+		obj := &alpha.Disk{}
+		fields := setVersionOnlyFields(obj, &ga.Disk{})
+		if err := mock.AlphaDisks().Insert(ctx, *key, obj); err != nil {
+			t.Fatalf("AlphaDisks().Insert(%v, %v, %v) = %v; want nil", ctx, key, obj, err)
+		}
+		got, err := mock.AlphaDisks().Get(ctx, *key)
+		if err != nil {
+			t.Fatalf("AlphaDisks().Get(%v, %v) = _, %v; want nil", ctx, key, err)
+		}
+		if lost := lostVersionOnlyFields(got, fields); len(lost) > 0 {
+			t.Errorf("AlphaDisks().Get(%v, %v) lost %v; want %v kept", ctx, key, lost, fields)
+		}
+	}
+}
+
+func TestForwardingRulesMergeVersions(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := NewMockGCE()
+
+	// Insert with GA.
+	{
+		key := meta.RegionalKey("key-ga", "us-central1")
+		obj := &ga.ForwardingRule{}
+		fields := setVersionOnlyFields(obj, &alpha.ForwardingRule{})
+		if err := mock.ForwardingRules().Insert(ctx, *key, obj); err != nil {
+			t.Fatalf("ForwardingRules().Insert(%v, %v, %v) = %v; want nil", ctx, key, obj, err)
+		}
+		got, err := mock.ForwardingRules().Get(ctx, *key)
+		if err != nil {
+			t.Fatalf("ForwardingRules().Get(%v, %v) = _, %v; want nil", ctx, key, err)
+		}
+		if lost := lostVersionOnlyFields(got, fields); len(lost) > 0 {
+			t.Errorf("ForwardingRules().Get(%v, %v) lost %v; want %v kept", ctx, key, lost, fields)
+		}
+	}
+
+	// Insert with Alpha.
+	{
+		key := meta.RegionalKey("key-alpha", "us-central1")
+		obj := &alpha.ForwardingRule{}
+		fields := setVersionOnlyFields(obj, &ga.ForwardingRule{})
+		if err := mock.AlphaForwardingRules().Insert(ctx, *key, obj); err != nil {
+			t.Fatalf("AlphaForwardingRules().Insert(%v, %v, %v) = %v; want nil", ctx, key, obj, err)
+		}
+		got, err := mock.AlphaForwardingRules().Get(ctx, *key)
+		if err != nil {
+			t.Fatalf("AlphaForwardingRules().Get(%v, %v) = _, %v; want nil", ctx, key, err)
+		}
+		if lost := lostVersionOnlyFields(got, fields); len(lost) > 0 {
+			t.Errorf("AlphaForwardingRules().Get(%v, %v) lost %v; want %v kept", ctx, key, lost, fields)
+		}
+	}
+}
+
+func TestHealthChecksMergeVersions(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := NewMockGCE()
+
+	// Insert with GA.
+	{
+		key := meta.GlobalKey("key-ga")
+		obj := &ga.HealthCheck{}
+		fields := setVersionOnlyFields(obj, &alpha.HealthCheck{})
+		if err := mock.HealthChecks().Insert(ctx, *key, obj); err != nil {
+			t.Fatalf("HealthChecks().Insert(%v, %v, %v) = %v; want nil", ctx, key, obj, err)
+		}
+		if got, err := mock.AlphaHealthChecks().Get(ctx, *key); err != nil {
+			t.Errorf("AlphaHealthChecks().Get(%v, %v) = _, %v; want nil", ctx, key, err)
+		} else if err := mock.AlphaHealthChecks().Update(ctx, *key, got); err != nil {
+			t.Errorf("AlphaHealthChecks().Update(%v, %v, %v) = %v; want nil", ctx, key, got, err)
+		}
+		got, err := mock.HealthChecks().Get(ctx, *key)
+		if err != nil {
+			t.Fatalf("HealthChecks().Get(%v, %v) = _, %v; want nil", ctx, key, err)
+		}
+		if lost := lostVersionOnlyFields(got, fields); len(lost) > 0 {
+			t.Errorf("HealthChecks().Get(%v, %v) lost %v; want %v kept", ctx, key, lost, fields)
+		}
+	}
+
+	// Insert with Alpha.
+	{
+		key := meta.GlobalKey("key-alpha")
+		obj := &alpha.HealthCheck{}
+		fields := setVersionOnlyFields(obj, &ga.HealthCheck{})
+		if err := mock.AlphaHealthChecks().Insert(ctx, *key, obj); err != nil {
+			t.Fatalf("AlphaHealthChecks().Insert(%v, %v, %v) = %v; want nil", ctx, key, obj, err)
+		}
+		if got, err := mock.HealthChecks().Get(ctx, *key); err != nil {
+			t.Errorf("HealthChecks().Get(%v, %v) = _, %v; want nil", ctx, key, err)
+		} else if err := mock.HealthChecks().Update(ctx, *key, got); err != nil {
+			t.Errorf("HealthChecks().Update(%v, %v, %v) = %v; want nil", ctx, key, got, err)
+		}
+		got, err := mock.AlphaHealthChecks().Get(ctx, *key)
+		if err != nil {
+			t.Fatalf("AlphaHealthChecks().Get(%v, %v) = _, %v; want nil", ctx, key, err)
+		}
+		if lost := lostVersionOnlyFields(got, fields); len(lost) > 0 {
+			t.Errorf("AlphaHealthChecks().Get(%v, %v) lost %v; want %v kept", ctx, key, lost, fields)
+		}
+	}
+}
+
+func TestInstancesMergeVersions(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := NewMockGCE()
+
+	// Insert with GA.
+	{
+		key := meta.ZonalKey("key-ga", "us-central1-b")
+		obj := &ga.Instance{}
+		fields := setVersionOnlyFields(obj, &alpha.Instance{}, &beta.Instance{})
+		if err := mock.Instances().Insert(ctx, *key, obj); err != nil {
+			t.Fatalf("Instances().Insert(%v, %v, %v) = %v; want nil", ctx, key, obj, err)
+		}
+		got, err := mock.Instances().Get(ctx, *key)
+		if err != nil {
+			t.Fatalf("Instances().Get(%v, %v) = _, %v; want nil", ctx, key, err)
+		}
+		if lost := lostVersionOnlyFields(got, fields); len(lost) > 0 {
+			t.Errorf("Instances().Get(%v, %v) lost %v; want %v kept", ctx, key, lost, fields)
+		}
+	}
+
+	// Insert with Alpha.
+	{
+		key := meta.ZonalKey("key-alpha", "us-central1-b")
+		obj := &alpha.Instance{}
+		fields := setVersionOnlyFields(obj, &ga.Instance{}, &beta.Instance{})
+		if err := mock.AlphaInstances().Insert(ctx, *key, obj); err != nil {
+			t.Fatalf("AlphaInstances().Insert(%v, %v, %v) = %v; want nil", ctx, key, obj, err)
+		}
+		got, err := mock.AlphaInstances().Get(ctx, *key)
+		if err != nil {
+			t.Fatalf("AlphaInstances().Get(%v, %v) = _, %v; want nil", ctx, key, err)
+		}
+		if lost := lostVersionOnlyFields(got, fields); len(lost) > 0 {
+			t.Errorf("AlphaInstances().Get(%v, %v) lost %v; want %v kept", ctx, key, lost, fields)
+		}
+	}
+
+	// Insert with Beta.
+	{
+		key := meta.ZonalKey("key-beta", "us-central1-b")
+		obj := &beta.Instance{}
+		fields := setVersionOnlyFields(obj, &ga.Instance{}, &alpha.Instance{})
+		if err := mock.BetaInstances().Insert(ctx, *key, obj); err != nil {
+			t.Fatalf("BetaInstances().Insert(%v, %v, %v) = %v; want nil", ctx, key, obj, err)
+		}
+		got, err := mock.BetaInstances().Get(ctx, *key)
+		if err != nil {
+			t.Fatalf("BetaInstances().Get(%v, %v) = _, %v; want nil", ctx, key, err)
+		}
+		if lost := lostVersionOnlyFields(got, fields); len(lost) > 0 {
+			t.Errorf("BetaInstances().Get(%v, %v) lost %v; want %v kept", ctx, key, lost, fields)
+		}
+	}
+}
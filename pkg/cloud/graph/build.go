@@ -0,0 +1,248 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package graph
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/golang/glog"
+
+	"github.com/bowei/gce-gen/pkg/cloud"
+	"github.com/bowei/gce-gen/pkg/cloud/filter"
+	"github.com/bowei/gce-gen/pkg/cloud/meta"
+)
+
+// BuildOptions configure Build().
+type BuildOptions struct {
+	// Resources to list (e.g. "backendServices"). All of the resources with
+	// a List() method in meta.AllServices are listed if it is empty.
+	Resources []string
+}
+
+// Build returns the graph of all of the resources of the project of c, as
+// listed by the List() and AggregatedList() methods of the services in
+// meta.AllServices, and of the resources that they reference. Regional and
+// zonal resources are listed in each region and zone of the project, unless
+// the service has an AggregatedList() method.
+//
+// A referenced resource that was not listed is in the graph with a nil
+// Object. It is missing (see Dangling()) if it is in the project and its
+// resource was listed.
+func Build(ctx context.Context, c cloud.Cloud, opts BuildOptions) (*Graph, error) {
+	g := New()
+	topology := cloud.NewTopology(c, 0)
+	listed := map[string]bool{}
+	projects := map[string]bool{}
+
+	for _, svc := range listServices(c, opts.Resources) {
+		objs, err := listAll(ctx, svc.svc, svc.keyType, topology)
+		if err != nil {
+			return nil, fmt.Errorf("graph: listing %s: %v", svc.resource, err)
+		}
+		listed[svc.resource+"/"+string(svc.keyType)] = true
+		for _, obj := range objs {
+			id, ok := selfLinkID(obj)
+			if !ok {
+				glog.Warningf("graph: %T in %s without a self link", obj, svc.resource)
+				continue
+			}
+			n, _ := g.add(id)
+			n.Object = obj
+			projects[id.ProjectID] = true
+		}
+	}
+
+	for _, n := range g.Nodes() {
+		refs, err := cloud.References(n.Object)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range refs {
+			if id.Key == nil || id.Equal(n.ID) {
+				continue
+			}
+			ref, added := g.add(id)
+			g.addRef(n, ref)
+			if added && projects[id.ProjectID] && listed[id.Resource+"/"+string(id.Key.Type())] {
+				ref.missing = true
+			}
+		}
+	}
+	return g, nil
+}
+
+// Reference from a resource to another.
+type Reference struct {
+	From, To *Node
+}
+
+// String returns the reference as "from -> to".
+func (r *Reference) String() string {
+	return fmt.Sprintf("%s -> %s", r.From, r.To)
+}
+
+// Dangling returns the references to resources that are known not to exist
+// (e.g. a BackendService referencing a deleted InstanceGroup), sorted.
+func (g *Graph) Dangling() []*Reference {
+	var ret []*Reference
+	for _, n := range g.Nodes() {
+		if !n.missing {
+			continue
+		}
+		for _, u := range n.Users {
+			ret = append(ret, &Reference{From: u, To: n})
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].String() < ret[j].String() })
+	return ret
+}
+
+// Orphans returns the resources that exist, that no other resource references
+// and for which owned returns true, sorted by name. Resources such as
+// forwarding rules, firewalls and instances are never referenced, so owned
+// should usually exclude them.
+func (g *Graph) Orphans(owned func(n *Node) bool) []*Node {
+	var ret []*Node
+	for _, n := range g.Nodes() {
+		if n.Object != nil && len(n.Users) == 0 && owned(n) {
+			ret = append(ret, n)
+		}
+	}
+	return ret
+}
+
+// HasLabel returns a function for Orphans() that is true for the resources
+// with the label key. The value of the label must be value, unless value is
+// empty. Objects without labels never match.
+func HasLabel(key, value string) func(n *Node) bool {
+	return func(n *Node) bool {
+		if n.Object == nil {
+			return false
+		}
+		labels := reflect.ValueOf(n.Object).Elem().FieldByName("Labels")
+		if !labels.IsValid() || labels.Kind() != reflect.Map {
+			return false
+		}
+		v := labels.MapIndex(reflect.ValueOf(key))
+		return v.IsValid() && (value == "" || v.String() == value)
+	}
+}
+
+// listService is a service listed by Build().
+type listService struct {
+	resource string
+	keyType  meta.KeyType
+	svc      reflect.Value
+}
+
+// listServices returns the services of c that list each of the resources,
+// preferring the GA services to the beta and alpha ones.
+func listServices(c cloud.Cloud, resources []string) []*listService {
+	want := map[string]bool{}
+	for _, r := range resources {
+		want[r] = true
+	}
+	var ret []*listService
+	seen := map[string]bool{}
+	for _, si := range meta.AllServices {
+		t := si.Resource + "/" + string(si.KeyType())
+		if len(want) > 0 && !want[si.Resource] || seen[t] {
+			continue
+		}
+		seen[t] = true
+		resource, keyType := si.Resource, si.KeyType()
+		svc, _, ok := cloud.FindService(c, func(si *meta.ServiceInfo) bool {
+			return si.Resource == resource && si.KeyType() == keyType
+		}, "List")
+		if ok {
+			ret = append(ret, &listService{resource: resource, keyType: keyType, svc: svc})
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].resource != ret[j].resource {
+			return ret[i].resource < ret[j].resource
+		}
+		return ret[i].keyType < ret[j].keyType
+	})
+	return ret
+}
+
+// listAll returns all of the objects of svc, in all of the regions or zones
+// of topology.
+func listAll(ctx context.Context, svc reflect.Value, keyType meta.KeyType, topology *cloud.Topology) ([]interface{}, error) {
+	var ret []interface{}
+	appendAll := func(list reflect.Value) {
+		for i := 0; i < list.Len(); i++ {
+			ret = append(ret, list.Index(i).Interface())
+		}
+	}
+
+	if keyType == meta.Global {
+		list, err := cloud.CallMethod(svc, "List", ctx, filter.None)
+		if err != nil {
+			return nil, err
+		}
+		appendAll(reflect.ValueOf(list))
+		return ret, nil
+	}
+
+	if cloud.HasMethods(svc, "AggregatedList") {
+		lists, err := cloud.CallMethod(svc, "AggregatedList", ctx, filter.None)
+		if err != nil {
+			return nil, err
+		}
+		m := reflect.ValueOf(lists)
+		var locations []string
+		for _, k := range m.MapKeys() {
+			locations = append(locations, k.String())
+		}
+		sort.Strings(locations)
+		for _, l := range locations {
+			appendAll(m.MapIndex(reflect.ValueOf(l)))
+		}
+		return ret, nil
+	}
+
+	locations, err := topology.Regions(ctx)
+	if keyType == meta.Zonal {
+		locations, err = topology.Zones(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range locations {
+		list, err := cloud.CallMethod(svc, "List", ctx, l, filter.None)
+		if err != nil {
+			return nil, err
+		}
+		appendAll(reflect.ValueOf(list))
+	}
+	return ret, nil
+}
+
+// selfLinkID returns the ID of obj, from its SelfLink.
+func selfLinkID(obj interface{}) (*cloud.ResourceID, bool) {
+	v := reflect.ValueOf(obj).Elem().FieldByName("SelfLink")
+	if !v.IsValid() || v.Kind() != reflect.String {
+		return nil, false
+	}
+	id, ok := cloud.ParseReference(v.String())
+	return id, ok && id.Key != nil
+}
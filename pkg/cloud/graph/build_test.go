@@ -0,0 +1,128 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package graph
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	ga "google.golang.org/api/compute/v1"
+
+	"github.com/bowei/gce-gen/pkg/cloud"
+	"github.com/bowei/gce-gen/pkg/cloud/meta"
+)
+
+func newProject(t *testing.T) *cloud.MockGCE {
+	ctx := context.Background()
+	mock := cloud.NewMockGCE(cloud.WithMockTopology(map[string][]string{"us-central1": {"us-central1-b"}}))
+	owned := map[string]string{"owner": "me"}
+
+	for _, err := range []error{
+		mock.HealthChecks().Insert(ctx, *meta.GlobalKey("hc"), &ga.HealthCheck{}),
+		mock.InstanceGroups().Insert(ctx, *meta.ZonalKey("ig", zone), &ga.InstanceGroup{}),
+		mock.BackendServices().Insert(ctx, *meta.GlobalKey("bs"), &ga.BackendService{
+			Backends: []*ga.Backend{
+				{Group: "zones/" + zone + "/instanceGroups/ig"},
+				{Group: "zones/" + zone + "/instanceGroups/gone"},
+			},
+			HealthChecks: []string{"global/healthChecks/hc", "projects/other/global/healthChecks/hc"},
+		}),
+		mock.UrlMaps().Insert(ctx, *meta.GlobalKey("um"), &ga.UrlMap{
+			DefaultService: "global/backendServices/bs",
+			PathMatchers:   []*ga.PathMatcher{{DefaultService: "global/backendServices/deleted"}},
+		}),
+		mock.Firewalls().Insert(ctx, *meta.GlobalKey("fw"), &ga.Firewall{Network: "global/networks/default"}),
+		mock.Disks().Insert(ctx, *meta.ZonalKey("orphan", zone), &ga.Disk{Labels: owned}),
+		mock.Disks().Insert(ctx, *meta.ZonalKey("attached", zone), &ga.Disk{Labels: owned}),
+		mock.Disks().Insert(ctx, *meta.ZonalKey("not-owned", zone), &ga.Disk{Labels: map[string]string{"owner": "someone-else"}}),
+		mock.Instances().Insert(ctx, *meta.ZonalKey("vm", zone), &ga.Instance{
+			Disks: []*ga.AttachedDisk{{Source: "zones/" + zone + "/disks/attached"}},
+		}),
+	} {
+		if err != nil {
+			t.Fatalf("Insert() = %v", err)
+		}
+	}
+	return mock
+}
+
+func TestBuild(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := newProject(t)
+	g, err := Build(ctx, mock, BuildOptions{})
+	if err != nil {
+		t.Fatalf("Build() = _, %v", err)
+	}
+
+	var dangling []string
+	for _, r := range g.Dangling() {
+		dangling = append(dangling, r.String())
+	}
+	wantDangling := []string{
+		"projects/mock-project/global/backendServices/bs -> projects/mock-project/zones/us-central1-b/instanceGroups/gone",
+		"projects/mock-project/global/urlMaps/um -> projects/mock-project/global/backendServices/deleted",
+	}
+	if !reflect.DeepEqual(dangling, wantDangling) {
+		t.Errorf("Dangling() = %v; want %v", dangling, wantDangling)
+	}
+
+	var orphans []string
+	for _, n := range g.Orphans(HasLabel("owner", "me")) {
+		orphans = append(orphans, n.String())
+	}
+	wantOrphans := []string{"projects/mock-project/zones/us-central1-b/disks/orphan"}
+	if !reflect.DeepEqual(orphans, wantOrphans) {
+		t.Errorf("Orphans(owner=me) = %v; want %v", orphans, wantOrphans)
+	}
+	if got := g.Orphans(HasLabel("owner", "")); len(got) != 2 {
+		t.Errorf("Orphans(owner) = %v; want the orphan and not-owned disks", got)
+	}
+
+	// Read-only resources are listed too.
+	zoneNode := g.Node(&cloud.ResourceID{ProjectID: "mock-project", Resource: "zones", Key: meta.GlobalKey(zone)})
+	if zoneNode == nil || zoneNode.Object == nil {
+		t.Errorf("zone node = %+v; want a listed zone", zoneNode)
+	}
+}
+
+func TestBuildResources(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := newProject(t)
+	g, err := Build(ctx, mock, BuildOptions{Resources: []string{"backendServices"}})
+	if err != nil {
+		t.Fatalf("Build() = _, %v", err)
+	}
+	// The references of the backend service cannot be checked, as instance
+	// groups were not listed.
+	if got := g.Dangling(); len(got) != 0 {
+		t.Errorf("Dangling() = %v; want none", got)
+	}
+	var listed []string
+	for _, n := range g.Nodes() {
+		if n.Object != nil {
+			listed = append(listed, n.String())
+		}
+	}
+	if want := []string{"projects/mock-project/global/backendServices/bs"}; !reflect.DeepEqual(listed, want) {
+		t.Errorf("listed nodes = %v; want %v", listed, want)
+	}
+}
@@ -0,0 +1,265 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package graph builds the graph of the references between compute
+// resources (a UrlMap references its BackendServices, which reference their
+// HealthChecks and InstanceGroups...).
+//
+// Build() lists all of the resources of a project to find dangling
+// references and orphaned resources:
+//
+//  g, err := graph.Build(ctx, c, graph.BuildOptions{})
+//  for _, r := range g.Dangling() {
+//    fmt.Printf("%v does not exist\n", r)
+//  }
+//  orphans := g.Orphans(graph.HasLabel("owner", "my-cluster"))
+//
+// Discover() follows the references of a few resources, and Teardown() uses
+// it to delete resources in dependency order:
+//
+//  root := cloud.NewResourceID("forwardingRules", *meta.GlobalKey("my-lb"))
+//  err := graph.Teardown(ctx, c, graph.TeardownOptions{Parallelism: 4}, root)
+//
+// Build() finds the references in all of the fields of the objects that hold
+// resource URLs (see cloud.References()). Discover() only follows the fields
+// in DefaultEdges, so that a teardown does not delete the instances of a
+// target pool or the disks of an instance; more are followed with
+// DiscoverOptions.Follow.
+package graph
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/bowei/gce-gen/pkg/cloud"
+	"github.com/bowei/gce-gen/pkg/cloud/meta"
+)
+
+// Node is a resource in the Graph.
+type Node struct {
+	ID *cloud.ResourceID
+	// Object returned by Get() (e.g. *ga.UrlMap). It is nil if the resource
+	// does not exist.
+	Object interface{}
+	// Refs are the nodes of the resources referenced by Object.
+	Refs []*Node
+	// Users are the nodes that reference this one.
+	Users []*Node
+
+	// missing is true if the resource is known not to exist.
+	missing bool
+}
+
+// String returns the path of the resource, e.g.
+// "projects/my-project/global/urlMaps/my-lb".
+func (n *Node) String() string {
+	return nodeName(n.ID)
+}
+
+// Graph of compute resources and the references between them.
+type Graph struct {
+	nodes map[string]*Node
+}
+
+// New returns an empty graph.
+func New() *Graph {
+	return &Graph{nodes: map[string]*Node{}}
+}
+
+// Node returns the node for id, or nil if it is not in the graph.
+func (g *Graph) Node(id *cloud.ResourceID) *Node {
+	return g.nodes[nodeName(id)]
+}
+
+// Nodes returns the nodes of the graph, sorted by name.
+func (g *Graph) Nodes() []*Node {
+	var ret []*Node
+	for _, n := range g.nodes {
+		ret = append(ret, n)
+	}
+	sortNodes(ret)
+	return ret
+}
+
+// add returns the node for id, adding it to the graph if needed. The bool is
+// true if the node was added.
+func (g *Graph) add(id *cloud.ResourceID) (*Node, bool) {
+	name := nodeName(id)
+	if n, ok := g.nodes[name]; ok {
+		return n, false
+	}
+	n := &Node{ID: id}
+	g.nodes[name] = n
+	return n, true
+}
+
+func (g *Graph) addRef(from, to *Node) {
+	from.Refs = append(from.Refs, to)
+	to.Users = append(to.Users, from)
+}
+
+// Edges are the fields of the objects followed by Discover(), by resource
+// (e.g. "urlMaps": {"defaultService"}). The fields are JSON paths, as in
+// cloud.FieldReferences().
+type Edges map[string][]string
+
+// DefaultEdges are the references followed by Discover(): from forwarding
+// rules to target proxies, target pools and backend services, then to URL
+// maps, backend services, SSL certificates, health checks, instance groups
+// and NEGs. The references to instances, disks and addresses, which are
+// usually not owned by a load balancer, are not followed.
+var DefaultEdges = Edges{
+	"forwardingRules":    {"target", "backendService"},
+	"targetHttpProxies":  {"urlMap"},
+	"targetHttpsProxies": {"urlMap", "sslCertificates"},
+	"targetSslProxies":   {"service", "sslCertificates"},
+	"targetTcpProxies":   {"service"},
+	"targetPools":        {"healthChecks"},
+	"urlMaps":            {"defaultService", "pathMatchers.defaultService", "pathMatchers.pathRules.service"},
+	"backendServices":    {"backends.group", "healthChecks"},
+}
+
+// DiscoverOptions configure Discover().
+type DiscoverOptions struct {
+	// Follow are the edges followed in addition to DefaultEdges, e.g.
+	// graph.Edges{"targetPools": {"instances"}} for the instances of the
+	// target pools.
+	Follow Edges
+}
+
+// Discover returns the graph of the roots and of all of the resources that
+// they reference, directly or not, through DefaultEdges and opts.Follow.
+// Only the resources that can be deleted with a service in meta.AllServices
+// are part of the graph, so for instance the network of a Firewall is not.
+// Resources that do not exist are in the graph with a nil Object.
+func Discover(ctx context.Context, c cloud.Cloud, opts DiscoverOptions, roots ...*cloud.ResourceID) (*Graph, error) {
+	g := New()
+	fetch := func(n *Node) error {
+		svc, _ := service(c, n.ID)
+		obj, err := cloud.CallMethod(svc, "Get", ctx, *n.ID.QualifiedKey())
+		if cloud.IsNotFound(err) {
+			n.missing = true
+			return nil
+		}
+		n.Object = obj
+		return err
+	}
+
+	var queue []*Node
+	for _, id := range roots {
+		if _, ok := service(c, id); !ok {
+			return nil, fmt.Errorf("graph: no service to delete %s", nodeName(id))
+		}
+		n, added := g.add(id)
+		if !added {
+			continue
+		}
+		if err := fetch(n); err != nil {
+			return nil, err
+		}
+		g.setProject(n)
+		queue = append(queue, n)
+	}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		if n.Object == nil {
+			continue
+		}
+		fields := append(append([]string(nil), DefaultEdges[n.ID.Resource]...), opts.Follow[n.ID.Resource]...)
+		refs, err := cloud.FieldReferences(n.Object, fields)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range refs {
+			if _, ok := service(c, id); !ok || id.Equal(n.ID) {
+				continue
+			}
+			ref, added := g.add(id)
+			g.addRef(n, ref)
+			if !added {
+				continue
+			}
+			if err := fetch(ref); err != nil {
+				return nil, err
+			}
+			queue = append(queue, ref)
+		}
+	}
+	return g, nil
+}
+
+// setProject sets the project of the ID of n, if it does not have one, to
+// the project of the SelfLink of its object. Root IDs often do not have a
+// project, while references always do.
+func (g *Graph) setProject(n *Node) {
+	if n.ID.ProjectID != "" || n.Object == nil {
+		return
+	}
+	r, ok := selfLinkID(n.Object)
+	if !ok || r.ProjectID == "" {
+		return
+	}
+	id := *n.ID
+	id.ProjectID = r.ProjectID
+	if _, ok := g.nodes[nodeName(&id)]; ok {
+		return
+	}
+	delete(g.nodes, nodeName(n.ID))
+	n.ID = &id
+	g.nodes[nodeName(n.ID)] = n
+}
+
+// service returns the wrapper in c (e.g. cloud.UrlMaps) that gets and
+// deletes the resource of id. The GA service is preferred to the beta and
+// alpha ones.
+func service(c cloud.Cloud, id *cloud.ResourceID) (reflect.Value, bool) {
+	if id.Key == nil {
+		return reflect.Value{}, false
+	}
+	svc, _, ok := cloud.FindService(c, func(si *meta.ServiceInfo) bool {
+		return si.Resource == id.Resource && si.KeyType() == id.Key.Type()
+	}, "Get", "Delete")
+	return svc, ok
+}
+
+func nodeName(id *cloud.ResourceID) string {
+	var name string
+	switch {
+	case id.Key == nil:
+	case id.Resource == "regions" || id.Resource == "zones":
+		name = id.Resource + "/" + id.Key.Name
+	case id.Key.Type() == meta.Zonal:
+		name = fmt.Sprintf("zones/%s/%s/%s", id.Key.Zone, id.Resource, id.Key.Name)
+	case id.Key.Type() == meta.Regional:
+		name = fmt.Sprintf("regions/%s/%s/%s", id.Key.Region, id.Resource, id.Key.Name)
+	default:
+		name = fmt.Sprintf("global/%s/%s", id.Resource, id.Key.Name)
+	}
+	if id.ProjectID == "" {
+		return name
+	}
+	if name == "" {
+		return "projects/" + id.ProjectID
+	}
+	return "projects/" + id.ProjectID + "/" + name
+}
+
+func sortNodes(nodes []*Node) {
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].String() < nodes[j].String() })
+}
@@ -0,0 +1,251 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package graph
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/golang/glog"
+
+	"github.com/bowei/gce-gen/pkg/cloud"
+)
+
+// TeardownOptions configure Teardown().
+type TeardownOptions struct {
+	// Parallelism is the maximum number of concurrent Delete() calls. It
+	// is 1 if not set.
+	Parallelism int
+	// Keep, if set, returns true for the resources that must not be deleted
+	// (e.g. reserved static IP addresses). The resources that they
+	// reference are not deleted either, as they are still in use.
+	Keep func(n *Node) bool
+	// DryRun prints the plan to Out instead of deleting the resources.
+	DryRun bool
+	// Out is where the plan is printed. It is os.Stdout if not set.
+	Out io.Writer
+	// Follow are the references followed in addition to DefaultEdges
+	// (see DiscoverOptions).
+	Follow Edges
+}
+
+// Plan is the order in which the resources are deleted. The resources of a
+// step only reference resources of later steps, so they are deleted in
+// parallel once the previous steps are done.
+type Plan struct {
+	Steps [][]*Node
+}
+
+// String returns the plan with one resource per line, prefixed by its step.
+func (p *Plan) String() string {
+	var buf bytes.Buffer
+	for i, step := range p.Steps {
+		for _, n := range step {
+			fmt.Fprintf(&buf, "%d %s\n", i+1, n)
+		}
+	}
+	return buf.String()
+}
+
+// PlanTeardown returns the plan to delete the roots and the resources that
+// they reference in g. A resource is not deleted if it does not exist, if
+// keep returns true for it or if it is used by a resource that is not
+// deleted.
+func PlanTeardown(g *Graph, keep func(n *Node) bool) (*Plan, error) {
+	deleted := map[*Node]bool{}
+	for _, n := range g.nodes {
+		deleted[n] = n.Object != nil && (keep == nil || !keep(n))
+	}
+	// Resources used by a resource that is not deleted are still in use.
+	for changed := true; changed; {
+		changed = false
+		for n := range deleted {
+			if !deleted[n] {
+				continue
+			}
+			for _, u := range n.Users {
+				if !deleted[u] {
+					deleted[n] = false
+					changed = true
+					break
+				}
+			}
+		}
+	}
+
+	plan := &Plan{}
+	done := map[*Node]bool{}
+	for remaining := count(deleted); remaining > 0; {
+		var step []*Node
+		for n, ok := range deleted {
+			if ok && !done[n] && usersDone(n, done) {
+				step = append(step, n)
+			}
+		}
+		if len(step) == 0 {
+			return nil, fmt.Errorf("graph: the references between %d resources form a cycle", remaining)
+		}
+		for _, n := range step {
+			done[n] = true
+		}
+		sortNodes(step)
+		plan.Steps = append(plan.Steps, step)
+		remaining -= len(step)
+	}
+	return plan, nil
+}
+
+func count(m map[*Node]bool) int {
+	var ret int
+	for _, ok := range m {
+		if ok {
+			ret++
+		}
+	}
+	return ret
+}
+
+func usersDone(n *Node, done map[*Node]bool) bool {
+	for _, u := range n.Users {
+		if !done[u] {
+			return false
+		}
+	}
+	return true
+}
+
+// TeardownError is returned by Execute() and Teardown() if some resources
+// could not be deleted.
+type TeardownError struct {
+	// Errors by resource.
+	Errors map[*Node]error
+	// Skipped resources, as a resource that uses them was not deleted.
+	Skipped []*Node
+}
+
+func (e *TeardownError) Error() string {
+	var msgs []string
+	for n, err := range e.Errors {
+		msgs = append(msgs, fmt.Sprintf("%s: %v", n, err))
+	}
+	sort.Strings(msgs)
+	return fmt.Sprintf("graph: %d resources not deleted, %d skipped: %s", len(e.Errors), len(e.Skipped), strings.Join(msgs, "; "))
+}
+
+// Execute deletes the resources of the plan with c, with at most parallelism
+// concurrent calls. Resources that are already gone are ignored. If a
+// resource cannot be deleted, the resources that it references are skipped
+// and Execute returns a *TeardownError once the other resources are deleted.
+func (p *Plan) Execute(ctx context.Context, c cloud.Cloud, parallelism int) error {
+	if parallelism < 1 {
+		parallelism = 1
+	}
+	tdErr := &TeardownError{Errors: map[*Node]error{}}
+	notDeleted := map[*Node]bool{}
+	for _, step := range p.Steps {
+		var (
+			wg     sync.WaitGroup
+			lock   sync.Mutex
+			sem    = make(chan struct{}, parallelism)
+			failed []*Node
+		)
+		for _, n := range step {
+			if blocked(n, notDeleted) || ctx.Err() != nil {
+				notDeleted[n] = true
+				tdErr.Skipped = append(tdErr.Skipped, n)
+				continue
+			}
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(n *Node) {
+				defer func() { <-sem; wg.Done() }()
+				err := deleteNode(ctx, c, n)
+				lock.Lock()
+				defer lock.Unlock()
+				if err != nil {
+					glog.V(2).Infof("graph: failed to delete %s: %v", n, err)
+					failed = append(failed, n)
+					tdErr.Errors[n] = err
+				}
+			}(n)
+		}
+		wg.Wait()
+		for _, n := range failed {
+			notDeleted[n] = true
+		}
+	}
+	if len(tdErr.Errors) > 0 || len(tdErr.Skipped) > 0 {
+		return tdErr
+	}
+	return nil
+}
+
+// blocked is true if n is used by a resource that was not deleted.
+func blocked(n *Node, notDeleted map[*Node]bool) bool {
+	for _, u := range n.Users {
+		if notDeleted[u] {
+			return true
+		}
+	}
+	return false
+}
+
+func deleteNode(ctx context.Context, c cloud.Cloud, n *Node) error {
+	svc, ok := service(c, n.ID)
+	if !ok {
+		return fmt.Errorf("graph: no service to delete %s", n)
+	}
+	if _, err := cloud.CallMethod(svc, "Delete", ctx, *n.ID.QualifiedKey()); err != nil && !cloud.IsNotFound(err) {
+		return err
+	}
+	glog.V(2).Infof("graph: deleted %s", n)
+	return nil
+}
+
+// Teardown deletes the roots (e.g. a GlobalForwardingRule) and all of the
+// resources that they reference through DefaultEdges and opts.Follow,
+// directly or not, in dependency order:
+// forwarding rules, then target proxies, URL maps, backend services and
+// finally health checks and instance groups. A resource that is still used
+// by a resource outside of the graph fails to be deleted, and the resources
+// that it references are skipped. See Discover(), PlanTeardown() and
+// Execute().
+func Teardown(ctx context.Context, c cloud.Cloud, opts TeardownOptions, roots ...*cloud.ResourceID) error {
+	g, err := Discover(ctx, c, DiscoverOptions{Follow: opts.Follow}, roots...)
+	if err != nil {
+		return err
+	}
+	plan, err := PlanTeardown(g, opts.Keep)
+	if err != nil {
+		return err
+	}
+	if opts.DryRun {
+		out := opts.Out
+		if out == nil {
+			out = os.Stdout
+		}
+		_, err := io.WriteString(out, plan.String())
+		return err
+	}
+	return plan.Execute(ctx, c, opts.Parallelism)
+}
@@ -0,0 +1,298 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package graph
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+
+	alpha "google.golang.org/api/compute/v0.alpha"
+	ga "google.golang.org/api/compute/v1"
+
+	"github.com/bowei/gce-gen/pkg/cloud"
+	"github.com/bowei/gce-gen/pkg/cloud/meta"
+)
+
+const zone = "us-central1-b"
+
+// newLoadBalancer returns a mock with reference checking and an L7 load
+// balancer with HTTP and HTTPS forwarding rules, which are returned.
+func newLoadBalancer(t *testing.T) (*cloud.MockGCE, []*cloud.ResourceID) {
+	ctx := context.Background()
+	mock := cloud.NewMockGCE(cloud.WithReferenceChecking())
+
+	for _, err := range []error{
+		mock.HealthChecks().Insert(ctx, *meta.GlobalKey("hc"), &ga.HealthCheck{Type: "HTTP"}),
+		mock.InstanceGroups().Insert(ctx, *meta.ZonalKey("ig", zone), &ga.InstanceGroup{}),
+		mock.AlphaNetworkEndpointGroups().Insert(ctx, *meta.ZonalKey("neg", zone), &alpha.NetworkEndpointGroup{}),
+		mock.BackendServices().Insert(ctx, *meta.GlobalKey("bs-ig"), &ga.BackendService{
+			Backends:     []*ga.Backend{{Group: "zones/" + zone + "/instanceGroups/ig"}},
+			HealthChecks: []string{"global/healthChecks/hc"},
+		}),
+		mock.BackendServices().Insert(ctx, *meta.GlobalKey("bs-neg"), &ga.BackendService{
+			Backends:     []*ga.Backend{{Group: "zones/" + zone + "/networkEndpointGroups/neg"}},
+			HealthChecks: []string{"global/healthChecks/hc"},
+		}),
+		mock.UrlMaps().Insert(ctx, *meta.GlobalKey("um"), &ga.UrlMap{
+			DefaultService: "global/backendServices/bs-ig",
+			PathMatchers: []*ga.PathMatcher{{
+				DefaultService: "global/backendServices/bs-ig",
+				PathRules:      []*ga.PathRule{{Paths: []string{"/neg"}, Service: "global/backendServices/bs-neg"}},
+			}},
+		}),
+		mock.SslCertificates().Insert(ctx, *meta.GlobalKey("cert"), &ga.SslCertificate{}),
+		mock.TargetHttpProxies().Insert(ctx, *meta.GlobalKey("http"), &ga.TargetHttpProxy{UrlMap: "global/urlMaps/um"}),
+		mock.TargetHttpsProxies().Insert(ctx, *meta.GlobalKey("https"), &ga.TargetHttpsProxy{
+			UrlMap:          "global/urlMaps/um",
+			SslCertificates: []string{"global/sslCertificates/cert"},
+		}),
+		mock.GlobalForwardingRules().Insert(ctx, *meta.GlobalKey("fr-http"), &ga.ForwardingRule{Target: "global/targetHttpProxies/http"}),
+		mock.GlobalForwardingRules().Insert(ctx, *meta.GlobalKey("fr-https"), &ga.ForwardingRule{Target: "global/targetHttpsProxies/https"}),
+	} {
+		if err != nil {
+			t.Fatalf("Insert() = %v", err)
+		}
+	}
+	return mock, []*cloud.ResourceID{
+		cloud.NewResourceID("forwardingRules", *meta.GlobalKey("fr-http")),
+		cloud.NewResourceID("forwardingRules", *meta.GlobalKey("fr-https")),
+	}
+}
+
+// exists returns the resources of the load balancer that exist in mock.
+func exists(mock *cloud.MockGCE) []string {
+	ctx := context.Background()
+	var ret []string
+	for _, r := range []struct {
+		name string
+		get  func() error
+	}{
+		{"fr-http", func() error { _, err := mock.GlobalForwardingRules().Get(ctx, *meta.GlobalKey("fr-http")); return err }},
+		{"fr-https", func() error { _, err := mock.GlobalForwardingRules().Get(ctx, *meta.GlobalKey("fr-https")); return err }},
+		{"http", func() error { _, err := mock.TargetHttpProxies().Get(ctx, *meta.GlobalKey("http")); return err }},
+		{"https", func() error { _, err := mock.TargetHttpsProxies().Get(ctx, *meta.GlobalKey("https")); return err }},
+		{"cert", func() error { _, err := mock.SslCertificates().Get(ctx, *meta.GlobalKey("cert")); return err }},
+		{"um", func() error { _, err := mock.UrlMaps().Get(ctx, *meta.GlobalKey("um")); return err }},
+		{"bs-ig", func() error { _, err := mock.BackendServices().Get(ctx, *meta.GlobalKey("bs-ig")); return err }},
+		{"bs-neg", func() error { _, err := mock.BackendServices().Get(ctx, *meta.GlobalKey("bs-neg")); return err }},
+		{"hc", func() error { _, err := mock.HealthChecks().Get(ctx, *meta.GlobalKey("hc")); return err }},
+		{"ig", func() error { _, err := mock.InstanceGroups().Get(ctx, *meta.ZonalKey("ig", zone)); return err }},
+		{"neg", func() error {
+			_, err := mock.AlphaNetworkEndpointGroups().Get(ctx, *meta.ZonalKey("neg", zone))
+			return err
+		}},
+	} {
+		if r.get() == nil {
+			ret = append(ret, r.name)
+		}
+	}
+	return ret
+}
+
+func TestTeardownPlan(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock, roots := newLoadBalancer(t)
+	var out bytes.Buffer
+	if err := Teardown(ctx, mock, TeardownOptions{DryRun: true, Out: &out}, roots...); err != nil {
+		t.Fatalf("Teardown(_, _, {DryRun: true}, %v) = %v; want nil", roots, err)
+	}
+
+	const want = `1 projects/mock-project/global/forwardingRules/fr-http
+1 projects/mock-project/global/forwardingRules/fr-https
+2 projects/mock-project/global/targetHttpProxies/http
+2 projects/mock-project/global/targetHttpsProxies/https
+3 projects/mock-project/global/sslCertificates/cert
+3 projects/mock-project/global/urlMaps/um
+4 projects/mock-project/global/backendServices/bs-ig
+4 projects/mock-project/global/backendServices/bs-neg
+5 projects/mock-project/global/healthChecks/hc
+5 projects/mock-project/zones/us-central1-b/instanceGroups/ig
+5 projects/mock-project/zones/us-central1-b/networkEndpointGroups/neg
+`
+	if got := out.String(); got != want {
+		t.Errorf("plan =\n%s\nwant\n%s", got, want)
+	}
+	if got := exists(mock); len(got) != 11 {
+		t.Errorf("resources after a dry run = %v; want all of them", got)
+	}
+}
+
+func TestTeardown(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	for _, tc := range []struct {
+		desc string
+		// setup is called before Teardown().
+		setup       func(mock *cloud.MockGCE)
+		keep        func(n *Node) bool
+		wantErrs    []string
+		wantSkipped []string
+		wantExists  []string
+	}{
+		{
+			desc:  "everything",
+			setup: func(*cloud.MockGCE) {},
+		},
+		{
+			desc: "backend service used by another URL map",
+			setup: func(mock *cloud.MockGCE) {
+				mock.UrlMaps().Insert(ctx, *meta.GlobalKey("other"), &ga.UrlMap{DefaultService: "global/backendServices/bs-ig"})
+			},
+			wantErrs:    []string{"projects/mock-project/global/backendServices/bs-ig"},
+			wantSkipped: []string{"projects/mock-project/global/healthChecks/hc", "projects/mock-project/zones/us-central1-b/instanceGroups/ig"},
+			wantExists:  []string{"bs-ig", "hc", "ig"},
+		},
+		{
+			desc:       "keep",
+			setup:      func(*cloud.MockGCE) {},
+			keep:       func(n *Node) bool { return n.ID.Resource == "targetHttpsProxies" },
+			wantExists: []string{"https", "cert", "um", "bs-ig", "bs-neg", "hc", "ig", "neg"},
+		},
+		{
+			desc: "already deleted",
+			setup: func(mock *cloud.MockGCE) {
+				mock.GlobalForwardingRules().Delete(ctx, *meta.GlobalKey("fr-https"))
+				mock.TargetHttpsProxies().Delete(ctx, *meta.GlobalKey("https"))
+			},
+			// The certificate is only referenced by the deleted proxy.
+			wantExists: []string{"cert"},
+		},
+	} {
+		mock, roots := newLoadBalancer(t)
+		tc.setup(mock)
+		err := Teardown(ctx, mock, TeardownOptions{Parallelism: 4, Keep: tc.keep}, roots...)
+
+		var gotErrs, gotSkipped []string
+		if err != nil {
+			tdErr, ok := err.(*TeardownError)
+			if !ok {
+				t.Fatalf("%s: Teardown() = %v; want nil or a *TeardownError", tc.desc, err)
+			}
+			for n := range tdErr.Errors {
+				gotErrs = append(gotErrs, n.String())
+			}
+			for _, n := range tdErr.Skipped {
+				gotSkipped = append(gotSkipped, n.String())
+			}
+		}
+		if !reflect.DeepEqual(gotErrs, tc.wantErrs) || !reflect.DeepEqual(gotSkipped, tc.wantSkipped) {
+			t.Errorf("%s: Teardown() = %v; want errors for %v and skipped %v", tc.desc, err, tc.wantErrs, tc.wantSkipped)
+		}
+		if got := exists(mock); !reflect.DeepEqual(got, tc.wantExists) {
+			t.Errorf("%s: resources after Teardown() = %v; want %v", tc.desc, got, tc.wantExists)
+		}
+	}
+}
+
+func TestDiscover(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock, _ := newLoadBalancer(t)
+	root := cloud.NewResourceID("backendServices", *meta.GlobalKey("bs-ig"))
+	g, err := Discover(ctx, mock, DiscoverOptions{}, root, cloud.NewResourceID("healthChecks", *meta.GlobalKey("missing")))
+	if err != nil {
+		t.Fatalf("Discover() = _, %v", err)
+	}
+
+	var got []string
+	for _, n := range g.Nodes() {
+		got = append(got, n.String())
+	}
+	want := []string{
+		"global/healthChecks/missing",
+		"projects/mock-project/global/backendServices/bs-ig",
+		"projects/mock-project/global/healthChecks/hc",
+		"projects/mock-project/zones/us-central1-b/instanceGroups/ig",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Discover() nodes = %v; want %v", got, want)
+	}
+	if n := g.Node(root); n != nil {
+		t.Errorf("g.Node(%v) = %v; want nil, the root is in mock-project", root, n)
+	}
+	bs := g.Node(&cloud.ResourceID{ProjectID: "mock-project", Resource: "backendServices", Key: meta.GlobalKey("bs-ig")})
+	if bs == nil || len(bs.Refs) != 2 || len(bs.Users) != 0 {
+		t.Errorf("backend service node = %+v; want 2 references and no users", bs)
+	}
+	if n := g.Node(cloud.NewResourceID("healthChecks", *meta.GlobalKey("missing"))); n == nil || n.Object != nil {
+		t.Errorf("missing node = %+v; want a node without an object", n)
+	}
+
+	if _, err := Discover(ctx, mock, DiscoverOptions{}, cloud.NewResourceID("regions", *meta.GlobalKey("us-central1"))); err == nil {
+		t.Errorf("Discover(regions) = _, nil; want error")
+	}
+}
+
+func TestTeardownTargetPool(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	const region = "us-central1"
+	for _, tc := range []struct {
+		desc          string
+		follow        Edges
+		wantInstances bool
+	}{
+		{desc: "instances are kept", wantInstances: true},
+		{desc: "instances followed", follow: Edges{"targetPools": {"instances"}}},
+	} {
+		mock := cloud.NewMockGCE(cloud.WithReferenceChecking())
+		for _, err := range []error{
+			mock.Instances().Insert(ctx, *meta.ZonalKey("vm", zone), &ga.Instance{
+				Disks: []*ga.AttachedDisk{{Source: "zones/" + zone + "/disks/vm"}},
+			}),
+			mock.Disks().Insert(ctx, *meta.ZonalKey("vm", zone), &ga.Disk{}),
+			mock.HttpHealthChecks().Insert(ctx, *meta.GlobalKey("hc"), &ga.HttpHealthCheck{}),
+			mock.TargetPools().Insert(ctx, *meta.RegionalKey("tp", region), &ga.TargetPool{
+				Instances:    []string{"zones/" + zone + "/instances/vm"},
+				HealthChecks: []string{"global/httpHealthChecks/hc"},
+			}),
+			mock.ForwardingRules().Insert(ctx, *meta.RegionalKey("fr", region), &ga.ForwardingRule{
+				Target: "regions/" + region + "/targetPools/tp",
+			}),
+		} {
+			if err != nil {
+				t.Fatalf("%s: Insert() = %v", tc.desc, err)
+			}
+		}
+
+		root := cloud.NewResourceID("forwardingRules", *meta.RegionalKey("fr", region))
+		if err := Teardown(ctx, mock, TeardownOptions{Follow: tc.follow}, root); err != nil {
+			t.Fatalf("%s: Teardown() = %v", tc.desc, err)
+		}
+		if _, err := mock.TargetPools().Get(ctx, *meta.RegionalKey("tp", region)); err == nil {
+			t.Errorf("%s: TargetPools().Get(tp) = _, nil; want the target pool deleted", tc.desc)
+		}
+		if _, err := mock.HttpHealthChecks().Get(ctx, *meta.GlobalKey("hc")); err == nil {
+			t.Errorf("%s: HttpHealthChecks().Get(hc) = _, nil; want the health check deleted", tc.desc)
+		}
+		_, err := mock.Instances().Get(ctx, *meta.ZonalKey("vm", zone))
+		if gotInstances := err == nil; gotInstances != tc.wantInstances {
+			t.Errorf("%s: Instances().Get(vm) = _, %v; want instance kept %t", tc.desc, err, tc.wantInstances)
+		}
+		// The disks of the instances are never followed by default.
+		if _, err := mock.Disks().Get(ctx, *meta.ZonalKey("vm", zone)); err != nil {
+			t.Errorf("%s: Disks().Get(vm) = _, %v; want the disk kept", tc.desc, err)
+		}
+	}
+}
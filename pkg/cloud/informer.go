@@ -0,0 +1,416 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+
+	"github.com/bowei/gce-gen/pkg/cloud/filter"
+	"github.com/bowei/gce-gen/pkg/cloud/meta"
+)
+
+// DefaultResyncPeriod is the ResyncPeriod of an Informer if none is set.
+const DefaultResyncPeriod = time.Minute
+
+// InformerOptions configure NewInformer().
+type InformerOptions struct {
+	// Filter of the List() calls. All of the objects are listed if it is
+	// nil.
+	Filter *filter.F
+	// Locations (regions or zones) listed for the regional and zonal
+	// services without AggregatedList(). All of the regions or zones of the
+	// project are listed if it is empty.
+	Locations []string
+	// ResyncPeriod is the time between two polls by Run(). It is
+	// DefaultResyncPeriod if not set.
+	ResyncPeriod time.Duration
+}
+
+// InformerHandler receives the events of an Informer. The functions that are
+// nil are not called. The objects are those of the version of the service
+// (e.g. *alpha.BackendService) and must not be modified.
+type InformerHandler struct {
+	OnAdd    func(key meta.Key, obj interface{})
+	OnUpdate func(key meta.Key, old, obj interface{})
+	OnDelete func(key meta.Key, obj interface{})
+}
+
+// Informer polls the List() or AggregatedList() method of a service and keeps
+// the objects in a local store keyed by meta.Key. The changes found by a poll
+// are sent to the handlers as events, ordered by key. An object is updated
+// if it is not reflect.DeepEqual() to the previous one, which includes a
+// change of its Fingerprint.
+//
+//  inf, err := cloud.NewInformer(gce, "BackendServices", cloud.InformerOptions{})
+//  inf.AddHandler(cloud.InformerHandler{
+//    OnUpdate: func(key meta.Key, old, obj interface{}) { ... },
+//  })
+//  go inf.Run(ctx)
+//
+// The informers created for a MockGCE are polled by MockGCE.PollInformers(),
+// which makes tests deterministic, until they are stopped with Stop() or the
+// context given to Run() is done.
+type Informer struct {
+	service  string
+	keyType  meta.KeyType
+	svc      reflect.Value
+	opts     InformerOptions
+	topology *Topology
+
+	// pollLock serializes the polls and the calls to the handlers.
+	pollLock sync.Mutex
+	handlers []InformerHandler
+
+	lock    sync.RWMutex
+	objects map[meta.Key]interface{}
+	synced  bool
+
+	stopOnce sync.Once
+	stopped  chan struct{}
+	// unregister removes the Informer from the informers of the
+	// MockGCE it was created for, if any.
+	unregister func()
+}
+
+// NewInformer returns an Informer for service, the name of a service of c
+// with a List() method (e.g. "AlphaBackendServices").
+func NewInformer(c Cloud, service string, opts InformerOptions) (*Informer, error) {
+	var si *meta.ServiceInfo
+	for _, s := range meta.AllServices {
+		if s.WrapType() == service {
+			si = s
+		}
+	}
+	if si == nil || !si.GenerateList() {
+		return nil, fmt.Errorf("informer: %q is not a service with List()", service)
+	}
+	svc, ok := ServiceOf(c, si)
+	if !ok {
+		return nil, fmt.Errorf("informer: %T has no service %q", c, service)
+	}
+	if opts.ResyncPeriod == 0 {
+		opts.ResyncPeriod = DefaultResyncPeriod
+	}
+
+	inf := &Informer{
+		service:  service,
+		keyType:  si.KeyType(),
+		svc:      svc,
+		opts:     opts,
+		topology: NewTopology(c, opts.ResyncPeriod),
+		objects:  map[meta.Key]interface{}{},
+		stopped:  make(chan struct{}),
+	}
+	if si.AggregatedList() {
+		// Locations are not used with AggregatedList().
+		inf.topology = nil
+	}
+	if mock, ok := c.(*MockGCE); ok {
+		mock.informers.add(inf)
+		inf.unregister = func() { mock.informers.remove(inf) }
+	}
+	return inf, nil
+}
+
+// AddHandler adds h to the handlers of the Informer. h is sent an add event
+// for each of the objects already in the store. The handlers must not call
+// AddHandler() or Poll().
+func (inf *Informer) AddHandler(h InformerHandler) {
+	inf.pollLock.Lock()
+	defer inf.pollLock.Unlock()
+
+	inf.handlers = append(inf.handlers, h)
+	if h.OnAdd == nil {
+		return
+	}
+	inf.lock.RLock()
+	objects := inf.objects
+	inf.lock.RUnlock()
+	for _, key := range sortedKeys(objects) {
+		h.OnAdd(key, objects[key])
+	}
+}
+
+// Run polls every ResyncPeriod until ctx is done or the Informer is stopped.
+// The errors are logged and the polling continues. The Informer is stopped
+// when Run() returns.
+func (inf *Informer) Run(ctx context.Context) {
+	defer inf.Stop()
+
+	ticker := time.NewTicker(inf.opts.ResyncPeriod)
+	defer ticker.Stop()
+
+	for {
+		if err := inf.Poll(ctx); err != nil && ctx.Err() == nil {
+			glog.Errorf("%v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-inf.stopped:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stop stops Run() and removes the Informer from the informers polled by
+// MockGCE.PollInformers(). The store and the handlers are kept. It can be
+// called more than once.
+func (inf *Informer) Stop() {
+	inf.stopOnce.Do(func() {
+		close(inf.stopped)
+		if inf.unregister != nil {
+			inf.unregister()
+		}
+	})
+}
+
+// Poll lists the objects now, updates the store and sends the events to the
+// handlers before returning. The store is unchanged if the listing fails.
+func (inf *Informer) Poll(ctx context.Context) error {
+	inf.pollLock.Lock()
+	defer inf.pollLock.Unlock()
+
+	objs, err := inf.list(ctx)
+	if err != nil {
+		return fmt.Errorf("informer: listing %s: %v", inf.service, err)
+	}
+
+	inf.lock.Lock()
+	old := inf.objects
+	objects := map[meta.Key]interface{}{}
+	for _, obj := range objs {
+		key, ok := objectKey(obj)
+		if !ok {
+			glog.Warningf("informer: %T in %s without a self link", obj, inf.service)
+			continue
+		}
+		// The store has copies of the listed objects, which may be
+		// modified by their owner (e.g. the objects of a MockGCE).
+		c := reflect.New(reflect.TypeOf(obj).Elem()).Interface()
+		if err := copyViaJSON(c, obj); err != nil {
+			inf.lock.Unlock()
+			return fmt.Errorf("informer: copying %T in %s: %v", obj, inf.service, err)
+		}
+		if prev, ok := old[key]; ok && reflect.DeepEqual(prev, c) {
+			c = prev
+		}
+		objects[key] = c
+	}
+	inf.objects = objects
+	inf.synced = true
+	inf.lock.Unlock()
+
+	for _, key := range sortedKeys(objects) {
+		obj := objects[key]
+		prev, ok := old[key]
+		for _, h := range inf.handlers {
+			switch {
+			case !ok && h.OnAdd != nil:
+				h.OnAdd(key, obj)
+			case ok && prev != obj && h.OnUpdate != nil:
+				h.OnUpdate(key, prev, obj)
+			}
+		}
+	}
+	for _, key := range sortedKeys(old) {
+		if _, ok := objects[key]; ok {
+			continue
+		}
+		for _, h := range inf.handlers {
+			if h.OnDelete != nil {
+				h.OnDelete(key, old[key])
+			}
+		}
+	}
+	return nil
+}
+
+// HasSynced returns true once a poll succeeded.
+func (inf *Informer) HasSynced() bool {
+	inf.lock.RLock()
+	defer inf.lock.RUnlock()
+	return inf.synced
+}
+
+// Get returns the object for key from the store.
+func (inf *Informer) Get(key meta.Key) (interface{}, bool) {
+	inf.lock.RLock()
+	defer inf.lock.RUnlock()
+	obj, ok := inf.objects[key]
+	return obj, ok
+}
+
+// Keys returns the sorted keys of the objects in the store.
+func (inf *Informer) Keys() []meta.Key {
+	inf.lock.RLock()
+	defer inf.lock.RUnlock()
+	return sortedKeys(inf.objects)
+}
+
+// List returns the objects in the store, ordered by key.
+func (inf *Informer) List() []interface{} {
+	inf.lock.RLock()
+	defer inf.lock.RUnlock()
+
+	var ret []interface{}
+	for _, key := range sortedKeys(inf.objects) {
+		ret = append(ret, inf.objects[key])
+	}
+	return ret
+}
+
+// list returns the objects of the service matching the filter.
+func (inf *Informer) list(ctx context.Context) ([]interface{}, error) {
+	var ret []interface{}
+	appendAll := func(list reflect.Value) {
+		for i := 0; i < list.Len(); i++ {
+			ret = append(ret, list.Index(i).Interface())
+		}
+	}
+
+	if inf.keyType == meta.Global {
+		list, err := inf.call("List", ctx, inf.opts.Filter)
+		if err != nil {
+			return nil, err
+		}
+		appendAll(list)
+		return ret, nil
+	}
+
+	if inf.topology == nil {
+		lists, err := inf.call("AggregatedList", ctx, inf.opts.Filter)
+		if err != nil {
+			return nil, err
+		}
+		for _, k := range lists.MapKeys() {
+			appendAll(lists.MapIndex(k))
+		}
+		return ret, nil
+	}
+
+	locations := inf.opts.Locations
+	if len(locations) == 0 {
+		var err error
+		if inf.keyType == meta.Zonal {
+			locations, err = inf.topology.Zones(ctx)
+		} else {
+			locations, err = inf.topology.Regions(ctx)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, l := range locations {
+		list, err := inf.call("List", ctx, l, inf.opts.Filter)
+		if err != nil {
+			return nil, err
+		}
+		appendAll(list)
+	}
+	return ret, nil
+}
+
+// call calls the method name of the service, which returns a value and an
+// error.
+func (inf *Informer) call(name string, args ...interface{}) (reflect.Value, error) {
+	ret, err := CallMethod(inf.svc, name, args...)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	return reflect.ValueOf(ret), nil
+}
+
+// objectKey returns the key of obj, from its SelfLink.
+func objectKey(obj interface{}) (meta.Key, bool) {
+	v := reflect.ValueOf(obj).Elem().FieldByName("SelfLink")
+	if !v.IsValid() || v.Kind() != reflect.String {
+		return meta.Key{}, false
+	}
+	id, ok := ParseReference(v.String())
+	if !ok || id.Key == nil {
+		return meta.Key{}, false
+	}
+	return *id.Key, true
+}
+
+// sortedKeys returns the keys of objects, ordered by location and name.
+func sortedKeys(objects map[meta.Key]interface{}) []meta.Key {
+	var ret []meta.Key
+	for k := range objects {
+		ret = append(ret, k)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		a, b := ret[i], ret[j]
+		if a.Region != b.Region {
+			return a.Region < b.Region
+		}
+		if a.Zone != b.Zone {
+			return a.Zone < b.Zone
+		}
+		return a.Name < b.Name
+	})
+	return ret
+}
+
+// informerSet is the set of the informers created for a MockGCE.
+type informerSet struct {
+	lock      sync.Mutex
+	informers []*Informer
+}
+
+func (s *informerSet) add(inf *Informer) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.informers = append(s.informers, inf)
+}
+
+func (s *informerSet) remove(inf *Informer) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for i, x := range s.informers {
+		if x == inf {
+			s.informers = append(s.informers[:i:i], s.informers[i+1:]...)
+			return
+		}
+	}
+}
+
+// PollInformers polls all of the informers created for the mock and not
+// stopped now, in the order in which they were created, and returns once their handlers were
+// called. It returns the first error.
+func (mock *MockGCE) PollInformers(ctx context.Context) error {
+	mock.informers.lock.Lock()
+	informers := append([]*Informer{}, mock.informers.informers...)
+	mock.informers.lock.Unlock()
+
+	var ret error
+	for _, inf := range informers {
+		if err := inf.Poll(ctx); err != nil && ret == nil {
+			ret = err
+		}
+	}
+	return ret
+}
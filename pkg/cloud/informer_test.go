@@ -0,0 +1,322 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	alpha "google.golang.org/api/compute/v0.alpha"
+	ga "google.golang.org/api/compute/v1"
+
+	"github.com/bowei/gce-gen/pkg/cloud/filter"
+	"github.com/bowei/gce-gen/pkg/cloud/meta"
+)
+
+// eventRecorder records the events of an Informer as strings.
+type eventRecorder struct {
+	lock   sync.Mutex
+	events []string
+}
+
+func (r *eventRecorder) handler() InformerHandler {
+	record := func(format string, args ...interface{}) {
+		r.lock.Lock()
+		defer r.lock.Unlock()
+		r.events = append(r.events, fmt.Sprintf(format, args...))
+	}
+	return InformerHandler{
+		OnAdd: func(key meta.Key, obj interface{}) {
+			record("add %s", key.Name)
+		},
+		OnUpdate: func(key meta.Key, old, obj interface{}) {
+			record("update %s %s -> %s", key.Name, old.(*ga.Firewall).Description, obj.(*ga.Firewall).Description)
+		},
+		OnDelete: func(key meta.Key, obj interface{}) {
+			record("delete %s", key.Name)
+		},
+	}
+}
+
+// take returns the events recorded since the last call.
+func (r *eventRecorder) take() []string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	ret := r.events
+	r.events = nil
+	return ret
+}
+
+func TestInformer(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := NewMockGCE()
+	for _, name := range []string{"fw-b", "fw-a"} {
+		mock.Firewalls().Insert(ctx, *meta.GlobalKey(name), &ga.Firewall{})
+	}
+	inf, err := NewInformer(mock, "Firewalls", InformerOptions{})
+	if err != nil {
+		t.Fatalf("NewInformer() = _, %v", err)
+	}
+	r := &eventRecorder{}
+	inf.AddHandler(r.handler())
+
+	for _, tc := range []struct {
+		desc string
+		// do is called before the poll.
+		do       func()
+		want     []string
+		wantKeys []string
+	}{
+		{
+			desc:     "initial list",
+			want:     []string{"add fw-a", "add fw-b"},
+			wantKeys: []string{"fw-a", "fw-b"},
+		},
+		{
+			desc:     "no changes",
+			wantKeys: []string{"fw-a", "fw-b"},
+		},
+		{
+			desc: "update",
+			do: func() {
+				mock.Firewalls().Update(ctx, *meta.GlobalKey("fw-b"), &ga.Firewall{Description: "changed"})
+			},
+			want:     []string{"update fw-b  -> changed"},
+			wantKeys: []string{"fw-a", "fw-b"},
+		},
+		{
+			desc: "update of the object returned by Get",
+			do: func() {
+				fw, err := mock.Firewalls().Get(ctx, *meta.GlobalKey("fw-b"))
+				if err != nil {
+					t.Fatalf("Firewalls().Get(fw-b) = _, %v", err)
+				}
+				fw.Description = "again"
+				mock.Firewalls().Update(ctx, *meta.GlobalKey("fw-b"), fw)
+			},
+			want:     []string{"update fw-b changed -> again"},
+			wantKeys: []string{"fw-a", "fw-b"},
+		},
+		{
+			desc: "add and delete",
+			do: func() {
+				mock.Firewalls().Delete(ctx, *meta.GlobalKey("fw-a"))
+				mock.Firewalls().Insert(ctx, *meta.GlobalKey("fw-c"), &ga.Firewall{})
+			},
+			want:     []string{"add fw-c", "delete fw-a"},
+			wantKeys: []string{"fw-b", "fw-c"},
+		},
+		{
+			desc: "list error",
+			do: func() {
+				err := errors.New("injected error")
+				mock.MockFirewalls.ListError = &err
+				mock.Firewalls().Delete(ctx, *meta.GlobalKey("fw-b"))
+			},
+			wantKeys: []string{"fw-b", "fw-c"},
+		},
+		{
+			desc:     "list recovered",
+			do:       func() { mock.MockFirewalls.ListError = nil },
+			want:     []string{"delete fw-b"},
+			wantKeys: []string{"fw-c"},
+		},
+	} {
+		if tc.do != nil {
+			tc.do()
+		}
+		err := mock.PollInformers(ctx)
+		if gotErr := err != nil; gotErr != (tc.desc == "list error") {
+			t.Errorf("%s: PollInformers() = %v", tc.desc, err)
+		}
+		if got := r.take(); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: events = %q; want %q", tc.desc, got, tc.want)
+		}
+		var gotKeys []string
+		for _, key := range inf.Keys() {
+			gotKeys = append(gotKeys, key.Name)
+		}
+		if !reflect.DeepEqual(gotKeys, tc.wantKeys) {
+			t.Errorf("%s: Keys() = %v; want %v", tc.desc, gotKeys, tc.wantKeys)
+		}
+	}
+
+	if !inf.HasSynced() {
+		t.Errorf("HasSynced() = false; want true")
+	}
+	if obj, ok := inf.Get(*meta.GlobalKey("fw-c")); !ok || obj.(*ga.Firewall).Name != "fw-c" {
+		t.Errorf("Get(fw-c) = %v, %t; want fw-c", obj, ok)
+	}
+
+	// A new handler is sent the objects in the store.
+	r2 := &eventRecorder{}
+	inf.AddHandler(r2.handler())
+	if got, want := r2.take(), []string{"add fw-c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("events of a new handler = %q; want %q", got, want)
+	}
+}
+
+func TestInformerLocations(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := NewMockGCE(WithMockTopology(map[string][]string{"us-central1": {"us-central1-a", "us-central1-b"}}))
+	for _, key := range []*meta.Key{
+		meta.ZonalKey("i-1", "us-central1-a"),
+		meta.ZonalKey("i-2", "us-central1-b"),
+		meta.ZonalKey("i-3", "europe-west1-b"),
+	} {
+		mock.Instances().Insert(ctx, *key, &ga.Instance{})
+		mock.AlphaNetworkEndpointGroups().Insert(ctx, *key, &alpha.NetworkEndpointGroup{})
+	}
+	mock.Instances().Insert(ctx, *meta.ZonalKey("other", "us-central1-a"), &ga.Instance{})
+
+	for _, tc := range []struct {
+		desc    string
+		service string
+		opts    InformerOptions
+		want    []meta.Key
+	}{
+		{
+			desc:    "zones of the topology",
+			service: "Instances",
+			opts:    InformerOptions{Filter: filter.Regexp("name", "i-.*")},
+			want:    []meta.Key{*meta.ZonalKey("i-1", "us-central1-a"), *meta.ZonalKey("i-2", "us-central1-b")},
+		},
+		{
+			desc:    "locations",
+			service: "Instances",
+			opts:    InformerOptions{Locations: []string{"europe-west1-b"}},
+			want:    []meta.Key{*meta.ZonalKey("i-3", "europe-west1-b")},
+		},
+		{
+			desc:    "aggregated list",
+			service: "AlphaNetworkEndpointGroups",
+			want: []meta.Key{
+				*meta.ZonalKey("i-3", "europe-west1-b"),
+				*meta.ZonalKey("i-1", "us-central1-a"),
+				*meta.ZonalKey("i-2", "us-central1-b"),
+			},
+		},
+	} {
+		inf, err := NewInformer(mock, tc.service, tc.opts)
+		if err != nil {
+			t.Fatalf("%s: NewInformer() = _, %v", tc.desc, err)
+		}
+		if err := inf.Poll(ctx); err != nil {
+			t.Errorf("%s: Poll() = %v", tc.desc, err)
+		}
+		if got := inf.Keys(); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: Keys() = %v; want %v", tc.desc, got, tc.want)
+		}
+	}
+
+	for _, service := range []string{"Projects", "NoSuchService"} {
+		if _, err := NewInformer(mock, service, InformerOptions{}); err == nil {
+			t.Errorf("NewInformer(%q) = _, nil; want an error", service)
+		}
+	}
+}
+
+func TestInformerRun(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	mock := NewMockGCE()
+	inf, err := NewInformer(mock, "Firewalls", InformerOptions{ResyncPeriod: time.Millisecond})
+	if err != nil {
+		t.Fatalf("NewInformer() = _, %v", err)
+	}
+	added := make(chan meta.Key, 1)
+	inf.AddHandler(InformerHandler{OnAdd: func(key meta.Key, obj interface{}) { added <- key }})
+
+	done := make(chan struct{})
+	go func() {
+		inf.Run(ctx)
+		close(done)
+	}()
+	key := *meta.GlobalKey("fw")
+	mock.Firewalls().Insert(ctx, key, &ga.Firewall{})
+	select {
+	case got := <-added:
+		if got != key {
+			t.Errorf("added %v; want %v", got, key)
+		}
+	case <-time.After(10 * time.Second):
+		t.Errorf("no add event for %v", key)
+	}
+	cancel()
+	<-done
+}
+
+func TestInformerStop(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := NewMockGCE()
+	adds := map[string]int{}
+	newInformer := func(name string) *Informer {
+		inf, err := NewInformer(mock, "Firewalls", InformerOptions{})
+		if err != nil {
+			t.Fatalf("NewInformer() = _, %v", err)
+		}
+		inf.AddHandler(InformerHandler{OnAdd: func(key meta.Key, obj interface{}) { adds[name]++ }})
+		return inf
+	}
+	kept := newInformer("kept")
+	stopped := newInformer("stopped")
+	stopped.Stop()
+	stopped.Stop()
+
+	// An informer whose Run() context is done is no longer polled.
+	runCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	newInformer("cancelled").Run(runCtx)
+
+	// Stop() makes Run() return.
+	run := newInformer("run")
+	done := make(chan struct{})
+	go func() {
+		run.Run(ctx)
+		close(done)
+	}()
+	run.Stop()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatalf("Run() did not return after Stop()")
+	}
+	adds = map[string]int{}
+
+	mock.Firewalls().Insert(ctx, *meta.GlobalKey("fw"), &ga.Firewall{})
+	if err := mock.PollInformers(ctx); err != nil {
+		t.Fatalf("PollInformers() = %v", err)
+	}
+	if want := map[string]int{"kept": 1}; !reflect.DeepEqual(adds, want) {
+		t.Errorf("add events = %v, want %v", adds, want)
+	}
+	if got := mock.informers.informers; len(got) != 1 || got[0] != kept {
+		t.Errorf("mock.informers = %v, want [kept]", got)
+	}
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+
+	ga "google.golang.org/api/compute/v1"
+
+	"github.com/bowei/gce-gen/pkg/cloud/filter"
+	"github.com/bowei/gce-gen/pkg/cloud/meta"
+)
+
+// firewallLister is a stand-in for the compute API that lists the firewalls
+// in fws, applying the filter argument with filter.Parse() and Match().
+type firewallLister struct {
+	fws []*ga.Firewall
+
+	lock    sync.Mutex
+	filters []string
+}
+
+func (f *firewallLister) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" || r.URL.Path != "/projects/proj/global/firewalls" {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error": {"code": 404, "message": "not found"}}`))
+		return
+	}
+	s := r.URL.Query().Get("filter")
+	f.lock.Lock()
+	f.filters = append(f.filters, s)
+	f.lock.Unlock()
+
+	fl, err := filter.Parse(s)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error": {"code": 400, "message": "invalid filter"}}`))
+		return
+	}
+	resp := &ga.FirewallList{}
+	for _, fw := range f.fws {
+		if fl.Match(fw) {
+			resp.Items = append(resp.Items, fw)
+		}
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+func TestGCEListSplitsFilter(t *testing.T) {
+	t.Parallel()
+
+	fws := []*ga.Firewall{
+		{Name: "fw-1", SourceRanges: []string{"10.0.0.0/8"}},
+		{Name: "fw-2", SourceRanges: []string{"192.168.0.0/16", "10.1.0.0/16"}},
+		{Name: "fw-3", SourceRanges: []string{"192.168.0.0/16"}},
+		{Name: "other", SourceRanges: []string{"10.0.0.0/8"}},
+	}
+	lister := &firewallLister{fws: fws}
+	srv := httptest.NewServer(lister)
+	defer srv.Close()
+	svc, err := ga.New(srv.Client())
+	if err != nil {
+		t.Fatalf("ga.New() = _, %v", err)
+	}
+	svc.BasePath = srv.URL + "/projects/"
+	gce := NewGCE(&Service{
+		GA:            svc,
+		ProjectRouter: &SingleProjectRouter{ID: "proj"},
+		RateLimiter:   &noWaitRateLimiter{},
+	})
+	mock := NewMockGCE()
+	for _, fw := range fws {
+		mock.Firewalls().Insert(context.Background(), *meta.GlobalKey(fw.Name), fw)
+	}
+
+	fl := filter.Regexp("name", "fw-.*").AndRegexp("sourceRanges", `10\..*`)
+	got, err := gce.Firewalls().List(context.Background(), fl)
+	if err != nil {
+		t.Fatalf("Firewalls().List(%v) = _, %v, want nil", fl, err)
+	}
+	mockGot, err := mock.Firewalls().List(context.Background(), fl)
+	if err != nil {
+		t.Fatalf("mock.Firewalls().List(%v) = _, %v, want nil", fl, err)
+	}
+
+	names := func(l []*ga.Firewall) []string {
+		var ret []string
+		for _, fw := range l {
+			ret = append(ret, fw.Name)
+		}
+		sort.Strings(ret)
+		return ret
+	}
+	want := []string{"fw-1", "fw-2"}
+	if n := names(got); len(n) != 2 || n[0] != want[0] || n[1] != want[1] {
+		t.Errorf("Firewalls().List(%v) = %v, want %v", fl, n, want)
+	}
+	if n := names(mockGot); len(n) != 2 || n[0] != want[0] || n[1] != want[1] {
+		t.Errorf("mock.Firewalls().List(%v) = %v, want %v", fl, n, want)
+	}
+	if len(lister.filters) != 1 || lister.filters[0] != "name eq fw-.*" {
+		t.Errorf("filters sent to the server = %q, want [\"name eq fw-.*\"]", lister.filters)
+	}
+}
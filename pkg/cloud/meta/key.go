@@ -18,6 +18,7 @@ package meta
 
 import (
 	"fmt"
+	"regexp"
 )
 
 // Key for a GCP resource.
@@ -25,6 +26,10 @@ type Key struct {
 	Name   string
 	Zone   string
 	Region string
+	// Project is optional. If set, calls for the key are made to the project
+	// instead of the one from the ProjectRouter. This is used to refer to
+	// resources in other projects, e.g. the host project of a shared VPC.
+	Project string
 }
 
 // KeyType is the type of the key.
@@ -41,17 +46,24 @@ const (
 
 // ZonalKey returns the key for a zonal resource.
 func ZonalKey(name, zone string) *Key {
-	return &Key{name, zone, ""}
+	return &Key{Name: name, Zone: zone}
 }
 
 // RegionalKey returns the key for a regional resource.
 func RegionalKey(name, region string) *Key {
-	return &Key{name, "", region}
+	return &Key{Name: name, Region: region}
 }
 
 // GlobalKey returns the key for a global resource.
 func GlobalKey(name string) *Key {
-	return &Key{name, "", ""}
+	return &Key{Name: name}
+}
+
+// WithProject returns a copy of the key in project. The project is cleared
+// if project is empty.
+func (k Key) WithProject(project string) *Key {
+	k.Project = project
+	return &k
 }
 
 // Type returns the type of the key.
@@ -68,22 +80,94 @@ func (k *Key) Type() KeyType {
 
 // String returns a string representation of the key.
 func (k Key) String() string {
+	var project string
+	if k.Project != "" {
+		project = fmt.Sprintf(", project: %q", k.Project)
+	}
 	switch k.Type() {
 	case Zonal:
-		return fmt.Sprintf("Key{%q, zone: %q}", k.Name, k.Zone)
+		return fmt.Sprintf("Key{%q, zone: %q%s}", k.Name, k.Zone, project)
 	case Regional:
-		return fmt.Sprintf("Key{%q, region: %q}", k.Name, k.Region)
+		return fmt.Sprintf("Key{%q, region: %q%s}", k.Name, k.Region, project)
 	default:
-		return fmt.Sprintf("Key{%q}", k.Name)
+		return fmt.Sprintf("Key{%q%s}", k.Name, project)
+	}
+}
+
+var (
+	// nameRE matches resource names, which must comply with RFC1035.
+	nameRE = regexp.MustCompile(`^[a-z]([-a-z0-9]{0,61}[a-z0-9])?$`)
+	// regionRE matches region names (e.g. "us-central1").
+	regionRE = regexp.MustCompile(`^[a-z]+(-[a-z]+)*[0-9]+$`)
+	// zoneRE matches zone names (e.g. "us-central1-b").
+	zoneRE = regexp.MustCompile(`^[a-z]+(-[a-z]+)*[0-9]+-[a-z]$`)
+	// projectRE matches project IDs, including domain-scoped project IDs
+	// (e.g. "example.com:my-project").
+	projectRE = regexp.MustCompile(`^([-a-z0-9.]+:)?[a-z]([-a-z0-9]*[a-z0-9])?$`)
+)
+
+// InvalidKeyError is returned for a key that cannot be used for the resource.
+type InvalidKeyError struct {
+	Key Key
+	// KeyType is the type of key expected by the service. It is empty if the
+	// key was validated without a type.
+	KeyType KeyType
+	// Reason the key is invalid.
+	Reason string
+}
+
+// Error implements error.
+func (e *InvalidKeyError) Error() string {
+	if e.KeyType == "" {
+		return fmt.Sprintf("invalid key %v: %s", e.Key, e.Reason)
+	}
+	return fmt.Sprintf("invalid %s key %v: %s", e.KeyType, e.Key, e.Reason)
+}
+
+// Validate the key for a service with keys of keyType. The name must comply
+// with RFC1035, the zone, region and project must be well-formed and the type
+// of the key must be keyType. keyType may be empty, in which case any type is
+// accepted. The error returned is an *InvalidKeyError.
+func (k *Key) Validate(keyType KeyType) error {
+	invalid := func(format string, args ...interface{}) error {
+		return &InvalidKeyError{Key: *k, KeyType: keyType, Reason: fmt.Sprintf(format, args...)}
+	}
+	switch {
+	case !nameRE.MatchString(k.Name):
+		return invalid("name %q must be 1-63 characters of lowercase letters, digits and '-', starting with a letter and not ending with '-'", k.Name)
+	case k.Zone != "" && k.Region != "":
+		return invalid("zone %q and region %q are both set", k.Zone, k.Region)
+	case k.Zone != "" && !zoneRE.MatchString(k.Zone):
+		return invalid("zone %q is not of the form \"us-central1-b\"", k.Zone)
+	case k.Region != "" && !regionRE.MatchString(k.Region):
+		return invalid("region %q is not of the form \"us-central1\"", k.Region)
+	case k.Project != "" && !projectRE.MatchString(k.Project):
+		return invalid("project %q is not a valid project ID", k.Project)
+	case keyType != "" && k.Type() != keyType:
+		return invalid("key is %s", k.Type())
 	}
+	return nil
 }
 
-// Valid is true if the key is valid.
+// Valid is true if the key is valid for a resource of typeName (e.g.
+// "UrlMap"). The key must be valid for at least one of the services in
+// AllServices with typeName as the Object. Only the name, zone and region are
+// checked if there is no such service.
 func (k *Key) Valid(typeName string) bool {
-	if k.Zone != "" && k.Region != "" {
+	var found bool
+	for _, si := range AllServices {
+		if si.Object != typeName {
+			continue
+		}
+		found = true
+		if k.Validate(si.keyType) == nil {
+			return true
+		}
+	}
+	if found {
 		return false
 	}
-	return true
+	return k.Validate("") == nil
 }
 
 // KeysToMap creates a map[Key]bool from a list of keys.
@@ -49,6 +49,39 @@ func TestKeyString(t *testing.T) {
 			t.Errorf(`k.String() = "", want non-empty`)
 		}
 	}
+
+	for _, tc := range []struct {
+		key  *Key
+		want string
+	}{
+		{GlobalKey("abc"), `Key{"abc"}`},
+		{GlobalKey("abc").WithProject("p1"), `Key{"abc", project: "p1"}`},
+		{RegionalKey("abc", "us-central1").WithProject("p1"), `Key{"abc", region: "us-central1", project: "p1"}`},
+		{ZonalKey("abc", "us-central1-b").WithProject("p1"), `Key{"abc", zone: "us-central1-b", project: "p1"}`},
+	} {
+		if got := tc.key.String(); got != tc.want {
+			t.Errorf("key.String() = %q, want %q", got, tc.want)
+		}
+	}
+}
+
+func TestKeyWithProject(t *testing.T) {
+	t.Parallel()
+
+	k := ZonalKey("abc", "us-central1-b")
+	pk := k.WithProject("p1")
+	if k.Project != "" {
+		t.Errorf("k.WithProject() modified k: %+v", k)
+	}
+	if want := (Key{Name: "abc", Zone: "us-central1-b", Project: "p1"}); *pk != want {
+		t.Errorf("k.WithProject(p1) = %+v, want %+v", *pk, want)
+	}
+	if pk.Type() != Zonal {
+		t.Errorf("pk.Type() = %v, want %v", pk.Type(), Zonal)
+	}
+	if got := pk.WithProject(""); *got != *k {
+		t.Errorf("pk.WithProject(\"\") = %+v, want %+v", *got, *k)
+	}
 }
 
 func TestKeyValid(t *testing.T) {
@@ -65,7 +98,18 @@ func TestKeyValid(t *testing.T) {
 		// Note: these test cases need to be synchronized with the
 		// actual settings for each type.
 		{GlobalKey("abc"), "UrlMap", true},
-		{&Key{"abc", zone, region}, "UrlMap", false},
+		{&Key{Name: "abc", Zone: zone, Region: region}, "UrlMap", false},
+		{ZonalKey("abc", zone), "UrlMap", false},
+		{GlobalKey("ABC"), "UrlMap", false},
+		{ZonalKey("abc", zone), "Instance", true},
+		{RegionalKey("abc", region), "Instance", false},
+		// Addresses are either global or regional.
+		{GlobalKey("abc"), "Address", true},
+		{RegionalKey("abc", region), "Address", true},
+		{ZonalKey("abc", zone), "Address", false},
+		// Unknown types only check the name and location.
+		{ZonalKey("abc", zone), "Unknown", true},
+		{ZonalKey("abc", region), "Unknown", false},
 	} {
 		valid := tc.key.Valid(tc.typeName)
 		if valid != tc.want {
@@ -73,3 +117,55 @@ func TestKeyValid(t *testing.T) {
 		}
 	}
 }
+
+func TestKeyValidate(t *testing.T) {
+	t.Parallel()
+
+	long := "a123456789012345678901234567890123456789012345678901234567890bc"
+	for _, tc := range []struct {
+		desc    string
+		key     *Key
+		keyType KeyType
+		wantErr bool
+	}{
+		{"global", GlobalKey("abc"), Global, false},
+		{"regional", RegionalKey("abc", "us-central1"), Regional, false},
+		{"zonal", ZonalKey("abc", "us-central1-b"), Zonal, false},
+		{"long region", RegionalKey("abc", "northamerica-northeast1"), Regional, false},
+		{"any type", ZonalKey("abc", "us-central1-b"), "", false},
+		{"63 chars", GlobalKey(long), Global, false},
+		{"64 chars", GlobalKey(long + "d"), Global, true},
+		{"single char", GlobalKey("a"), Global, false},
+		{"empty name", GlobalKey(""), Global, true},
+		{"uppercase", GlobalKey("Abc"), Global, true},
+		{"leading digit", GlobalKey("1abc"), Global, true},
+		{"trailing dash", GlobalKey("abc-"), Global, true},
+		{"underscore", GlobalKey("a_b"), Global, true},
+		{"zone and region", &Key{Name: "abc", Zone: "us-central1-b", Region: "us-central1"}, "", true},
+		{"region as zone", ZonalKey("abc", "us-central1"), Zonal, true},
+		{"zone as region", RegionalKey("abc", "us-central1-b"), Regional, true},
+		{"bad zone", ZonalKey("abc", "location"), Zonal, true},
+		{"wrong type", ZonalKey("abc", "us-central1-b"), Global, true},
+		{"global for regional", GlobalKey("abc"), Regional, true},
+		{"project", GlobalKey("abc").WithProject("host-project"), Global, false},
+		{"domain-scoped project", GlobalKey("abc").WithProject("example.com:host-project"), Global, false},
+		{"bad project", GlobalKey("abc").WithProject("Host/Project"), Global, true},
+	} {
+		err := tc.key.Validate(tc.keyType)
+		if gotErr := err != nil; gotErr != tc.wantErr {
+			t.Errorf("%s: %v.Validate(%q) = %v; want error = %t", tc.desc, tc.key, tc.keyType, err, tc.wantErr)
+			continue
+		}
+		if err == nil {
+			continue
+		}
+		ike, ok := err.(*InvalidKeyError)
+		if !ok {
+			t.Errorf("%s: %v.Validate(%q) = %T, want *InvalidKeyError", tc.desc, tc.key, tc.keyType, err)
+			continue
+		}
+		if ike.Key != *tc.key || ike.KeyType != tc.keyType || ike.Reason == "" {
+			t.Errorf("%s: %v.Validate(%q) = %+v; want Key, KeyType and Reason set", tc.desc, tc.key, tc.keyType, ike)
+		}
+	}
+}
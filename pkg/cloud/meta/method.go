@@ -135,6 +135,17 @@ func (mr *Method) args(skip int, nameArgs bool, prefix []string) []string {
 	return append(prefix, a...)
 }
 
+// UpdatesObject is true if the method replaces the object with its argument,
+// i.e. it is an Update() method taking the object (e.g. Firewalls.Update()).
+func (mr *Method) UpdatesObject() bool {
+	fType := mr.m.Func.Type()
+	if mr.Name() != "Update" || mr.ReturnType != "Operation" || fType.NumIn() != mr.argsSkip()+1 {
+		return false
+	}
+	t := fType.In(mr.argsSkip())
+	return t.Kind() == reflect.Ptr && t.Elem().Name() == mr.Object
+}
+
 func (mr *Method) init() {
 	fType := mr.m.Func.Type()
 	if fType.NumIn() < mr.argsSkip() {
@@ -204,6 +215,25 @@ func (mr *Method) CallArgs() string {
 	return fmt.Sprintf(", %s", strings.Join(args, ", "))
 }
 
+// RequestArg is the expression recorded as the Request of the CallContext of
+// the method: arg0 if the method has a single argument besides the key, and
+// a []interface{} of all of the arguments otherwise (e.g. the interface name
+// and the *alpha.NetworkInterface of Instances.UpdateNetworkInterface()).
+func (mr *Method) RequestArg() string {
+	n := mr.m.Func.Type().NumIn() - mr.argsSkip()
+	switch n {
+	case 0:
+		return ""
+	case 1:
+		return "arg0"
+	}
+	var args []string
+	for i := 0; i < n; i++ {
+		args = append(args, fmt.Sprintf("arg%d", i))
+	}
+	return fmt.Sprintf("[]interface{}{%s}", strings.Join(args, ", "))
+}
+
 func (mr *Method) MockHookName() string {
 	return mr.m.Name + "Hook"
 }
@@ -232,6 +262,20 @@ func (mr *Method) FcnArgs() string {
 	return fmt.Sprintf("%v(%v) (*%v.%v, error)", mr.m.Name, strings.Join(args, ", "), mr.Version(), mr.ReturnType)
 }
 
+// NamedFcnArgs is the same as FcnArgs but with named return values. The
+// error return value is named "err".
+func (mr *Method) NamedFcnArgs() string {
+	args := mr.args(mr.argsSkip(), true, []string{
+		"ctx context.Context",
+		"key meta.Key",
+	})
+
+	if mr.ReturnType == "Operation" {
+		return fmt.Sprintf("%v(%v) (err error)", mr.m.Name, strings.Join(args, ", "))
+	}
+	return fmt.Sprintf("%v(%v) (_ *%v.%v, err error)", mr.m.Name, strings.Join(args, ", "), mr.Version(), mr.ReturnType)
+}
+
 func (mr *Method) InterfaceFunc() string {
 	args := mr.args(mr.argsSkip(), false, []string{"context.Context", "meta.Key"})
 	if mr.ReturnType == "Operation" {
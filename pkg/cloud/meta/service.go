@@ -144,6 +144,11 @@ func (i *ServiceInfo) Methods() []*Method {
 	return ret
 }
 
+// KeyType of the keys used by the service.
+func (i *ServiceInfo) KeyType() KeyType {
+	return i.keyType
+}
+
 // KeyIsGlobal is true if the key is global.
 func (i *ServiceInfo) KeyIsGlobal() bool {
 	return i.keyType == Global
@@ -245,6 +250,42 @@ func (sg *ServiceGroup) HasBeta() bool {
 	return sg.Beta != nil
 }
 
+// Primary returns the GA service of the group, or the alpha or beta one if
+// the service is not in GA.
+func (sg *ServiceGroup) Primary() *ServiceInfo {
+	switch {
+	case sg.GA != nil:
+		return sg.GA
+	case sg.Alpha != nil:
+		return sg.Alpha
+	default:
+		return sg.Beta
+	}
+}
+
+// Versions returns the services of the group, GA first, then alpha and beta.
+func (sg *ServiceGroup) Versions() []*ServiceInfo {
+	var ret []*ServiceInfo
+	for _, si := range []*ServiceInfo{sg.GA, sg.Alpha, sg.Beta} {
+		if si != nil {
+			ret = append(ret, si)
+		}
+	}
+	return ret
+}
+
+// Location returns a valid zone or region for the keys of the group, for use
+// in generated tests. It is empty if the keys are global.
+func (sg *ServiceGroup) Location() string {
+	switch sg.Primary().keyType {
+	case Zonal:
+		return "us-central1-b"
+	case Regional:
+		return "us-central1"
+	}
+	return ""
+}
+
 // groupServices together by version.
 func groupServices(services []*ServiceInfo) map[string]*ServiceGroup {
 	ret := map[string]*ServiceGroup{}
@@ -0,0 +1,331 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metrics records metrics for the calls made through the generated
+// cloud code and exposes them in the Prometheus text format.
+//
+//  r := metrics.NewRegistry()
+//  svc := &cloud.Service{
+//    ...
+//    Observer: metrics.NewObserver(r),
+//  }
+//  http.Handle("/metrics", r)
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Collector is a named metric family that can be written in the Prometheus
+// text format.
+type Collector interface {
+	// Name of the metric family.
+	Name() string
+	// WriteText writes the metric family in the Prometheus text format.
+	WriteText(w io.Writer) error
+}
+
+// Registry is a set of Collectors.
+type Registry struct {
+	lock       sync.Mutex
+	collectors map[string]Collector
+}
+
+// NewRegistry returns a new, empty registry.
+func NewRegistry() *Registry {
+	return &Registry{collectors: map[string]Collector{}}
+}
+
+// Register adds c to the registry. It is an error to register two
+// collectors with the same name.
+func (r *Registry) Register(c Collector) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if _, ok := r.collectors[c.Name()]; ok {
+		return fmt.Errorf("collector %q is already registered", c.Name())
+	}
+	r.collectors[c.Name()] = c
+	return nil
+}
+
+// MustRegister is like Register but panics on error.
+func (r *Registry) MustRegister(cs ...Collector) {
+	for _, c := range cs {
+		if err := r.Register(c); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// WriteText writes all of the collectors in the registry in the Prometheus
+// text format, sorted by name.
+func (r *Registry) WriteText(w io.Writer) error {
+	r.lock.Lock()
+	var names []string
+	for name := range r.collectors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var cs []Collector
+	for _, name := range names {
+		cs = append(cs, r.collectors[name])
+	}
+	r.lock.Unlock()
+
+	bw := bufio.NewWriter(w)
+	for _, c := range cs {
+		if err := c.WriteText(bw); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// ServeHTTP serves the contents of the registry in the Prometheus text
+// format.
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	r.WriteText(w)
+}
+
+// series holds the state shared by the vector types: the ordered label
+// values of each series.
+type series struct {
+	name   string
+	help   string
+	labels []string
+}
+
+func (s *series) Name() string {
+	return s.name
+}
+
+func (s *series) key(values []string) string {
+	if len(values) != len(s.labels) {
+		panic(fmt.Errorf("metric %q: got %d label values, want %d", s.name, len(values), len(s.labels)))
+	}
+	return strings.Join(values, "\x00")
+}
+
+// labelString returns the {a="x",b="y"} label string for the values, with
+// extra appended to the list of labels.
+func (s *series) labelString(key string, extra ...string) string {
+	var values []string
+	if len(s.labels) > 0 {
+		values = strings.Split(key, "\x00")
+	}
+	var parts []string
+	for i, l := range s.labels {
+		parts = append(parts, l+"="+quote(values[i]))
+	}
+	for i := 0; i+1 < len(extra); i += 2 {
+		parts = append(parts, extra[i]+"="+quote(extra[i+1]))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+func (s *series) writeHeader(w io.Writer, typ string) error {
+	_, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", s.name, s.help, s.name, typ)
+	return err
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// quote returns the label value s quoted and escaped as required by the
+// Prometheus text format.
+func quote(s string) string {
+	return `"` + labelEscaper.Replace(s) + `"`
+}
+
+func formatFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// CounterVec is a counter partitioned by a set of labels.
+type CounterVec struct {
+	series
+
+	lock   sync.Mutex
+	values map[string]float64
+}
+
+// NewCounterVec returns a new counter with the given label names.
+func NewCounterVec(name, help string, labels ...string) *CounterVec {
+	return &CounterVec{
+		series: series{name: name, help: help, labels: labels},
+		values: map[string]float64{},
+	}
+}
+
+// Add v to the counter for the given label values.
+func (c *CounterVec) Add(v float64, values ...string) {
+	k := c.key(values)
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.values[k] += v
+}
+
+// Inc increments the counter for the given label values.
+func (c *CounterVec) Inc(values ...string) {
+	c.Add(1, values...)
+}
+
+// Value returns the current value of the counter for the label values.
+func (c *CounterVec) Value(values ...string) float64 {
+	k := c.key(values)
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.values[k]
+}
+
+// WriteText implements Collector.
+func (c *CounterVec) WriteText(w io.Writer) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if err := c.writeHeader(w, "counter"); err != nil {
+		return err
+	}
+	for _, k := range sortedKeys(c.values) {
+		if _, err := fmt.Fprintf(w, "%s%s %s\n", c.name, c.labelString(k), formatFloat(c.values[k])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DefBuckets are the default histogram buckets, in seconds.
+var DefBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60, 120, 300}
+
+// HistogramVec is a histogram partitioned by a set of labels.
+type HistogramVec struct {
+	series
+	buckets []float64
+
+	lock   sync.Mutex
+	values map[string]*histogram
+}
+
+type histogram struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// NewHistogramVec returns a new histogram with the given buckets (upper
+// bounds, in increasing order) and label names. If buckets is nil,
+// DefBuckets is used.
+func NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	if buckets == nil {
+		buckets = DefBuckets
+	}
+	return &HistogramVec{
+		series:  series{name: name, help: help, labels: labels},
+		buckets: buckets,
+		values:  map[string]*histogram{},
+	}
+}
+
+// Observe adds v to the histogram for the given label values.
+func (h *HistogramVec) Observe(v float64, values ...string) {
+	k := h.key(values)
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	hist, ok := h.values[k]
+	if !ok {
+		hist = &histogram{counts: make([]uint64, len(h.buckets))}
+		h.values[k] = hist
+	}
+	for i, b := range h.buckets {
+		if v <= b {
+			hist.counts[i]++
+		}
+	}
+	hist.count++
+	hist.sum += v
+}
+
+// Count returns the number of observations for the label values.
+func (h *HistogramVec) Count(values ...string) uint64 {
+	k := h.key(values)
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if hist, ok := h.values[k]; ok {
+		return hist.count
+	}
+	return 0
+}
+
+// WriteText implements Collector.
+func (h *HistogramVec) WriteText(w io.Writer) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if err := h.writeHeader(w, "histogram"); err != nil {
+		return err
+	}
+	var keys []string
+	for k := range h.values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		hist := h.values[k]
+		for i, b := range h.buckets {
+			if _, err := fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, h.labelString(k, "le", formatFloat(b)), hist.counts[i]); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, h.labelString(k, "le", "+Inf"), hist.count); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "%s_sum%s %s\n", h.name, h.labelString(k), formatFloat(hist.sum)); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "%s_count%s %d\n", h.name, h.labelString(k), hist.count); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sortedKeys(m map[string]float64) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	ga "google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+
+	"github.com/bowei/gce-gen/pkg/cloud"
+	"github.com/bowei/gce-gen/pkg/cloud/meta"
+)
+
+func TestWriteText(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry()
+	c := NewCounterVec("c_total", "A counter.", "a", "b")
+	h := NewHistogramVec("h_seconds", "A histogram.", []float64{1, 2}, "a")
+	r.MustRegister(c, h)
+
+	c.Inc("x", "y")
+	c.Add(2, "x", "y")
+	c.Inc(`q"\`, "line\nbreak")
+	h.Observe(0.5, "x")
+	h.Observe(1.5, "x")
+	h.Observe(3, "x")
+
+	buf := &bytes.Buffer{}
+	if err := r.WriteText(buf); err != nil {
+		t.Fatalf("WriteText() = %v, want nil", err)
+	}
+	want := `# HELP c_total A counter.
+# TYPE c_total counter
+c_total{a="q\"\\",b="line\nbreak"} 1
+c_total{a="x",b="y"} 3
+# HELP h_seconds A histogram.
+# TYPE h_seconds histogram
+h_seconds_bucket{a="x",le="1"} 1
+h_seconds_bucket{a="x",le="2"} 2
+h_seconds_bucket{a="x",le="+Inf"} 3
+h_seconds_sum{a="x"} 5
+h_seconds_count{a="x"} 3
+`
+	if got := buf.String(); got != want {
+		t.Errorf("WriteText() =\n%s\nwant\n%s", got, want)
+	}
+
+	if err := r.Register(NewCounterVec("c_total", "Duplicate.")); err == nil {
+		t.Errorf("Register(duplicate) = nil, want error")
+	}
+}
+
+func TestErrorLabels(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		err        error
+		wantCode   string
+		wantReason string
+	}{
+		{errors.New("x"), "", ""},
+		{&googleapi.Error{Code: http.StatusNotFound}, "404", ""},
+		{&googleapi.Error{Code: http.StatusBadRequest, Errors: []googleapi.ErrorItem{{Reason: "resourceInUseByAnotherResource"}}}, "400", "resourceInUseByAnotherResource"},
+	} {
+		code, reason := ErrorLabels(tc.err)
+		if code != tc.wantCode || reason != tc.wantReason {
+			t.Errorf("ErrorLabels(%v) = %q, %q; want %q, %q", tc.err, code, reason, tc.wantCode, tc.wantReason)
+		}
+	}
+}
+
+func TestObserverMock(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	r := NewRegistry()
+	o := NewObserver(r)
+	mock := cloud.NewMockGCE()
+	mock.SetObserver(o)
+
+	key := *meta.GlobalKey("fw")
+	if err := mock.Firewalls().Insert(ctx, key, &ga.Firewall{}); err != nil {
+		t.Fatalf("Firewalls().Insert() = %v, want nil", err)
+	}
+	if _, err := mock.Firewalls().Get(ctx, key); err != nil {
+		t.Fatalf("Firewalls().Get() = _, %v, want nil", err)
+	}
+	mock.MockFirewalls.InsertError[key] = &googleapi.Error{
+		Code:   http.StatusConflict,
+		Errors: []googleapi.ErrorItem{{Reason: "alreadyExists"}},
+	}
+	if err := mock.Firewalls().Insert(ctx, key, &ga.Firewall{}); err == nil {
+		t.Fatalf("Firewalls().Insert() = nil, want error")
+	}
+
+	if got := o.Calls.Value("mock-project", "ga", "Firewalls", "Insert"); got != 2 {
+		t.Errorf("Calls(Insert) = %v, want 2", got)
+	}
+	if got := o.Calls.Value("mock-project", "ga", "Firewalls", "Get"); got != 1 {
+		t.Errorf("Calls(Get) = %v, want 1", got)
+	}
+	if got := o.Errors.Value("mock-project", "ga", "Firewalls", "Insert", "409", "alreadyExists"); got != 1 {
+		t.Errorf("Errors(Insert, 409, alreadyExists) = %v, want 1", got)
+	}
+	if got := o.Latency.Count("mock-project", "ga", "Firewalls", "Insert"); got != 2 {
+		t.Errorf("Latency(Insert).Count = %v, want 2", got)
+	}
+	// The mock does not return operations.
+	if got := o.OperationWait.Count("mock-project", "ga", "Firewalls", "Insert"); got != 0 {
+		t.Errorf("OperationWait(Insert).Count = %v, want 0", got)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := r.WriteText(buf); err != nil {
+		t.Fatalf("WriteText() = %v, want nil", err)
+	}
+	for _, want := range []string{
+		`gce_api_calls_total{project="mock-project",version="ga",service="Firewalls",operation="Insert"} 2`,
+		`gce_api_errors_total{project="mock-project",version="ga",service="Firewalls",operation="Insert",code="409",reason="alreadyExists"} 1`,
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("WriteText() = %q, want it to contain %q", buf.String(), want)
+		}
+	}
+}
+
+func TestObserverOperationWait(t *testing.T) {
+	t.Parallel()
+
+	o := NewObserver(NewRegistry())
+	start := time.Now()
+	cc := &cloud.CallContext{
+		RateLimitKey: cloud.RateLimitKey{
+			ProjectID: "proj",
+			Operation: "Insert",
+			Version:   meta.VersionGA,
+			Service:   "Firewalls",
+		},
+		Start:         start,
+		End:           start.Add(3 * time.Second),
+		RateLimitWait: time.Second,
+		OperationWait: 2 * time.Second,
+	}
+	o.After(context.Background(), cc)
+
+	if got := o.OperationWait.Count("proj", "ga", "Firewalls", "Insert"); got != 1 {
+		t.Errorf("OperationWait.Count = %v, want 1", got)
+	}
+	if got := o.RateLimitWait.Count("proj", "ga", "Firewalls", "Insert"); got != 1 {
+		t.Errorf("RateLimitWait.Count = %v, want 1", got)
+	}
+}
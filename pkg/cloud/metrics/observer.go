@@ -0,0 +1,104 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"strconv"
+
+	"google.golang.org/api/googleapi"
+
+	"github.com/bowei/gce-gen/pkg/cloud"
+)
+
+// Observer is a cloud.CallObserver that records metrics for each call.
+type Observer struct {
+	// Calls is the number of calls made.
+	Calls *CounterVec
+	// Errors is the number of calls that returned an error, by HTTP code
+	// and googleapi reason.
+	Errors *CounterVec
+	// Latency is the end-to-end latency of the call, including the time
+	// spent rate limited and waiting for the operation to complete.
+	Latency *HistogramVec
+	// RateLimitWait is the time the call spent waiting in the RateLimiter.
+	RateLimitWait *HistogramVec
+	// OperationWait is the time spent waiting for the operation returned by
+	// the call to complete. This is only recorded for calls that returned an
+	// operation.
+	OperationWait *HistogramVec
+}
+
+// Labels common to all of the metrics.
+var callLabels = []string{"project", "version", "service", "operation"}
+
+// NewObserver returns a new Observer with its collectors registered in r.
+func NewObserver(r *Registry) *Observer {
+	o := &Observer{
+		Calls: NewCounterVec(
+			"gce_api_calls_total", "Number of GCE API calls.", callLabels...),
+		Errors: NewCounterVec(
+			"gce_api_errors_total", "Number of GCE API calls that returned an error.",
+			append(append([]string{}, callLabels...), "code", "reason")...),
+		Latency: NewHistogramVec(
+			"gce_api_call_duration_seconds", "Latency of GCE API calls, including the operation wait.",
+			nil, callLabels...),
+		RateLimitWait: NewHistogramVec(
+			"gce_api_rate_limit_wait_seconds", "Time GCE API calls spent waiting in the rate limiter.",
+			nil, callLabels...),
+		OperationWait: NewHistogramVec(
+			"gce_api_operation_wait_seconds", "Time spent waiting for GCE operations to complete.",
+			nil, callLabels...),
+	}
+	r.MustRegister(o.Calls, o.Errors, o.Latency, o.RateLimitWait, o.OperationWait)
+	return o
+}
+
+// Before implements cloud.CallObserver.
+func (o *Observer) Before(ctx context.Context, cc *cloud.CallContext) context.Context {
+	return ctx
+}
+
+// After implements cloud.CallObserver.
+func (o *Observer) After(ctx context.Context, cc *cloud.CallContext) {
+	labels := []string{cc.ProjectID, string(cc.Version), cc.Service, cc.Operation}
+
+	o.Calls.Inc(labels...)
+	o.Latency.Observe(cc.End.Sub(cc.Start).Seconds(), labels...)
+	o.RateLimitWait.Observe(cc.RateLimitWait.Seconds(), labels...)
+	if cc.OperationWait > 0 {
+		o.OperationWait.Observe(cc.OperationWait.Seconds(), labels...)
+	}
+	if cc.Err != nil {
+		code, reason := ErrorLabels(cc.Err)
+		o.Errors.Inc(append(labels, code, reason)...)
+	}
+}
+
+// ErrorLabels returns the HTTP code and reason of err for use as metric
+// labels. Errors that did not come from the API have code "" and reason "".
+func ErrorLabels(err error) (code, reason string) {
+	apiErr, ok := err.(*googleapi.Error)
+	if !ok {
+		return "", ""
+	}
+	code = strconv.Itoa(apiErr.Code)
+	if len(apiErr.Errors) > 0 {
+		reason = apiErr.Errors[0].Reason
+	}
+	return code, reason
+}
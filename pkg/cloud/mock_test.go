@@ -18,6 +18,8 @@ package cloud
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"testing"
 
@@ -148,3 +150,144 @@ func TestMocks(t *testing.T) {
 		t.Errorf("Addresses().Delete(%v, %v) = nil; want error", ctx, key)
 	}
 }
+
+func TestMockDebugValidatesFilters(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := NewMockGCE()
+	if err := mock.Firewalls().Insert(ctx, *meta.GlobalKey("fw"), &ga.Firewall{}); err != nil {
+		t.Fatalf("Firewalls().Insert() = %v, want nil", err)
+	}
+
+	typo := filter.Regexp("nmae", "fw")
+	if objs, err := mock.Firewalls().List(ctx, typo); err != nil || len(objs) != 0 {
+		t.Errorf("Firewalls().List(%v) = %v, %v; want [], nil without Debug", typo, objs, err)
+	}
+
+	mock.SetDebug(true)
+	if _, err := mock.Firewalls().List(ctx, typo); err == nil {
+		t.Errorf("Firewalls().List(%v) = _, nil; want error with Debug", typo)
+	}
+	if _, err := mock.AlphaNetworkEndpointGroups().AggregatedList(ctx, filter.CompareInt("name", filter.LessThan, 1)); err == nil {
+		t.Errorf("AlphaNetworkEndpointGroups().AggregatedList() = _, nil; want error with Debug")
+	}
+	if objs, err := mock.Firewalls().List(ctx, filter.Regexp("name", "fw")); err != nil || len(objs) != 1 {
+		t.Errorf("Firewalls().List() = %v, %v; want 1 item, nil", objs, err)
+	}
+}
+
+func TestInvalidKey(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+	svc, err := ga.New(srv.Client())
+	if err != nil {
+		t.Fatalf("ga.New() = _, %v", err)
+	}
+	svc.BasePath = srv.URL + "/projects/"
+	gce := NewGCE(&Service{
+		GA:            svc,
+		ProjectRouter: &SingleProjectRouter{ID: "proj"},
+		RateLimiter:   &noWaitRateLimiter{},
+	})
+	mock := NewMockGCE()
+	mock.MockFirewalls.GetHook = func(*MockFirewalls, context.Context, meta.Key) (bool, *ga.Firewall, error) {
+		t.Errorf("GetHook called")
+		return true, nil, nil
+	}
+
+	ctx := context.Background()
+	for _, key := range []*meta.Key{
+		meta.ZonalKey("fw", "us-central1-b"),
+		meta.GlobalKey("Fw"),
+		meta.GlobalKey(""),
+	} {
+		for _, c := range []Cloud{gce, mock} {
+			_, err := c.Firewalls().Get(ctx, *key)
+			if _, ok := err.(*meta.InvalidKeyError); !ok {
+				t.Errorf("%T.Firewalls().Get(%v) = _, %v; want *meta.InvalidKeyError", c, key, err)
+			}
+		}
+	}
+	if err := mock.Instances().Insert(ctx, *meta.ZonalKey("vm", "us-central1"), &ga.Instance{}); err == nil {
+		t.Errorf("Instances().Insert() with a region for the zone = nil, want error")
+	}
+}
+
+func TestMockUpdateKeepsVersionFields(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := NewMockGCE()
+	key := *meta.GlobalKey("bs")
+	obj := &alpha.BackendService{Port: 80, SecurityPolicy: "global/securityPolicies/sp"}
+	if err := mock.AlphaBackendServices().Insert(ctx, key, obj); err != nil {
+		t.Fatalf("AlphaBackendServices().Insert() = %v", err)
+	}
+
+	bs, err := mock.BackendServices().Get(ctx, key)
+	if err != nil {
+		t.Fatalf("BackendServices().Get() = _, %v", err)
+	}
+	bs.Port = 8080
+	bs.Description = "updated"
+	if err := mock.BackendServices().Update(ctx, key, bs); err != nil {
+		t.Fatalf("BackendServices().Update() = %v", err)
+	}
+
+	got, err := mock.AlphaBackendServices().Get(ctx, key)
+	if err != nil {
+		t.Fatalf("AlphaBackendServices().Get() = _, %v", err)
+	}
+	if got.SecurityPolicy != obj.SecurityPolicy || got.Port != 8080 || got.Description != "updated" {
+		t.Errorf("AlphaBackendServices().Get() = %+v; want the update with SecurityPolicy %q", got, obj.SecurityPolicy)
+	}
+
+	// An update with alpha replaces the alpha fields.
+	got.SecurityPolicy = ""
+	if err := mock.AlphaBackendServices().Update(ctx, key, got); err != nil {
+		t.Fatalf("AlphaBackendServices().Update() = %v", err)
+	}
+	if got, err := mock.AlphaBackendServices().Get(ctx, key); err != nil || got.SecurityPolicy != "" {
+		t.Errorf("AlphaBackendServices().Get() = %+v, %v; want no SecurityPolicy", got, err)
+	}
+}
+
+// setVersionOnlyFields sets the string fields of obj that at least one of
+// others does not have, which are objects of other versions of the API. It
+// returns the names of the fields.
+func setVersionOnlyFields(obj interface{}, others ...interface{}) []string {
+	v := reflect.ValueOf(obj).Elem()
+	var ret []string
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Type().Field(i)
+		if f.Type.Kind() != reflect.String {
+			continue
+		}
+		for _, o := range others {
+			if _, ok := reflect.TypeOf(o).Elem().FieldByName(f.Name); !ok {
+				v.Field(i).SetString("version-only")
+				ret = append(ret, f.Name)
+				break
+			}
+		}
+	}
+	return ret
+}
+
+// lostVersionOnlyFields returns the fields set by setVersionOnlyFields() that
+// obj does not have anymore.
+func lostVersionOnlyFields(obj interface{}, fields []string) []string {
+	var ret []string
+	for _, name := range fields {
+		if reflect.ValueOf(obj).Elem().FieldByName(name).String() != "version-only" {
+			ret = append(ret, name)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"time"
+
+	"github.com/bowei/gce-gen/pkg/cloud/meta"
+)
+
+// CallContext describes a single call made through the generated code. It
+// is passed to the CallObserver before and after the call.
+type CallContext struct {
+	// RateLimitKey identifies the project, version, service and operation
+	// of the call.
+	RateLimitKey
+	// Key is the key of the resource. This is nil for List() and
+	// AggregatedList().
+	Key *meta.Key
+	// Request is the request object of the call if there is one, e.g. the
+	// object to Insert(). It is a []interface{} of the arguments of the
+	// methods that take more than one besides the key (e.g. the interface
+	// name and the *alpha.NetworkInterface of UpdateNetworkInterface()).
+	Request interface{}
+	// Mutates is true if the call changes the state of a resource, i.e. it
+	// is an Insert(), Delete() or a method that returns an operation.
+	Mutates bool
+	// DryRun is true if the call was recorded in the Service.DryRun plan
+	// instead of being sent to GCE.
+	DryRun bool
+	// OperationName is the name of the GCE operation returned by the call,
+	// if any. This is set by WaitForCompletion().
+	OperationName string
+	// Start is the time the call started.
+	Start time.Time
+	// RateLimitWait is the time spent waiting in the RateLimiter before the
+	// call was made.
+	RateLimitWait time.Duration
+	// OperationWait is the time spent in WaitForCompletion() waiting for the
+	// operation returned by the call to complete.
+	OperationWait time.Duration
+	// End is the time the call completed, including the wait for the
+	// operation to complete. This is only set in After().
+	End time.Time
+	// Err is the result of the call. This is only set in After().
+	Err error
+}
+
+// CallObserver is invoked around every call made through the generated
+// code (both GCE and MockGCE). This allows for cross-cutting concerns such as
+// logging, metrics and tracing to be added without modifying the generated
+// code.
+type CallObserver interface {
+	// Before is invoked before the call is made. The returned context is used
+	// for the remainder of the call, which allows for values to be attached
+	// to the context (e.g. a trace span).
+	Before(ctx context.Context, cc *CallContext) context.Context
+	// After is invoked after the call has completed. For calls that return
+	// an operation, this is after the operation has completed. ctx is the
+	// context returned by Before().
+	After(ctx context.Context, cc *CallContext)
+}
+
+// OperationPoll describes a single poll of the status of an operation by
+// WaitForCompletion().
+type OperationPoll struct {
+	// RateLimitKey of the poll.
+	RateLimitKey
+	// Name of the operation.
+	Name string
+	// Start and End time of the poll.
+	Start time.Time
+	End   time.Time
+	// Done is true if the operation was complete.
+	Done bool
+	// Err is the error returned by the poll, if any.
+	Err error
+}
+
+// OperationObserver can be implemented by a CallObserver to be notified each
+// time WaitForCompletion() polls the status of an operation. ctx is the
+// context of the call waiting on the operation.
+type OperationObserver interface {
+	OperationPolled(ctx context.Context, p *OperationPoll)
+}
+
+// CallObservers chains a list of observers together. Before() is invoked in
+// order and After() is invoked in reverse order.
+type CallObservers []CallObserver
+
+// Before implements CallObserver.
+func (l CallObservers) Before(ctx context.Context, cc *CallContext) context.Context {
+	for _, o := range l {
+		ctx = o.Before(ctx, cc)
+	}
+	return ctx
+}
+
+// After implements CallObserver.
+func (l CallObservers) After(ctx context.Context, cc *CallContext) {
+	for i := len(l) - 1; i >= 0; i-- {
+		l[i].After(ctx, cc)
+	}
+}
+
+// OperationPolled implements OperationObserver by calling each observer in
+// the chain that implements OperationObserver.
+func (l CallObservers) OperationPolled(ctx context.Context, p *OperationPoll) {
+	for _, o := range l {
+		if oo, ok := o.(OperationObserver); ok {
+			oo.OperationPolled(ctx, p)
+		}
+	}
+}
+
+type callContextKey struct{}
+
+// callContextFrom returns the CallContext for the call in progress in ctx or
+// nil if there is none.
+func callContextFrom(ctx context.Context) *CallContext {
+	cc, _ := ctx.Value(callContextKey{}).(*CallContext)
+	return cc
+}
+
+// startCall records the start of the call and notifies the observer o. o may
+// be nil. The returned context carries cc for use by WaitForCompletion().
+func startCall(ctx context.Context, o CallObserver, cc *CallContext) context.Context {
+	cc.Start = time.Now()
+	ctx = context.WithValue(ctx, callContextKey{}, cc)
+	if o == nil {
+		return ctx
+	}
+	return o.Before(ctx, cc)
+}
+
+// endCall records the result of the call and notifies the observer o. o may
+// be nil.
+func endCall(ctx context.Context, o CallObserver, cc *CallContext, err error) {
+	cc.End = time.Now()
+	cc.Err = err
+	if o == nil {
+		return
+	}
+	o.After(ctx, cc)
+}
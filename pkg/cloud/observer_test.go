@@ -0,0 +1,104 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	ga "google.golang.org/api/compute/v1"
+
+	"github.com/bowei/gce-gen/pkg/cloud/filter"
+	"github.com/bowei/gce-gen/pkg/cloud/meta"
+)
+
+type ctxKey string
+
+// recordingObserver records the calls to Before() and After().
+type recordingObserver struct {
+	name   string
+	events *[]string
+	calls  []CallContext
+}
+
+func (o *recordingObserver) Before(ctx context.Context, cc *CallContext) context.Context {
+	*o.events = append(*o.events, fmt.Sprintf("%s.Before(%s)", o.name, cc.Operation))
+	return context.WithValue(ctx, ctxKey(o.name), true)
+}
+
+func (o *recordingObserver) After(ctx context.Context, cc *CallContext) {
+	*o.events = append(*o.events, fmt.Sprintf("%s.After(%s)", o.name, cc.Operation))
+	if ctx.Value(ctxKey(o.name)) == nil {
+		*o.events = append(*o.events, fmt.Sprintf("%s: context not propagated", o.name))
+	}
+	o.calls = append(o.calls, *cc)
+}
+
+func TestCallObserverMock(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := NewMockGCE()
+
+	var events []string
+	o1 := &recordingObserver{name: "o1", events: &events}
+	o2 := &recordingObserver{name: "o2", events: &events}
+	mock.SetObserver(CallObservers{o1, o2})
+
+	key := meta.GlobalKey("fw-1")
+	fw := &ga.Firewall{}
+	if err := mock.Firewalls().Insert(ctx, *key, fw); err != nil {
+		t.Fatalf("Firewalls().Insert(%v, %v, _) = %v; want nil", ctx, key, err)
+	}
+	if _, err := mock.Firewalls().Get(ctx, *meta.GlobalKey("does-not-exist")); err == nil {
+		t.Fatalf("Firewalls().Get(_, does-not-exist) = _, nil; want error")
+	}
+	if _, err := mock.Firewalls().List(ctx, filter.None); err != nil {
+		t.Fatalf("Firewalls().List() = _, %v; want nil", err)
+	}
+
+	wantEvents := []string{
+		"o1.Before(Insert)", "o2.Before(Insert)", "o2.After(Insert)", "o1.After(Insert)",
+		"o1.Before(Get)", "o2.Before(Get)", "o2.After(Get)", "o1.After(Get)",
+		"o1.Before(List)", "o2.Before(List)", "o2.After(List)", "o1.After(List)",
+	}
+	if !reflect.DeepEqual(events, wantEvents) {
+		t.Errorf("events = %v, want %v", events, wantEvents)
+	}
+
+	if len(o1.calls) != 3 {
+		t.Fatalf("len(o1.calls) = %d, want 3", len(o1.calls))
+	}
+	insert := o1.calls[0]
+	if insert.Service != "Firewalls" || insert.Version != meta.VersionGA || *insert.Key != *key {
+		t.Errorf("insert = %+v, want Firewalls, ga, %v", insert, key)
+	}
+	if insert.Request != fw {
+		t.Errorf("insert.Request = %v, want %v", insert.Request, fw)
+	}
+	if insert.Err != nil || insert.Start.IsZero() || insert.End.Before(insert.Start) {
+		t.Errorf("insert = %+v, want Err = nil and Start <= End", insert)
+	}
+	if get := o1.calls[1]; get.Err == nil {
+		t.Errorf("get.Err = nil, want error")
+	}
+	if list := o1.calls[2]; list.Key != nil {
+		t.Errorf("list.Key = %v, want nil", list.Key)
+	}
+}
@@ -34,6 +34,8 @@ type operation interface {
 	// This rate limit will govern how fast the server will be polled for
 	// operation completion status.
 	rateLimitKey() *RateLimitKey
+	// name of the operation.
+	name() string
 }
 
 type gaOperation struct {
@@ -62,6 +64,10 @@ func (o *gaOperation) isDone(ctx context.Context) (bool, error) {
 	return op != nil && op.Status == "DONE", nil
 }
 
+func (o *gaOperation) name() string {
+	return o.op.Name
+}
+
 func (o *gaOperation) rateLimitKey() *RateLimitKey {
 	return &RateLimitKey{
 		ProjectID: o.projectID,
@@ -97,6 +103,10 @@ func (o *alphaOperation) isDone(ctx context.Context) (bool, error) {
 	return op != nil && op.Status == "DONE", nil
 }
 
+func (o *alphaOperation) name() string {
+	return o.op.Name
+}
+
 func (o *alphaOperation) rateLimitKey() *RateLimitKey {
 	return &RateLimitKey{
 		ProjectID: o.projectID,
@@ -132,6 +142,10 @@ func (o *betaOperation) isDone(ctx context.Context) (bool, error) {
 	return op != nil && op.Status == "DONE", nil
 }
 
+func (o *betaOperation) name() string {
+	return o.op.Name
+}
+
 func (o *betaOperation) rateLimitKey() *RateLimitKey {
 	return &RateLimitKey{
 		ProjectID: o.projectID,
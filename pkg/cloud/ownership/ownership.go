@@ -0,0 +1,214 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ownership marks the compute resources created by an owner, such as
+// a cluster identified by its UID, and deletes the resources of the owners
+// that are gone.
+//
+// The owner is stamped in the Labels of the objects that have them, and in
+// the Description of the others. NewObserver() stamps the objects of all of
+// the Insert() calls:
+//
+//  svc := &cloud.Service{
+//    ...
+//    Observer: ownership.NewObserver(clusterUID),
+//  }
+//
+// GC() deletes the resources whose owner is no longer live, in dependency
+// order:
+//
+//  err := ownership.GC(ctx, gce, ownership.GCOptions{
+//    Live: func(owner string) bool { return clusters[owner] },
+//  })
+package ownership
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/golang/glog"
+
+	"github.com/bowei/gce-gen/pkg/cloud"
+	"github.com/bowei/gce-gen/pkg/cloud/graph"
+)
+
+// Label is the label, or the prefix in the description, holding the owner of
+// a resource.
+const Label = "gce-owner"
+
+var (
+	// ownerRE is the format of owners, which must be valid label values.
+	ownerRE = regexp.MustCompile(`^[a-z0-9_-]{1,63}$`)
+	// descriptionRE matches the owner in a description.
+	descriptionRE = regexp.MustCompile(`(^|\s)` + Label + `=([a-z0-9_-]{1,63})(\s|$)`)
+)
+
+// Stamp sets the owner of obj, a compute object such as *ga.Disk, in its
+// Labels if it has them or else in its Description, which is appended
+// "gce-owner=<owner>". A previous owner is replaced. The owner must be a
+// valid label value: up to 63 lowercase letters, digits, "_" and "-".
+func Stamp(obj interface{}, owner string) error {
+	if !ownerRE.MatchString(owner) {
+		return fmt.Errorf("ownership: %q is not a valid owner", owner)
+	}
+	v := reflect.ValueOf(obj)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("ownership: %T is not a compute object", obj)
+	}
+
+	if labels := v.Elem().FieldByName("Labels"); labels.IsValid() && labels.Type() == reflect.TypeOf(map[string]string{}) {
+		if labels.IsNil() {
+			labels.Set(reflect.ValueOf(map[string]string{}))
+		}
+		labels.SetMapIndex(reflect.ValueOf(Label), reflect.ValueOf(owner))
+		return nil
+	}
+	if desc := v.Elem().FieldByName("Description"); desc.IsValid() && desc.Kind() == reflect.String {
+		d := strings.TrimSpace(descriptionRE.ReplaceAllString(desc.String(), " "))
+		if d != "" {
+			d += " "
+		}
+		desc.SetString(d + Label + "=" + owner)
+		return nil
+	}
+	return fmt.Errorf("ownership: %T has neither labels nor a description", obj)
+}
+
+// Owner returns the owner stamped on obj by Stamp(), if any.
+func Owner(obj interface{}) (string, bool) {
+	v := reflect.ValueOf(obj)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return "", false
+	}
+	if labels := v.Elem().FieldByName("Labels"); labels.IsValid() && labels.Kind() == reflect.Map {
+		if owner := labels.MapIndex(reflect.ValueOf(Label)); owner.IsValid() {
+			return owner.String(), true
+		}
+	}
+	if desc := v.Elem().FieldByName("Description"); desc.IsValid() && desc.Kind() == reflect.String {
+		if m := descriptionRE.FindStringSubmatch(desc.String()); m != nil {
+			return m[2], true
+		}
+	}
+	return "", false
+}
+
+// observer stamps the objects of the Insert() calls.
+type observer struct {
+	owner string
+}
+
+// NewObserver returns a cloud.CallObserver that stamps owner on the object of
+// each Insert() call before it is made. Objects that cannot be stamped are
+// inserted as they are, with a warning.
+func NewObserver(owner string) cloud.CallObserver {
+	return &observer{owner: owner}
+}
+
+// Before implements cloud.CallObserver.
+func (o *observer) Before(ctx context.Context, cc *cloud.CallContext) context.Context {
+	if cc.Operation != "Insert" || cc.Request == nil {
+		return ctx
+	}
+	if err := Stamp(cc.Request, o.owner); err != nil {
+		glog.Warningf("ownership: %s %v: %v", cc.Service, cc.Key, err)
+	}
+	return ctx
+}
+
+// After implements cloud.CallObserver.
+func (o *observer) After(ctx context.Context, cc *cloud.CallContext) {}
+
+// Resource is a resource with an owner.
+type Resource struct {
+	ID    *cloud.ResourceID
+	Owner string
+	// Object as listed (e.g. *ga.Disk).
+	Object interface{}
+}
+
+// String returns the path of the resource and its owner.
+func (r *Resource) String() string {
+	return fmt.Sprintf("%s (owner %s)", &graph.Node{ID: r.ID}, r.Owner)
+}
+
+// List returns the resources with an owner in the project of c, from all of
+// the services in meta.AllServices with a List() method, sorted by path.
+func List(ctx context.Context, c cloud.Cloud) ([]*Resource, error) {
+	g, err := graph.Build(ctx, c, graph.BuildOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var ret []*Resource
+	for _, n := range g.Nodes() {
+		if n.Object == nil {
+			continue
+		}
+		if owner, ok := Owner(n.Object); ok {
+			ret = append(ret, &Resource{ID: n.ID, Owner: owner, Object: n.Object})
+		}
+	}
+	return ret, nil
+}
+
+// GCOptions configure GC().
+type GCOptions struct {
+	// Live returns true if owner still exists. It is required.
+	Live func(owner string) bool
+	// Parallelism is the maximum number of concurrent Delete() calls. It
+	// is 1 if not set.
+	Parallelism int
+	// DryRun prints the plan to Out instead of deleting the resources.
+	DryRun bool
+	// Out is where the plan is printed. It is os.Stdout if not set.
+	Out io.Writer
+}
+
+// GC deletes the resources in the project of c whose owner is not live,
+// users first (e.g. forwarding rules before target proxies). A resource that
+// is still referenced by a resource that is not deleted, such as a resource
+// of a live owner or without owner, is kept. GC returns a
+// *graph.TeardownError if some resources could not be deleted.
+func GC(ctx context.Context, c cloud.Cloud, opts GCOptions) error {
+	if opts.Live == nil {
+		return fmt.Errorf("ownership: GCOptions.Live is required")
+	}
+	g, err := graph.Build(ctx, c, graph.BuildOptions{})
+	if err != nil {
+		return err
+	}
+	plan, err := graph.PlanTeardown(g, func(n *graph.Node) bool {
+		owner, ok := Owner(n.Object)
+		return !ok || opts.Live(owner)
+	})
+	if err != nil {
+		return err
+	}
+	if opts.DryRun {
+		out := opts.Out
+		if out == nil {
+			out = os.Stdout
+		}
+		_, err := io.WriteString(out, plan.String())
+		return err
+	}
+	return plan.Execute(ctx, c, opts.Parallelism)
+}
@@ -0,0 +1,229 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ownership
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+
+	ga "google.golang.org/api/compute/v1"
+
+	"github.com/bowei/gce-gen/pkg/cloud"
+	"github.com/bowei/gce-gen/pkg/cloud/meta"
+)
+
+func TestStamp(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		desc      string
+		obj       interface{}
+		owner     string
+		wantErr   bool
+		wantLabel map[string]string
+		wantDesc  string
+	}{
+		{
+			desc:      "labels",
+			obj:       &ga.Disk{},
+			owner:     "uid-1",
+			wantLabel: map[string]string{Label: "uid-1"},
+		},
+		{
+			desc:      "existing labels",
+			obj:       &ga.Disk{Labels: map[string]string{"app": "x", Label: "uid-0"}},
+			owner:     "uid-1",
+			wantLabel: map[string]string{"app": "x", Label: "uid-1"},
+		},
+		{
+			desc:     "description",
+			obj:      &ga.Firewall{},
+			owner:    "uid-1",
+			wantDesc: "gce-owner=uid-1",
+		},
+		{
+			desc:     "existing description",
+			obj:      &ga.Firewall{Description: "allow http"},
+			owner:    "uid-1",
+			wantDesc: "allow http gce-owner=uid-1",
+		},
+		{
+			desc:     "previous owner",
+			obj:      &ga.Firewall{Description: "allow gce-owner=uid-0 http"},
+			owner:    "uid-1",
+			wantDesc: "allow http gce-owner=uid-1",
+		},
+		{
+			desc:    "invalid owner",
+			obj:     &ga.Firewall{},
+			owner:   "Cluster A",
+			wantErr: true,
+		},
+		{
+			desc:    "no labels nor description",
+			obj:     &struct{ Name string }{},
+			owner:   "uid-1",
+			wantErr: true,
+		},
+		{
+			desc:    "not a pointer",
+			obj:     ga.Firewall{},
+			owner:   "uid-1",
+			wantErr: true,
+		},
+	} {
+		err := Stamp(tc.obj, tc.owner)
+		if gotErr := err != nil; gotErr != tc.wantErr {
+			t.Errorf("%s: Stamp() = %v; want error %t", tc.desc, err, tc.wantErr)
+		}
+		if tc.wantErr {
+			continue
+		}
+		switch obj := tc.obj.(type) {
+		case *ga.Disk:
+			if !reflect.DeepEqual(obj.Labels, tc.wantLabel) {
+				t.Errorf("%s: Labels = %v; want %v", tc.desc, obj.Labels, tc.wantLabel)
+			}
+		case *ga.Firewall:
+			if obj.Description != tc.wantDesc {
+				t.Errorf("%s: Description = %q; want %q", tc.desc, obj.Description, tc.wantDesc)
+			}
+		}
+		if owner, ok := Owner(tc.obj); !ok || owner != tc.owner {
+			t.Errorf("%s: Owner() = %q, %t; want %q, true", tc.desc, owner, ok, tc.owner)
+		}
+	}
+
+	if owner, ok := Owner(&ga.Firewall{Description: "gce-owner=uid-1x y"}); !ok || owner != "uid-1x" {
+		t.Errorf("Owner() = %q, %t; want uid-1x, true", owner, ok)
+	}
+	if owner, ok := Owner(&ga.Firewall{Description: "not-gce-owner=uid-1"}); ok {
+		t.Errorf("Owner() = %q, true; want false", owner)
+	}
+}
+
+const zone = "us-central1-b"
+
+// newResources returns a mock with the resources of two owners, "dead" and
+// "live", and without owner.
+//
+//  um (dead) -> bs (dead) -> hc (dead)
+//  bs-live (live) -> hc-shared (dead)
+//  disk (dead), fw (none)
+func newResources(t *testing.T) *cloud.MockGCE {
+	ctx := context.Background()
+	mock := cloud.NewMockGCE(
+		cloud.WithMockTopology(map[string][]string{"us-central1": {zone}}),
+		cloud.WithReferenceChecking())
+
+	insert := func(owner string, f func() error) {
+		mock.SetObserver(nil)
+		if owner != "" {
+			mock.SetObserver(NewObserver(owner))
+		}
+		if err := f(); err != nil {
+			t.Fatalf("Insert() = %v", err)
+		}
+	}
+	insert("dead", func() error { return mock.HealthChecks().Insert(ctx, *meta.GlobalKey("hc"), &ga.HealthCheck{}) })
+	insert("dead", func() error { return mock.HealthChecks().Insert(ctx, *meta.GlobalKey("hc-shared"), &ga.HealthCheck{}) })
+	insert("dead", func() error {
+		return mock.BackendServices().Insert(ctx, *meta.GlobalKey("bs"), &ga.BackendService{HealthChecks: []string{"global/healthChecks/hc"}})
+	})
+	insert("dead", func() error {
+		return mock.UrlMaps().Insert(ctx, *meta.GlobalKey("um"), &ga.UrlMap{DefaultService: "global/backendServices/bs"})
+	})
+	insert("dead", func() error { return mock.Disks().Insert(ctx, *meta.ZonalKey("disk", zone), &ga.Disk{}) })
+	insert("live", func() error {
+		return mock.BackendServices().Insert(ctx, *meta.GlobalKey("bs-live"), &ga.BackendService{HealthChecks: []string{"global/healthChecks/hc-shared"}})
+	})
+	insert("", func() error { return mock.Firewalls().Insert(ctx, *meta.GlobalKey("fw"), &ga.Firewall{}) })
+	mock.SetObserver(nil)
+	return mock
+}
+
+func TestList(t *testing.T) {
+	t.Parallel()
+
+	resources, err := List(context.Background(), newResources(t))
+	if err != nil {
+		t.Fatalf("List() = _, %v", err)
+	}
+	var got []string
+	for _, r := range resources {
+		got = append(got, r.String())
+	}
+	want := []string{
+		"projects/mock-project/global/backendServices/bs (owner dead)",
+		"projects/mock-project/global/backendServices/bs-live (owner live)",
+		"projects/mock-project/global/healthChecks/hc (owner dead)",
+		"projects/mock-project/global/healthChecks/hc-shared (owner dead)",
+		"projects/mock-project/global/urlMaps/um (owner dead)",
+		"projects/mock-project/zones/us-central1-b/disks/disk (owner dead)",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("List() = %q; want %q", got, want)
+	}
+}
+
+func TestGC(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	live := func(owner string) bool { return owner == "live" }
+
+	mock := newResources(t)
+	var buf bytes.Buffer
+	if err := GC(ctx, mock, GCOptions{Live: live, DryRun: true, Out: &buf}); err != nil {
+		t.Fatalf("GC(DryRun) = %v", err)
+	}
+	wantPlan := "1 projects/mock-project/global/urlMaps/um\n" +
+		"1 projects/mock-project/zones/us-central1-b/disks/disk\n" +
+		"2 projects/mock-project/global/backendServices/bs\n" +
+		"3 projects/mock-project/global/healthChecks/hc\n"
+	if got := buf.String(); got != wantPlan {
+		t.Errorf("GC(DryRun) printed\n%s\nwant\n%s", got, wantPlan)
+	}
+
+	if err := GC(ctx, mock, GCOptions{Live: live}); err != nil {
+		t.Fatalf("GC() = %v", err)
+	}
+	resources, err := List(ctx, mock)
+	if err != nil {
+		t.Fatalf("List() = _, %v", err)
+	}
+	var got []string
+	for _, r := range resources {
+		got = append(got, r.String())
+	}
+	want := []string{
+		"projects/mock-project/global/backendServices/bs-live (owner live)",
+		"projects/mock-project/global/healthChecks/hc-shared (owner dead)",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("List() after GC() = %q; want %q", got, want)
+	}
+	if _, err := mock.Firewalls().Get(ctx, *meta.GlobalKey("fw")); err != nil {
+		t.Errorf("Firewalls().Get(fw) = _, %v; want the firewall without owner to be kept", err)
+	}
+
+	if err := GC(ctx, mock, GCOptions{}); err == nil {
+		t.Errorf("GC() without Live = nil; want an error")
+	}
+}
@@ -42,3 +42,146 @@ type SingleProjectRouter struct {
 func (r *SingleProjectRouter) ProjectID(ctx context.Context, version meta.Version, service string) string {
 	return r.ID
 }
+
+// ServiceRoute identifies the calls to an API (version, service) for
+// MapProjectRouter. An empty Version matches all of the versions of the
+// service.
+type ServiceRoute struct {
+	Version meta.Version
+	Service string
+}
+
+// MapProjectRouter routes the calls to each (version, service) in Routes to
+// the corresponding router, and all other calls to Default. A route for a
+// specific version takes precedence over a route for all versions.
+//
+//  r := &MapProjectRouter{
+//    Routes: map[ServiceRoute]ProjectRouter{
+//      {Service: "Instances"}: &SingleProjectRouter{ID: "compute-project"},
+//    },
+//    Default: &SingleProjectRouter{ID: "my-project"},
+//  }
+type MapProjectRouter struct {
+	Routes  map[ServiceRoute]ProjectRouter
+	Default ProjectRouter
+}
+
+func (r *MapProjectRouter) ProjectID(ctx context.Context, version meta.Version, service string) string {
+	if route, ok := r.Routes[ServiceRoute{version, service}]; ok {
+		return route.ProjectID(ctx, version, service)
+	}
+	if route, ok := r.Routes[ServiceRoute{Service: service}]; ok {
+		return route.ProjectID(ctx, version, service)
+	}
+	return r.Default.ProjectID(ctx, version, service)
+}
+
+// projectIDKey is the context key for the project set by WithProjectID().
+type projectIDKey struct{}
+
+// WithProjectID returns a copy of ctx with the project for the calls made
+// with the context, to be used by ContextProjectRouter.
+func WithProjectID(ctx context.Context, projectID string) context.Context {
+	return context.WithValue(ctx, projectIDKey{}, projectID)
+}
+
+// ProjectIDFromContext returns the project set with WithProjectID(), if any.
+func ProjectIDFromContext(ctx context.Context) (string, bool) {
+	projectID, ok := ctx.Value(projectIDKey{}).(string)
+	return projectID, ok && projectID != ""
+}
+
+// ContextProjectRouter routes calls to the project set in the context with
+// WithProjectID(), and to Default if there is none. This is used to make the
+// calls for each request to the project of the request.
+type ContextProjectRouter struct {
+	Default ProjectRouter
+}
+
+func (r *ContextProjectRouter) ProjectID(ctx context.Context, version meta.Version, service string) string {
+	if projectID, ok := ProjectIDFromContext(ctx); ok {
+		return projectID
+	}
+	return r.Default.ProjectID(ctx, version, service)
+}
+
+// SharedVPCServices are the services that SharedVPCProjectRouter routes to
+// the host project by default.
+var SharedVPCServices = []string{"Networks", "Subnetworks", "Firewalls", "Routes", "Addresses"}
+
+// SharedVPCProjectRouter routes the calls to network-scoped services to Host,
+// the router for the host project of a shared VPC, and all other calls to
+// Service, the router for the service project.
+type SharedVPCProjectRouter struct {
+	Host    ProjectRouter
+	Service ProjectRouter
+	// NetworkServices are the services routed to Host. SharedVPCServices is
+	// used if it is nil.
+	NetworkServices []string
+}
+
+func (r *SharedVPCProjectRouter) ProjectID(ctx context.Context, version meta.Version, service string) string {
+	services := r.NetworkServices
+	if services == nil {
+		services = SharedVPCServices
+	}
+	for _, s := range services {
+		if s == service {
+			return r.Host.ProjectID(ctx, version, service)
+		}
+	}
+	return r.Service.ProjectID(ctx, version, service)
+}
+
+// keyProjectID returns the project for a call on key: the project of the key
+// if it is set, otherwise the project from the ProjectRouter.
+func (s *Service) keyProjectID(ctx context.Context, key meta.Key, version meta.Version, service string) string {
+	if key.Project != "" {
+		return key.Project
+	}
+	return s.ProjectRouter.ProjectID(ctx, version, service)
+}
+
+// WithMockProjectRouter sets the ProjectRouter of the mock to r, as
+// MockGCE.SetProjectRouter() does. Options are applied in order, so it must
+// come before the options that seed objects, such as WithMockTopology().
+func WithMockProjectRouter(r ProjectRouter) MockOption {
+	return func(mock *MockGCE) {
+		mock.SetProjectRouter(r)
+	}
+}
+
+// mockProjectID is the project of the calls to the mock when there is no
+// project in the key and no ProjectRouter.
+const mockProjectID = "mock-project"
+
+// mockCallProjectID returns the project of a call to the mock: the project
+// of key if it is set, otherwise the project from r if it is set, otherwise
+// mockProjectID. key is nil for calls without a key, such as List().
+func mockCallProjectID(ctx context.Context, r ProjectRouter, key *meta.Key, version meta.Version, service string) string {
+	if key != nil && key.Project != "" {
+		return key.Project
+	}
+	if r != nil {
+		return r.ProjectID(ctx, version, service)
+	}
+	return mockProjectID
+}
+
+// mockStorageProject returns the project of the keys that the mock stores
+// the objects in project under. Objects in mockProjectID are stored under
+// keys without a project so that Objects (and the error maps) can be used
+// with unqualified keys.
+func mockStorageProject(project string) string {
+	if project == mockProjectID {
+		return ""
+	}
+	return project
+}
+
+// mockStorageKey returns the key that the mock stores the object for key in
+// project under.
+func mockStorageKey(key meta.Key, project string) meta.Key {
+	key.Project = mockStorageProject(project)
+	return key
+}
@@ -0,0 +1,292 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+
+	ga "google.golang.org/api/compute/v1"
+
+	"github.com/bowei/gce-gen/pkg/cloud/filter"
+	"github.com/bowei/gce-gen/pkg/cloud/meta"
+)
+
+// pathRecorder is a stand-in for the compute API that records the paths of
+// the requests and returns an empty object.
+type pathRecorder struct {
+	lock  sync.Mutex
+	paths []string
+}
+
+func (p *pathRecorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.lock.Lock()
+	p.paths = append(p.paths, r.URL.Path)
+	p.lock.Unlock()
+	json.NewEncoder(w).Encode(map[string]interface{}{})
+}
+
+func (p *pathRecorder) Paths() []string {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return append([]string(nil), p.paths...)
+}
+
+func TestGCEKeyProject(t *testing.T) {
+	t.Parallel()
+
+	rec := &pathRecorder{}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+	svc, err := ga.New(srv.Client())
+	if err != nil {
+		t.Fatalf("ga.New() = _, %v", err)
+	}
+	svc.BasePath = srv.URL + "/projects/"
+	gce := NewGCE(&Service{
+		GA:            svc,
+		ProjectRouter: &SingleProjectRouter{ID: "service-project"},
+		RateLimiter:   &noWaitRateLimiter{},
+	})
+
+	ctx := context.Background()
+	addr := meta.RegionalKey("addr", "us-central1")
+	if _, err := gce.Addresses().Get(ctx, *addr); err != nil {
+		t.Errorf("Addresses().Get(%v) = _, %v", addr, err)
+	}
+	if _, err := gce.Addresses().Get(ctx, *addr.WithProject("host-project")); err != nil {
+		t.Errorf("Addresses().Get(%v) = _, %v", addr.WithProject("host-project"), err)
+	}
+	want := []string{
+		"/projects/service-project/regions/us-central1/addresses/addr",
+		"/projects/host-project/regions/us-central1/addresses/addr",
+	}
+	if got := rec.Paths(); !reflect.DeepEqual(got, want) {
+		t.Errorf("paths = %q, want %q", got, want)
+	}
+}
+
+func TestMockKeyProject(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := NewMockGCE()
+	key := meta.GlobalKey("fw")
+	hostKey := key.WithProject("host-project")
+
+	if err := mock.Firewalls().Insert(ctx, *key, &ga.Firewall{Description: "service"}); err != nil {
+		t.Fatalf("Insert(%v) = %v", key, err)
+	}
+	if err := mock.Firewalls().Insert(ctx, *hostKey, &ga.Firewall{Description: "host"}); err != nil {
+		t.Fatalf("Insert(%v) = %v", hostKey, err)
+	}
+	// Keys in the mock project are the same as keys without a project.
+	if err := mock.Firewalls().Insert(ctx, *key.WithProject("mock-project"), &ga.Firewall{}); err == nil {
+		t.Errorf("Insert(%v) = nil, want error", key.WithProject("mock-project"))
+	}
+
+	for _, tc := range []struct {
+		key      *meta.Key
+		wantDesc string
+		wantLink string
+	}{
+		{key, "service", "https://www.googleapis.com/compute/v1/projects/mock-project/global/firewalls/fw"},
+		{key.WithProject("mock-project"), "service", "https://www.googleapis.com/compute/v1/projects/mock-project/global/firewalls/fw"},
+		{hostKey, "host", "https://www.googleapis.com/compute/v1/projects/host-project/global/firewalls/fw"},
+	} {
+		fw, err := mock.Firewalls().Get(ctx, *tc.key)
+		if err != nil {
+			t.Errorf("Get(%v) = _, %v", tc.key, err)
+			continue
+		}
+		if fw.Description != tc.wantDesc || fw.SelfLink != tc.wantLink {
+			t.Errorf("Get(%v) = %+v; want Description %q, SelfLink %q", tc.key, fw, tc.wantDesc, tc.wantLink)
+		}
+	}
+
+	// Only the objects in the mock project are listed.
+	if fws, err := mock.Firewalls().List(ctx, filter.None); err != nil || len(fws) != 1 || fws[0].Description != "service" {
+		t.Errorf("List() = %v, %v; want [service], nil", fws, err)
+	}
+	if _, ok := mock.MockFirewalls.Objects[*hostKey]; !ok {
+		t.Errorf("mock.MockFirewalls.Objects[%v] not found", hostKey)
+	}
+
+	if err := mock.Firewalls().Delete(ctx, *hostKey); err != nil {
+		t.Errorf("Delete(%v) = %v", hostKey, err)
+	}
+	if _, err := mock.Firewalls().Get(ctx, *hostKey); err == nil {
+		t.Errorf("Get(%v) = _, nil; want error after Delete", hostKey)
+	}
+	if _, err := mock.Firewalls().Get(ctx, *key); err != nil {
+		t.Errorf("Get(%v) = _, %v; want nil", key, err)
+	}
+}
+
+func TestProjectRouters(t *testing.T) {
+	t.Parallel()
+
+	single := func(id string) ProjectRouter { return &SingleProjectRouter{ID: id} }
+	mapRouter := &MapProjectRouter{
+		Routes: map[ServiceRoute]ProjectRouter{
+			{Service: "Instances"}:                             single("compute"),
+			{Version: meta.VersionAlpha, Service: "Instances"}: single("alpha-compute"),
+		},
+		Default: single("default"),
+	}
+	sharedVPC := &SharedVPCProjectRouter{
+		Host:    single("host"),
+		Service: &ContextProjectRouter{Default: mapRouter},
+	}
+	ctx := context.Background()
+	reqCtx := WithProjectID(ctx, "request")
+
+	for _, tc := range []struct {
+		desc    string
+		r       ProjectRouter
+		ctx     context.Context
+		version meta.Version
+		service string
+		want    string
+	}{
+		{"map default", mapRouter, ctx, meta.VersionGA, "Firewalls", "default"},
+		{"map all versions", mapRouter, ctx, meta.VersionGA, "Instances", "compute"},
+		{"map version", mapRouter, ctx, meta.VersionAlpha, "Instances", "alpha-compute"},
+		{"context default", &ContextProjectRouter{Default: single("default")}, ctx, meta.VersionGA, "Instances", "default"},
+		{"context", &ContextProjectRouter{Default: single("default")}, reqCtx, meta.VersionGA, "Instances", "request"},
+		{"context empty", &ContextProjectRouter{Default: single("default")}, WithProjectID(ctx, ""), meta.VersionGA, "Instances", "default"},
+		{"shared VPC network", sharedVPC, reqCtx, meta.VersionGA, "Firewalls", "host"},
+		{"shared VPC addresses", sharedVPC, reqCtx, meta.VersionBeta, "Addresses", "host"},
+		{"shared VPC service", sharedVPC, reqCtx, meta.VersionGA, "Instances", "request"},
+		{"shared VPC service default", sharedVPC, ctx, meta.VersionGA, "Instances", "compute"},
+		{"shared VPC custom services", &SharedVPCProjectRouter{Host: single("host"), Service: single("svc"), NetworkServices: []string{"Networks"}}, ctx, meta.VersionGA, "Firewalls", "svc"},
+	} {
+		if got := tc.r.ProjectID(tc.ctx, tc.version, tc.service); got != tc.want {
+			t.Errorf("%s: ProjectID(_, %q, %q) = %q, want %q", tc.desc, tc.version, tc.service, got, tc.want)
+		}
+	}
+}
+
+// projectRecorder records the project of each call.
+type projectRecorder struct {
+	lock     sync.Mutex
+	projects map[string]string
+}
+
+func (r *projectRecorder) Before(ctx context.Context, cc *CallContext) context.Context {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.projects[cc.Service+"."+cc.Operation] = cc.ProjectID
+	return ctx
+}
+
+func (r *projectRecorder) After(ctx context.Context, cc *CallContext) {}
+
+func TestMockProjectRouters(t *testing.T) {
+	t.Parallel()
+
+	mock := NewMockGCE()
+	mock.SetProjectRouter(&SharedVPCProjectRouter{
+		Host: &SingleProjectRouter{ID: "host"},
+		Service: &ContextProjectRouter{
+			Default: &MapProjectRouter{
+				Routes: map[ServiceRoute]ProjectRouter{
+					{Service: "Instances"}: &SingleProjectRouter{ID: "compute"},
+				},
+				Default: &SingleProjectRouter{ID: "mock-project"},
+			},
+		},
+	})
+	rec := &projectRecorder{projects: map[string]string{}}
+	mock.SetObserver(rec)
+
+	ctx := context.Background()
+	tenantCtx := WithProjectID(ctx, "tenant")
+	fwKey := meta.GlobalKey("fw")
+	vmKey := meta.ZonalKey("vm", "us-central1-b")
+	bsKey := meta.GlobalKey("bs")
+
+	if err := mock.Firewalls().Insert(tenantCtx, *fwKey, &ga.Firewall{}); err != nil {
+		t.Fatalf("Firewalls().Insert() = %v", err)
+	}
+	if err := mock.Instances().Insert(ctx, *vmKey, &ga.Instance{}); err != nil {
+		t.Fatalf("Instances().Insert() = %v", err)
+	}
+	if err := mock.Instances().Insert(tenantCtx, *vmKey, &ga.Instance{}); err != nil {
+		t.Fatalf("Instances().Insert() = %v", err)
+	}
+	if err := mock.BackendServices().Insert(ctx, *bsKey, &ga.BackendService{}); err != nil {
+		t.Fatalf("BackendServices().Insert() = %v", err)
+	}
+
+	// Objects are stored per project, with the default project unqualified.
+	for _, k := range []meta.Key{
+		*fwKey.WithProject("host"),
+		*vmKey.WithProject("compute"),
+		*vmKey.WithProject("tenant"),
+	} {
+		if _, ok := mock.MockFirewalls.Objects[k]; !ok && k.Name == "fw" {
+			t.Errorf("MockFirewalls.Objects[%v] not found", k)
+		}
+		if _, ok := mock.MockInstances.Objects[k]; !ok && k.Name == "vm" {
+			t.Errorf("MockInstances.Objects[%v] not found", k)
+		}
+	}
+	if _, ok := mock.MockBackendServices.Objects[*bsKey]; !ok {
+		t.Errorf("MockBackendServices.Objects[%v] not found", bsKey)
+	}
+	if got := rec.projects["Firewalls.Insert"]; got != "host" {
+		t.Errorf("Firewalls.Insert project = %q, want host", got)
+	}
+
+	fw, err := mock.Firewalls().Get(ctx, *fwKey)
+	if err != nil {
+		t.Fatalf("Firewalls().Get() = _, %v", err)
+	}
+	if want := "https://www.googleapis.com/compute/v1/projects/host/global/firewalls/fw"; fw.SelfLink != want {
+		t.Errorf("fw.SelfLink = %q, want %q", fw.SelfLink, want)
+	}
+
+	for _, tc := range []struct {
+		ctx  context.Context
+		want int
+	}{
+		{ctx, 1},
+		{tenantCtx, 1},
+		{WithProjectID(ctx, "other"), 0},
+	} {
+		vms, err := mock.Instances().List(tc.ctx, "us-central1-b", filter.None)
+		if err != nil || len(vms) != tc.want {
+			t.Errorf("Instances().List() = %v, %v; want %d items, nil", vms, err, tc.want)
+		}
+	}
+	if bss, err := mock.BackendServices().List(tenantCtx, filter.None); err != nil || len(bss) != 0 {
+		t.Errorf("BackendServices().List(tenant) = %v, %v; want [], nil", bss, err)
+	}
+	if bss, err := mock.BackendServices().List(ctx, filter.None); err != nil || len(bss) != 1 {
+		t.Errorf("BackendServices().List() = %v, %v; want 1 item, nil", bss, err)
+	}
+	// The project of the key takes precedence over the router.
+	if _, err := mock.Instances().Get(tenantCtx, *vmKey.WithProject("compute")); err != nil {
+		t.Errorf("Instances().Get(%v) = _, %v; want nil", vmKey.WithProject("compute"), err)
+	}
+}
@@ -0,0 +1,205 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"google.golang.org/api/googleapi"
+)
+
+// nonReferenceFields are the fields of compute objects that hold resource
+// URLs that are not references from the object to other resources: the
+// link to the object itself and the resources that use it (Disk.Users).
+var nonReferenceFields = map[string]bool{
+	"selfLink":       true,
+	"selfLinkWithId": true,
+	"users":          true,
+}
+
+// ParseReference parses s if it is a reference to a resource: a URL, a
+// "projects/..." path or a path relative to a project, such as
+// "global/networks/default". The ProjectID is empty for relative paths.
+func ParseReference(s string) (*ResourceID, bool) {
+	switch {
+	case strings.HasPrefix(s, "https://www.googleapis.com/compute/"), strings.HasPrefix(s, "projects/"):
+		r, err := ParseResourceURL(s)
+		return r, err == nil
+	case strings.HasPrefix(s, "global/"), strings.HasPrefix(s, "regions/"), strings.HasPrefix(s, "zones/"):
+		r, err := ParseResourceURL("projects/-/" + s)
+		if err != nil {
+			return nil, false
+		}
+		r.ProjectID = ""
+		return r, true
+	}
+	return nil, false
+}
+
+// References returns the resources referenced by the fields of obj, a
+// compute object such as *ga.UrlMap, ordered by the JSON names of the
+// fields. Relative references are in the project of the SelfLink of obj, if
+// it has one.
+func References(obj interface{}) ([]*ResourceID, error) {
+	return references(obj, nil)
+}
+
+// FieldReferences returns the resources referenced by the given fields of
+// obj, in the order of the fields. Fields are JSON paths whose repeated
+// elements are all followed, e.g. "backends.group" for the groups of all of
+// the backends of a BackendService. Relative references are in the project
+// of the SelfLink of obj, if it has one.
+func FieldReferences(obj interface{}, fields []string) ([]*ResourceID, error) {
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	return references(obj, fields)
+}
+
+// references returns the resources referenced by the fields of obj, or by
+// all of them if fields is nil.
+func references(obj interface{}, fields []string) ([]*ResourceID, error) {
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	var values map[string]interface{}
+	if err := json.Unmarshal(b, &values); err != nil {
+		return nil, fmt.Errorf("%T is not a compute object: %v", obj, err)
+	}
+
+	var project string
+	if selfLink, ok := values["selfLink"].(string); ok {
+		if r, ok := ParseReference(selfLink); ok {
+			project = r.ProjectID
+		}
+	}
+
+	var ret []*ResourceID
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			var keys []string
+			for k := range v {
+				if !nonReferenceFields[k] {
+					keys = append(keys, k)
+				}
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				walk(v[k])
+			}
+		case []interface{}:
+			for _, e := range v {
+				walk(e)
+			}
+		case string:
+			r, ok := ParseReference(v)
+			if !ok {
+				return
+			}
+			if r.ProjectID == "" {
+				r.ProjectID = project
+			}
+			for _, other := range ret {
+				if other.Equal(r) {
+					return
+				}
+			}
+			ret = append(ret, r)
+		}
+	}
+	if fields == nil {
+		walk(values)
+		return ret, nil
+	}
+	for _, f := range fields {
+		for _, v := range fieldValues(values, strings.Split(f, ".")) {
+			walk(v)
+		}
+	}
+	return ret, nil
+}
+
+// fieldValues returns the values of the field path in v, decoded from JSON.
+// The path is followed in all of the elements of lists.
+func fieldValues(v interface{}, path []string) []interface{} {
+	switch x := v.(type) {
+	case []interface{}:
+		var ret []interface{}
+		for _, e := range x {
+			ret = append(ret, fieldValues(e, path)...)
+		}
+		return ret
+	case map[string]interface{}:
+		if len(path) == 0 {
+			return []interface{}{v}
+		}
+		if f, ok := x[path[0]]; ok {
+			return fieldValues(f, path[1:])
+		}
+		return nil
+	}
+	if len(path) == 0 {
+		return []interface{}{v}
+	}
+	return nil
+}
+
+// WithReferenceChecking makes Delete() in the mock fail, as in GCE, if the
+// object is referenced by another object in the mock (e.g. deleting a
+// BackendService used by a UrlMap). The error is a 400 with the reason
+// "resourceInUseByAnotherResource".
+func WithReferenceChecking() MockOption {
+	return func(mock *MockGCE) {
+		mock.setDeleteCheck(func(id *ResourceID) error {
+			for _, obj := range mock.objects() {
+				refs, err := References(obj)
+				if err != nil {
+					return err
+				}
+				for _, r := range refs {
+					if r.Equal(id) {
+						return resourceInUseError(id, obj)
+					}
+				}
+			}
+			return nil
+		})
+	}
+}
+
+func resourceInUseError(id *ResourceID, user interface{}) error {
+	var selfLink string
+	if b, err := json.Marshal(user); err == nil {
+		var fields struct{ SelfLink string }
+		if json.Unmarshal(b, &fields) == nil {
+			selfLink = fields.SelfLink
+		}
+	}
+	msg := fmt.Sprintf("The %s resource '%s' is already being used by '%s'", id.Resource, id.Key.Name, selfLink)
+	return &googleapi.Error{
+		Code:    http.StatusBadRequest,
+		Message: msg,
+		Errors:  []googleapi.ErrorItem{{Reason: "resourceInUseByAnotherResource", Message: msg}},
+	}
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	ga "google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+
+	"github.com/bowei/gce-gen/pkg/cloud/meta"
+)
+
+func TestReferences(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		desc string
+		obj  interface{}
+		want []*ResourceID
+	}{
+		{
+			desc: "no references",
+			obj:  &ga.Firewall{Name: "fw", SourceRanges: []string{"10.0.0.0/8"}},
+		},
+		{
+			desc: "relative references without a self link",
+			obj:  &ga.TargetHttpProxy{UrlMap: "global/urlMaps/um"},
+			want: []*ResourceID{{"", "urlMaps", meta.GlobalKey("um")}},
+		},
+		{
+			desc: "nested, duplicate and relative references",
+			obj: &ga.BackendService{
+				SelfLink: "https://www.googleapis.com/compute/v1/projects/proj/global/backendServices/bs",
+				Backends: []*ga.Backend{
+					{Group: "zones/us-central1-b/instanceGroups/ig"},
+					{Group: "projects/other/zones/us-central1-b/instanceGroups/ig"},
+				},
+				HealthChecks: []string{
+					"https://www.googleapis.com/compute/v1/projects/proj/global/healthChecks/hc",
+					"global/healthChecks/hc",
+				},
+				Description: "not/a/reference",
+			},
+			want: []*ResourceID{
+				{"proj", "instanceGroups", meta.ZonalKey("ig", "us-central1-b")},
+				{"other", "instanceGroups", meta.ZonalKey("ig", "us-central1-b")},
+				{"proj", "healthChecks", meta.GlobalKey("hc")},
+			},
+		},
+		{
+			desc: "users are not references",
+			obj: &ga.Disk{
+				SelfLink: "https://www.googleapis.com/compute/v1/projects/proj/zones/us-central1-b/disks/d",
+				Users:    []string{"https://www.googleapis.com/compute/v1/projects/proj/zones/us-central1-b/instances/vm"},
+				Zone:     "https://www.googleapis.com/compute/v1/projects/proj/zones/us-central1-b",
+			},
+			want: []*ResourceID{{"proj", "zones", meta.GlobalKey("us-central1-b")}},
+		},
+	} {
+		got, err := References(tc.obj)
+		if err != nil {
+			t.Errorf("%s: References() = _, %v; want nil", tc.desc, err)
+			continue
+		}
+		if len(got) != len(tc.want) {
+			t.Errorf("%s: References() = %v; want %v", tc.desc, got, tc.want)
+			continue
+		}
+		for i := range got {
+			if !got[i].Equal(tc.want[i]) {
+				t.Errorf("%s: References()[%d] = %+v; want %+v", tc.desc, i, got[i], tc.want[i])
+			}
+		}
+	}
+
+	if _, err := References("abc"); err == nil {
+		t.Errorf("References(\"abc\") = _, nil; want error")
+	}
+}
+
+func TestFieldReferences(t *testing.T) {
+	t.Parallel()
+
+	obj := &ga.UrlMap{
+		SelfLink:       "https://www.googleapis.com/compute/v1/projects/proj/global/urlMaps/um",
+		DefaultService: "global/backendServices/bs-1",
+		PathMatchers: []*ga.PathMatcher{
+			{PathRules: []*ga.PathRule{{Service: "global/backendServices/bs-2"}, {Service: "global/backendServices/bs-3"}}},
+			{DefaultService: "global/backendServices/bs-1"},
+		},
+	}
+	for _, tc := range []struct {
+		fields []string
+		want   []*ResourceID
+	}{
+		{},
+		{fields: []string{"noSuchField", "defaultService.x"}},
+		{
+			fields: []string{"pathMatchers.pathRules.service", "defaultService"},
+			want: []*ResourceID{
+				{"proj", "backendServices", meta.GlobalKey("bs-2")},
+				{"proj", "backendServices", meta.GlobalKey("bs-3")},
+				{"proj", "backendServices", meta.GlobalKey("bs-1")},
+			},
+		},
+		{
+			fields: []string{"pathMatchers"},
+			want: []*ResourceID{
+				{"proj", "backendServices", meta.GlobalKey("bs-2")},
+				{"proj", "backendServices", meta.GlobalKey("bs-3")},
+				{"proj", "backendServices", meta.GlobalKey("bs-1")},
+			},
+		},
+	} {
+		got, err := FieldReferences(obj, tc.fields)
+		if err != nil {
+			t.Errorf("FieldReferences(_, %q) = _, %v; want nil", tc.fields, err)
+			continue
+		}
+		if len(got) != len(tc.want) {
+			t.Errorf("FieldReferences(_, %q) = %v; want %v", tc.fields, got, tc.want)
+			continue
+		}
+		for i := range got {
+			if !got[i].Equal(tc.want[i]) {
+				t.Errorf("FieldReferences(_, %q)[%d] = %+v; want %+v", tc.fields, i, got[i], tc.want[i])
+			}
+		}
+	}
+}
+
+func TestMockReferenceChecking(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	for _, tc := range []struct {
+		opts    []MockOption
+		wantErr bool
+	}{
+		{nil, false},
+		{[]MockOption{WithReferenceChecking()}, true},
+	} {
+		mock := NewMockGCE(tc.opts...)
+		bsKey := *meta.GlobalKey("bs")
+		if err := mock.BackendServices().Insert(ctx, bsKey, &ga.BackendService{}); err != nil {
+			t.Fatalf("BackendServices().Insert() = %v", err)
+		}
+		if err := mock.UrlMaps().Insert(ctx, *meta.GlobalKey("um"), &ga.UrlMap{DefaultService: "global/backendServices/bs"}); err != nil {
+			t.Fatalf("UrlMaps().Insert() = %v", err)
+		}
+
+		err := mock.BackendServices().Delete(ctx, bsKey)
+		if !tc.wantErr {
+			if err != nil {
+				t.Errorf("BackendServices().Delete() = %v; want nil without reference checking", err)
+			}
+			continue
+		}
+		apiErr, ok := err.(*googleapi.Error)
+		if !ok || apiErr.Code != http.StatusBadRequest || len(apiErr.Errors) != 1 || apiErr.Errors[0].Reason != "resourceInUseByAnotherResource" {
+			t.Errorf("BackendServices().Delete() = %v; want a resourceInUseByAnotherResource error", err)
+		}
+
+		if err := mock.UrlMaps().Delete(ctx, *meta.GlobalKey("um")); err != nil {
+			t.Errorf("UrlMaps().Delete() = %v; want nil", err)
+		}
+		if err := mock.BackendServices().Delete(ctx, bsKey); err != nil {
+			t.Errorf("BackendServices().Delete() = %v; want nil once the URL map is deleted", err)
+		}
+	}
+}
@@ -19,6 +19,7 @@ package cloud
 import (
 	"context"
 	"fmt"
+	"time"
 
 	alpha "google.golang.org/api/compute/v0.alpha"
 	beta "google.golang.org/api/compute/v0.beta"
@@ -33,6 +34,16 @@ type Service struct {
 	Beta          *beta.Service
 	ProjectRouter ProjectRouter
 	RateLimiter   RateLimiter
+	// Observer, if set, is invoked around each call made through the
+	// generated code. Use CallObservers to install more than one.
+	Observer CallObserver
+	// DryRun, if set, puts the Service in dry-run mode. Calls that mutate
+	// resources are recorded in the DryRun plan instead of being sent to
+	// GCE. Calls that read resources are sent to GCE as usual.
+	DryRun *DryRunPlan
+	// Debug enables additional checks of the arguments to calls, such as the
+	// validation of List() filters with filter.Validate().
+	Debug bool
 }
 
 // wrapOperation wraps a GCE anyOP in a version generic operation type.
@@ -69,7 +80,12 @@ func (g *Service) WaitForCompletion(ctx context.Context, genericOp interface{})
 	if err != nil {
 		return err
 	}
-	for done, err := op.isDone(ctx); !done; done, err = op.isDone(ctx) {
+	if cc := callContextFrom(ctx); cc != nil {
+		cc.OperationName = op.name()
+		start := time.Now()
+		defer func() { cc.OperationWait += time.Since(start) }()
+	}
+	for done, err := g.pollOperation(ctx, op); !done; done, err = g.pollOperation(ctx, op) {
 		if err != nil {
 			return err
 		}
@@ -77,3 +93,30 @@ func (g *Service) WaitForCompletion(ctx context.Context, genericOp interface{})
 	}
 	return nil
 }
+
+// pollOperation checks if op is done, notifying the Observer if it is an
+// OperationObserver.
+func (g *Service) pollOperation(ctx context.Context, op operation) (bool, error) {
+	oo, ok := g.Observer.(OperationObserver)
+	if !ok {
+		return op.isDone(ctx)
+	}
+	p := &OperationPoll{
+		RateLimitKey: *op.rateLimitKey(),
+		Name:         op.name(),
+		Start:        time.Now(),
+	}
+	p.Done, p.Err = op.isDone(ctx)
+	p.End = time.Now()
+	oo.OperationPolled(ctx, p)
+	return p.Done, p.Err
+}
+
+// acceptRateLimit waits for the call described by cc to be accepted by the
+// RateLimiter, recording the time spent waiting in cc.
+func (g *Service) acceptRateLimit(ctx context.Context, cc *CallContext) error {
+	start := time.Now()
+	err := g.RateLimiter.Accept(ctx, &cc.RateLimitKey)
+	cc.RateLimitWait += time.Since(start)
+	return err
+}
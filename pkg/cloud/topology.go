@@ -0,0 +1,250 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	ga "google.golang.org/api/compute/v1"
+
+	"github.com/bowei/gce-gen/pkg/cloud/filter"
+	"github.com/bowei/gce-gen/pkg/cloud/meta"
+)
+
+// Topology answers questions about the regions and zones of a project, such
+// as the region of a zone. It is built from Regions().List() and
+// Zones().List(), which are cached for the TTL given to NewTopology(). It is
+// safe for concurrent use.
+//
+//  topo := NewTopology(c, time.Hour)
+//  region, err := topo.RegionOfZone(ctx, key.Zone)
+type Topology struct {
+	c   Cloud
+	ttl time.Duration
+	// now is replaced in tests.
+	now func() time.Time
+
+	lock       sync.Mutex
+	fetched    time.Time
+	valid      bool
+	regions    map[string][]string
+	zoneRegion map[string]string
+}
+
+// NewTopology returns a Topology for c. The regions and zones are fetched
+// again after ttl. They are never fetched again if ttl is 0.
+func NewTopology(c Cloud, ttl time.Duration) *Topology {
+	return &Topology{c: c, ttl: ttl, now: time.Now}
+}
+
+// Regions returns the sorted names of all of the regions.
+func (t *Topology) Regions(ctx context.Context) ([]string, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if err := t.refresh(ctx); err != nil {
+		return nil, err
+	}
+	var ret []string
+	for r := range t.regions {
+		ret = append(ret, r)
+	}
+	sort.Strings(ret)
+	return ret, nil
+}
+
+// Zones returns the sorted names of all of the zones.
+func (t *Topology) Zones(ctx context.Context) ([]string, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if err := t.refresh(ctx); err != nil {
+		return nil, err
+	}
+	var ret []string
+	for z := range t.zoneRegion {
+		ret = append(ret, z)
+	}
+	sort.Strings(ret)
+	return ret, nil
+}
+
+// ZonesInRegion returns the sorted names of the zones in region.
+func (t *Topology) ZonesInRegion(ctx context.Context, region string) ([]string, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if err := t.refresh(ctx); err != nil {
+		return nil, err
+	}
+	zones, ok := t.regions[region]
+	if !ok {
+		return nil, fmt.Errorf("region %q not found", region)
+	}
+	return append([]string(nil), zones...), nil
+}
+
+// RegionOfZone returns the name of the region that zone is in.
+func (t *Topology) RegionOfZone(ctx context.Context, zone string) (string, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if err := t.refresh(ctx); err != nil {
+		return "", err
+	}
+	region, ok := t.zoneRegion[zone]
+	if !ok {
+		return "", fmt.Errorf("zone %q not found", zone)
+	}
+	return region, nil
+}
+
+// RegionOfKey returns the region of the key: the region of a regional key or
+// the region of the zone of a zonal key. It is an error if key is global.
+func (t *Topology) RegionOfKey(ctx context.Context, key meta.Key) (string, error) {
+	switch key.Type() {
+	case meta.Regional:
+		return key.Region, nil
+	case meta.Zonal:
+		return t.RegionOfZone(ctx, key.Zone)
+	}
+	return "", fmt.Errorf("key %v is global", key)
+}
+
+// Invalidate the cache so that the next call fetches the regions and zones.
+func (t *Topology) Invalidate() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.valid = false
+}
+
+// refresh the regions and zones if the cache has expired. t.lock must be held.
+func (t *Topology) refresh(ctx context.Context) error {
+	if t.valid && (t.ttl == 0 || t.now().Sub(t.fetched) < t.ttl) {
+		return nil
+	}
+	regions, err := t.c.Regions().List(ctx, filter.None)
+	if err != nil {
+		return err
+	}
+	zones, err := t.c.Zones().List(ctx, filter.None)
+	if err != nil {
+		return err
+	}
+
+	byRegion := map[string]map[string]bool{}
+	zoneRegion := map[string]string{}
+	for _, r := range regions {
+		byRegion[r.Name] = map[string]bool{}
+		for _, url := range r.Zones {
+			if z := resourceName(url); z != "" {
+				byRegion[r.Name][z] = true
+				zoneRegion[z] = r.Name
+			}
+		}
+	}
+	for _, z := range zones {
+		r := resourceName(z.Region)
+		if r == "" {
+			continue
+		}
+		if byRegion[r] == nil {
+			byRegion[r] = map[string]bool{}
+		}
+		byRegion[r][z.Name] = true
+		zoneRegion[z.Name] = r
+	}
+
+	t.regions = map[string][]string{}
+	for r, zones := range byRegion {
+		t.regions[r] = []string{}
+		for z := range zones {
+			t.regions[r] = append(t.regions[r], z)
+		}
+		sort.Strings(t.regions[r])
+	}
+	t.zoneRegion = zoneRegion
+	t.fetched = t.now()
+	t.valid = true
+	return nil
+}
+
+// resourceName returns the name in a region or zone URL, or "" if url is not
+// valid.
+func resourceName(url string) string {
+	id, err := ParseResourceURL(url)
+	if err != nil || id.Key == nil {
+		return ""
+	}
+	return id.Key.Name
+}
+
+// MockOption configures the mock returned by NewMockGCE().
+type MockOption func(*MockGCE)
+
+// DefaultTopologyCatalog is a catalog of regions and their zones similar to
+// the one in production, for use with WithMockTopology().
+var DefaultTopologyCatalog = map[string][]string{
+	"asia-east1":      {"asia-east1-a", "asia-east1-b", "asia-east1-c"},
+	"asia-northeast1": {"asia-northeast1-a", "asia-northeast1-b", "asia-northeast1-c"},
+	"europe-west1":    {"europe-west1-b", "europe-west1-c", "europe-west1-d"},
+	"europe-west4":    {"europe-west4-a", "europe-west4-b", "europe-west4-c"},
+	"us-central1":     {"us-central1-a", "us-central1-b", "us-central1-c", "us-central1-f"},
+	"us-east1":        {"us-east1-b", "us-east1-c", "us-east1-d"},
+	"us-east4":        {"us-east4-a", "us-east4-b", "us-east4-c"},
+	"us-west1":        {"us-west1-a", "us-west1-b", "us-west1-c"},
+}
+
+// WithMockTopology seeds the Regions and Zones of the mock with catalog, a
+// map of region name to the names of the zones in the region (e.g.
+// DefaultTopologyCatalog). Region.Zones and Zone.Region link the objects in
+// the same way as in production. The objects are stored in the project that
+// Regions().List() and Zones().List() are routed to, so a ProjectRouter must
+// be set before, e.g. with WithMockProjectRouter().
+func WithMockTopology(catalog map[string][]string) MockOption {
+	return func(mock *MockGCE) {
+		ctx := context.Background()
+		regionProject := mockCallProjectID(ctx, mock.MockRegions.ProjectRouter, nil, meta.VersionGA, "Regions")
+		zoneProject := mockCallProjectID(ctx, mock.MockZones.ProjectRouter, nil, meta.VersionGA, "Zones")
+		for region, zones := range catalog {
+			regionKey := meta.GlobalKey(region)
+			regionLink := SelfLink(meta.VersionGA, regionProject, "regions", *regionKey)
+			obj := &ga.Region{
+				Name:     region,
+				SelfLink: regionLink,
+				Status:   "UP",
+			}
+			for _, zone := range zones {
+				zoneKey := meta.GlobalKey(zone)
+				zoneLink := SelfLink(meta.VersionGA, zoneProject, "zones", *zoneKey)
+				obj.Zones = append(obj.Zones, zoneLink)
+				mock.MockZones.Objects[mockStorageKey(*zoneKey, zoneProject)] = &MockZonesObj{&ga.Zone{
+					Name:     zone,
+					SelfLink: zoneLink,
+					Region:   regionLink,
+					Status:   "UP",
+				}}
+			}
+			mock.MockRegions.Objects[mockStorageKey(*regionKey, regionProject)] = &MockRegionsObj{obj}
+		}
+	}
+}
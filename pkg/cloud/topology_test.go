@@ -0,0 +1,153 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	ga "google.golang.org/api/compute/v1"
+
+	"github.com/bowei/gce-gen/pkg/cloud/filter"
+	"github.com/bowei/gce-gen/pkg/cloud/meta"
+)
+
+func TestTopology(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := NewMockGCE(WithMockTopology(DefaultTopologyCatalog))
+	topo := NewTopology(mock, 0)
+
+	region, err := topo.RegionOfZone(ctx, "us-central1-b")
+	if err != nil || region != "us-central1" {
+		t.Errorf("RegionOfZone(us-central1-b) = %q, %v; want us-central1, nil", region, err)
+	}
+	zones, err := topo.ZonesInRegion(ctx, "us-central1")
+	if want := []string{"us-central1-a", "us-central1-b", "us-central1-c", "us-central1-f"}; err != nil || !reflect.DeepEqual(zones, want) {
+		t.Errorf("ZonesInRegion(us-central1) = %v, %v; want %v, nil", zones, err, want)
+	}
+	regions, err := topo.Regions(ctx)
+	if err != nil || len(regions) != len(DefaultTopologyCatalog) {
+		t.Errorf("Regions() = %v, %v; want %d regions, nil", regions, err, len(DefaultTopologyCatalog))
+	}
+	all, err := topo.Zones(ctx)
+	if err != nil || len(all) != 25 {
+		t.Errorf("Zones() = %v, %v; want 25 zones, nil", all, err)
+	}
+	if region, err := topo.RegionOfKey(ctx, *meta.ZonalKey("vm", "europe-west1-d")); err != nil || region != "europe-west1" {
+		t.Errorf("RegionOfKey() = %q, %v; want europe-west1, nil", region, err)
+	}
+	if region, err := topo.RegionOfKey(ctx, *meta.RegionalKey("addr", "us-east4")); err != nil || region != "us-east4" {
+		t.Errorf("RegionOfKey() = %q, %v; want us-east4, nil", region, err)
+	}
+	if _, err := topo.RegionOfKey(ctx, *meta.GlobalKey("fw")); err == nil {
+		t.Errorf("RegionOfKey(global) = _, nil; want error")
+	}
+	if _, err := topo.RegionOfZone(ctx, "mars-north1-a"); err == nil {
+		t.Errorf("RegionOfZone(mars-north1-a) = _, nil; want error")
+	}
+	if _, err := topo.ZonesInRegion(ctx, "mars-north1"); err == nil {
+		t.Errorf("ZonesInRegion(mars-north1) = _, nil; want error")
+	}
+
+	// The mock objects are linked in the same way as in production.
+	r, err := mock.Regions().Get(ctx, *meta.GlobalKey("us-east1"))
+	if err != nil {
+		t.Fatalf("Regions().Get(us-east1) = _, %v", err)
+	}
+	z, err := mock.Zones().Get(ctx, *meta.GlobalKey("us-east1-b"))
+	if err != nil {
+		t.Fatalf("Zones().Get(us-east1-b) = _, %v", err)
+	}
+	if z.Region != r.SelfLink || len(r.Zones) != 3 || r.Zones[0] != z.SelfLink {
+		t.Errorf("Region = %+v, Zone = %+v; want linked objects", r, z)
+	}
+}
+
+func TestTopologyProjectRouter(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := NewMockGCE(
+		WithMockProjectRouter(&ContextProjectRouter{Default: &SingleProjectRouter{ID: "my-project"}}),
+		WithMockTopology(map[string][]string{"us-central1": {"us-central1-a", "us-central1-b"}}),
+	)
+
+	regions, err := mock.Regions().List(ctx, filter.None)
+	if err != nil || len(regions) != 1 {
+		t.Fatalf("Regions().List() = %v, %v; want 1 region, nil", regions, err)
+	}
+	if want := "https://www.googleapis.com/compute/v1/projects/my-project/regions/us-central1"; regions[0].SelfLink != want {
+		t.Errorf("Region.SelfLink = %q, want %q", regions[0].SelfLink, want)
+	}
+	zones, err := mock.Zones().List(ctx, filter.None)
+	if err != nil || len(zones) != 2 {
+		t.Fatalf("Zones().List() = %v, %v; want 2 zones, nil", zones, err)
+	}
+	if _, err := mock.Zones().Get(ctx, *meta.GlobalKey("us-central1-b")); err != nil {
+		t.Errorf("Zones().Get(us-central1-b) = _, %v; want nil", err)
+	}
+	if region, err := NewTopology(mock, 0).RegionOfZone(ctx, "us-central1-b"); err != nil || region != "us-central1" {
+		t.Errorf("RegionOfZone(us-central1-b) = %q, %v; want us-central1, nil", region, err)
+	}
+
+	// Other projects do not have the regions.
+	if regions, err := mock.Regions().List(WithProjectID(ctx, "other"), filter.None); err != nil || len(regions) != 0 {
+		t.Errorf("Regions().List(other) = %v, %v; want [], nil", regions, err)
+	}
+}
+
+func TestTopologyCache(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := NewMockGCE(WithMockTopology(map[string][]string{"us-central1": {"us-central1-a"}}))
+	var lists int
+	mock.MockZones.ListHook = func(*MockZones, context.Context, *filter.F) (bool, []*ga.Zone, error) {
+		lists++
+		return false, nil, nil
+	}
+	now := time.Now()
+	topo := NewTopology(mock, time.Minute)
+	topo.now = func() time.Time { return now }
+
+	check := func(wantRegion string, wantLists int) {
+		t.Helper()
+		region, err := topo.RegionOfZone(ctx, "us-central1-b")
+		if err != nil && wantRegion != "" || err == nil && region != wantRegion {
+			t.Errorf("RegionOfZone(us-central1-b) = %q, %v; want %q", region, err, wantRegion)
+		}
+		if lists != wantLists {
+			t.Errorf("Zones().List() was called %d times, want %d", lists, wantLists)
+		}
+	}
+	check("", 1)
+
+	// A zone added to the mock is not seen until the cache expires.
+	WithMockTopology(map[string][]string{"us-central1": {"us-central1-a", "us-central1-b"}})(mock)
+	now = now.Add(30 * time.Second)
+	check("", 1)
+	now = now.Add(time.Minute)
+	check("us-central1", 2)
+	check("us-central1", 2)
+
+	topo.Invalidate()
+	check("us-central1", 3)
+}
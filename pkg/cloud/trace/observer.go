@@ -0,0 +1,82 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trace
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/bowei/gce-gen/pkg/cloud"
+)
+
+// Span attributes set by the Observer.
+const (
+	AttrProject   = "gce.project"
+	AttrVersion   = "gce.version"
+	AttrKey       = "gce.key"
+	AttrOperation = "gce.operation"
+	AttrDone      = "gce.operation.done"
+)
+
+// Observer is a cloud.CallObserver that creates a span named
+// "<Service>.<Operation>" for each call. Each poll of an operation in
+// WaitForCompletion() creates a child span named "Operations.Get".
+type Observer struct {
+	tracer *Tracer
+}
+
+// NewObserver returns an observer that creates spans with t.
+func NewObserver(t *Tracer) *Observer {
+	return &Observer{tracer: t}
+}
+
+// callSpanKey is used to find the span of the call in After(). This is
+// separate from the current span as other observers may start spans of
+// their own.
+type callSpanKey struct{}
+
+// Before implements cloud.CallObserver.
+func (o *Observer) Before(ctx context.Context, cc *cloud.CallContext) context.Context {
+	ctx, span := o.tracer.Start(ctx, cc.Service+"."+cc.Operation)
+	span.SetAttribute(AttrProject, cc.ProjectID)
+	span.SetAttribute(AttrVersion, string(cc.Version))
+	if cc.Key != nil {
+		span.SetAttribute(AttrKey, cc.Key.String())
+	}
+	return context.WithValue(ctx, callSpanKey{}, span)
+}
+
+// After implements cloud.CallObserver.
+func (o *Observer) After(ctx context.Context, cc *cloud.CallContext) {
+	span, ok := ctx.Value(callSpanKey{}).(*Span)
+	if !ok {
+		return
+	}
+	span.SetError(cc.Err)
+	span.endAt(cc.End)
+}
+
+// OperationPolled implements cloud.OperationObserver.
+func (o *Observer) OperationPolled(ctx context.Context, p *cloud.OperationPoll) {
+	span := o.tracer.newSpan(ctx, p.Service+"."+p.Operation, p.Start)
+	span.SetAttribute(AttrProject, p.ProjectID)
+	span.SetAttribute(AttrVersion, string(p.Version))
+	span.SetAttribute(AttrOperation, p.Name)
+	span.SetAttribute(AttrDone, strconv.FormatBool(p.Done))
+	span.SetError(p.Err)
+	span.endAt(p.End)
+}
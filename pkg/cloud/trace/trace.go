@@ -0,0 +1,230 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package trace creates trace spans for the calls made through the generated
+// cloud code. Span and trace IDs follow the OpenTelemetry (W3C trace
+// context) format so that the spans can be forwarded to an OpenTelemetry
+// collector by an Exporter.
+//
+//  exporter := trace.NewInMemoryExporter()
+//  svc := &cloud.Service{
+//    ...
+//    Observer: trace.NewObserver(trace.NewTracer(exporter)),
+//  }
+package trace
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// TraceID is the 16 byte ID of a trace.
+type TraceID [16]byte
+
+// String returns the hex representation of the ID.
+func (id TraceID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// SpanID is the 8 byte ID of a span.
+type SpanID [8]byte
+
+// String returns the hex representation of the ID.
+func (id SpanID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// IsValid is true if the ID is not all zeros.
+func (id SpanID) IsValid() bool {
+	return id != SpanID{}
+}
+
+// StatusCode of a span.
+type StatusCode int
+
+const (
+	// StatusUnset is the default status.
+	StatusUnset StatusCode = iota
+	// StatusOK indicates the operation completed successfully.
+	StatusOK
+	// StatusError indicates the operation failed.
+	StatusError
+)
+
+// Event is a timestamped annotation on a span.
+type Event struct {
+	Name       string
+	Time       time.Time
+	Attributes map[string]string
+}
+
+// SpanData is the exported (immutable) data of a span.
+type SpanData struct {
+	Name         string
+	TraceID      TraceID
+	SpanID       SpanID
+	ParentSpanID SpanID
+	Start        time.Time
+	End          time.Time
+	Attributes   map[string]string
+	Events       []Event
+	Status       StatusCode
+	// StatusMessage is the error message if Status is StatusError.
+	StatusMessage string
+}
+
+// Exporter receives spans when they end.
+type Exporter interface {
+	ExportSpan(s *SpanData)
+}
+
+// Tracer creates spans.
+type Tracer struct {
+	exporter Exporter
+}
+
+// NewTracer returns a new Tracer that sends finished spans to exporter.
+func NewTracer(exporter Exporter) *Tracer {
+	return &Tracer{exporter: exporter}
+}
+
+// Span is a span in progress.
+type Span struct {
+	tracer *Tracer
+
+	lock  sync.Mutex
+	data  SpanData
+	ended bool
+}
+
+type spanKey struct{}
+
+// SpanFromContext returns the current span in ctx or nil if there is none.
+func SpanFromContext(ctx context.Context) *Span {
+	s, _ := ctx.Value(spanKey{}).(*Span)
+	return s
+}
+
+// ContextWithSpan returns a context with s as the current span.
+func ContextWithSpan(ctx context.Context, s *Span) context.Context {
+	return context.WithValue(ctx, spanKey{}, s)
+}
+
+// Start a new span named name. The span is a child of the current span in ctx
+// if there is one. The returned context has the new span as the current span.
+func (t *Tracer) Start(ctx context.Context, name string) (context.Context, *Span) {
+	s := t.newSpan(ctx, name, time.Now())
+	return ContextWithSpan(ctx, s), s
+}
+
+func (t *Tracer) newSpan(ctx context.Context, name string, start time.Time) *Span {
+	s := &Span{
+		tracer: t,
+		data: SpanData{
+			Name:       name,
+			SpanID:     newSpanID(),
+			Start:      start,
+			Attributes: map[string]string{},
+		},
+	}
+	if parent := SpanFromContext(ctx); parent != nil {
+		s.data.TraceID = parent.data.TraceID
+		s.data.ParentSpanID = parent.data.SpanID
+	} else {
+		s.data.TraceID = newTraceID()
+	}
+	return s
+}
+
+// TraceID of the span.
+func (s *Span) TraceID() TraceID {
+	return s.data.TraceID
+}
+
+// SpanID of the span.
+func (s *Span) SpanID() SpanID {
+	return s.data.SpanID
+}
+
+// SetAttribute sets the attribute k to v.
+func (s *Span) SetAttribute(k, v string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.data.Attributes[k] = v
+}
+
+// AddEvent adds an event to the span.
+func (s *Span) AddEvent(name string, attrs map[string]string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.data.Events = append(s.data.Events, Event{Name: name, Time: time.Now(), Attributes: attrs})
+}
+
+// SetError sets the status of the span to StatusError if err is not nil and
+// StatusOK otherwise.
+func (s *Span) SetError(err error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if err != nil {
+		s.data.Status = StatusError
+		s.data.StatusMessage = err.Error()
+		return
+	}
+	s.data.Status = StatusOK
+	s.data.StatusMessage = ""
+}
+
+// End the span and send it to the exporter. Calling End more than once has
+// no effect.
+func (s *Span) End() {
+	s.endAt(time.Now())
+}
+
+func (s *Span) endAt(t time.Time) {
+	s.lock.Lock()
+	if s.ended {
+		s.lock.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.End = t
+	data := s.data
+	data.Attributes = map[string]string{}
+	for k, v := range s.data.Attributes {
+		data.Attributes[k] = v
+	}
+	data.Events = append([]Event(nil), s.data.Events...)
+	s.lock.Unlock()
+
+	if s.tracer.exporter != nil {
+		s.tracer.exporter.ExportSpan(&data)
+	}
+}
+
+func newTraceID() TraceID {
+	var id TraceID
+	rand.Read(id[:])
+	return id
+}
+
+func newSpanID() SpanID {
+	var id SpanID
+	rand.Read(id[:])
+	return id
+}
@@ -0,0 +1,202 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trace
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	ga "google.golang.org/api/compute/v1"
+
+	"github.com/bowei/gce-gen/pkg/cloud"
+	"github.com/bowei/gce-gen/pkg/cloud/meta"
+)
+
+type noWaitRateLimiter struct{}
+
+func (*noWaitRateLimiter) Accept(ctx context.Context, key *cloud.RateLimitKey) error {
+	return nil
+}
+
+// fakeCompute is a stand-in for the compute API that handles firewall
+// insert and get. Operations are reported as done on the second poll.
+type fakeCompute struct {
+	lock  sync.Mutex
+	polls int
+}
+
+func (f *fakeCompute) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	const opLink = "https://www.googleapis.com/compute/v1/projects/proj/global/operations/op-1"
+	var resp interface{}
+	switch {
+	case r.Method == "POST" && r.URL.Path == "/projects/proj/global/firewalls":
+		resp = &ga.Operation{Name: "op-1", SelfLink: opLink, Status: "RUNNING"}
+	case r.Method == "GET" && r.URL.Path == "/projects/proj/global/operations/op-1":
+		f.polls++
+		status := "RUNNING"
+		if f.polls > 1 {
+			status = "DONE"
+		}
+		resp = &ga.Operation{Name: "op-1", SelfLink: opLink, Status: status}
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error": {"code": 404, "message": "not found"}}`))
+		return
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+func newGCE(t *testing.T, o cloud.CallObserver) (*cloud.GCE, func()) {
+	srv := httptest.NewServer(&fakeCompute{})
+	svc, err := ga.New(srv.Client())
+	if err != nil {
+		t.Fatalf("ga.New() = _, %v", err)
+	}
+	svc.BasePath = srv.URL + "/projects/"
+	gce := cloud.NewGCE(&cloud.Service{
+		GA:            svc,
+		ProjectRouter: &cloud.SingleProjectRouter{ID: "proj"},
+		RateLimiter:   &noWaitRateLimiter{},
+		Observer:      o,
+	})
+	return gce, srv.Close
+}
+
+func TestObserverGCE(t *testing.T) {
+	t.Parallel()
+
+	exporter := NewInMemoryExporter()
+	tracer := NewTracer(exporter)
+	gce, cleanup := newGCE(t, NewObserver(tracer))
+	defer cleanup()
+
+	ctx, root := tracer.Start(context.Background(), "root")
+	key := meta.GlobalKey("fw-1")
+	if err := gce.Firewalls().Insert(ctx, *key, &ga.Firewall{}); err != nil {
+		t.Fatalf("Firewalls().Insert() = %v, want nil", err)
+	}
+	if _, err := gce.Firewalls().Get(ctx, *key); err == nil {
+		t.Fatalf("Firewalls().Get() = _, nil, want error")
+	}
+	root.End()
+
+	spans := exporter.Spans()
+	var names []string
+	for _, s := range spans {
+		names = append(names, s.Name)
+	}
+	wantNames := []string{"Operations.Get", "Operations.Get", "Firewalls.Insert", "Firewalls.Get", "root"}
+	if len(names) != len(wantNames) {
+		t.Fatalf("span names = %v, want %v", names, wantNames)
+	}
+	for i := range names {
+		if names[i] != wantNames[i] {
+			t.Fatalf("span names = %v, want %v", names, wantNames)
+		}
+	}
+
+	poll1, poll2, insert, get := spans[0], spans[1], spans[2], spans[3]
+	for _, s := range spans {
+		if s.TraceID != root.TraceID() {
+			t.Errorf("span %q TraceID = %v, want %v", s.Name, s.TraceID, root.TraceID())
+		}
+	}
+	if insert.ParentSpanID != root.SpanID() || get.ParentSpanID != root.SpanID() {
+		t.Errorf("call spans not children of the caller span")
+	}
+	if poll1.ParentSpanID != insert.SpanID || poll2.ParentSpanID != insert.SpanID {
+		t.Errorf("poll spans not children of the Insert span")
+	}
+	if poll1.Attributes[AttrOperation] != "op-1" || poll1.Attributes[AttrDone] != "false" || poll2.Attributes[AttrDone] != "true" {
+		t.Errorf("poll attributes = %v, %v; want operation op-1, done false then true", poll1.Attributes, poll2.Attributes)
+	}
+	wantAttrs := map[string]string{AttrProject: "proj", AttrVersion: "ga", AttrKey: key.String()}
+	for k, v := range wantAttrs {
+		if insert.Attributes[k] != v {
+			t.Errorf("insert.Attributes[%q] = %q, want %q", k, insert.Attributes[k], v)
+		}
+	}
+	if insert.Status != StatusOK {
+		t.Errorf("insert.Status = %v, want StatusOK", insert.Status)
+	}
+	if get.Status != StatusError || get.StatusMessage == "" {
+		t.Errorf("get.Status = %v, %q; want StatusError", get.Status, get.StatusMessage)
+	}
+}
+
+func TestObserverMock(t *testing.T) {
+	t.Parallel()
+
+	exporter := NewInMemoryExporter()
+	mock := cloud.NewMockGCE()
+	mock.SetObserver(NewObserver(NewTracer(exporter)))
+
+	if err := mock.Firewalls().Insert(context.Background(), *meta.GlobalKey("fw"), &ga.Firewall{}); err != nil {
+		t.Fatalf("Firewalls().Insert() = %v, want nil", err)
+	}
+	spans := exporter.Spans()
+	if len(spans) != 1 || spans[0].Name != "Firewalls.Insert" || spans[0].ParentSpanID.IsValid() {
+		t.Errorf("spans = %+v, want a single root span Firewalls.Insert", spans)
+	}
+}
+
+func TestSpan(t *testing.T) {
+	t.Parallel()
+
+	exporter := NewInMemoryExporter()
+	tracer := NewTracer(exporter)
+
+	ctx, parent := tracer.Start(context.Background(), "parent")
+	if SpanFromContext(ctx) != parent {
+		t.Errorf("SpanFromContext() != parent")
+	}
+	_, child := tracer.Start(ctx, "child")
+	child.SetAttribute("a", "b")
+	child.AddEvent("event", map[string]string{"x": "y"})
+	child.SetError(errors.New("injected"))
+	child.End()
+	child.End()
+	parent.End()
+
+	spans := exporter.Spans()
+	if len(spans) != 2 {
+		t.Fatalf("len(spans) = %d, want 2", len(spans))
+	}
+	c := spans[0]
+	if c.ParentSpanID != parent.SpanID() || c.TraceID != parent.TraceID() {
+		t.Errorf("child = %+v, want child of %v", c, parent.SpanID())
+	}
+	if c.Attributes["a"] != "b" || len(c.Events) != 1 || c.Status != StatusError || c.StatusMessage != "injected" {
+		t.Errorf("child = %+v", c)
+	}
+	if len(c.TraceID.String()) != 32 || len(c.SpanID.String()) != 16 {
+		t.Errorf("IDs = %v, %v; want 32 and 16 hex digits", c.TraceID, c.SpanID)
+	}
+
+	exporter.Reset()
+	if len(exporter.Spans()) != 0 {
+		t.Errorf("Spans() after Reset() = %v, want empty", exporter.Spans())
+	}
+}
@@ -55,6 +55,24 @@ func (r *ResourceID) Equal(other *ResourceID) bool {
 	return false
 }
 
+// NewResourceID returns the ResourceID of resource (e.g. "firewalls") for
+// key. The ProjectID is the project of the key, which may be empty; the Key of
+// the ResourceID does not have a project.
+func NewResourceID(resource string, key meta.Key) *ResourceID {
+	ret := &ResourceID{ProjectID: key.Project, Resource: resource}
+	ret.Key = key.WithProject("")
+	return ret
+}
+
+// QualifiedKey returns the key of the resource with the project set to
+// ProjectID. It returns nil if the ResourceID does not have a key.
+func (r *ResourceID) QualifiedKey() *meta.Key {
+	if r.Key == nil {
+		return nil
+	}
+	return r.Key.WithProject(r.ProjectID)
+}
+
 // ParseResourceURL parses resource URLs of the following formats:
 //
 //   projects/<proj>/global/<res>/<name>
@@ -161,7 +179,10 @@ func SelfLink(ver meta.Version, project, resource string, key meta.Key) string {
 	case meta.Regional:
 		return fmt.Sprintf("%sprojects/%s/regions/%s/%s/%s", prefix, project, key.Region, resource, key.Name)
 	case meta.Global:
-		return fmt.Sprintf("%sprojects/%s/%s/%s", prefix, project, resource, key.Name)
+		if resource == "regions" || resource == "zones" {
+			return fmt.Sprintf("%sprojects/%s/%s/%s", prefix, project, resource, key.Name)
+		}
+		return fmt.Sprintf("%sprojects/%s/global/%s/%s", prefix, project, resource, key.Name)
 	}
 	return "invalid-self-link"
 }
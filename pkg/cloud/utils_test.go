@@ -187,11 +187,62 @@ func TestSelfLink(t *testing.T) {
 			"proj4",
 			"urlMaps",
 			*meta.GlobalKey("key3"),
-			"https://www.googleapis.com/compute/v1/projects/proj4/urlMaps/key3",
+			"https://www.googleapis.com/compute/v1/projects/proj4/global/urlMaps/key3",
+		},
+		{
+			meta.VersionGA,
+			"proj4",
+			"zones",
+			*meta.GlobalKey("us-central1-b"),
+			"https://www.googleapis.com/compute/v1/projects/proj4/zones/us-central1-b",
 		},
 	}{
 		if link := SelfLink(tc.ver, tc.project, tc.resource, tc.key); link != tc.want {
 			t.Errorf("SelfLink(%v, %q, %q, %v) = %v, want %q", tc.ver, tc.project, tc.resource, tc.key, link, tc.want)
 		}
+		want := &ResourceID{tc.project, tc.resource, &tc.key}
+		if r, err := ParseResourceURL(tc.want); err != nil || !r.Equal(want) {
+			t.Errorf("ParseResourceURL(%q) = %+v, %v; want %+v, nil", tc.want, r, err, want)
+		}
+	}
+}
+
+func TestResourceIDQualifiedKey(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		resource string
+		key      *meta.Key
+		want     *ResourceID
+	}{
+		{
+			"subnetworks",
+			meta.RegionalKey("subnet", "us-central1").WithProject("host-project"),
+			&ResourceID{"host-project", "subnetworks", meta.RegionalKey("subnet", "us-central1")},
+		},
+		{
+			"instances",
+			meta.ZonalKey("vm", "us-central1-b"),
+			&ResourceID{"", "instances", meta.ZonalKey("vm", "us-central1-b")},
+		},
+	} {
+		r := NewResourceID(tc.resource, *tc.key)
+		if !r.Equal(tc.want) {
+			t.Errorf("NewResourceID(%q, %v) = %+v, want %+v", tc.resource, tc.key, r, tc.want)
+		}
+		if got := r.QualifiedKey(); *got != *tc.key {
+			t.Errorf("NewResourceID(%q, %v).QualifiedKey() = %v, want %v", tc.resource, tc.key, got, tc.key)
+		}
+	}
+
+	r, err := ParseResourceURL("https://www.googleapis.com/compute/v1/projects/host-project/regions/us-central1/subnetworks/subnet")
+	if err != nil {
+		t.Fatalf("ParseResourceURL() = _, %v", err)
+	}
+	if got, want := r.QualifiedKey(), meta.RegionalKey("subnet", "us-central1").WithProject("host-project"); *got != *want {
+		t.Errorf("QualifiedKey() = %v, want %v", got, want)
+	}
+	if got := (&ResourceID{ProjectID: "p", Resource: "projects"}).QualifiedKey(); got != nil {
+		t.Errorf("QualifiedKey() = %v, want nil", got)
 	}
 }